@@ -47,6 +47,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/state"
 	"github.com/azure/azure-dev/cli/azd/pkg/templates"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/bicep"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/dotnet"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
@@ -489,6 +490,7 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 
 	container.MustRegisterSingleton(templates.NewTemplateManager)
 	container.MustRegisterSingleton(templates.NewSourceManager)
+	container.MustRegisterSingleton(templates.NewGitCloner)
 	container.MustRegisterScoped(project.NewResourceManager)
 	container.MustRegisterScoped(func(serviceLocator ioc.ServiceLocator) *lazy.Lazy[project.ResourceManager] {
 		return lazy.NewLazy(func() (project.ResourceManager, error) {
@@ -519,6 +521,15 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 		})
 	})
 	container.MustRegisterSingleton(repository.NewInitializer)
+	container.MustRegisterSingleton(repository.NewTemplateValidator)
+	container.MustRegisterSingleton(func(serviceLocator ioc.ServiceLocator) *lazy.Lazy[bicep.BicepCli] {
+		return lazy.NewLazy(func() (bicep.BicepCli, error) {
+			var bicepCli bicep.BicepCli
+			err := serviceLocator.Resolve(&bicepCli)
+
+			return bicepCli, err
+		})
+	})
 	container.MustRegisterSingleton(alpha.NewFeaturesManager)
 	container.MustRegisterSingleton(config.NewUserConfigManager)
 	container.MustRegisterSingleton(config.NewManager)
@@ -536,6 +547,7 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 	container.MustRegisterSingleton(account.NewSubscriptionsManager)
 	container.MustRegisterSingleton(account.NewSubscriptionCredentialProvider)
 	container.MustRegisterSingleton(azcli.NewManagedClustersService)
+	container.MustRegisterSingleton(azcli.NewManagedIdentityService)
 	container.MustRegisterSingleton(azcli.NewAdService)
 	container.MustRegisterSingleton(azcli.NewContainerRegistryService)
 	container.MustRegisterSingleton(containerapps.NewContainerAppService)
@@ -566,10 +578,20 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 	})
 	container.MustRegisterSingleton(azapi.NewDeployments)
 	container.MustRegisterSingleton(azapi.NewDeploymentOperations)
+	container.MustRegisterSingleton(azapi.NewDeploymentStacks)
+	container.MustRegisterSingleton(azapi.NewCostManagement)
 	container.MustRegisterSingleton(docker.NewDocker)
 	container.MustRegisterSingleton(dotnet.NewDotNetCli)
 	container.MustRegisterSingleton(git.NewGitCli)
 	container.MustRegisterSingleton(github.NewGitHubCli)
+	container.MustRegisterSingleton(func(serviceLocator ioc.ServiceLocator) *lazy.Lazy[github.GitHubCli] {
+		return lazy.NewLazy(func() (github.GitHubCli, error) {
+			var ghCli github.GitHubCli
+			err := serviceLocator.Resolve(&ghCli)
+
+			return ghCli, err
+		})
+	})
 	container.MustRegisterSingleton(javac.NewCli)
 	container.MustRegisterSingleton(kubectl.NewKubectl)
 	container.MustRegisterSingleton(maven.NewMavenCli)
@@ -599,6 +621,7 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 		project.AksTarget:                project.NewAksTarget,
 		project.SpringAppTarget:          project.NewSpringAppTarget,
 		project.DotNetContainerAppTarget: project.NewDotNetContainerAppTarget,
+		project.DotNetAksTarget:          project.NewDotNetAksTarget,
 	}
 
 	for target, constructor := range serviceTargetMap {
@@ -705,6 +728,29 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 		})
 	})
 
+	// Dev Center components are only registered when the devcenter platform is enabled
+	container.MustRegisterSingleton(func(
+		serviceLocator ioc.ServiceLocator,
+		lazyPlatformConfig *lazy.Lazy[*platform.Config],
+	) *lazy.Lazy[devcenter.Manager] {
+		return lazy.NewLazy(func() (devcenter.Manager, error) {
+			platformConfig, err := lazyPlatformConfig.GetValue()
+			if err != nil || platformConfig.Type != devcenter.PlatformKindDevCenter {
+				return nil, fmt.Errorf(
+					"this command requires the devcenter platform. Run %s to enable it",
+					output.WithBackticks("azd config set platform.type devcenter"),
+				)
+			}
+
+			var manager devcenter.Manager
+			if err := serviceLocator.Resolve(&manager); err != nil {
+				return nil, err
+			}
+
+			return manager, nil
+		})
+	})
+
 	// Platform Providers
 	platformProviderMap := map[platform.PlatformKind]any{
 		azd.PlatformKindDefault:         azd.NewDefaultPlatform,
@@ -76,6 +76,8 @@ func envActions(root *actions.ActionDescriptor) *actions.ActionDescriptor {
 		DefaultFormat:  output.EnvVarsFormat,
 	})
 
+	envDevCenterActions(group)
+
 	return group
 }
 
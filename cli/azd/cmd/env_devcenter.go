@@ -0,0 +1,610 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/devcenter"
+	"github.com/azure/azure-dev/cli/azd/pkg/devcentersdk"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/lazy"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func envDevCenterActions(env *actions.ActionDescriptor) *actions.ActionDescriptor {
+	group := env.Add("devcenter", &actions.ActionDescriptorOptions{
+		Command: &cobra.Command{
+			Short: "Manage the lifecycle of Azure Deployment Environments.",
+		},
+	})
+
+	group.Add("show", &actions.ActionDescriptorOptions{
+		Command:        newEnvDevCenterShowCmd(),
+		FlagsResolver:  newEnvDevCenterFlags,
+		ActionResolver: newEnvDevCenterShowAction,
+		OutputFormats:  []output.Format{output.JsonFormat, output.NoneFormat},
+		DefaultFormat:  output.NoneFormat,
+	})
+
+	group.Add("history", &actions.ActionDescriptorOptions{
+		Command:        newEnvDevCenterHistoryCmd(),
+		FlagsResolver:  newEnvDevCenterFlags,
+		ActionResolver: newEnvDevCenterHistoryAction,
+		OutputFormats:  []output.Format{output.JsonFormat, output.TableFormat},
+		DefaultFormat:  output.TableFormat,
+	})
+
+	group.Add("extend", &actions.ActionDescriptorOptions{
+		Command:        newEnvDevCenterExtendCmd(),
+		FlagsResolver:  newEnvDevCenterExtendFlags,
+		ActionResolver: newEnvDevCenterExtendAction,
+	})
+
+	group.Add("expire", &actions.ActionDescriptorOptions{
+		Command:        newEnvDevCenterExpireCmd(),
+		FlagsResolver:  newEnvDevCenterExpireFlags,
+		ActionResolver: newEnvDevCenterExpireAction,
+	})
+
+	group.Add("redeploy", &actions.ActionDescriptorOptions{
+		Command:        newEnvDevCenterRedeployCmd(),
+		FlagsResolver:  newEnvDevCenterRedeployFlags,
+		ActionResolver: newEnvDevCenterRedeployAction,
+	})
+
+	return group
+}
+
+type envDevCenterFlags struct {
+	internal.EnvFlag
+	global *internal.GlobalCommandOptions
+}
+
+func (f *envDevCenterFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	f.EnvFlag.Bind(local, global)
+	f.global = global
+}
+
+func newEnvDevCenterFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *envDevCenterFlags {
+	flags := &envDevCenterFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newEnvDevCenterShowCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show",
+		Short: "Show the owner, state, expiration and cost of a dev center environment.",
+		Args:  cobra.NoArgs,
+	}
+}
+
+// envDevCenterShowResult is the output of `azd env devcenter show`
+type envDevCenterShowResult struct {
+	Name                  string     `json:"name"`
+	Owner                 string     `json:"owner"`
+	EnvironmentType       string     `json:"environmentType"`
+	Catalog               string     `json:"catalog"`
+	EnvironmentDefinition string     `json:"environmentDefinition"`
+	ProvisioningState     string     `json:"provisioningState"`
+	ResourceGroupId       string     `json:"resourceGroupId"`
+	ExpirationDate        *time.Time `json:"expirationDate,omitempty"`
+	MonthToDateCost       *float64   `json:"monthToDateCost,omitempty"`
+	Currency              string     `json:"currency,omitempty"`
+}
+
+type envDevCenterShowAction struct {
+	lazyManager *lazy.Lazy[devcenter.Manager]
+	env         *environment.Environment
+	console     input.Console
+	formatter   output.Formatter
+	writer      io.Writer
+}
+
+func newEnvDevCenterShowAction(
+	lazyManager *lazy.Lazy[devcenter.Manager],
+	env *environment.Environment,
+	console input.Console,
+	formatter output.Formatter,
+	writer io.Writer,
+) actions.Action {
+	return &envDevCenterShowAction{
+		lazyManager: lazyManager,
+		env:         env,
+		console:     console,
+		formatter:   formatter,
+		writer:      writer,
+	}
+}
+
+func (a *envDevCenterShowAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	manager, err := a.lazyManager.GetValue()
+	if err != nil {
+		return nil, err
+	}
+
+	devCenterEnv, err := manager.Environment(ctx, a.env.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	result := envDevCenterShowResult{
+		Name:                  devCenterEnv.Name,
+		Owner:                 devCenterEnv.User,
+		EnvironmentType:       devCenterEnv.EnvironmentType,
+		Catalog:               devCenterEnv.CatalogName,
+		EnvironmentDefinition: devCenterEnv.EnvironmentDefinitionName,
+		ProvisioningState:     string(devCenterEnv.ProvisioningState),
+		ResourceGroupId:       devCenterEnv.ResourceGroupId,
+		ExpirationDate:        devCenterEnv.ExpirationDate,
+	}
+
+	// Cost data requires access to the Cost Management API of the environment subscription, which isn't
+	// granted to every dev center user. The rest of the details are still useful without it.
+	cost, err := manager.Cost(ctx, devCenterEnv)
+	if err != nil {
+		a.console.Message(ctx, output.WithWarningFormat("WARNING: unable to retrieve environment cost: %v", err))
+	} else {
+		result.MonthToDateCost = &cost.Amount
+		result.Currency = cost.Currency
+	}
+
+	if a.formatter.Kind() == output.JsonFormat {
+		return nil, a.formatter.Format(result, a.writer, nil)
+	}
+
+	expiration := "Never"
+	if result.ExpirationDate != nil {
+		expiration = result.ExpirationDate.Local().Format(time.RFC1123)
+	}
+
+	costText := "Unavailable"
+	if result.MonthToDateCost != nil {
+		costText = fmt.Sprintf("%.2f %s", *result.MonthToDateCost, result.Currency)
+	}
+
+	lines := []struct {
+		label string
+		value string
+	}{
+		{"Name", result.Name},
+		{"Owner", result.Owner},
+		{"Environment type", result.EnvironmentType},
+		{"Definition", fmt.Sprintf("%s/%s", result.Catalog, result.EnvironmentDefinition)},
+		{"State", result.ProvisioningState},
+		{"Resource group", result.ResourceGroupId},
+		{"Expires", expiration},
+		{"Cost (month to date)", costText},
+	}
+
+	for _, line := range lines {
+		a.console.Message(ctx, fmt.Sprintf("%-22s %s", line.label+":", output.WithHighLightFormat(line.value)))
+	}
+
+	return nil, nil
+}
+
+func newEnvDevCenterHistoryCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "history",
+		Short: "List the deploy and delete operations of a dev center environment.",
+		Args:  cobra.NoArgs,
+	}
+}
+
+type envDevCenterHistoryAction struct {
+	lazyManager *lazy.Lazy[devcenter.Manager]
+	env         *environment.Environment
+	formatter   output.Formatter
+	writer      io.Writer
+}
+
+func newEnvDevCenterHistoryAction(
+	lazyManager *lazy.Lazy[devcenter.Manager],
+	env *environment.Environment,
+	formatter output.Formatter,
+	writer io.Writer,
+) actions.Action {
+	return &envDevCenterHistoryAction{
+		lazyManager: lazyManager,
+		env:         env,
+		formatter:   formatter,
+		writer:      writer,
+	}
+}
+
+func (a *envDevCenterHistoryAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	manager, err := a.lazyManager.GetValue()
+	if err != nil {
+		return nil, err
+	}
+
+	devCenterEnv, err := manager.Environment(ctx, a.env.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	operations, err := manager.Operations(ctx, devCenterEnv)
+	if err != nil {
+		return nil, err
+	}
+
+	if a.formatter.Kind() == output.TableFormat {
+		columns := []output.Column{
+			{
+				Heading:       "STARTED",
+				ValueTemplate: `{{if .StartTime}}{{.StartTime.Format "2006-01-02 15:04:05Z07:00"}}{{end}}`,
+			},
+			{
+				Heading:       "KIND",
+				ValueTemplate: "{{.Kind}}",
+			},
+			{
+				Heading:       "STATUS",
+				ValueTemplate: "{{.Status}}",
+			},
+			{
+				Heading:       "CREATED BY",
+				ValueTemplate: "{{.CreatedByObjectId}}",
+			},
+			{
+				Heading:       "ERROR",
+				ValueTemplate: "{{if .Error}}{{.Error.Code}}{{end}}",
+			},
+		}
+
+		return nil, a.formatter.Format(operations, a.writer, output.TableFormatterOptions{
+			Columns: columns,
+		})
+	}
+
+	return nil, a.formatter.Format(operations, a.writer, nil)
+}
+
+type envDevCenterExtendFlags struct {
+	envDevCenterFlags
+	by string
+}
+
+func (f *envDevCenterExtendFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	f.envDevCenterFlags.Bind(local, global)
+	local.StringVar(
+		&f.by,
+		"by",
+		"",
+		"The time to add to the expiration date, for example 8h or 2d. "+
+			"Environments without an expiration are extended from now.",
+	)
+}
+
+func newEnvDevCenterExtendFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *envDevCenterExtendFlags {
+	flags := &envDevCenterExtendFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newEnvDevCenterExtendCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "extend",
+		Short: "Extend the expiration date of a dev center environment.",
+		Args:  cobra.NoArgs,
+	}
+}
+
+type envDevCenterExtendAction struct {
+	lazyManager *lazy.Lazy[devcenter.Manager]
+	env         *environment.Environment
+	flags       *envDevCenterExtendFlags
+}
+
+func newEnvDevCenterExtendAction(
+	lazyManager *lazy.Lazy[devcenter.Manager],
+	env *environment.Environment,
+	flags *envDevCenterExtendFlags,
+) actions.Action {
+	return &envDevCenterExtendAction{
+		lazyManager: lazyManager,
+		env:         env,
+		flags:       flags,
+	}
+}
+
+func (a *envDevCenterExtendAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	if a.flags.by == "" {
+		return nil, errors.New("'--by' is required")
+	}
+
+	duration, err := parseExpirationDuration(a.flags.by)
+	if err != nil {
+		return nil, err
+	}
+
+	manager, err := a.lazyManager.GetValue()
+	if err != nil {
+		return nil, err
+	}
+
+	devCenterEnv, err := manager.Environment(ctx, a.env.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	from := time.Now()
+	if devCenterEnv.ExpirationDate != nil && devCenterEnv.ExpirationDate.After(from) {
+		from = *devCenterEnv.ExpirationDate
+	}
+
+	expirationDate := from.Add(duration).UTC()
+	if _, err := manager.SetExpiration(ctx, devCenterEnv, &expirationDate); err != nil {
+		return nil, err
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf(
+				"Environment %s now expires on %s.",
+				output.WithHighLightFormat(devCenterEnv.Name),
+				expirationDate.Local().Format(time.RFC1123),
+			),
+		},
+	}, nil
+}
+
+type envDevCenterExpireFlags struct {
+	envDevCenterFlags
+	at    string
+	never bool
+}
+
+func (f *envDevCenterExpireFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	f.envDevCenterFlags.Bind(local, global)
+	local.StringVar(
+		&f.at,
+		"at",
+		"",
+		"The date the environment is deleted, as an RFC 3339 timestamp or a YYYY-MM-DD date.",
+	)
+	local.BoolVar(&f.never, "never", false, "Removes the expiration date of the environment.")
+}
+
+func newEnvDevCenterExpireFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *envDevCenterExpireFlags {
+	flags := &envDevCenterExpireFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newEnvDevCenterExpireCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "expire",
+		Short: "Set or remove the expiration date of a dev center environment.",
+		Args:  cobra.NoArgs,
+	}
+}
+
+type envDevCenterExpireAction struct {
+	lazyManager *lazy.Lazy[devcenter.Manager]
+	env         *environment.Environment
+	flags       *envDevCenterExpireFlags
+}
+
+func newEnvDevCenterExpireAction(
+	lazyManager *lazy.Lazy[devcenter.Manager],
+	env *environment.Environment,
+	flags *envDevCenterExpireFlags,
+) actions.Action {
+	return &envDevCenterExpireAction{
+		lazyManager: lazyManager,
+		env:         env,
+		flags:       flags,
+	}
+}
+
+func (a *envDevCenterExpireAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	if (a.flags.at == "") == !a.flags.never {
+		return nil, errors.New("exactly one of '--at' or '--never' must be specified")
+	}
+
+	var expirationDate *time.Time
+	if a.flags.at != "" {
+		value, err := parseExpirationDate(a.flags.at)
+		if err != nil {
+			return nil, err
+		}
+		expirationDate = &value
+	}
+
+	manager, err := a.lazyManager.GetValue()
+	if err != nil {
+		return nil, err
+	}
+
+	devCenterEnv, err := manager.Environment(ctx, a.env.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := manager.SetExpiration(ctx, devCenterEnv, expirationDate); err != nil {
+		return nil, err
+	}
+
+	header := fmt.Sprintf("Environment %s no longer expires.", output.WithHighLightFormat(devCenterEnv.Name))
+	if expirationDate != nil {
+		header = fmt.Sprintf(
+			"Environment %s now expires on %s.",
+			output.WithHighLightFormat(devCenterEnv.Name),
+			expirationDate.Local().Format(time.RFC1123),
+		)
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: header,
+		},
+	}, nil
+}
+
+type envDevCenterRedeployFlags struct {
+	envDevCenterFlags
+	parameters []string
+}
+
+func (f *envDevCenterRedeployFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	f.envDevCenterFlags.Bind(local, global)
+	local.StringArrayVarP(
+		&f.parameters,
+		"parameter",
+		"p",
+		nil,
+		"A parameter value to change, in the form <name>=<value>. Can be specified multiple times.",
+	)
+}
+
+func newEnvDevCenterRedeployFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *envDevCenterRedeployFlags {
+	flags := &envDevCenterRedeployFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newEnvDevCenterRedeployCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "redeploy",
+		Short: "Redeploy a dev center environment, optionally with new parameter values.",
+		Args:  cobra.NoArgs,
+	}
+}
+
+type envDevCenterRedeployAction struct {
+	lazyManager *lazy.Lazy[devcenter.Manager]
+	env         *environment.Environment
+	envManager  environment.Manager
+	console     input.Console
+	flags       *envDevCenterRedeployFlags
+}
+
+func newEnvDevCenterRedeployAction(
+	lazyManager *lazy.Lazy[devcenter.Manager],
+	env *environment.Environment,
+	envManager environment.Manager,
+	console input.Console,
+	flags *envDevCenterRedeployFlags,
+) actions.Action {
+	return &envDevCenterRedeployAction{
+		lazyManager: lazyManager,
+		env:         env,
+		envManager:  envManager,
+		console:     console,
+		flags:       flags,
+	}
+}
+
+func (a *envDevCenterRedeployAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	overrides := map[string]string{}
+	for _, parameter := range a.flags.parameters {
+		name, value, has := strings.Cut(parameter, "=")
+		if !has || name == "" {
+			return nil, fmt.Errorf("invalid parameter '%s'. Parameters must be in the form <name>=<value>", parameter)
+		}
+		overrides[name] = value
+	}
+
+	manager, err := a.lazyManager.GetValue()
+	if err != nil {
+		return nil, err
+	}
+
+	devCenterEnv, err := manager.Environment(ctx, a.env.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	if devCenterEnv.ProvisioningState != devcentersdk.ProvisioningStateSucceeded &&
+		devCenterEnv.ProvisioningState != devcentersdk.ProvisioningStateFailed {
+		return nil, fmt.Errorf(
+			"environment '%s' can't be redeployed while it is in the '%s' state",
+			devCenterEnv.Name,
+			devCenterEnv.ProvisioningState,
+		)
+	}
+
+	spinnerMessage := fmt.Sprintf("Redeploying devcenter environment %s", output.WithHighLightFormat(devCenterEnv.Name))
+	a.console.ShowSpinner(ctx, spinnerMessage, input.Step)
+	parameters, err := manager.Redeploy(ctx, devCenterEnv, overrides)
+	a.console.StopSpinner(ctx, spinnerMessage, input.GetStepResultFormat(err))
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep the azd environment in sync so the next `azd provision` deploys the same parameter values
+	for key := range overrides {
+		path := fmt.Sprintf("%s.%s", devcenter.ProvisionParametersConfigPath, key)
+		if err := a.env.Config.Set(path, parameters[key]); err != nil {
+			return nil, fmt.Errorf("failed setting config value %s: %w", path, err)
+		}
+	}
+
+	if len(overrides) > 0 {
+		if err := a.envManager.Save(ctx, a.env); err != nil {
+			return nil, fmt.Errorf("saving environment: %w", err)
+		}
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("Environment %s was redeployed.", output.WithHighLightFormat(devCenterEnv.Name)),
+			FollowUp: fmt.Sprintf(
+				"Run %s to update the environment outputs.", output.WithHighLightFormat("azd env refresh")),
+		},
+	}, nil
+}
+
+// parseExpirationDuration parses a duration which, in addition to the units supported by time.ParseDuration,
+// supports days, e.g. `2d`.
+func parseExpirationDuration(value string) (time.Duration, error) {
+	var duration time.Duration
+	var err error
+	if days, has := strings.CutSuffix(value, "d"); has {
+		var count int
+		count, err = strconv.Atoi(days)
+		duration = time.Duration(count) * 24 * time.Hour
+	} else {
+		duration, err = time.ParseDuration(value)
+	}
+
+	if err != nil || duration <= 0 {
+		return 0, fmt.Errorf("invalid duration '%s'. Use a positive duration like 8h or 2d", value)
+	}
+
+	return duration, nil
+}
+
+// parseExpirationDate parses either an RFC 3339 timestamp or a date, which is interpreted as midnight local time.
+func parseExpirationDate(value string) (time.Time, error) {
+	if date, err := time.Parse(time.RFC3339, value); err == nil {
+		return date.UTC(), nil
+	}
+
+	date, err := time.ParseInLocation(time.DateOnly, value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf(
+			"invalid expiration date '%s'. Use an RFC 3339 timestamp or a YYYY-MM-DD date", value)
+	}
+
+	return date.UTC(), nil
+}
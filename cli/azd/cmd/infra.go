@@ -48,5 +48,7 @@ func infraActions(root *actions.ActionDescriptor) *actions.ActionDescriptor {
 			DefaultFormat:  output.NoneFormat,
 		})
 
+	infraStateActions(group)
+
 	return group
 }
@@ -0,0 +1,108 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func infraStateActions(infra *actions.ActionDescriptor) *actions.ActionDescriptor {
+	group := infra.Add("state", &actions.ActionDescriptorOptions{
+		Command: &cobra.Command{
+			Short: "Manage the deployment state of your Azure infrastructure.",
+		},
+	})
+
+	group.Add("migrate", &actions.ActionDescriptorOptions{
+		Command:        newInfraStateMigrateCmd(),
+		FlagsResolver:  newInfraStateMigrateFlags,
+		ActionResolver: newInfraStateMigrateAction,
+		OutputFormats:  []output.Format{output.NoneFormat},
+		DefaultFormat:  output.NoneFormat,
+	})
+
+	return group
+}
+
+type infraStateMigrateFlags struct {
+	global *internal.GlobalCommandOptions
+	internal.EnvFlag
+}
+
+func (f *infraStateMigrateFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	f.EnvFlag.Bind(local, global)
+	f.global = global
+}
+
+func newInfraStateMigrateFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *infraStateMigrateFlags {
+	flags := &infraStateMigrateFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newInfraStateMigrateCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "migrate",
+		Short: "Move the local deployment state of an environment into the configured remote backend.",
+	}
+}
+
+type infraStateMigrateAction struct {
+	provisionManager *provisioning.Manager
+	importManager    *project.ImportManager
+	projectConfig    *project.ProjectConfig
+	console          input.Console
+}
+
+func newInfraStateMigrateAction(
+	provisionManager *provisioning.Manager,
+	importManager *project.ImportManager,
+	projectConfig *project.ProjectConfig,
+	console input.Console,
+) actions.Action {
+	return &infraStateMigrateAction{
+		provisionManager: provisionManager,
+		importManager:    importManager,
+		projectConfig:    projectConfig,
+		console:          console,
+	}
+}
+
+func (a *infraStateMigrateAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	a.console.MessageUxItem(ctx, &ux.MessageTitle{
+		Title: "Migrating local deployment state to the remote backend (azd infra state migrate)",
+	})
+
+	infra, err := a.importManager.ProjectInfrastructure(ctx, a.projectConfig)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = infra.Cleanup() }()
+
+	if err := a.provisionManager.Initialize(ctx, a.projectConfig.Path, infra.Options); err != nil {
+		return nil, fmt.Errorf("initializing provisioning manager: %w", err)
+	}
+
+	if err := a.provisionManager.MigrateState(ctx); err != nil {
+		return nil, err
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: "Your deployment state was migrated to the remote backend.",
+		},
+	}, nil
+}
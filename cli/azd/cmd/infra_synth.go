@@ -12,6 +12,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/internal"
 	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
@@ -25,7 +26,8 @@ import (
 type infraSynthFlags struct {
 	global *internal.GlobalCommandOptions
 	*internal.EnvFlag
-	force bool
+	force    bool
+	provider string
 }
 
 func newInfraSynthFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *infraSynthFlags {
@@ -40,7 +42,16 @@ func newInfraSynthFlags(cmd *cobra.Command, global *internal.GlobalCommandOption
 func (f *infraSynthFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
 	f.global = global
 	f.EnvFlag.Bind(local, global)
-	local.BoolVar(&f.force, "force", false, "Overwrite any existing files without prompting")
+	local.BoolVar(
+		&f.force,
+		"force",
+		false,
+		"Overwrite any existing files and update the provider of the project without prompting")
+	local.StringVar(
+		&f.provider,
+		"provider",
+		"",
+		"The infrastructure as code provider to synthesize: bicep or terraform. Defaults to the provider of the project.")
 }
 
 func newInfraSynthCmd() *cobra.Command {
@@ -90,10 +101,19 @@ func (a *infraSynthAction) Run(ctx context.Context) (*actions.ActionResult, erro
 
 	a.console.WarnForFeature(ctx, infraSynthFeature)
 
+	provider := provisioning.ProviderKind(a.flags.provider)
+	if provider == provisioning.NotSpecified {
+		provider = a.projectConfig.Infra.Provider
+	}
+
+	if provider != provisioning.NotSpecified && provider != provisioning.Bicep && provider != provisioning.Terraform {
+		return nil, fmt.Errorf("unsupported provider '%s', expected bicep or terraform", provider)
+	}
+
 	spinnerMessage := "Synthesizing infrastructure"
 
 	a.console.ShowSpinner(ctx, spinnerMessage, input.Step)
-	synthFS, err := a.importManager.SynthAllInfrastructure(ctx, a.projectConfig)
+	synthFS, err := a.importManager.SynthAllInfrastructure(ctx, a.projectConfig, provider)
 	if err != nil {
 		a.console.StopSpinner(ctx, spinnerMessage, input.StepFailed)
 		return nil, err
@@ -156,9 +176,70 @@ func (a *infraSynthAction) Run(ctx context.Context) (*actions.ActionResult, erro
 		return nil, fmt.Errorf("copying contents from temp staging directory: %w", err)
 	}
 
+	if err := a.updateProvider(ctx, provider); err != nil {
+		return nil, err
+	}
+
 	return nil, nil
 }
 
+// updateProvider sets the provider of the project to terraform, since the synthesized terraform files are only used when
+// the project is configured with the terraform provider. The provider is only set with the consent of the user, and
+// never when the project is explicitly configured with another provider.
+func (a *infraSynthAction) updateProvider(ctx context.Context, provider provisioning.ProviderKind) error {
+	if provider != provisioning.Terraform || a.projectConfig.Infra.Provider == provisioning.Terraform {
+		return nil
+	}
+
+	instructions := fmt.Sprintf(
+		"To provision with the synthesized files, set 'infra.provider' to 'terraform' in %s.", azdcontext.ProjectFileName)
+
+	if a.projectConfig.Infra.Provider != provisioning.NotSpecified {
+		a.console.MessageUxItem(ctx, &ux.WarningMessage{
+			Description: fmt.Sprintf(
+				"The project is configured with the '%s' provider, which doesn't use the synthesized terraform files. %s",
+				a.projectConfig.Infra.Provider,
+				instructions,
+			),
+		})
+		return nil
+	}
+
+	if !a.flags.force {
+		confirm, err := a.console.Confirm(ctx, input.ConsoleOptions{
+			Message: fmt.Sprintf(
+				"Set 'infra.provider' to 'terraform' in %s to provision with the synthesized files?",
+				azdcontext.ProjectFileName,
+			),
+			DefaultValue: true,
+		})
+		if err != nil {
+			return fmt.Errorf("prompting to update the provider: %w", err)
+		}
+
+		if !confirm {
+			a.console.Message(ctx, instructions)
+			return nil
+		}
+	}
+
+	projectConfig, err := project.LoadConfig(ctx, a.azdCtx.ProjectPath())
+	if err != nil {
+		return err
+	}
+
+	if err := projectConfig.Set("infra.provider", string(provisioning.Terraform)); err != nil {
+		return err
+	}
+
+	if err := project.SaveConfig(ctx, projectConfig, a.azdCtx.ProjectPath()); err != nil {
+		return fmt.Errorf("updating %s: %w", azdcontext.ProjectFileName, err)
+	}
+
+	a.console.Message(ctx, fmt.Sprintf("Set 'infra.provider' to 'terraform' in %s.", azdcontext.ProjectFileName))
+	return nil
+}
+
 func (a *infraSynthAction) promptForDuplicates(
 	ctx context.Context, staging string, target string) (skipSourceFiles map[string]struct{}, err error) {
 	log.Printf(
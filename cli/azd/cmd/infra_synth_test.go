@@ -0,0 +1,109 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockinput"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_InfraSynth_UpdateProvider(t *testing.T) {
+	tests := []struct {
+		name             string
+		projectProvider  provisioning.ProviderKind
+		synthProvider    provisioning.ProviderKind
+		force            bool
+		confirm          *bool
+		expectedProvider string
+		expectedOutput   string
+	}{
+		{
+			name:             "ConfirmedUpdate",
+			synthProvider:    provisioning.Terraform,
+			confirm:          to.Ptr(true),
+			expectedProvider: "terraform",
+			expectedOutput:   "Set 'infra.provider' to 'terraform' in azure.yaml.",
+		},
+		{
+			name:           "DeclinedUpdate",
+			synthProvider:  provisioning.Terraform,
+			confirm:        to.Ptr(false),
+			expectedOutput: "To provision with the synthesized files, set 'infra.provider' to 'terraform'",
+		},
+		{
+			name:             "ForcedUpdate",
+			synthProvider:    provisioning.Terraform,
+			force:            true,
+			expectedProvider: "terraform",
+			expectedOutput:   "Set 'infra.provider' to 'terraform' in azure.yaml.",
+		},
+		{
+			name:             "ExplicitProviderKept",
+			projectProvider:  provisioning.Bicep,
+			synthProvider:    provisioning.Terraform,
+			force:            true,
+			expectedProvider: "bicep",
+			expectedOutput:   "The project is configured with the 'bicep' provider",
+		},
+		{
+			name:          "Bicep",
+			synthProvider: provisioning.Bicep,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			azdCtx := azdcontext.NewAzdContextWithDirectory(t.TempDir())
+			projectYaml := "name: app\n"
+			if tt.projectProvider != provisioning.NotSpecified {
+				projectYaml += "infra:\n  provider: " + string(tt.projectProvider) + "\n"
+			}
+			err := os.WriteFile(azdCtx.ProjectPath(), []byte(projectYaml), osutil.PermissionFile)
+			require.NoError(t, err)
+
+			projectConfig, err := project.Load(context.Background(), azdCtx.ProjectPath())
+			require.NoError(t, err)
+
+			console := mockinput.NewMockConsole()
+			if tt.confirm != nil {
+				console.WhenConfirm(func(options input.ConsoleOptions) bool {
+					return strings.Contains(options.Message, "Set 'infra.provider' to 'terraform'")
+				}).Respond(*tt.confirm)
+			}
+
+			action := &infraSynthAction{
+				projectConfig: projectConfig,
+				console:       console,
+				azdCtx:        azdCtx,
+				flags:         &infraSynthFlags{force: tt.force},
+			}
+
+			err = action.updateProvider(context.Background(), tt.synthProvider)
+			require.NoError(t, err)
+
+			savedConfig, err := project.LoadConfig(context.Background(), azdCtx.ProjectPath())
+			require.NoError(t, err)
+			provider, _ := savedConfig.GetString("infra.provider")
+			require.Equal(t, tt.expectedProvider, provider)
+
+			output := strings.Join(console.Output(), "\n")
+			if tt.expectedOutput == "" {
+				require.Empty(t, output)
+			} else {
+				require.Contains(t, output, tt.expectedOutput)
+			}
+		})
+	}
+}
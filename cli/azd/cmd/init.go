@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/MakeNowJust/heredoc/v2"
 	"github.com/azure/azure-dev/cli/azd/cmd/actions"
@@ -16,14 +17,18 @@ import (
 	"github.com/azure/azure-dev/cli/azd/internal/repository"
 	"github.com/azure/azure-dev/cli/azd/internal/tracing"
 	"github.com/azure/azure-dev/cli/azd/internal/tracing/fields"
+	"github.com/azure/azure-dev/cli/azd/pkg/account"
 	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
+	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/lazy"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/prompt"
 	"github.com/azure/azure-dev/cli/azd/pkg/templates"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
@@ -48,12 +53,15 @@ func newInitCmd() *cobra.Command {
 }
 
 type initFlags struct {
-	templatePath   string
-	templateBranch string
-	subscription   string
-	location       string
-	global         *internal.GlobalCommandOptions
-	fromCode       bool
+	templatePath      string
+	templateBranch    string
+	subscription      string
+	location          string
+	global            *internal.GlobalCommandOptions
+	fromCode          bool
+	fromResourceGroup string
+	provider          string
+	templateValues    []string
 	internal.EnvFlag
 }
 
@@ -86,7 +94,25 @@ func (i *initFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOpt
 		false,
 		"Initializes a new application from your existing code.",
 	)
+	local.StringVar(
+		&i.fromResourceGroup,
+		"from-resource-group",
+		"",
+		"Initializes a new application from the resources of an existing Azure resource group.",
+	)
+	local.StringVar(
+		&i.provider,
+		"provider",
+		"",
+		"The infrastructure as code provider of the files generated from your existing code: bicep or terraform.",
+	)
 	local.StringVarP(&i.location, "location", "l", "", "Azure location for the new environment")
+	local.StringArrayVar(
+		&i.templateValues,
+		"set",
+		nil,
+		"Sets the value of a template parameter, in the form <name>=<value>. Can be specified multiple times.",
+	)
 	i.EnvFlag.Bind(local, global)
 
 	i.global = global
@@ -102,6 +128,9 @@ type initAction struct {
 	repoInitializer *repository.Initializer
 	templateManager *templates.TemplateManager
 	featuresManager *alpha.FeatureManager
+	accountManager  account.Manager
+	azCli           azcli.AzCli
+	portalUrlBase   cloud.PortalUrlBase
 }
 
 func newInitAction(
@@ -113,7 +142,10 @@ func newInitAction(
 	flags *initFlags,
 	repoInitializer *repository.Initializer,
 	templateManager *templates.TemplateManager,
-	featuresManager *alpha.FeatureManager) actions.Action {
+	featuresManager *alpha.FeatureManager,
+	accountManager account.Manager,
+	azCli azcli.AzCli,
+	portalUrlBase cloud.PortalUrlBase) actions.Action {
 	return &initAction{
 		lazyAzdCtx:      lazyAzdCtx,
 		lazyEnvManager:  lazyEnvManager,
@@ -124,6 +156,9 @@ func newInitAction(
 		repoInitializer: repoInitializer,
 		templateManager: templateManager,
 		featuresManager: featuresManager,
+		accountManager:  accountManager,
+		azCli:           azCli,
+		portalUrlBase:   portalUrlBase,
 	}
 }
 
@@ -169,12 +204,25 @@ func (i *initAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 
 	if i.flags.fromCode {
 		if i.flags.templatePath != "" {
-			return nil, errors.New("only one of init modes: --template, or --from-code should be set")
+			return nil, errors.New("only one of init modes: --template, --from-code or --from-resource-group should be set")
 		}
 		initTypeSelect = initFromApp
 	}
 
-	if i.flags.templatePath == "" && !i.flags.fromCode && existingProject {
+	if i.flags.fromResourceGroup != "" {
+		if i.flags.templatePath != "" || i.flags.fromCode {
+			return nil, errors.New("only one of init modes: --template, --from-code or --from-resource-group should be set")
+		}
+		initTypeSelect = initFromResourceGroup
+	}
+
+	infraProvider := provisioning.ProviderKind(i.flags.provider)
+	if infraProvider != provisioning.NotSpecified &&
+		infraProvider != provisioning.Bicep && infraProvider != provisioning.Terraform {
+		return nil, fmt.Errorf("unsupported --provider '%s', expected bicep or terraform", i.flags.provider)
+	}
+
+	if i.flags.templatePath == "" && !i.flags.fromCode && i.flags.fromResourceGroup == "" && existingProject {
 		// only initialize environment when no mode is set explicitly
 		initTypeSelect = initEnvironment
 	}
@@ -186,6 +234,10 @@ func (i *initAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 		}
 	}
 
+	if infraProvider != provisioning.NotSpecified && initTypeSelect != initFromApp {
+		return nil, errors.New("--provider can only be used when initializing from existing code")
+	}
+
 	header := "New project initialized!"
 	followUp := heredoc.Docf(`
 	You can view the template code in your directory: %s
@@ -231,12 +283,40 @@ func (i *initAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 			}
 		}
 
-		err = i.repoInitializer.InitFromApp(ctx, azdCtx, func() (*environment.Environment, error) {
+		err = i.repoInitializer.InitFromApp(ctx, azdCtx, infraProvider, func() (*environment.Environment, error) {
 			return i.initializeEnv(ctx, azdCtx, nil)
 		})
 		if err != nil {
 			return nil, err
 		}
+	case initFromResourceGroup:
+		tracing.SetUsageAttributes(fields.InitMethod.String("resource-group"))
+
+		header = "Your app is ready to deploy to your existing resources!"
+		followUp = "You can deploy your app to the resources of " +
+			output.WithHighLightFormat(i.flags.fromResourceGroup) + " by running the " + color.BlueString("azd deploy") +
+			" command in this directory. Review " + output.WithHighLightFormat("./infra/main.bicep") +
+			" before running " + color.BlueString("azd provision") + "."
+
+		env, err := i.initializeEnv(ctx, azdCtx, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		if env.GetSubscriptionId() == "" {
+			prompter := prompt.NewDefaultPrompter(env, i.console, i.accountManager, i.azCli, i.portalUrlBase)
+			subscriptionId, err := prompter.PromptSubscription(ctx, "Select an Azure Subscription to use:")
+			if err != nil {
+				return nil, err
+			}
+
+			env.SetSubscriptionId(subscriptionId)
+		}
+
+		err = i.repoInitializer.InitFromResourceGroup(ctx, azdCtx, env, i.flags.fromResourceGroup)
+		if err != nil {
+			return nil, err
+		}
 	case initEnvironment:
 		_, err = i.initializeEnv(ctx, azdCtx, nil)
 		if err != nil {
@@ -261,6 +341,7 @@ const (
 	initFromApp
 	initAppTemplate
 	initEnvironment
+	initFromResourceGroup
 )
 
 func promptInitType(console input.Console, ctx context.Context) (initType, error) {
@@ -313,7 +394,12 @@ func (i *initAction) initializeTemplate(
 			}
 		}
 
-		err = i.repoInitializer.Initialize(ctx, azdCtx, template, i.flags.templateBranch)
+		templateValues, err := parseTemplateValues(i.flags.templateValues)
+		if err != nil {
+			return nil, err
+		}
+
+		err = i.repoInitializer.Initialize(ctx, azdCtx, template, i.flags.templateBranch, templateValues)
 		if err != nil {
 			return nil, fmt.Errorf("init from template repository: %w", err)
 		}
@@ -421,5 +507,24 @@ func getCmdInitHelpFooter(*cobra.Command) string {
 			output.WithHighLightFormat("--branch"),
 			output.WithWarningFormat("[Branch name]"),
 		),
+		"Initialize an app from the resources of an existing Azure resource group.": fmt.Sprintf("%s %s",
+			output.WithHighLightFormat("azd init --from-resource-group"),
+			output.WithWarningFormat("[Resource group name]"),
+		),
 	})
 }
+
+// parseTemplateValues parses the `--set <name>=<value>` assignments of template parameters.
+func parseTemplateValues(assignments []string) (map[string]string, error) {
+	values := map[string]string{}
+	for _, assignment := range assignments {
+		name, value, has := strings.Cut(assignment, "=")
+		if !has || name == "" {
+			return nil, fmt.Errorf("invalid value '%s' for '--set'. Values must be in the form <name>=<value>", assignment)
+		}
+
+		values[name] = value
+	}
+
+	return values, nil
+}
@@ -0,0 +1,292 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+type logsFlags struct {
+	follow bool
+	since  time.Duration
+	system bool
+	global *internal.GlobalCommandOptions
+	internal.EnvFlag
+}
+
+func (l *logsFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.BoolVarP(&l.follow, "follow", "f", false, "Keep streaming new logs.")
+	local.DurationVar(
+		&l.since,
+		"since",
+		0,
+		"Only show logs newer than a relative duration like 5s, 2m or 3h. Ignored by App Service, Function Apps and "+
+			"Container Apps.",
+	)
+	local.BoolVar(
+		&l.system,
+		"system",
+		false,
+		"Show the system logs of Container Apps instead of the console logs of their containers.",
+	)
+	l.EnvFlag.Bind(local, global)
+	l.global = global
+}
+
+func newLogsFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *logsFlags {
+	flags := &logsFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newLogsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "logs <service>",
+		Short: fmt.Sprintf("Stream the logs of a deployed application. %s", output.WithWarningFormat("(Beta)")),
+		Args:  cobra.MaximumNArgs(1),
+	}
+}
+
+type logsAction struct {
+	flags          *logsFlags
+	args           []string
+	console        input.Console
+	formatter      output.Formatter
+	writer         io.Writer
+	projectConfig  *project.ProjectConfig
+	projectManager project.ProjectManager
+	importManager  *project.ImportManager
+	serviceManager project.ServiceManager
+}
+
+func newLogsAction(
+	flags *logsFlags,
+	args []string,
+	console input.Console,
+	formatter output.Formatter,
+	writer io.Writer,
+	projectConfig *project.ProjectConfig,
+	projectManager project.ProjectManager,
+	importManager *project.ImportManager,
+	serviceManager project.ServiceManager,
+) actions.Action {
+	return &logsAction{
+		flags:          flags,
+		args:           args,
+		console:        console,
+		formatter:      formatter,
+		writer:         writer,
+		projectConfig:  projectConfig,
+		projectManager: projectManager,
+		importManager:  importManager,
+		serviceManager: serviceManager,
+	}
+}
+
+func (la *logsAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	targetServiceName := ""
+	if len(la.args) == 1 {
+		targetServiceName = la.args[0]
+	}
+
+	// The logs of all services are streamed when a service isn't specified
+	targetServiceName, err := getTargetServiceName(
+		ctx,
+		la.projectManager,
+		la.importManager,
+		la.projectConfig,
+		"logs",
+		targetServiceName,
+		targetServiceName == "",
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	stableServices, err := la.importManager.ServiceStable(ctx, la.projectConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	services := []*project.ServiceConfig{}
+	for _, svc := range stableServices {
+		if targetServiceName == "" || targetServiceName == svc.Name {
+			services = append(services, svc)
+		}
+	}
+
+	prefixWidth := 0
+	for _, svc := range services {
+		prefixWidth = max(prefixWidth, len(svc.Name))
+	}
+
+	options := &project.LogsOptions{
+		Follow: la.flags.follow,
+		Since:  la.flags.since,
+		System: la.flags.system,
+	}
+
+	// Every service writes whole lines to the output under the same lock, which interleaves the logs line by line
+	var writeLock sync.Mutex
+	var wg sync.WaitGroup
+	errs := make([]error, len(services))
+
+	for i, svc := range services {
+		logWriter := &serviceLogWriter{
+			serviceName: svc.Name,
+			prefix:      logPrefixColors[i%len(logPrefixColors)].Sprintf("%-*s |", prefixWidth, svc.Name),
+			jsonLines:   la.formatter.Kind() == output.JsonFormat,
+			writer:      la.writer,
+			writeLock:   &writeLock,
+		}
+
+		wg.Add(1)
+		go func(i int, svc *project.ServiceConfig) {
+			defer wg.Done()
+
+			err := la.serviceManager.Logs(ctx, svc, options, logWriter)
+			if flushErr := logWriter.Flush(); flushErr != nil && err == nil {
+				err = flushErr
+			}
+
+			// Skip the services that can't stream logs when streaming the logs of all services
+			if errors.Is(err, project.ErrLogsNotSupported) && targetServiceName == "" {
+				writeLock.Lock()
+				la.console.Message(ctx, output.WithWarningFormat("WARNING: skipping service '%s', %v", svc.Name, err))
+				writeLock.Unlock()
+
+				return
+			}
+
+			if err != nil {
+				errs[i] = fmt.Errorf("streaming logs of service '%s': %w", svc.Name, err)
+			}
+		}(i, svc)
+	}
+
+	wg.Wait()
+
+	return nil, errors.Join(errs...)
+}
+
+// The colors of the service prefixes, which are assigned to the services in order
+var logPrefixColors = []*color.Color{
+	color.New(color.FgCyan),
+	color.New(color.FgMagenta),
+	color.New(color.FgYellow),
+	color.New(color.FgGreen),
+	color.New(color.FgBlue),
+}
+
+// serviceLogLine is a log line of a service written as a JSON line
+type serviceLogLine struct {
+	Service string `json:"service"`
+	Message string `json:"message"`
+}
+
+// serviceLogWriter buffers the logs of a service and writes each complete line to the output, either prefixed with the
+// name of the service or as a JSON line.
+type serviceLogWriter struct {
+	serviceName string
+	prefix      string
+	jsonLines   bool
+	writer      io.Writer
+	writeLock   *sync.Mutex
+	buffer      []byte
+}
+
+func (w *serviceLogWriter) Write(p []byte) (int, error) {
+	w.writeLock.Lock()
+	defer w.writeLock.Unlock()
+
+	w.buffer = append(w.buffer, p...)
+	for {
+		index := bytes.IndexByte(w.buffer, '\n')
+		if index < 0 {
+			break
+		}
+
+		line := strings.TrimSuffix(string(w.buffer[:index]), "\r")
+		w.buffer = w.buffer[index+1:]
+		if err := w.writeLine(line); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(p), nil
+}
+
+// Flush writes the remaining partial line, if any
+func (w *serviceLogWriter) Flush() error {
+	w.writeLock.Lock()
+	defer w.writeLock.Unlock()
+
+	if len(w.buffer) == 0 {
+		return nil
+	}
+
+	line := string(w.buffer)
+	w.buffer = nil
+
+	return w.writeLine(line)
+}
+
+func (w *serviceLogWriter) writeLine(line string) error {
+	if w.jsonLines {
+		jsonLine, err := json.Marshal(serviceLogLine{
+			Service: w.serviceName,
+			Message: line,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintf(w.writer, "%s\n", jsonLine)
+		return err
+	}
+
+	_, err := fmt.Fprintf(w.writer, "%s %s\n", w.prefix, line)
+	return err
+}
+
+func getCmdLogsHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		fmt.Sprintf("Stream the logs of a deployed application. %s", output.WithWarningFormat("(Beta)")),
+		[]string{
+			formatHelpNote("The logs of every service are interleaved, each line prefixed with the name of its service."),
+			formatHelpNote(fmt.Sprintf(
+				"Use %s to write each line as a JSON object with the service name and the message.",
+				output.WithHighLightFormat("--output json"),
+			)),
+			formatHelpNote("App Service and Function Apps only stream live logs, which requires --follow."),
+		})
+}
+
+func getCmdLogsHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Show the logs of all services.": output.WithHighLightFormat("azd logs"),
+		"Stream the logs of a specific service from the last 10 minutes.": output.WithHighLightFormat(
+			"azd logs <service> --follow --since 10m",
+		),
+		"Stream the logs of all services as JSON lines.": output.WithHighLightFormat("azd logs --follow --output json"),
+	})
+}
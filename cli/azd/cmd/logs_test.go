@@ -0,0 +1,47 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ServiceLogWriter(t *testing.T) {
+	t.Run("Prefix", func(t *testing.T) {
+		output := &bytes.Buffer{}
+		writer := &serviceLogWriter{
+			serviceName: "api",
+			prefix:      "api |",
+			writer:      output,
+			writeLock:   &sync.Mutex{},
+		}
+
+		_, err := writer.Write([]byte("first line\r\nsecond "))
+		require.NoError(t, err)
+		require.Equal(t, "api | first line\n", output.String())
+
+		_, err = writer.Write([]byte("line\npartial"))
+		require.NoError(t, err)
+		require.NoError(t, writer.Flush())
+		require.Equal(t, "api | first line\napi | second line\napi | partial\n", output.String())
+	})
+
+	t.Run("JsonLines", func(t *testing.T) {
+		output := &bytes.Buffer{}
+		writer := &serviceLogWriter{
+			serviceName: "api",
+			jsonLines:   true,
+			writer:      output,
+			writeLock:   &sync.Mutex{},
+		}
+
+		_, err := writer.Write([]byte("listening on \"0.0.0.0\"\n"))
+		require.NoError(t, err)
+		require.Equal(t, `{"service":"api","message":"listening on \"0.0.0.0\""}`+"\n", output.String())
+	})
+}
@@ -179,7 +179,7 @@ func (pa *packageAction) Run(ctx context.Context) (*actions.ActionResult, error)
 		// It's probably right for us to think about "package" for a containerized application as meaning "produce the tgz"
 		// of the image, as would be done by `docker save` and then do this for both DotNetContainerAppTargets and
 		// ContainerAppTargets.
-		if svc.Host == project.DotNetContainerAppTarget {
+		if svc.Host == project.DotNetContainerAppTarget || svc.Host == project.DotNetAksTarget {
 			continue
 		}
 
@@ -218,6 +218,21 @@ func NewRootCmd(
 		}).
 		UseMiddleware("hooks", middleware.NewHooksMiddleware)
 
+	root.Add("run", &actions.ActionDescriptorOptions{
+		Command:        newRunCmd(),
+		FlagsResolver:  newRunFlags,
+		ActionResolver: newRunAction,
+		OutputFormats:  []output.Format{output.JsonFormat, output.NoneFormat},
+		DefaultFormat:  output.NoneFormat,
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdRunHelpDescription,
+			Footer:      getCmdRunHelpFooter,
+		},
+		GroupingOptions: actions.CommandGroupOptions{
+			RootLevelHelp: actions.CmdGroupConfig,
+		},
+	})
+
 	root.
 		Add("provision", &actions.ActionDescriptorOptions{
 			Command:        cmd.NewProvisionCmd(),
@@ -304,6 +319,21 @@ func NewRootCmd(
 		},
 	})
 
+	root.Add("logs", &actions.ActionDescriptorOptions{
+		Command:        newLogsCmd(),
+		FlagsResolver:  newLogsFlags,
+		ActionResolver: newLogsAction,
+		OutputFormats:  []output.Format{output.JsonFormat, output.NoneFormat},
+		DefaultFormat:  output.NoneFormat,
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdLogsHelpDescription,
+			Footer:      getCmdLogsHelpFooter,
+		},
+		GroupingOptions: actions.CommandGroupOptions{
+			RootLevelHelp: actions.CmdGroupMonitor,
+		},
+	})
+
 	root.
 		Add("down", &actions.ActionDescriptorOptions{
 			Command:        newDownCmd(),
@@ -0,0 +1,387 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+type runFlags struct {
+	noEmulators bool
+	global      *internal.GlobalCommandOptions
+	internal.EnvFlag
+}
+
+func (r *runFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.BoolVar(
+		&r.noEmulators,
+		"no-emulators",
+		false,
+		"Don't start the local emulators declared in azure.yaml.",
+	)
+	r.EnvFlag.Bind(local, global)
+	r.global = global
+}
+
+func newRunFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *runFlags {
+	flags := &runFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newRunCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "run <service>",
+		Short: fmt.Sprintf("Run the application locally. %s", output.WithWarningFormat("(Beta)")),
+		Args:  cobra.MaximumNArgs(1),
+	}
+}
+
+type runAction struct {
+	flags          *runFlags
+	args           []string
+	console        input.Console
+	formatter      output.Formatter
+	writer         io.Writer
+	projectConfig  *project.ProjectConfig
+	projectManager project.ProjectManager
+	importManager  *project.ImportManager
+	serviceManager project.ServiceManager
+	docker         docker.Docker
+}
+
+func newRunAction(
+	flags *runFlags,
+	args []string,
+	console input.Console,
+	formatter output.Formatter,
+	writer io.Writer,
+	projectConfig *project.ProjectConfig,
+	projectManager project.ProjectManager,
+	importManager *project.ImportManager,
+	serviceManager project.ServiceManager,
+	docker docker.Docker,
+) actions.Action {
+	return &runAction{
+		flags:          flags,
+		args:           args,
+		console:        console,
+		formatter:      formatter,
+		writer:         writer,
+		projectConfig:  projectConfig,
+		projectManager: projectManager,
+		importManager:  importManager,
+		serviceManager: serviceManager,
+		docker:         docker,
+	}
+}
+
+// A process started by `azd run`, either a service or an emulator
+type runProcess struct {
+	name string
+	run  func(ctx context.Context, writer io.Writer) error
+}
+
+func (ra *runAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	// Command title
+	ra.console.MessageUxItem(ctx, &ux.MessageTitle{
+		Title: "Running services locally (azd run)",
+	})
+
+	targetServiceName := ""
+	if len(ra.args) == 1 {
+		targetServiceName = ra.args[0]
+	}
+
+	// All services are run when a service isn't specified
+	targetServiceName, err := getTargetServiceName(
+		ctx,
+		ra.projectManager,
+		ra.importManager,
+		ra.projectConfig,
+		"run",
+		targetServiceName,
+		targetServiceName == "",
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ra.projectManager.EnsureFrameworkTools(ctx, ra.projectConfig, func(svc *project.ServiceConfig) bool {
+		return targetServiceName == "" || svc.Name == targetServiceName
+	}); err != nil {
+		return nil, err
+	}
+
+	startEmulators := !ra.flags.noEmulators && len(ra.projectConfig.Emulators) > 0
+	if startEmulators {
+		if err := tools.EnsureInstalled(ctx, ra.docker); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := ra.projectManager.Initialize(ctx, ra.projectConfig); err != nil {
+		return nil, err
+	}
+
+	stableServices, err := ra.importManager.ServiceStable(ctx, ra.projectConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	services := []*project.ServiceConfig{}
+	for _, svc := range stableServices {
+		if targetServiceName == "" || targetServiceName == svc.Name {
+			services = append(services, svc)
+		}
+	}
+
+	ports, err := assignServicePorts(services)
+	if err != nil {
+		return nil, err
+	}
+
+	processes := []runProcess{}
+
+	if startEmulators {
+		emulatorNames := []string{}
+		for name := range ra.projectConfig.Emulators {
+			emulatorNames = append(emulatorNames, name)
+		}
+		slices.Sort(emulatorNames)
+
+		for _, name := range emulatorNames {
+			emulator := ra.projectConfig.Emulators[name]
+			options := &docker.RunOptions{
+				Name:  fmt.Sprintf("%s-%s", strings.ToLower(ra.projectConfig.Name), strings.ToLower(name)),
+				Ports: emulator.Ports,
+			}
+
+			for key, value := range emulator.Env {
+				options.Env = append(options.Env, fmt.Sprintf("%s=%s", key, value))
+			}
+			slices.Sort(options.Env)
+
+			processes = append(processes, runProcess{
+				name: name,
+				run: func(ctx context.Context, writer io.Writer) error {
+					return ra.docker.Run(ctx, ra.projectConfig.Path, emulator.Image, options, writer)
+				},
+			})
+			ra.console.MessageUxItem(ctx, &ux.DoneMessage{
+				Message: fmt.Sprintf("Starting emulator %s (%s)", name, emulator.Image),
+			})
+		}
+	}
+
+	for _, svc := range services {
+		buildResult, err := ra.build(ctx, svc)
+		if err != nil {
+			return nil, err
+		}
+
+		svc := svc
+		options := &project.RunOptions{Port: ports[svc.Name]}
+		processes = append(processes, runProcess{
+			name: svc.Name,
+			run: func(ctx context.Context, writer io.Writer) error {
+				return ra.serviceManager.Run(ctx, svc, buildResult, options, writer)
+			},
+		})
+		ra.console.MessageUxItem(ctx, &ux.DoneMessage{
+			Message: fmt.Sprintf(
+				"Starting service %s at %s",
+				svc.Name,
+				output.WithLinkFormat("http://localhost:%d", options.Port),
+			),
+		})
+	}
+
+	ra.console.Message(ctx, output.WithGrayFormat("Press Ctrl+C to stop.\n"))
+
+	return nil, ra.runProcesses(ctx, processes, targetServiceName == "")
+}
+
+// Restores and builds the service before it is run
+func (ra *runAction) build(ctx context.Context, svc *project.ServiceConfig) (*project.ServiceBuildResult, error) {
+	stepMessage := fmt.Sprintf("Building service %s", svc.Name)
+	ra.console.ShowSpinner(ctx, stepMessage, input.Step)
+
+	restoreTask := ra.serviceManager.Restore(ctx, svc)
+	go func() {
+		for restoreProgress := range restoreTask.Progress() {
+			progressMessage := fmt.Sprintf("Building service %s (%s)", svc.Name, restoreProgress.Message)
+			ra.console.ShowSpinner(ctx, progressMessage, input.Step)
+		}
+	}()
+
+	restoreResult, err := restoreTask.Await()
+	if err != nil {
+		ra.console.StopSpinner(ctx, stepMessage, input.StepFailed)
+		return nil, err
+	}
+
+	buildTask := ra.serviceManager.Build(ctx, svc, restoreResult)
+	go func() {
+		for buildProgress := range buildTask.Progress() {
+			progressMessage := fmt.Sprintf("Building service %s (%s)", svc.Name, buildProgress.Message)
+			ra.console.ShowSpinner(ctx, progressMessage, input.Step)
+		}
+	}()
+
+	buildResult, err := buildTask.Await()
+	if err != nil {
+		ra.console.StopSpinner(ctx, stepMessage, input.StepFailed)
+		return nil, err
+	}
+
+	ra.console.StopSpinner(ctx, stepMessage, input.StepDone)
+	return buildResult, nil
+}
+
+// Runs the processes concurrently until they all exit, interleaving their output line by line.
+// When running all services, the services that can't run locally are skipped with a warning.
+func (ra *runAction) runProcesses(ctx context.Context, processes []runProcess, allServices bool) error {
+	prefixWidth := 0
+	for _, process := range processes {
+		prefixWidth = max(prefixWidth, len(process.name))
+	}
+
+	var writeLock sync.Mutex
+	var wg sync.WaitGroup
+	errs := make([]error, len(processes))
+
+	for i, process := range processes {
+		writer := &serviceLogWriter{
+			serviceName: process.name,
+			prefix:      logPrefixColors[i%len(logPrefixColors)].Sprintf("%-*s |", prefixWidth, process.name),
+			jsonLines:   ra.formatter.Kind() == output.JsonFormat,
+			writer:      ra.writer,
+			writeLock:   &writeLock,
+		}
+
+		wg.Add(1)
+		go func(i int, process runProcess) {
+			defer wg.Done()
+
+			err := process.run(ctx, writer)
+			if flushErr := writer.Flush(); flushErr != nil && err == nil {
+				err = flushErr
+			}
+
+			if errors.Is(err, project.ErrRunNotSupported) && allServices {
+				writeLock.Lock()
+				ra.console.Message(ctx, output.WithWarningFormat("WARNING: skipping service '%s', %v", process.name, err))
+				writeLock.Unlock()
+
+				return
+			}
+
+			// Processes are stopped by cancelling the context, which isn't a failure
+			if err != nil && ctx.Err() == nil {
+				errs[i] = fmt.Errorf("running '%s': %w", process.name, err)
+			}
+		}(i, process)
+	}
+
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// assignServicePorts returns the local port of each service, which is either the port configured in azure.yaml or a
+// free port
+func assignServicePorts(services []*project.ServiceConfig) (map[string]int, error) {
+	ports := map[string]int{}
+	usedPorts := map[int]string{}
+
+	for _, svc := range services {
+		if svc.Run.Port == 0 {
+			continue
+		}
+
+		if other, has := usedPorts[svc.Run.Port]; has {
+			return nil, fmt.Errorf("services '%s' and '%s' are both configured to run on port %d", other, svc.Name, svc.Run.Port)
+		}
+
+		ports[svc.Name] = svc.Run.Port
+		usedPorts[svc.Run.Port] = svc.Name
+	}
+
+	for _, svc := range services {
+		if svc.Run.Port != 0 {
+			continue
+		}
+
+		port, err := findFreePort(usedPorts)
+		if err != nil {
+			return nil, fmt.Errorf("assigning a port to service '%s': %w", svc.Name, err)
+		}
+
+		ports[svc.Name] = port
+		usedPorts[port] = svc.Name
+	}
+
+	return ports, nil
+}
+
+// findFreePort returns a port that is free on the local machine and not already used
+func findFreePort(usedPorts map[int]string) (int, error) {
+	for {
+		listener, err := net.Listen("tcp", "localhost:0")
+		if err != nil {
+			return 0, err
+		}
+
+		port := listener.Addr().(*net.TCPAddr).Port
+		if err := listener.Close(); err != nil {
+			return 0, err
+		}
+
+		if _, has := usedPorts[port]; !has {
+			return port, nil
+		}
+	}
+}
+
+func getCmdRunHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		fmt.Sprintf(
+			"Restore, build and run the application locally with the values of the azd environment. %s",
+			output.WithWarningFormat("(Beta)"),
+		),
+		[]string{
+			formatHelpNote("Each service is assigned a local port, which is set as the PORT environment variable. " +
+				"Set 'run.port' on a service in azure.yaml to use a fixed port."),
+			formatHelpNote("Services hosted in containers run in a local container built from their Dockerfile."),
+			formatHelpNote("The emulators declared in azure.yaml under 'emulators' are started along with the services."),
+		})
+}
+
+func getCmdRunHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Run all services locally.":  output.WithHighLightFormat("azd run"),
+		"Run a specific service.":    output.WithHighLightFormat("azd run <service>"),
+		"Run without the emulators.": output.WithHighLightFormat("azd run --no-emulators"),
+	})
+}
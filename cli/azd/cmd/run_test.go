@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_AssignServicePorts(t *testing.T) {
+	t.Run("ConfiguredAndFreePorts", func(t *testing.T) {
+		services := []*project.ServiceConfig{
+			{Name: "web"},
+			{Name: "api", Run: project.LocalRunOptions{Port: 3100}},
+			{Name: "worker"},
+		}
+
+		ports, err := assignServicePorts(services)
+		require.NoError(t, err)
+		require.Len(t, ports, 3)
+		require.Equal(t, 3100, ports["api"])
+		require.NotZero(t, ports["web"])
+		require.NotZero(t, ports["worker"])
+		require.NotEqual(t, ports["web"], ports["worker"])
+		require.NotEqual(t, 3100, ports["web"])
+		require.NotEqual(t, 3100, ports["worker"])
+	})
+
+	t.Run("DuplicatePorts", func(t *testing.T) {
+		services := []*project.ServiceConfig{
+			{Name: "web", Run: project.LocalRunOptions{Port: 3100}},
+			{Name: "api", Run: project.LocalRunOptions{Port: 3100}},
+		}
+
+		_, err := assignServicePorts(services)
+		require.ErrorContains(t, err, "port 3100")
+	})
+}
@@ -0,0 +1,171 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal/repository"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
+	"github.com/spf13/cobra"
+)
+
+type templateUpgradeFlags struct {
+	ref     string
+	preview bool
+}
+
+func newTemplateUpgradeFlags(cmd *cobra.Command) *templateUpgradeFlags {
+	flags := &templateUpgradeFlags{}
+	cmd.Flags().StringVar(
+		&flags.ref,
+		"ref",
+		"",
+		"The branch or tag of the template to upgrade to. Defaults to the one the project was initialized from.",
+	)
+	cmd.Flags().BoolVar(
+		&flags.preview,
+		"preview",
+		false,
+		"Shows the changes of the upgrade without modifying the project.",
+	)
+
+	return flags
+}
+
+func newTemplateUpgradeCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "upgrade",
+		Short: "Merge the latest changes of the template into the project.",
+		Long: heredoc.Doc(`
+			Merge the latest changes of the template into the project.
+
+			The changes of the template since the commit the project was initialized or last upgraded from are
+			merged with the local changes of each file. Conflicting changes are left in the files with conflict
+			markers for review.`),
+		Args: cobra.NoArgs,
+	}
+}
+
+type templateUpgradeAction struct {
+	flags           *templateUpgradeFlags
+	azdCtx          *azdcontext.AzdContext
+	repoInitializer *repository.Initializer
+	gitCli          git.GitCli
+	console         input.Console
+	formatter       output.Formatter
+	writer          io.Writer
+}
+
+func newTemplateUpgradeAction(
+	flags *templateUpgradeFlags,
+	azdCtx *azdcontext.AzdContext,
+	repoInitializer *repository.Initializer,
+	gitCli git.GitCli,
+	console input.Console,
+	formatter output.Formatter,
+	writer io.Writer,
+) actions.Action {
+	return &templateUpgradeAction{
+		flags:           flags,
+		azdCtx:          azdCtx,
+		repoInitializer: repoInitializer,
+		gitCli:          gitCli,
+		console:         console,
+		formatter:       formatter,
+		writer:          writer,
+	}
+}
+
+func (a *templateUpgradeAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	if err := tools.EnsureInstalled(ctx, a.gitCli); err != nil {
+		return nil, err
+	}
+
+	if a.formatter.Kind() == output.NoneFormat {
+		title := "Upgrade the project template (azd template upgrade)"
+		if a.flags.preview {
+			title = "Preview the upgrade of the project template (azd template upgrade --preview)"
+		}
+		a.console.MessageUxItem(ctx, &ux.MessageTitle{Title: title})
+	}
+
+	upgrade, err := a.repoInitializer.Upgrade(ctx, a.azdCtx, a.flags.ref, a.flags.preview)
+	if err != nil {
+		return nil, err
+	}
+
+	if a.formatter.Kind() == output.JsonFormat {
+		return nil, a.formatter.Format(upgrade, a.writer, nil)
+	}
+
+	if upgrade.FromCommit == upgrade.ToCommit {
+		return &actions.ActionResult{
+			Message: &actions.ResultMessage{
+				Header: fmt.Sprintf("The project is up to date with the template (%s).", upgrade.ToCommit),
+			},
+		}, nil
+	}
+
+	a.console.Message(ctx, fmt.Sprintf(
+		"\nChanges of %s from %s to %s:", upgrade.Repository, upgrade.FromCommit, upgrade.ToCommit))
+	if len(upgrade.Changes) == 0 {
+		a.console.Message(ctx, "  (none of the files of the project are affected)")
+	}
+	for _, change := range upgrade.Changes {
+		line := fmt.Sprintf("  %-9s %s", change.Kind, change.Path)
+		if change.Kind == repository.TemplateChangeConflict {
+			line = output.WithWarningFormat(line)
+		}
+		a.console.Message(ctx, line)
+	}
+	a.console.Message(ctx, "")
+
+	conflicts := upgrade.Conflicts()
+	if a.flags.preview {
+		return &actions.ActionResult{
+			Message: &actions.ResultMessage{
+				Header: fmt.Sprintf(
+					"%d file(s) would be changed, %d with conflicts.", len(upgrade.Changes), len(conflicts)),
+				FollowUp: "Run `azd template upgrade` without --preview to apply the changes.",
+			},
+		}, nil
+	}
+
+	if len(conflicts) > 0 {
+		followUp := []string{"Review and resolve the conflicts before committing the upgrade:"}
+		for _, conflict := range conflicts {
+			if conflict.Reason != "" {
+				followUp = append(followUp, fmt.Sprintf("  %s: %s", conflict.Path, conflict.Reason))
+			} else {
+				followUp = append(followUp, fmt.Sprintf("  %s: resolve the conflict markers", conflict.Path))
+			}
+		}
+
+		return &actions.ActionResult{
+			Message: &actions.ResultMessage{
+				Header: fmt.Sprintf(
+					"Upgraded the template to %s with %d conflict(s).", upgrade.ToCommit, len(conflicts)),
+				FollowUp: strings.Join(followUp, "\n"),
+			},
+		}, nil
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header:   fmt.Sprintf("Upgraded the template to %s.", upgrade.ToCommit),
+			FollowUp: "Review the changes, e.g. with `git diff`, before committing them.",
+		},
+	}, nil
+}
@@ -0,0 +1,169 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal/repository"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/templates"
+	"github.com/spf13/cobra"
+)
+
+type templateValidateFlags struct {
+	ref string
+}
+
+func newTemplateValidateFlags(cmd *cobra.Command) *templateValidateFlags {
+	flags := &templateValidateFlags{}
+	cmd.Flags().StringVar(
+		&flags.ref,
+		"ref",
+		"",
+		"The branch or tag of the template repository to validate. Defaults to the default branch.",
+	)
+
+	return flags
+}
+
+func newTemplateValidateCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "validate [<path|repository>]",
+		Short: "Validate a template before publishing it.",
+		Long: heredoc.Doc(`
+			Validate a template before publishing it.
+
+			The template is a local directory, the current one by default, or a template repository.
+			The project file is checked against the azure.yaml schema, the infrastructure is compiled with
+			Bicep or validated with Terraform, each service is checked to have a resource tagged with its name
+			and the scripts of hooks are checked to exist.
+
+			Use --output json for a report suited to CI pipelines. The command fails when a check fails.`),
+		Args: cobra.MaximumNArgs(1),
+	}
+}
+
+type templateValidateAction struct {
+	flags     *templateValidateFlags
+	args      []string
+	validator *repository.TemplateValidator
+	gitCloner *templates.GitCloner
+	console   input.Console
+	formatter output.Formatter
+	writer    io.Writer
+}
+
+func newTemplateValidateAction(
+	flags *templateValidateFlags,
+	args []string,
+	validator *repository.TemplateValidator,
+	gitCloner *templates.GitCloner,
+	console input.Console,
+	formatter output.Formatter,
+	writer io.Writer,
+) actions.Action {
+	return &templateValidateAction{
+		flags:     flags,
+		args:      args,
+		validator: validator,
+		gitCloner: gitCloner,
+		console:   console,
+		formatter: formatter,
+		writer:    writer,
+	}
+}
+
+func (a *templateValidateAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	template := "."
+	if len(a.args) > 0 {
+		template = a.args[0]
+	}
+
+	if a.formatter.Kind() == output.NoneFormat {
+		a.console.MessageUxItem(ctx, &ux.MessageTitle{Title: "Validate template (azd template validate)"})
+	}
+
+	templateDir := template
+	if info, err := os.Stat(template); err != nil || !info.IsDir() {
+		templateUrl, err := templates.Absolute(template)
+		if err != nil {
+			return nil, err
+		}
+
+		templateDir, err = os.MkdirTemp("", "az-dev-template-validate")
+		if err != nil {
+			return nil, fmt.Errorf("creating temp folder: %w", err)
+		}
+		defer func() {
+			_ = os.RemoveAll(templateDir)
+		}()
+
+		stepMessage := fmt.Sprintf("Fetching template %s", templateUrl)
+		a.console.ShowSpinner(ctx, stepMessage, input.Step)
+		err = a.gitCloner.ShallowClone(ctx, templateUrl, a.flags.ref, templateDir)
+		a.console.StopSpinner(ctx, stepMessage, input.GetStepResultFormat(err))
+		if err != nil {
+			return nil, fmt.Errorf("fetching template: %w", err)
+		}
+	}
+
+	stepMessage := "Validating template"
+	a.console.ShowSpinner(ctx, stepMessage, input.Step)
+	report, err := a.validator.Validate(ctx, templateDir, template)
+	a.console.StopSpinner(ctx, "", input.Step)
+	if err != nil {
+		return nil, err
+	}
+
+	if a.formatter.Kind() == output.JsonFormat {
+		if err := a.formatter.Format(report, a.writer, nil); err != nil {
+			return nil, err
+		}
+	} else {
+		for _, check := range report.Checks {
+			a.console.Message(ctx, fmt.Sprintf("  %s %s", validationStatusText(check.Status), check.Name))
+			for _, message := range check.Messages {
+				a.console.Message(ctx, fmt.Sprintf("      %s", message))
+			}
+		}
+		a.console.Message(ctx, "")
+	}
+
+	if !report.Valid {
+		return nil, errors.New("the template validation failed")
+	}
+
+	if a.formatter.Kind() == output.JsonFormat {
+		return nil, nil
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: "The template is valid.",
+		},
+	}, nil
+}
+
+func validationStatusText(status repository.ValidationStatus) string {
+	text := fmt.Sprintf("%-8s", status)
+	switch status {
+	case repository.ValidationPassed:
+		return output.WithSuccessFormat(text)
+	case repository.ValidationWarning:
+		return output.WithWarningFormat(text)
+	case repository.ValidationFailed:
+		return output.WithErrorFormat(text)
+	default:
+		return output.WithGrayFormat(text)
+	}
+}
@@ -46,6 +46,22 @@ func templatesActions(root *actions.ActionDescriptor) *actions.ActionDescriptor
 		DefaultFormat:  output.NoneFormat,
 	})
 
+	group.Add("upgrade", &actions.ActionDescriptorOptions{
+		Command:        newTemplateUpgradeCmd(),
+		ActionResolver: newTemplateUpgradeAction,
+		FlagsResolver:  newTemplateUpgradeFlags,
+		OutputFormats:  []output.Format{output.JsonFormat, output.NoneFormat},
+		DefaultFormat:  output.NoneFormat,
+	})
+
+	group.Add("validate", &actions.ActionDescriptorOptions{
+		Command:        newTemplateValidateCmd(),
+		ActionResolver: newTemplateValidateAction,
+		FlagsResolver:  newTemplateValidateFlags,
+		OutputFormats:  []output.Format{output.JsonFormat, output.NoneFormat},
+		DefaultFormat:  output.NoneFormat,
+	})
+
 	_ = templateSourceActions(group)
 
 	return group
@@ -314,6 +330,8 @@ type templateSourceAddFlags struct {
 	name     string
 	location string
 	kind     string
+	ref      string
+	path     string
 }
 
 func newTemplateSourceAddFlags(cmd *cobra.Command) *templateSourceAddFlags {
@@ -322,6 +340,13 @@ func newTemplateSourceAddFlags(cmd *cobra.Command) *templateSourceAddFlags {
 	cmd.Flags().StringVarP(&flags.kind, "type", "t", "", "Kind of the template source.")
 	cmd.Flags().StringVarP(&flags.location, "location", "l", "", "Location of the template source.")
 	cmd.Flags().StringVarP(&flags.name, "name", "n", "", "Display name of the template source.")
+	cmd.Flags().StringVar(&flags.ref, "ref", "", "Branch or tag of a git template source.")
+	cmd.Flags().StringVar(
+		&flags.path,
+		"path",
+		"",
+		"Path of the JSON list of templates in the repository of a git template source. Defaults to templates.json.",
+	)
 
 	return flags
 }
@@ -363,7 +388,7 @@ func (a *templateSourceAddAction) Run(ctx context.Context) (*actions.ActionResul
 		if wellKnownSource.Type == templates.SourceKind(a.flags.kind) {
 			a.console.StopSpinner(ctx, spinnerMessage, input.StepFailed)
 			return nil, fmt.Errorf(
-				"template source type '%s' is not supported. Supported types are 'file' and 'url'",
+				"template source type '%s' is not supported. Supported types are 'file', 'url' and 'git'",
 				a.flags.kind,
 			)
 		}
@@ -375,6 +400,8 @@ func (a *templateSourceAddAction) Run(ctx context.Context) (*actions.ActionResul
 			Type:     templates.SourceKind(a.flags.kind),
 			Location: a.flags.location,
 			Name:     a.flags.name,
+			Ref:      a.flags.ref,
+			Path:     a.flags.path,
 		}
 
 		// Validate the custom source config
@@ -383,7 +410,7 @@ func (a *templateSourceAddAction) Run(ctx context.Context) (*actions.ActionResul
 		if err != nil {
 			if errors.Is(err, templates.ErrSourceTypeInvalid) {
 				return nil, fmt.Errorf(
-					"template source type '%s' is not supported. Supported types are 'file' and 'url'",
+					"template source type '%s' is not supported. Supported types are 'file', 'url' and 'git'",
 					a.flags.kind,
 				)
 			}
@@ -488,6 +515,9 @@ func getCmdTemplateSourceHelpFooter(*cobra.Command) string {
 		"Add a new url template source.": output.WithHighLightFormat(
 			"azd template source add <key> --type url --location <url>",
 		),
+		"Add a new git template source, with its list of templates in a private repository.": output.WithHighLightFormat(
+			"azd template source add <key> --type git --location <repository> --ref <branch> --path <path>",
+		),
 		"Remove a previously registered template source.": output.WithHighLightFormat(
 			"azd template source remove <key>",
 		),
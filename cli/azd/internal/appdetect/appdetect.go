@@ -128,6 +128,28 @@ func (db DatabaseDep) Display() string {
 	return ""
 }
 
+// An Azure service, other than a database, that is inferred through heuristics while scanning project information.
+type AzureDep string
+
+const (
+	AzureDepServiceBus AzureDep = "servicebus"
+	AzureDepEventHubs  AzureDep = "eventhubs"
+	AzureDepStorage    AzureDep = "storage"
+)
+
+func (azureDep AzureDep) Display() string {
+	switch azureDep {
+	case AzureDepServiceBus:
+		return "Azure Service Bus"
+	case AzureDepEventHubs:
+		return "Azure Event Hubs"
+	case AzureDepStorage:
+		return "Azure Storage"
+	}
+
+	return ""
+}
+
 type Project struct {
 	// The language associated with the project.
 	Language Language
@@ -138,6 +160,9 @@ type Project struct {
 	// Experimental: Database dependencies inferred through heuristics while scanning dependencies in the project.
 	DatabaseDeps []DatabaseDep
 
+	// Experimental: Azure service dependencies inferred through heuristics while scanning dependencies in the project.
+	AzureDeps []AzureDep
+
 	// The path to the project directory.
 	Path string
 
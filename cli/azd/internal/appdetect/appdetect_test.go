@@ -36,11 +36,40 @@ func TestDetect(t *testing.T) {
 					Path:          "dotnet",
 					DetectionRule: "Inferred by presence of: dotnettestapp.csproj, Program.cs",
 				},
+				{
+					Language:      DotNet,
+					Path:          "dotnet-full",
+					DetectionRule: "Inferred by presence of: dotnetfullapp.csproj, Program.cs",
+					DatabaseDeps: []DatabaseDep{
+						DbMongo,
+						DbPostgres,
+						DbRedis,
+					},
+					AzureDeps: []AzureDep{
+						AzureDepServiceBus,
+						AzureDepStorage,
+					},
+				},
 				{
 					Language:      Java,
 					Path:          "java",
 					DetectionRule: "Inferred by presence of: pom.xml",
 				},
+				{
+					Language:      Java,
+					Path:          "java-full",
+					DetectionRule: "Inferred by presence of: pom.xml",
+					DatabaseDeps: []DatabaseDep{
+						DbMySql,
+						DbRedis,
+						DbSqlServer,
+					},
+					AzureDeps: []AzureDep{
+						AzureDepEventHubs,
+						AzureDepServiceBus,
+						AzureDepStorage,
+					},
+				},
 				{
 					Language:      JavaScript,
 					Path:          "javascript",
@@ -63,6 +92,10 @@ func TestDetect(t *testing.T) {
 						DbRedis,
 						DbSqlServer,
 					},
+					AzureDeps: []AzureDep{
+						AzureDepEventHubs,
+						AzureDepStorage,
+					},
 				},
 				{
 					Language:      Python,
@@ -83,6 +116,12 @@ func TestDetect(t *testing.T) {
 						DbMySql,
 						DbPostgres,
 						DbRedis,
+						DbSqlServer,
+					},
+					AzureDeps: []AzureDep{
+						AzureDepEventHubs,
+						AzureDepServiceBus,
+						AzureDepStorage,
 					},
 				},
 				{
@@ -106,11 +145,40 @@ func TestDetect(t *testing.T) {
 					Path:          "dotnet",
 					DetectionRule: "Inferred by presence of: dotnettestapp.csproj, Program.cs",
 				},
+				{
+					Language:      DotNet,
+					Path:          "dotnet-full",
+					DetectionRule: "Inferred by presence of: dotnetfullapp.csproj, Program.cs",
+					DatabaseDeps: []DatabaseDep{
+						DbMongo,
+						DbPostgres,
+						DbRedis,
+					},
+					AzureDeps: []AzureDep{
+						AzureDepServiceBus,
+						AzureDepStorage,
+					},
+				},
 				{
 					Language:      Java,
 					Path:          "java",
 					DetectionRule: "Inferred by presence of: pom.xml",
 				},
+				{
+					Language:      Java,
+					Path:          "java-full",
+					DetectionRule: "Inferred by presence of: pom.xml",
+					DatabaseDeps: []DatabaseDep{
+						DbMySql,
+						DbRedis,
+						DbSqlServer,
+					},
+					AzureDeps: []AzureDep{
+						AzureDepEventHubs,
+						AzureDepServiceBus,
+						AzureDepStorage,
+					},
+				},
 			},
 		},
 		{
@@ -125,11 +193,40 @@ func TestDetect(t *testing.T) {
 					Path:          "dotnet",
 					DetectionRule: "Inferred by presence of: dotnettestapp.csproj, Program.cs",
 				},
+				{
+					Language:      DotNet,
+					Path:          "dotnet-full",
+					DetectionRule: "Inferred by presence of: dotnetfullapp.csproj, Program.cs",
+					DatabaseDeps: []DatabaseDep{
+						DbMongo,
+						DbPostgres,
+						DbRedis,
+					},
+					AzureDeps: []AzureDep{
+						AzureDepServiceBus,
+						AzureDepStorage,
+					},
+				},
 				{
 					Language:      Java,
 					Path:          "java",
 					DetectionRule: "Inferred by presence of: pom.xml",
 				},
+				{
+					Language:      Java,
+					Path:          "java-full",
+					DetectionRule: "Inferred by presence of: pom.xml",
+					DatabaseDeps: []DatabaseDep{
+						DbMySql,
+						DbRedis,
+						DbSqlServer,
+					},
+					AzureDeps: []AzureDep{
+						AzureDepEventHubs,
+						AzureDepServiceBus,
+						AzureDepStorage,
+					},
+				},
 			},
 		},
 		{
@@ -0,0 +1,45 @@
+package appdetect
+
+import (
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// libraryDeps collects the database and Azure service dependencies of a project, as inferred from the client libraries
+// it depends on.
+type libraryDeps struct {
+	databases map[DatabaseDep]struct{}
+	azure     map[AzureDep]struct{}
+}
+
+func newLibraryDeps() *libraryDeps {
+	return &libraryDeps{
+		databases: map[DatabaseDep]struct{}{},
+		azure:     map[AzureDep]struct{}{},
+	}
+}
+
+func (d *libraryDeps) addDatabase(db DatabaseDep) {
+	d.databases[db] = struct{}{}
+}
+
+func (d *libraryDeps) addAzure(azureDep AzureDep) {
+	d.azure[azureDep] = struct{}{}
+}
+
+// apply sets the collected dependencies on the project, sorted by name.
+func (d *libraryDeps) apply(project *Project) {
+	if len(d.databases) > 0 {
+		project.DatabaseDeps = maps.Keys(d.databases)
+		slices.SortFunc(project.DatabaseDeps, func(a, b DatabaseDep) bool {
+			return string(a) < string(b)
+		})
+	}
+
+	if len(d.azure) > 0 {
+		project.AzureDeps = maps.Keys(d.azure)
+		slices.SortFunc(project.AzureDeps, func(a, b AzureDep) bool {
+			return string(a) < string(b)
+		})
+	}
+}
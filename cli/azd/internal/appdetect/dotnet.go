@@ -2,9 +2,11 @@ package appdetect
 
 import (
 	"context"
+	"encoding/xml"
 	"fmt"
 	"io/fs"
 	"log"
+	"os"
 	"path/filepath"
 	"strings"
 
@@ -53,16 +55,69 @@ func (dd *dotNetDetector) DetectProject(ctx context.Context, path string, entrie
 			return nil, filepath.SkipDir
 		}
 
-		return &Project{
+		project := &Project{
 			Language:      DotNet,
 			Path:          path,
 			DetectionRule: "Inferred by presence of: " + fmt.Sprintf("%s, %s", projFileName, startUpFileName),
-		}, nil
+		}
+
+		if err := detectPackageReferences(projectPath, project); err != nil {
+			log.Printf("error reading package references of %s: %v", projectPath, err)
+		}
+
+		return project, nil
 	}
 
 	return nil, nil
 }
 
+// msbuildProject is the subset of an MSBuild project file that is used to infer the dependencies of a project.
+type msbuildProject struct {
+	PackageReferences []struct {
+		Include string `xml:"Include,attr"`
+	} `xml:"ItemGroup>PackageReference"`
+}
+
+// detectPackageReferences infers the database and Azure service dependencies of the project from the NuGet packages
+// referenced by its project file.
+func detectPackageReferences(projectPath string, project *Project) error {
+	contents, err := os.ReadFile(projectPath)
+	if err != nil {
+		return err
+	}
+
+	var msbuild msbuildProject
+	if err := xml.Unmarshal(contents, &msbuild); err != nil {
+		return err
+	}
+
+	libraryDeps := newLibraryDeps()
+	for _, ref := range msbuild.PackageReferences {
+		// NuGet package ids are case insensitive
+		switch strings.ToLower(ref.Include) {
+		case "mysqlconnector", "mysql.data", "pomelo.entityframeworkcore.mysql":
+			libraryDeps.addDatabase(DbMySql)
+		case "npgsql", "npgsql.entityframeworkcore.postgresql":
+			libraryDeps.addDatabase(DbPostgres)
+		case "mongodb.driver", "mongodb.entityframeworkcore":
+			libraryDeps.addDatabase(DbMongo)
+		case "microsoft.data.sqlclient", "system.data.sqlclient", "microsoft.entityframeworkcore.sqlserver":
+			libraryDeps.addDatabase(DbSqlServer)
+		case "stackexchange.redis", "microsoft.extensions.caching.stackexchangeredis":
+			libraryDeps.addDatabase(DbRedis)
+		case "azure.messaging.servicebus":
+			libraryDeps.addAzure(AzureDepServiceBus)
+		case "azure.messaging.eventhubs", "azure.messaging.eventhubs.processor":
+			libraryDeps.addAzure(AzureDepEventHubs)
+		case "azure.storage.blobs", "azure.storage.queues", "azure.data.tables":
+			libraryDeps.addAzure(AzureDepStorage)
+		}
+	}
+
+	libraryDeps.apply(project)
+	return nil
+}
+
 func (ad *dotNetDetector) isWasmProject(ctx context.Context, projectPath string) (bool, error) {
 	value, err := ad.dotnetCli.GetMsBuildProperty(ctx, projectPath, "RuntimeIdentifier")
 	if err != nil {
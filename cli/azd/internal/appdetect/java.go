@@ -2,13 +2,26 @@ package appdetect
 
 import (
 	"context"
+	"encoding/xml"
 	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
 	"strings"
 )
 
 type javaDetector struct {
 }
 
+// mavenProject is the subset of a Maven pom.xml that is used to infer the dependencies of a project.
+type mavenProject struct {
+	Dependencies []mavenDependency `xml:"dependencies>dependency"`
+}
+
+type mavenDependency struct {
+	ArtifactId string `xml:"artifactId"`
+}
+
 func (jd *javaDetector) Language() Language {
 	return Java
 }
@@ -16,11 +29,49 @@ func (jd *javaDetector) Language() Language {
 func (jd *javaDetector) DetectProject(ctx context.Context, path string, entries []fs.DirEntry) (*Project, error) {
 	for _, entry := range entries {
 		if strings.ToLower(entry.Name()) == "pom.xml" {
-			return &Project{
+			project := &Project{
 				Language:      Java,
 				Path:          path,
 				DetectionRule: "Inferred by presence of: " + entry.Name(),
-			}, nil
+			}
+
+			contents, err := os.ReadFile(filepath.Join(path, entry.Name()))
+			if err != nil {
+				return nil, err
+			}
+
+			var pom mavenProject
+			if err := xml.Unmarshal(contents, &pom); err != nil {
+				// the project is still detected, only its dependencies are unknown
+				log.Printf("error parsing %s: %v", filepath.Join(path, entry.Name()), err)
+			}
+
+			libraryDeps := newLibraryDeps()
+			for _, dep := range pom.Dependencies {
+				switch dep.ArtifactId {
+				case "mysql-connector-java", "mysql-connector-j":
+					libraryDeps.addDatabase(DbMySql)
+				case "postgresql":
+					libraryDeps.addDatabase(DbPostgres)
+				case "mongodb-driver-sync", "mongodb-driver-reactivestreams", "spring-boot-starter-data-mongodb":
+					libraryDeps.addDatabase(DbMongo)
+				case "mssql-jdbc":
+					libraryDeps.addDatabase(DbSqlServer)
+				case "jedis", "lettuce-core", "spring-boot-starter-data-redis":
+					libraryDeps.addDatabase(DbRedis)
+				case "azure-messaging-servicebus", "spring-cloud-azure-starter-servicebus",
+					"spring-cloud-azure-starter-servicebus-jms":
+					libraryDeps.addAzure(AzureDepServiceBus)
+				case "azure-messaging-eventhubs", "spring-cloud-azure-starter-eventhubs":
+					libraryDeps.addAzure(AzureDepEventHubs)
+				case "azure-storage-blob", "azure-storage-queue", "azure-data-tables",
+					"spring-cloud-azure-starter-storage-blob", "spring-cloud-azure-starter-storage-queue":
+					libraryDeps.addAzure(AzureDepStorage)
+				}
+			}
+
+			libraryDeps.apply(project)
+			return project, nil
 		}
 	}
 
@@ -8,7 +8,6 @@ import (
 	"path/filepath"
 	"strings"
 
-	"golang.org/x/exp/maps"
 	"golang.org/x/exp/slices"
 )
 
@@ -44,7 +43,7 @@ func (nd *javaScriptDetector) DetectProject(ctx context.Context, path string, en
 			}
 
 			angularAdded := false
-			databaseDepMap := map[DatabaseDep]struct{}{}
+			libraryDeps := newLibraryDeps()
 
 			for dep := range packagesJson.Dependencies {
 				switch dep {
@@ -62,25 +61,29 @@ func (nd *javaScriptDetector) DetectProject(ctx context.Context, path string, en
 				}
 
 				switch dep {
-				case "mysql":
-					databaseDepMap[DbMySql] = struct{}{}
+				case "mysql", "mysql2":
+					libraryDeps.addDatabase(DbMySql)
 				case "mongodb", "mongojs", "mongoose":
-					databaseDepMap[DbMongo] = struct{}{}
+					libraryDeps.addDatabase(DbMongo)
 				case "pg", "pg-promise":
-					databaseDepMap[DbPostgres] = struct{}{}
-				case "tedious":
-					databaseDepMap[DbSqlServer] = struct{}{}
-				case "redis", "redis-om":
-					databaseDepMap[DbRedis] = struct{}{}
+					libraryDeps.addDatabase(DbPostgres)
+				case "tedious", "mssql":
+					libraryDeps.addDatabase(DbSqlServer)
+				case "redis", "redis-om", "ioredis":
+					libraryDeps.addDatabase(DbRedis)
+				case "@azure/service-bus":
+					libraryDeps.addAzure(AzureDepServiceBus)
+				case "@azure/event-hubs":
+					libraryDeps.addAzure(AzureDepEventHubs)
+				case "@azure/storage-blob",
+					"@azure/storage-queue",
+					"@azure/storage-file-datalake",
+					"@azure/data-tables":
+					libraryDeps.addAzure(AzureDepStorage)
 				}
 			}
 
-			if len(databaseDepMap) > 0 {
-				project.DatabaseDeps = maps.Keys(databaseDepMap)
-				slices.SortFunc(project.DatabaseDeps, func(a, b DatabaseDep) bool {
-					return string(a) < string(b)
-				})
-			}
+			libraryDeps.apply(project)
 
 			slices.SortFunc(project.Dependencies, func(a, b Dependency) bool {
 				return string(a) < string(b)
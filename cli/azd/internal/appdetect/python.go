@@ -8,7 +8,6 @@ import (
 	"path/filepath"
 	"strings"
 
-	"golang.org/x/exp/maps"
 	"golang.org/x/exp/slices"
 )
 
@@ -35,17 +34,18 @@ func (pd *pythonDetector) DetectProject(ctx context.Context, path string, entrie
 			}
 
 			scanner := bufio.NewScanner(file)
-			databaseDepMap := map[DatabaseDep]struct{}{}
+			libraryDeps := newLibraryDeps()
 
 			for scanner.Scan() {
-				split := strings.Split(scanner.Text(), "==")
-				if len(split) < 1 {
-					continue
+				// the name of the requirement ends at its extras, version specifier or environment marker
+				name, _, _ := strings.Cut(scanner.Text(), "#")
+				if i := strings.IndexAny(name, "[<>=!~;@ "); i >= 0 {
+					name = name[:i]
 				}
 
 				// pip is case insensitive: PEP 426
 				// https://peps.python.org/pep-0426/#name
-				module := strings.ToLower(strings.TrimSpace(split[0]))
+				module := strings.ToLower(strings.TrimSpace(name))
 				switch module {
 				case "fastapi":
 					project.Dependencies = append(project.Dependencies, PyFastApi)
@@ -59,21 +59,36 @@ func (pd *pythonDetector) DetectProject(ctx context.Context, path string, entrie
 				case "flask_mysqldb",
 					"mysqlclient",
 					"aiomysql",
-					"asyncmy":
-					databaseDepMap[DbMySql] = struct{}{}
+					"asyncmy",
+					"pymysql",
+					"mysql-connector-python":
+					libraryDeps.addDatabase(DbMySql)
 				case "psycopg2",
 					"psycopg2-binary",
 					"psycopg",
 					"psycopgbinary",
 					"asyncpg",
 					"aiopg":
-					databaseDepMap[DbPostgres] = struct{}{}
+					libraryDeps.addDatabase(DbPostgres)
 				case "pymongo",
 					"beanie",
 					"motor":
-					databaseDepMap[DbMongo] = struct{}{}
+					libraryDeps.addDatabase(DbMongo)
+				case "pyodbc",
+					"pymssql",
+					"mssql-django":
+					libraryDeps.addDatabase(DbSqlServer)
 				case "redis", "redis-om":
-					databaseDepMap[DbRedis] = struct{}{}
+					libraryDeps.addDatabase(DbRedis)
+				case "azure-servicebus":
+					libraryDeps.addAzure(AzureDepServiceBus)
+				case "azure-eventhub":
+					libraryDeps.addAzure(AzureDepEventHubs)
+				case "azure-storage-blob",
+					"azure-storage-queue",
+					"azure-storage-file-datalake",
+					"azure-data-tables":
+					libraryDeps.addAzure(AzureDepStorage)
 				}
 			}
 
@@ -81,12 +96,7 @@ func (pd *pythonDetector) DetectProject(ctx context.Context, path string, entrie
 				return nil, err
 			}
 
-			if len(databaseDepMap) > 0 {
-				project.DatabaseDeps = maps.Keys(databaseDepMap)
-				slices.SortFunc(project.DatabaseDeps, func(a, b DatabaseDep) bool {
-					return string(a) < string(b)
-				})
-			}
+			libraryDeps.apply(project)
 
 			slices.SortFunc(project.Dependencies, func(a, b Dependency) bool {
 				return string(a) < string(b)
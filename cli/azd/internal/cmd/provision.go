@@ -2,9 +2,11 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -27,6 +29,9 @@ import (
 type ProvisionFlags struct {
 	noProgress            bool
 	preview               bool
+	planOut               string
+	fromPlan              string
+	targets               []string
 	ignoreDeploymentState bool
 	global                *internal.GlobalCommandOptions
 	*internal.EnvFlag
@@ -52,6 +57,21 @@ func (i *ProvisionFlags) BindNonCommon(local *pflag.FlagSet, global *internal.Gl
 
 func (i *ProvisionFlags) bindCommon(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
 	local.BoolVar(&i.preview, "preview", false, "Preview changes to Azure resources.")
+	local.StringVar(
+		&i.planOut,
+		"out",
+		"",
+		"Save the plan created by --preview to a file, so it can be reviewed and applied later with --from-plan.")
+	local.StringVar(
+		&i.fromPlan,
+		"from-plan",
+		"",
+		"Apply a plan saved with --preview --out. Fails when the infrastructure changed since the plan was created.")
+	local.StringArrayVar(
+		&i.targets,
+		"target",
+		nil,
+		"Provision only the given module or resource. Can be specified multiple times.")
 	local.BoolVar(
 		&i.ignoreDeploymentState,
 		"no-state",
@@ -158,6 +178,16 @@ func (p *ProvisionAction) Run(ctx context.Context) (*actions.ActionResult, error
 	}
 	previewMode := p.flags.preview
 
+	if p.flags.planOut != "" && !previewMode {
+		return nil, errors.New("'--out' can only be used together with '--preview'")
+	}
+	if p.flags.fromPlan != "" && previewMode {
+		return nil, errors.New("'--from-plan' can't be used together with '--preview'")
+	}
+	if p.flags.fromPlan != "" && len(p.flags.targets) > 0 {
+		return nil, errors.New("'--target' can't be used together with '--from-plan', set the targets when saving the plan")
+	}
+
 	// Command title
 	defaultTitle := "Provisioning Azure resources (azd provision)"
 	defaultTitleNote := "Provisioning Azure resources can take some time"
@@ -185,6 +215,18 @@ func (p *ProvisionAction) Run(ctx context.Context) (*actions.ActionResult, error
 
 	infraOptions := infra.Options
 	infraOptions.IgnoreDeploymentState = p.flags.ignoreDeploymentState
+	infraOptions.Targets = p.flags.targets
+	// plan files are resolved from the working directory, as the providers run tools from the infra directory
+	if p.flags.planOut != "" {
+		if infraOptions.PlanOutputPath, err = filepath.Abs(p.flags.planOut); err != nil {
+			return nil, fmt.Errorf("resolving plan output path: %w", err)
+		}
+	}
+	if p.flags.fromPlan != "" {
+		if infraOptions.FromPlan, err = filepath.Abs(p.flags.fromPlan); err != nil {
+			return nil, fmt.Errorf("resolving plan path: %w", err)
+		}
+	}
 	if err := p.provisionManager.Initialize(ctx, p.projectConfig.Path, infraOptions); err != nil {
 		return nil, fmt.Errorf("initializing provisioning manager: %w", err)
 	}
@@ -281,6 +323,13 @@ func (p *ProvisionAction) Run(ctx context.Context) (*actions.ActionResult, error
 
 	if previewMode {
 		p.console.MessageUxItem(ctx, deployResultToUx(deployPreviewResult))
+		if infraOptions.PlanOutputPath != "" {
+			p.console.Message(ctx, fmt.Sprintf(
+				"Plan saved to %s. Run %s to apply it.",
+				output.WithHighLightFormat(infraOptions.PlanOutputPath),
+				output.WithHighLightFormat("azd provision --from-plan %s", p.flags.planOut),
+			))
+		}
 
 		return &actions.ActionResult{
 			Message: &actions.ResultMessage{
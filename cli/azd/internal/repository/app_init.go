@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"text/template"
 	"time"
 
 	"github.com/azure/azure-dev/cli/azd/internal"
@@ -17,6 +18,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/apphost"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
@@ -34,17 +36,33 @@ var languageMap = map[appdetect.Language]project.ServiceLanguageKind{
 }
 
 var dbMap = map[appdetect.DatabaseDep]struct{}{
-	appdetect.DbMongo:    {},
-	appdetect.DbPostgres: {},
-	appdetect.DbRedis:    {},
+	appdetect.DbMongo:     {},
+	appdetect.DbPostgres:  {},
+	appdetect.DbMySql:     {},
+	appdetect.DbSqlServer: {},
+	appdetect.DbRedis:     {},
+}
+
+var azureDepMap = map[appdetect.AzureDep]struct{}{
+	appdetect.AzureDepServiceBus: {},
+	appdetect.AzureDepEventHubs:  {},
+	appdetect.AzureDepStorage:    {},
+}
+
+var hostMap = map[scaffold.HostKind]project.ServiceTargetKind{
+	scaffold.HostContainerApp: project.ContainerAppTarget,
+	scaffold.HostAppService:   project.AppServiceTarget,
+	scaffold.HostFunction:     project.AzureFunctionTarget,
 }
 
 var ErrNoServicesDetected = errors.New("no services detected in the current directory")
 
-// InitFromApp initializes the infra directory and project file from the current existing app.
+// InitFromApp initializes the infra directory and project file from the current existing app. The infrastructure files
+// are generated for the given provider, which defaults to bicep.
 func (i *Initializer) InitFromApp(
 	ctx context.Context,
 	azdCtx *azdcontext.AzdContext,
+	infraProvider provisioning.ProviderKind,
 	initializeEnv func() (*environment.Environment, error)) error {
 	i.console.Message(ctx, "")
 	title := "Scanning app code in current directory"
@@ -219,11 +237,21 @@ func (i *Initializer) InitFromApp(
 			filepath.Base(azdCtx.ProjectDirectory()),
 			appHostManifests[appHost.Path],
 			appHost.Path,
+			string(infraProvider),
 		)
 		if err != nil {
 			return err
 		}
 
+		warnings, err := apphost.Warnings(appHostManifests[appHost.Path])
+		if err != nil {
+			return err
+		}
+
+		for _, warning := range warnings {
+			i.console.Message(ctx, output.WithWarningFormat("WARNING: %s", warning))
+		}
+
 		staging, err := os.MkdirTemp("", "azd-infra")
 		if err != nil {
 			return fmt.Errorf("mkdir temp: %w", err)
@@ -299,7 +327,7 @@ func (i *Initializer) InitFromApp(
 	tracing.SetUsageAttributes(fields.AppInitLastStep.String("generate"))
 
 	i.console.Message(ctx, "\n"+output.WithBold("Generating files to run your app on Azure:")+"\n")
-	err = i.genProjectFile(ctx, azdCtx, detect)
+	err = i.genProjectFile(ctx, azdCtx, detect, infraProvider)
 	if err != nil {
 		return err
 	}
@@ -315,12 +343,22 @@ func (i *Initializer) InitFromApp(
 	}
 
 	defer func() { _ = os.RemoveAll(staging) }()
-	t, err := scaffold.Load()
-	if err != nil {
-		return fmt.Errorf("loading scaffold templates: %w", err)
-	}
+	var t *template.Template
+	if infraProvider == provisioning.Terraform {
+		t, err = scaffold.LoadTerraform()
+		if err != nil {
+			return fmt.Errorf("loading scaffold templates: %w", err)
+		}
 
-	err = scaffold.ExecInfra(t, spec, staging)
+		err = scaffold.ExecInfraTerraform(t, spec, staging)
+	} else {
+		t, err = scaffold.Load()
+		if err != nil {
+			return fmt.Errorf("loading scaffold templates: %w", err)
+		}
+
+		err = scaffold.ExecInfra(t, spec, staging)
+	}
 	if err != nil {
 		return err
 	}
@@ -361,14 +399,15 @@ func (i *Initializer) InitFromApp(
 func (i *Initializer) genProjectFile(
 	ctx context.Context,
 	azdCtx *azdcontext.AzdContext,
-	detect detectConfirm) error {
+	detect detectConfirm,
+	infraProvider provisioning.ProviderKind) error {
 	title := "Generating " + output.WithHighLightFormat("./"+azdcontext.ProjectFileName)
 
 	i.console.ShowSpinner(ctx, title, input.Step)
 	var err error
 	defer i.console.StopSpinner(ctx, title, input.GetStepResultFormat(err))
 
-	config, err := prjConfigFromDetect(azdCtx.ProjectDirectory(), detect)
+	config, err := prjConfigFromDetect(azdCtx.ProjectDirectory(), detect, infraProvider)
 	if err != nil {
 		return fmt.Errorf("converting config: %w", err)
 	}
@@ -387,12 +426,16 @@ const InitGenTemplateId = "azd-init"
 
 func prjConfigFromDetect(
 	root string,
-	detect detectConfirm) (project.ProjectConfig, error) {
+	detect detectConfirm,
+	infraProvider provisioning.ProviderKind) (project.ProjectConfig, error) {
 	config := project.ProjectConfig{
 		Name: filepath.Base(root),
 		Metadata: &project.ProjectMetadata{
 			Template: fmt.Sprintf("%s@%s", InitGenTemplateId, internal.VersionInfo().Version),
 		},
+		Infra: provisioning.Options{
+			Provider: infraProvider,
+		},
 		Services: map[string]*project.ServiceConfig{},
 	}
 	for _, prj := range detect.Services {
@@ -402,7 +445,7 @@ func prjConfigFromDetect(
 		}
 
 		svc := project.ServiceConfig{}
-		svc.Host = project.ContainerAppTarget
+		svc.Host = hostMap[detect.host(prj)]
 		svc.RelativePath = rel
 
 		language, supported := languageMap[prj.Language]
@@ -411,7 +454,7 @@ func prjConfigFromDetect(
 		}
 		svc.Language = language
 
-		if prj.Docker != nil {
+		if prj.Docker != nil && svc.Host == project.ContainerAppTarget {
 			relDocker, err := filepath.Rel(prj.Path, prj.Docker.Path)
 			if err != nil {
 				return project.ProjectConfig{}, err
@@ -10,6 +10,7 @@ import (
 
 	"github.com/AlecAivazis/survey/v2/terminal"
 	"github.com/azure/azure-dev/cli/azd/internal/appdetect"
+	"github.com/azure/azure-dev/cli/azd/internal/scaffold"
 	"github.com/azure/azure-dev/cli/azd/internal/tracing"
 	"github.com/azure/azure-dev/cli/azd/internal/tracing/fields"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
@@ -40,11 +41,23 @@ const (
 	EntryKindModified EntryKind = "modified"
 )
 
-// detectConfirm handles prompting for confirming the detected services and databases
+// hostKinds are the hosts that can be selected for a service, in display order.
+var hostKinds = []scaffold.HostKind{
+	scaffold.HostContainerApp,
+	scaffold.HostAppService,
+	scaffold.HostFunction,
+}
+
+// detectConfirm handles prompting for confirming the detected services, databases and Azure services
 type detectConfirm struct {
-	// detected services and databases
+	// detected services, databases and Azure services
 	Services  []appdetect.Project
 	Databases map[appdetect.DatabaseDep]EntryKind
+	AzureDeps map[appdetect.AzureDep]EntryKind
+
+	// the selected host of services, by the path of the service. Services without a selected host are hosted by
+	// Azure Container Apps.
+	Hosts map[string]scaffold.HostKind
 
 	// the root directory of the project
 	root string
@@ -57,6 +70,8 @@ type detectConfirm struct {
 // Init initializes state from initial detection output
 func (d *detectConfirm) Init(projects []appdetect.Project, root string) {
 	d.Databases = make(map[appdetect.DatabaseDep]EntryKind)
+	d.AzureDeps = make(map[appdetect.AzureDep]EntryKind)
+	d.Hosts = make(map[string]scaffold.HostKind)
 	d.Services = make([]appdetect.Project, 0, len(projects))
 	d.modified = false
 	d.root = root
@@ -71,6 +86,12 @@ func (d *detectConfirm) Init(projects []appdetect.Project, root string) {
 				d.Databases[dbType] = EntryKindDetected
 			}
 		}
+
+		for _, azureDep := range project.AzureDeps {
+			if _, supported := azureDepMap[azureDep]; supported {
+				d.AzureDeps[azureDep] = EntryKindDetected
+			}
+		}
 	}
 
 	d.captureUsage(
@@ -112,6 +133,7 @@ func (d *detectConfirm) Confirm(ctx context.Context) error {
 				"Confirm and continue initializing my app",
 				"Remove a detected service",
 				"Add an undetected service",
+				"Change the Azure host of a service",
 			},
 		})
 		if err != nil {
@@ -142,6 +164,13 @@ func (d *detectConfirm) Confirm(ctx context.Context) error {
 			}
 
 			tracing.IncrementUsageAttribute(fields.AppInitModifyAddCount.Int(1))
+		case 3:
+			if err := d.changeHost(ctx); err != nil {
+				if errors.Is(err, terminal.InterruptErr) {
+					continue
+				}
+				return err
+			}
 		}
 	}
 }
@@ -161,6 +190,7 @@ func (d *detectConfirm) render(ctx context.Context) error {
 
 	recommendedServices := []string{}
 	for _, svc := range d.Services {
+		host := d.host(svc)
 		status := ""
 		if svc.DetectionRule == string(EntryKindModified) {
 			status = " " + output.WithSuccessFormat("[Updated]")
@@ -170,10 +200,11 @@ func (d *detectConfirm) render(ctx context.Context) error {
 
 		d.console.Message(ctx, "  "+color.BlueString(projectDisplayName(svc))+status)
 		d.console.Message(ctx, "  "+"Detected in: "+output.WithHighLightFormat(relSafe(d.root, svc.Path)))
+		d.console.Message(ctx, "  "+"Hosted by: "+output.WithHighLightFormat(host.Display()))
 		d.console.Message(ctx, "")
 
-		if len(recommendedServices) == 0 {
-			recommendedServices = append(recommendedServices, "Azure Container Apps")
+		if !slices.Contains(recommendedServices, host.Display()) {
+			recommendedServices = append(recommendedServices, host.Display())
 		}
 	}
 
@@ -183,18 +214,22 @@ func (d *detectConfirm) render(ctx context.Context) error {
 			recommendedServices = append(recommendedServices, "Azure Database for PostgreSQL flexible server")
 		case appdetect.DbMongo:
 			recommendedServices = append(recommendedServices, "Azure CosmosDB API for MongoDB")
+		case appdetect.DbMySql:
+			recommendedServices = append(recommendedServices, "Azure Database for MySQL flexible server")
+		case appdetect.DbSqlServer:
+			recommendedServices = append(recommendedServices, "Azure SQL Database")
 		case appdetect.DbRedis:
-			recommendedServices = append(recommendedServices, "Azure Container Apps Redis add-on")
+			recommendedServices = append(recommendedServices, "Azure Cache for Redis")
 		}
 
-		status := ""
-		if entry == EntryKindModified {
-			status = " " + output.WithSuccessFormat("[Updated]")
-		} else if entry == EntryKindManual {
-			status = " " + output.WithSuccessFormat("[Added]")
-		}
+		d.console.Message(ctx, "  "+color.BlueString(db.Display())+entryStatus(entry))
+		d.console.Message(ctx, "")
+	}
+
+	for azureDep, entry := range d.AzureDeps {
+		recommendedServices = append(recommendedServices, azureDep.Display())
 
-		d.console.Message(ctx, "  "+color.BlueString(db.Display())+status)
+		d.console.Message(ctx, "  "+color.BlueString(azureDep.Display())+entryStatus(entry))
 		d.console.Message(ctx, "")
 	}
 
@@ -212,8 +247,20 @@ func (d *detectConfirm) render(ctx context.Context) error {
 	return nil
 }
 
+// entryStatus returns the status displayed next to a database or Azure service that was modified or added.
+func entryStatus(entry EntryKind) string {
+	switch entry {
+	case EntryKindModified:
+		return " " + output.WithSuccessFormat("[Updated]")
+	case EntryKindManual:
+		return " " + output.WithSuccessFormat("[Added]")
+	}
+
+	return ""
+}
+
 func (d *detectConfirm) remove(ctx context.Context) error {
-	modifyOptions := make([]string, 0, len(d.Services)+len(d.Databases))
+	modifyOptions := make([]string, 0, len(d.Services)+len(d.Databases)+len(d.AzureDeps))
 	for _, svc := range d.Services {
 		modifyOptions = append(
 			modifyOptions, fmt.Sprintf("%s in %s", projectDisplayName(svc), relSafe(d.root, svc.Path)))
@@ -224,6 +271,11 @@ func (d *detectConfirm) remove(ctx context.Context) error {
 		modifyOptions = append(modifyOptions, db.Display())
 	}
 
+	displayAzureDeps := maps.Keys(d.AzureDeps)
+	for _, azureDep := range displayAzureDeps {
+		modifyOptions = append(modifyOptions, azureDep.Display())
+	}
+
 	i, err := d.console.Select(ctx, input.ConsoleOptions{
 		Message: "Select the service you want to remove",
 		Options: modifyOptions,
@@ -247,6 +299,7 @@ func (d *detectConfirm) remove(ctx context.Context) error {
 		}
 
 		d.Services = append(d.Services[:i], d.Services[i+1:]...)
+		delete(d.Hosts, svc.Path)
 		d.modified = true
 	} else if i < len(d.Services)+len(d.Databases) {
 		db := displayDbs[i-len(d.Services)]
@@ -276,6 +329,30 @@ func (d *detectConfirm) remove(ctx context.Context) error {
 			}
 		}
 		d.modified = true
+	} else {
+		azureDep := displayAzureDeps[i-len(d.Services)-len(d.Databases)]
+
+		confirm, err := d.console.Confirm(ctx, input.ConsoleOptions{
+			Message: fmt.Sprintf(
+				"Remove %s?", azureDep.Display()),
+		})
+		if err != nil {
+			return err
+		}
+
+		if !confirm {
+			return nil
+		}
+
+		delete(d.AzureDeps, azureDep)
+
+		for i := range d.Services {
+			if idx := slices.Index(d.Services[i].AzureDeps, azureDep); idx >= 0 {
+				d.Services[i].AzureDeps = slices.Delete(d.Services[i].AzureDeps, idx, idx+1)
+				d.Services[i].DetectionRule = string(EntryKindModified)
+			}
+		}
+		d.modified = true
 	}
 
 	return nil
@@ -304,8 +381,21 @@ func (d *detectConfirm) add(ctx context.Context) error {
 		return a.Display() < b.Display()
 	})
 
-	selections := make([]string, 0, len(languages)+len(frameworks)+len(databases))
-	entries := make([]any, 0, len(languages)+len(frameworks)+len(databases))
+	// only include Azure services not already added
+	allAzureDeps := maps.Keys(azureDepMap)
+	azureDeps := make([]appdetect.AzureDep, 0, len(allAzureDeps))
+	for _, azureDep := range allAzureDeps {
+		if _, ok := d.AzureDeps[azureDep]; !ok {
+			azureDeps = append(azureDeps, azureDep)
+		}
+	}
+	slices.SortFunc(azureDeps, func(a, b appdetect.AzureDep) bool {
+		return a.Display() < b.Display()
+	})
+
+	count := len(languages) + len(frameworks) + len(databases) + len(azureDeps)
+	selections := make([]string, 0, count)
+	entries := make([]any, 0, count)
 
 	for _, lang := range languages {
 		selections = append(selections, fmt.Sprintf("%s\t%s", lang.Display(), "[Language]"))
@@ -322,6 +412,11 @@ func (d *detectConfirm) add(ctx context.Context) error {
 		entries = append(entries, db)
 	}
 
+	for _, azureDep := range azureDeps {
+		selections = append(selections, fmt.Sprintf("%s\t%s", azureDep.Display(), "[Azure service]"))
+		entries = append(entries, azureDep)
+	}
+
 	// only apply tab-align if interactive
 	if d.console.IsSpinnerInteractive() {
 		formatted, err := tabWrite(selections, 3)
@@ -333,7 +428,7 @@ func (d *detectConfirm) add(ctx context.Context) error {
 	}
 
 	i, err := d.console.Select(ctx, input.ConsoleOptions{
-		Message: "Select a language or database to add",
+		Message: "Select a language, database or Azure service to add",
 		Options: selections,
 	})
 	if err != nil {
@@ -372,6 +467,28 @@ func (d *detectConfirm) add(ctx context.Context) error {
 		d.Services[idx].DetectionRule = string(EntryKindModified)
 		d.modified = true
 		return nil
+	case appdetect.AzureDep:
+		azureDep := entries[i].(appdetect.AzureDep)
+		d.AzureDeps[azureDep] = EntryKindManual
+
+		svcSelect := make([]string, 0, len(d.Services))
+		for _, svc := range d.Services {
+			svcSelect = append(svcSelect,
+				fmt.Sprintf("%s in %s", projectDisplayName(svc), filepath.Base(svc.Path)))
+		}
+
+		idx, err := d.console.Select(ctx, input.ConsoleOptions{
+			Message: "Select the service that uses " + azureDep.Display(),
+			Options: svcSelect,
+		})
+		if err != nil {
+			return err
+		}
+
+		d.Services[idx].AzureDeps = append(d.Services[idx].AzureDeps, azureDep)
+		d.Services[idx].DetectionRule = string(EntryKindModified)
+		d.modified = true
+		return nil
 	default:
 		log.Panic("unhandled entry type")
 	}
@@ -414,3 +531,53 @@ func (d *detectConfirm) add(ctx context.Context) error {
 	d.modified = true
 	return nil
 }
+
+// host returns the host of the service.
+func (d *detectConfirm) host(svc appdetect.Project) scaffold.HostKind {
+	if host, has := d.Hosts[svc.Path]; has {
+		return host
+	}
+
+	return scaffold.HostContainerApp
+}
+
+// changeHost prompts for a service, and the Azure host of the service.
+func (d *detectConfirm) changeHost(ctx context.Context) error {
+	svcSelect := make([]string, 0, len(d.Services))
+	for _, svc := range d.Services {
+		svcSelect = append(svcSelect,
+			fmt.Sprintf("%s in %s (%s)", projectDisplayName(svc), relSafe(d.root, svc.Path), d.host(svc).Display()))
+	}
+
+	idx, err := d.console.Select(ctx, input.ConsoleOptions{
+		Message: "Select the service you want to change the host of",
+		Options: svcSelect,
+	})
+	if err != nil {
+		return err
+	}
+
+	svc := d.Services[idx]
+	hostSelect := make([]string, 0, len(hostKinds))
+	for _, host := range hostKinds {
+		hostSelect = append(hostSelect, host.Display())
+	}
+
+	hostIdx, err := d.console.Select(ctx, input.ConsoleOptions{
+		Message:      fmt.Sprintf("Select the Azure host for %s in %s", projectDisplayName(svc), relSafe(d.root, svc.Path)),
+		Options:      hostSelect,
+		DefaultValue: d.host(svc).Display(),
+	})
+	if err != nil {
+		return err
+	}
+
+	if hostKinds[hostIdx] == d.host(svc) {
+		return nil
+	}
+
+	d.Hosts[svc.Path] = hostKinds[hostIdx]
+	d.Services[idx].DetectionRule = string(EntryKindModified)
+	d.modified = true
+	return nil
+}
@@ -9,7 +9,10 @@ import (
 	"testing"
 
 	"github.com/azure/azure-dev/cli/azd/internal/appdetect"
+	"github.com/azure/azure-dev/cli/azd/internal/scaffold"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
 	"github.com/azure/azure-dev/cli/azd/test/ostest"
 	"github.com/stretchr/testify/require"
 )
@@ -161,6 +164,57 @@ func Test_detectConfirm_confirm(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "add an azure service",
+			detection: []appdetect.Project{
+				{
+					Language: appdetect.DotNet,
+					Path:     dotNetDir,
+				},
+			},
+			interactions: []string{
+				"Add an undetected service",
+				fmt.Sprintf("%s\t%s", appdetect.AzureDepServiceBus.Display(), "[Azure service]"),
+				fmt.Sprintf("%s in %s", appdetect.DotNet.Display(), "dotnet-dir"),
+				"Confirm and continue initializing my app",
+			},
+			want: []appdetect.Project{
+				{
+					Language: appdetect.DotNet,
+					Path:     dotNetDir,
+					AzureDeps: []appdetect.AzureDep{
+						appdetect.AzureDepServiceBus,
+					},
+					DetectionRule: string(EntryKindModified),
+				},
+			},
+		},
+		{
+			name: "remove an azure service",
+			detection: []appdetect.Project{
+				{
+					Language: appdetect.DotNet,
+					Path:     dotNetDir,
+					AzureDeps: []appdetect.AzureDep{
+						appdetect.AzureDepStorage,
+					},
+				},
+			},
+			interactions: []string{
+				"Remove a detected service",
+				appdetect.AzureDepStorage.Display(),
+				"y",
+				"Confirm and continue initializing my app",
+			},
+			want: []appdetect.Project{
+				{
+					Language:      appdetect.DotNet,
+					Path:          dotNetDir,
+					AzureDeps:     []appdetect.AzureDep{},
+					DetectionRule: string(EntryKindModified),
+				},
+			},
+		},
 		{
 			name: "remove a database",
 			detection: []appdetect.Project{
@@ -216,3 +270,55 @@ func Test_detectConfirm_confirm(t *testing.T) {
 		})
 	}
 }
+
+func Test_detectConfirm_changeHost(t *testing.T) {
+	dir := t.TempDir()
+	pyDir := filepath.Join(dir, "py")
+	jsDir := filepath.Join(dir, "js")
+
+	d := &detectConfirm{
+		console: input.NewConsole(
+			false,
+			false,
+			input.Writers{Output: os.Stdout},
+			input.ConsoleHandles{
+				Stderr: os.Stderr,
+				Stdin: strings.NewReader(strings.Join([]string{
+					"Change the Azure host of a service",
+					"Python in js (Azure Container Apps)",
+					"Azure Functions",
+					"Confirm and continue initializing my app",
+				}, "\n") + "\n"),
+				Stdout: os.Stdout,
+			},
+			nil,
+			nil),
+	}
+	d.Init([]appdetect.Project{
+		{
+			Language: appdetect.Python,
+			Path:     pyDir,
+		},
+		{
+			Language: appdetect.Python,
+			Path:     jsDir,
+		},
+	}, dir)
+
+	err := d.Confirm(context.Background())
+
+	// Print extra newline to avoid mangling `go test -v` final test result output while waiting for final stdin,
+	// which may result in incorrect `gotestsum` reporting
+	fmt.Println()
+
+	require.NoError(t, err)
+	require.Equal(t, scaffold.HostContainerApp, d.host(d.Services[0]))
+	require.Equal(t, scaffold.HostFunction, d.host(d.Services[1]))
+	require.Equal(t, string(EntryKindModified), d.Services[1].DetectionRule)
+
+	config, err := prjConfigFromDetect(dir, *d, provisioning.Terraform)
+	require.NoError(t, err)
+	require.Equal(t, project.ContainerAppTarget, config.Services["py"].Host)
+	require.Equal(t, project.AzureFunctionTarget, config.Services["js"].Host)
+	require.Equal(t, provisioning.Terraform, config.Infra.Provider)
+}
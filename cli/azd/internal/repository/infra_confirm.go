@@ -14,6 +14,15 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
 )
 
+// runtimeMap maps languages to the default runtime of services hosted by App Service or Functions.
+var runtimeMap = map[appdetect.Language]scaffold.Runtime{
+	appdetect.DotNet:     {Name: "dotnet", Version: "8.0"},
+	appdetect.Java:       {Name: "java", Version: "17"},
+	appdetect.JavaScript: {Name: "node", Version: "20"},
+	appdetect.TypeScript: {Name: "node", Version: "20"},
+	appdetect.Python:     {Name: "python", Version: "3.11"},
+}
+
 // A regex that matches against "likely" well-formed database names
 var wellFormedDbNameRegex = regexp.MustCompile(`^[a-zA-Z\-_0-9]*$`)
 
@@ -25,6 +34,7 @@ func (i *Initializer) infraSpecFromDetect(
 	spec := scaffold.InfraSpec{}
 	for database := range detect.Databases {
 		if database == appdetect.DbRedis { // no configuration needed for redis
+			spec.DbRedis = &scaffold.DatabaseRedis{}
 			continue
 		}
 
@@ -88,20 +98,60 @@ func (i *Initializer) infraSpecFromDetect(
 				spec.DbPostgres = &scaffold.DatabasePostgres{
 					DatabaseName: dbName,
 				}
+			case appdetect.DbMySql:
+				if dbName == "" {
+					i.console.Message(ctx, "Database name is required.")
+					continue
+				}
+
+				spec.DbMySql = &scaffold.DatabaseMySql{
+					DatabaseName: dbName,
+				}
+			case appdetect.DbSqlServer:
+				if dbName == "" {
+					i.console.Message(ctx, "Database name is required.")
+					continue
+				}
+
+				spec.DbSqlServer = &scaffold.DatabaseSqlServer{
+					DatabaseName: dbName,
+				}
 			}
 			break dbPrompt
 		}
 	}
 
+	for azureDep := range detect.AzureDeps {
+		switch azureDep {
+		case appdetect.AzureDepServiceBus:
+			spec.ServiceBus = &scaffold.ServiceBus{}
+		case appdetect.AzureDepEventHubs:
+			spec.EventHubs = &scaffold.EventHubs{}
+		case appdetect.AzureDepStorage:
+			spec.StorageAccount = &scaffold.StorageAccount{}
+		}
+	}
+
 	for _, svc := range detect.Services {
 		name := filepath.Base(svc.Path)
 		serviceSpec := scaffold.ServiceSpec{
 			Name: name,
 			Port: -1,
+			Host: detect.Hosts[svc.Path],
+		}
+
+		if serviceSpec.HostKind() != scaffold.HostContainerApp {
+			runtime, supported := runtimeMap[svc.Language]
+			if !supported {
+				return scaffold.InfraSpec{}, fmt.Errorf(
+					"%s is not supported by %s", svc.Language.Display(), serviceSpec.HostKind().Display())
+			}
+
+			serviceSpec.Runtime = &runtime
 		}
 
-		if svc.Docker == nil || svc.Docker.Path == "" {
-			// default builder always specifies port 80
+		if svc.Docker == nil || svc.Docker.Path == "" || serviceSpec.Runtime != nil {
+			// default builder always specifies port 80, App Service and Functions route requests to the port of the app
 			serviceSpec.Port = 80
 		}
 
@@ -126,12 +176,36 @@ func (i *Initializer) infraSpecFromDetect(
 				serviceSpec.DbPostgres = &scaffold.DatabaseReference{
 					DatabaseName: spec.DbPostgres.DatabaseName,
 				}
+			case appdetect.DbMySql:
+				serviceSpec.DbMySql = &scaffold.DatabaseReference{
+					DatabaseName: spec.DbMySql.DatabaseName,
+				}
+			case appdetect.DbSqlServer:
+				serviceSpec.DbSqlServer = &scaffold.DatabaseReference{
+					DatabaseName: spec.DbSqlServer.DatabaseName,
+				}
 			case appdetect.DbRedis:
 				serviceSpec.DbRedis = &scaffold.DatabaseReference{
 					DatabaseName: "redis",
 				}
 			}
 		}
+
+		for _, azureDep := range svc.AzureDeps {
+			// filter out Azure services that were removed
+			if _, ok := detect.AzureDeps[azureDep]; !ok {
+				continue
+			}
+
+			switch azureDep {
+			case appdetect.AzureDepServiceBus:
+				serviceSpec.ServiceBus = spec.ServiceBus
+			case appdetect.AzureDepEventHubs:
+				serviceSpec.EventHubs = spec.EventHubs
+			case appdetect.AzureDepStorage:
+				serviceSpec.StorageAccount = spec.StorageAccount
+			}
+		}
 		spec.Services = append(spec.Services, serviceSpec)
 	}
 
@@ -167,12 +241,14 @@ func (i *Initializer) infraSpecFromDetect(
 		if spec.Services[idx].Frontend == nil && spec.Services[idx].Port != 0 {
 			backends = append(backends, scaffold.ServiceReference{
 				Name: spec.Services[idx].Name,
+				Host: spec.Services[idx].Host,
 			})
 
 			spec.Services[idx].Backend = &scaffold.Backend{}
 		} else {
 			frontends = append(frontends, scaffold.ServiceReference{
 				Name: spec.Services[idx].Name,
+				Host: spec.Services[idx].Host,
 			})
 		}
 	}
@@ -197,6 +197,113 @@ func TestInitializer_infraSpecFromDetect(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "api with mysql, redis and service bus",
+			detect: detectConfirm{
+				Services: []appdetect.Project{
+					{
+						Language: appdetect.Java,
+						Path:     "java",
+						DatabaseDeps: []appdetect.DatabaseDep{
+							appdetect.DbMySql,
+							appdetect.DbRedis,
+						},
+						AzureDeps: []appdetect.AzureDep{
+							appdetect.AzureDepServiceBus,
+							appdetect.AzureDepStorage,
+						},
+					},
+				},
+				Databases: map[appdetect.DatabaseDep]EntryKind{
+					appdetect.DbMySql: EntryKindDetected,
+					appdetect.DbRedis: EntryKindDetected,
+				},
+				AzureDeps: map[appdetect.AzureDep]EntryKind{
+					appdetect.AzureDepServiceBus: EntryKindDetected,
+				},
+			},
+			interactions: []string{
+				"",        // db name is required
+				"myappdb", // fill in db name
+			},
+			want: scaffold.InfraSpec{
+				DbMySql: &scaffold.DatabaseMySql{
+					DatabaseName: "myappdb",
+				},
+				DbRedis:    &scaffold.DatabaseRedis{},
+				ServiceBus: &scaffold.ServiceBus{},
+				Services: []scaffold.ServiceSpec{
+					{
+						Name:    "java",
+						Port:    80,
+						Backend: &scaffold.Backend{},
+						DbMySql: &scaffold.DatabaseReference{
+							DatabaseName: "myappdb",
+						},
+						DbRedis: &scaffold.DatabaseReference{
+							DatabaseName: "redis",
+						},
+						ServiceBus: &scaffold.ServiceBus{},
+					},
+				},
+			},
+		},
+		{
+			name: "api on functions and web on app service",
+			detect: detectConfirm{
+				Services: []appdetect.Project{
+					{
+						Language: appdetect.Python,
+						Path:     "py",
+						Docker:   &appdetect.Docker{Path: "Dockerfile"},
+					},
+					{
+						Language: appdetect.TypeScript,
+						Path:     "ts",
+						Dependencies: []appdetect.Dependency{
+							appdetect.JsReact,
+						},
+					},
+				},
+				Hosts: map[string]scaffold.HostKind{
+					"py": scaffold.HostFunction,
+					"ts": scaffold.HostAppService,
+				},
+			},
+			interactions: []string{},
+			want: scaffold.InfraSpec{
+				Services: []scaffold.ServiceSpec{
+					{
+						Name:    "py",
+						Port:    80,
+						Host:    scaffold.HostFunction,
+						Runtime: &scaffold.Runtime{Name: "python", Version: "3.11"},
+						Backend: &scaffold.Backend{
+							Frontends: []scaffold.ServiceReference{
+								{
+									Name: "ts",
+									Host: scaffold.HostAppService,
+								},
+							},
+						},
+					},
+					{
+						Name:    "ts",
+						Port:    80,
+						Host:    scaffold.HostAppService,
+						Runtime: &scaffold.Runtime{Name: "node", Version: "20"},
+						Frontend: &scaffold.Frontend{
+							Backends: []scaffold.ServiceReference{
+								{
+									Name: "py",
+									Host: scaffold.HostFunction,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
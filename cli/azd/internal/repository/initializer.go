@@ -12,6 +12,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
@@ -21,6 +22,8 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
 	"github.com/azure/azure-dev/cli/azd/pkg/project"
 	"github.com/azure/azure-dev/cli/azd/pkg/templates"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/bicep"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/dotnet"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
 	"github.com/azure/azure-dev/cli/azd/resources"
@@ -31,32 +34,48 @@ import (
 type Initializer struct {
 	console        input.Console
 	gitCli         git.GitCli
+	gitCloner      *templates.GitCloner
 	dotnetCli      dotnet.DotNetCli
 	lazyEnvManager *lazy.Lazy[environment.Manager]
+	azCli          azcli.AzCli
+	lazyBicepCli   *lazy.Lazy[bicep.BicepCli]
+	cloud          *cloud.Cloud
 }
 
 func NewInitializer(
 	console input.Console,
 	gitCli git.GitCli,
+	gitCloner *templates.GitCloner,
 	dotnetCli dotnet.DotNetCli,
 	lazyEnvManager *lazy.Lazy[environment.Manager],
+	azCli azcli.AzCli,
+	lazyBicepCli *lazy.Lazy[bicep.BicepCli],
+	cloud *cloud.Cloud,
 ) *Initializer {
 	return &Initializer{
 		console:        console,
 		gitCli:         gitCli,
+		gitCloner:      gitCloner,
 		lazyEnvManager: lazyEnvManager,
 		dotnetCli:      dotnetCli,
+		azCli:          azCli,
+		lazyBicepCli:   lazyBicepCli,
+		cloud:          cloud,
 	}
 }
 
 // Initializes a local repository in the project directory from a remote repository.
 //
 // A confirmation prompt is displayed for any existing files to be overwritten.
+// templateBranch overrides the ref of the template; only the subdirectory of the template is copied when set.
+// templateValues are `--set` assignments for the parameters declared by the template manifest; values which aren't
+// assigned are prompted for.
 func (i *Initializer) Initialize(
 	ctx context.Context,
 	azdCtx *azdcontext.AzdContext,
 	template *templates.Template,
-	templateBranch string) error {
+	templateBranch string,
+	templateValues map[string]string) error {
 	var err error
 	stepMessage := fmt.Sprintf("Downloading template code to: %s", output.WithLinkFormat("%s", azdCtx.ProjectDirectory()))
 	i.console.ShowSpinner(ctx, stepMessage, input.Step)
@@ -81,12 +100,23 @@ func (i *Initializer) Initialize(
 		return err
 	}
 
-	filesWithExecPerms, err := i.fetchCode(ctx, templateUrl, templateBranch, staging)
+	ref := templateBranch
+	if ref == "" {
+		ref = template.Ref
+	}
+
+	// templateRoot is the subdirectory of the template in staging, staging is kept to remove the whole clone
+	templateRoot, filesWithExecPerms, commit, err := i.fetchCode(ctx, templateUrl, ref, template.Subdirectory, staging)
 	if err != nil {
 		return err
 	}
 
-	skipStagingFiles, err := i.promptForDuplicates(ctx, staging, target)
+	values, filesWithExecPerms, err := i.applyTemplateManifest(ctx, templateRoot, templateValues, filesWithExecPerms)
+	if err != nil {
+		return err
+	}
+
+	skipStagingFiles, err := i.promptForDuplicates(ctx, templateRoot, target)
 	if err != nil {
 		return err
 	}
@@ -107,7 +137,7 @@ func (i *Initializer) Initialize(
 		}
 	}
 
-	if err := copy.Copy(staging, target, options); err != nil {
+	if err := copy.Copy(templateRoot, target, options); err != nil {
 		return fmt.Errorf("copying template contents from temp staging directory: %w", err)
 	}
 
@@ -120,6 +150,17 @@ func (i *Initializer) Initialize(
 		return fmt.Errorf("initializing project: %w", err)
 	}
 
+	templateSource := &project.TemplateSource{
+		Repository:   templateUrl,
+		Ref:          ref,
+		Subdirectory: template.Subdirectory,
+		Commit:       commit,
+		Values:       values,
+	}
+	if err := project.SaveTemplateSource(azdCtx.ProjectPath(), templateSource); err != nil {
+		return fmt.Errorf("recording template source: %w", err)
+	}
+
 	err = i.gitInitialize(ctx, target, filesWithExecPerms, isEmpty)
 	if err != nil {
 		return err
@@ -130,33 +171,210 @@ func (i *Initializer) Initialize(
 	return nil
 }
 
+// fetchCode clones the template into destination and returns the directory of the template, which is the subdirectory
+// of the clone when set, along with its executable files relative to that directory.
 func (i *Initializer) fetchCode(
 	ctx context.Context,
 	templateUrl string,
 	templateBranch string,
-	destination string) (executableFilePaths []string, err error) {
-	err = i.gitCli.ShallowClone(ctx, templateUrl, templateBranch, destination)
+	subdirectory string,
+	destination string) (templateDir string, executableFilePaths []string, commit string, err error) {
+	err = i.gitCloner.ShallowClone(ctx, templateUrl, templateBranch, destination)
 	if err != nil {
-		return nil, fmt.Errorf("fetching template: %w", err)
+		return "", nil, "", fmt.Errorf("fetching template: %w", err)
 	}
 
-	stagedFilesOutput, err := i.gitCli.ListStagedFiles(ctx, destination)
+	commit, err = i.gitCli.GetCommitHash(ctx, destination, "HEAD")
 	if err != nil {
-		return nil, fmt.Errorf("listing files with permissions: %w", err)
+		return "", nil, "", fmt.Errorf("resolving template commit: %w", err)
 	}
 
-	executableFilePaths, err = parseExecutableFiles(stagedFilesOutput)
+	executableFilePaths, err = i.listExecutableFiles(ctx, destination)
 	if err != nil {
-		return nil, fmt.Errorf("parsing file permissions output: %w", err)
+		return "", nil, "", err
 	}
 
 	if err := os.RemoveAll(filepath.Join(destination, ".git")); err != nil {
-		return nil, fmt.Errorf("removing .git folder after clone: %w", err)
+		return "", nil, "", fmt.Errorf("removing .git folder after clone: %w", err)
+	}
+
+	templateDir, executableFilePaths, err = templateSubdirectory(destination, subdirectory, executableFilePaths)
+	if err != nil {
+		return "", nil, "", err
+	}
+
+	return templateDir, executableFilePaths, commit, nil
+}
+
+// templateSubdirectory returns the directory of the template in the repository cloned at repositoryDir, and the
+// executable files of the repository which are in that directory, relative to it.
+func templateSubdirectory(
+	repositoryDir string,
+	subdirectory string,
+	executableFilePaths []string) (string, []string, error) {
+	subdirectory = strings.Trim(filepath.ToSlash(filepath.Clean(subdirectory)), "/")
+	if subdirectory == "" || subdirectory == "." {
+		return repositoryDir, executableFilePaths, nil
+	}
+
+	if subdirectory == ".." || strings.HasPrefix(subdirectory, "../") {
+		return "", nil, fmt.Errorf("template subdirectory '%s' is outside of the repository", subdirectory)
+	}
+
+	templateDir := filepath.Join(repositoryDir, filepath.FromSlash(subdirectory))
+	if info, err := os.Stat(templateDir); err != nil || !info.IsDir() {
+		return "", nil, fmt.Errorf("template subdirectory '%s' was not found in the repository", subdirectory)
+	}
+
+	var templateExecutableFiles []string
+	for _, path := range executableFilePaths {
+		if relative, has := strings.CutPrefix(path, subdirectory+"/"); has {
+			templateExecutableFiles = append(templateExecutableFiles, relative)
+		}
+	}
+
+	return templateDir, templateExecutableFiles, nil
+}
+
+func (i *Initializer) listExecutableFiles(ctx context.Context, repositoryPath string) ([]string, error) {
+	stagedFilesOutput, err := i.gitCli.ListStagedFiles(ctx, repositoryPath)
+	if err != nil {
+		return nil, fmt.Errorf("listing files with permissions: %w", err)
+	}
+
+	executableFilePaths, err := parseExecutableFiles(stagedFilesOutput)
+	if err != nil {
+		return nil, fmt.Errorf("parsing file permissions output: %w", err)
 	}
 
 	return executableFilePaths, nil
 }
 
+// applyTemplateManifest resolves the values of the parameters declared by the template manifest, from the assigned
+// values or by prompting, and applies them to the staged template. The resolved values and the executable files which
+// remain after the manifest removed excluded paths are returned.
+func (i *Initializer) applyTemplateManifest(
+	ctx context.Context,
+	staging string,
+	assignments map[string]string,
+	executableFilePaths []string) (map[string]string, []string, error) {
+	manifest, err := templates.LoadManifest(staging)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if manifest == nil {
+		if len(assignments) > 0 {
+			return nil, nil, fmt.Errorf("values were set but the template doesn't declare any parameters in %s",
+				templates.ManifestFileName)
+		}
+
+		return nil, executableFilePaths, nil
+	}
+
+	values, err := manifest.ParseValues(assignments)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := i.promptTemplateValues(ctx, manifest, values); err != nil {
+		return nil, nil, err
+	}
+
+	if err := manifest.Apply(staging, values); err != nil {
+		return nil, nil, fmt.Errorf("applying template manifest: %w", err)
+	}
+
+	remaining := []string{}
+	for _, path := range executableFilePaths {
+		if _, err := os.Stat(filepath.Join(staging, path)); err == nil {
+			remaining = append(remaining, path)
+		}
+	}
+
+	resolved := make(map[string]string, len(values))
+	for name, value := range values {
+		resolved[name] = fmt.Sprint(value)
+	}
+
+	return resolved, remaining, nil
+}
+
+// promptTemplateValues prompts for the values of the template parameters which weren't assigned.
+func (i *Initializer) promptTemplateValues(
+	ctx context.Context,
+	manifest *templates.Manifest,
+	values map[string]any) error {
+	stoppedSpinner := false
+	for _, param := range manifest.Parameters {
+		if _, has := values[param.Name]; has {
+			continue
+		}
+
+		if !stoppedSpinner {
+			i.console.StopSpinner(ctx, "", input.StepDone)
+			stoppedSpinner = true
+		}
+
+		message := param.Prompt
+		if message == "" {
+			message = fmt.Sprintf("Enter a value for '%s'", param.Name)
+		}
+
+		options := input.ConsoleOptions{
+			Message:      message,
+			Help:         param.Help,
+			DefaultValue: param.Default,
+		}
+
+		var value any
+		switch {
+		case param.Type == templates.ManifestParameterTypeBool:
+			// The default may be written as a string in the manifest, ex) default: "true"
+			options.DefaultValue = false
+			if param.Default != nil {
+				defaultValue, err := param.Parse(fmt.Sprint(param.Default))
+				if err != nil {
+					return err
+				}
+				options.DefaultValue = defaultValue
+			}
+			confirmed, err := i.console.Confirm(ctx, options)
+			if err != nil {
+				return fmt.Errorf("prompting for '%s': %w", param.Name, err)
+			}
+			value = confirmed
+		case len(param.Allowed) > 0:
+			options.Options = param.Allowed
+			if param.Default != nil {
+				options.DefaultValue = fmt.Sprint(param.Default)
+			}
+			selected, err := i.console.Select(ctx, options)
+			if err != nil {
+				return fmt.Errorf("prompting for '%s': %w", param.Name, err)
+			}
+			value = param.Allowed[selected]
+		default:
+			if param.Default != nil {
+				options.DefaultValue = fmt.Sprint(param.Default)
+			}
+			response, err := i.console.Prompt(ctx, options)
+			if err != nil {
+				return fmt.Errorf("prompting for '%s': %w", param.Name, err)
+			}
+
+			value, err = param.Parse(response)
+			if err != nil {
+				return err
+			}
+		}
+
+		values[param.Name] = value
+	}
+
+	return nil
+}
+
 // promptForDuplicates prompts the user for any duplicate files detected.
 // The list of absolute source file paths to skip are returned.
 func (i *Initializer) promptForDuplicates(
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io/fs"
 	"os"
@@ -12,6 +13,7 @@ import (
 	"testing"
 
 	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
@@ -23,7 +25,9 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/templates"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/dotnet"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/github"
 	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockconfig"
 	"github.com/azure/azure-dev/cli/azd/test/mocks/mockenv"
 	"github.com/azure/azure-dev/cli/azd/test/mocks/mockexec"
 	"github.com/azure/azure-dev/cli/azd/test/mocks/mockinput"
@@ -58,10 +62,14 @@ func Test_Initializer_Initialize(t *testing.T) {
 			i := NewInitializer(
 				mockContext.Console,
 				git.NewGitCli(mockContext.CommandRunner),
+				newTestGitCloner(mockContext.CommandRunner),
 				dotnet.NewDotNetCli(mockContext.CommandRunner),
 				lazy.From[environment.Manager](mockEnv),
+				nil,
+				nil,
+				nil,
 			)
-			err := i.Initialize(*mockContext.Context, azdCtx, &templates.Template{RepositoryPath: "local"}, "")
+			err := i.Initialize(*mockContext.Context, azdCtx, &templates.Template{RepositoryPath: "local"}, "", nil)
 			require.NoError(t, err)
 
 			verifyTemplateCopied(t, testDataPath(tt.templateDir), projectDir, verifyOptions{})
@@ -74,6 +82,45 @@ func Test_Initializer_Initialize(t *testing.T) {
 	}
 }
 
+func Test_Initializer_InitializeSubdirectory(t *testing.T) {
+	projectDir := t.TempDir()
+	tempDir := t.TempDir()
+	t.Setenv("TMPDIR", tempDir)
+	t.Setenv("TMP", tempDir)
+
+	azdCtx := azdcontext.NewAzdContextWithDirectory(projectDir)
+	mockContext := mocks.NewMockContext(context.Background())
+	mockGitClone(t, mockContext, "https://github.com/Azure-Samples/local", testCase{
+		name:        "Subdirectory",
+		templateDir: "template",
+	})
+
+	mockEnv := &mockenv.MockEnvManager{}
+	mockEnv.On("Save", mock.Anything, mock.Anything).Return(nil)
+
+	i := NewInitializer(
+		mockContext.Console,
+		git.NewGitCli(mockContext.CommandRunner),
+		newTestGitCloner(mockContext.CommandRunner),
+		dotnet.NewDotNetCli(mockContext.CommandRunner),
+		lazy.From[environment.Manager](mockEnv),
+		nil,
+		nil,
+		nil,
+	)
+	err := i.Initialize(
+		*mockContext.Context, azdCtx, &templates.Template{RepositoryPath: "local", Subdirectory: "src"}, "", nil)
+	require.NoError(t, err)
+
+	require.FileExists(t, filepath.Join(projectDir, "Program.cs"))
+	require.NoFileExists(t, filepath.Join(projectDir, "README.md"))
+
+	// the whole clone is removed, not only the subdirectory of the template
+	staging, err := filepath.Glob(filepath.Join(tempDir, "az-dev-template*"))
+	require.NoError(t, err)
+	require.Empty(t, staging)
+}
+
 func Test_Initializer_DevCenter(t *testing.T) {
 	projectDir := t.TempDir()
 	azdCtx := azdcontext.NewAzdContextWithDirectory(projectDir)
@@ -102,10 +149,14 @@ func Test_Initializer_DevCenter(t *testing.T) {
 	i := NewInitializer(
 		mockContext.Console,
 		git.NewGitCli(mockContext.CommandRunner),
+		newTestGitCloner(mockContext.CommandRunner),
 		dotnet.NewDotNetCli(mockContext.CommandRunner),
 		lazy.From[environment.Manager](mockEnv),
+		nil,
+		nil,
+		nil,
 	)
-	err := i.Initialize(*mockContext.Context, azdCtx, template, "")
+	err := i.Initialize(*mockContext.Context, azdCtx, template, "", nil)
 	require.NoError(t, err)
 
 	prj, err := project.Load(*mockContext.Context, azdCtx.ProjectPath())
@@ -116,6 +167,136 @@ func Test_Initializer_DevCenter(t *testing.T) {
 	require.Equal(t, prj.Platform.Config["environmentDefinition"], "DEVCENTER_ENV_DEFINITION")
 }
 
+func Test_Initializer_TemplateManifest(t *testing.T) {
+	tests := []struct {
+		name          string
+		values        map[string]string
+		includeWorker bool
+	}{
+		{"Prompted", map[string]string{}, false},
+		{"Set", map[string]string{"serviceName": "orders", "region": "eastus", "includeWorker": "true"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projectDir := t.TempDir()
+			azdCtx := azdcontext.NewAzdContextWithDirectory(projectDir)
+			mockContext := mocks.NewMockContext(context.Background())
+			mockGitClone(t, mockContext, "https://github.com/Azure-Samples/local", testCase{
+				name:            tt.name,
+				templateDir:     "template-manifest",
+				executableFiles: []string{"src/worker/run.sh"},
+			})
+
+			mockContext.Console.WhenPrompt(func(options input.ConsoleOptions) bool {
+				return options.Message == "Name of the API service"
+			}).Respond("orders")
+			mockContext.Console.WhenSelect(func(options input.ConsoleOptions) bool {
+				return options.Message == "Region of the service"
+			}).Respond(0)
+			mockContext.Console.WhenConfirm(func(options input.ConsoleOptions) bool {
+				return options.Message == "Include a background worker?"
+			}).Respond(false)
+
+			mockEnv := &mockenv.MockEnvManager{}
+			mockEnv.On("Save", mock.Anything, mock.Anything).Return(nil)
+
+			i := NewInitializer(
+				mockContext.Console,
+				git.NewGitCli(mockContext.CommandRunner),
+				newTestGitCloner(mockContext.CommandRunner),
+				dotnet.NewDotNetCli(mockContext.CommandRunner),
+				lazy.From[environment.Manager](mockEnv),
+				nil,
+				nil,
+				nil,
+			)
+			err := i.Initialize(
+				*mockContext.Context, azdCtx, &templates.Template{RepositoryPath: "local"}, "", tt.values)
+			require.NoError(t, err)
+
+			prj, err := project.Load(*mockContext.Context, azdCtx.ProjectPath())
+			require.NoError(t, err)
+			require.Equal(t, "orders", prj.Name)
+			require.Contains(t, prj.Services, "orders")
+			require.Equal(t, map[string]string{
+				"serviceName":   "orders",
+				"region":        "eastus",
+				"includeWorker": fmt.Sprint(tt.includeWorker),
+			}, prj.Metadata.TemplateSource.Values)
+
+			verifyFileContent(t, filepath.Join(projectDir, "src", "api", "settings.json"),
+				"{\n  \"service\": \"ORDERS\",\n  \"region\": \"eastus\"\n}\n")
+			verifyFileContent(t, filepath.Join(projectDir, "README.md"),
+				"Files which aren't rendered keep {{ .serviceName }} as is.\n")
+			require.NoFileExists(t, filepath.Join(projectDir, templates.ManifestFileName))
+
+			if tt.includeWorker {
+				require.FileExists(t, filepath.Join(projectDir, "src", "worker", "run.sh"))
+				verifyExecutableFilePermissions(
+					t, *mockContext.Context, i.gitCli, projectDir, []string{"src/worker/run.sh"})
+			} else {
+				require.NoDirExists(t, filepath.Join(projectDir, "src", "worker"))
+			}
+		})
+	}
+
+	t.Run("UndeclaredValue", func(t *testing.T) {
+		projectDir := t.TempDir()
+		azdCtx := azdcontext.NewAzdContextWithDirectory(projectDir)
+		mockContext := mocks.NewMockContext(context.Background())
+		mockGitClone(t, mockContext, "https://github.com/Azure-Samples/local", testCase{
+			name:        "UndeclaredValue",
+			templateDir: "template-manifest",
+		})
+
+		i := NewInitializer(
+			mockContext.Console,
+			git.NewGitCli(mockContext.CommandRunner),
+			newTestGitCloner(mockContext.CommandRunner),
+			dotnet.NewDotNetCli(mockContext.CommandRunner),
+			lazy.From[environment.Manager](&mockenv.MockEnvManager{}),
+			nil,
+			nil,
+			nil,
+		)
+		err := i.Initialize(
+			*mockContext.Context,
+			azdCtx,
+			&templates.Template{RepositoryPath: "local"},
+			"",
+			map[string]string{"location": "eastus"})
+		require.ErrorContains(t, err, "doesn't declare a parameter named 'location'")
+	})
+}
+
+func Test_Initializer_PromptTemplateValues_BoolDefault(t *testing.T) {
+	manifest := &templates.Manifest{
+		Parameters: []templates.ManifestParameter{
+			{Name: "quoted", Prompt: "quoted", Type: templates.ManifestParameterTypeBool, Default: "true"},
+			{Name: "unquoted", Prompt: "unquoted", Type: templates.ManifestParameterTypeBool, Default: true},
+			{Name: "none", Prompt: "none", Type: templates.ManifestParameterTypeBool},
+		},
+	}
+
+	console := mockinput.NewMockConsole()
+	defaults := map[string]any{}
+	console.WhenConfirm(func(options input.ConsoleOptions) bool {
+		return true
+	}).RespondFn(func(options input.ConsoleOptions) (any, error) {
+		defaults[options.Message] = options.DefaultValue
+		return options.DefaultValue, nil
+	})
+
+	i := &Initializer{console: console}
+	values := map[string]any{}
+	err := i.promptTemplateValues(context.Background(), manifest, values)
+	require.NoError(t, err)
+
+	// Confirm ignores defaults which aren't bools, the default written as a string is converted
+	require.Equal(t, map[string]any{"quoted": true, "unquoted": true, "none": false}, defaults)
+	require.Equal(t, map[string]any{"quoted": true, "unquoted": true, "none": false}, values)
+}
+
 func Test_Initializer_InitializeWithOverwritePrompt(t *testing.T) {
 	templateDir := "template"
 	tests := []struct {
@@ -157,8 +338,7 @@ func Test_Initializer_InitializeWithOverwritePrompt(t *testing.T) {
 						slices.Contains(args.Args, "https://github.com/Azure-Samples/local") {
 						stagingDir := args.Args[len(args.Args)-1]
 						copyTemplate(t, testDataPath(templateDir), stagingDir)
-						_, err := realRunner.Run(context.Background(), exec.NewRunArgs("git", "-C", stagingDir, "init"))
-						require.NoError(t, err)
+						gitCommitAll(t, realRunner, stagingDir)
 
 						return exec.NewRunResult(0, "", ""), nil
 					}
@@ -172,10 +352,14 @@ func Test_Initializer_InitializeWithOverwritePrompt(t *testing.T) {
 			i := NewInitializer(
 				console,
 				git.NewGitCli(mockRunner),
+				newTestGitCloner(mockRunner),
 				dotnet.NewDotNetCli(mockRunner),
 				lazy.From[environment.Manager](mockEnv),
+				nil,
+				nil,
+				nil,
 			)
-			err = i.Initialize(context.Background(), azdCtx, &templates.Template{RepositoryPath: "local"}, "")
+			err = i.Initialize(context.Background(), azdCtx, &templates.Template{RepositoryPath: "local"}, "", nil)
 			require.NoError(t, err)
 
 			switch tt.selection {
@@ -277,11 +461,20 @@ func verifyTemplateCopied(
 		}
 
 		relCopied := strings.TrimSuffix(rel, ".txt")
+		expected := readFile(t, filepath.Join(original, rel))
+
+		// init records the template source in the metadata of the project file
+		if relCopied == azdcontext.ProjectFileName {
+			prj, err := project.Load(context.Background(), filepath.Join(copied, relCopied))
+			require.NoError(t, err)
+			require.NotNil(t, prj.Metadata.TemplateSource)
+
+			withSource, err := project.SetTemplateSource([]byte(expected), prj.Metadata.TemplateSource)
+			require.NoError(t, err)
+			expected = string(withSource)
+		}
 
-		verifyFileContent(
-			t,
-			filepath.Join(copied, relCopied),
-			readFile(t, filepath.Join(original, rel)))
+		verifyFileContent(t, filepath.Join(copied, relCopied), expected)
 
 		return nil
 	})
@@ -376,7 +569,14 @@ func Test_Initializer_WriteCoreAssets(t *testing.T) {
 			envManager.On("Save", mock.Anything, mock.Anything).Return(nil)
 
 			i := NewInitializer(
-				console, git.NewGitCli(realRunner), nil, lazy.From[environment.Manager](envManager))
+				console,
+				git.NewGitCli(realRunner),
+				newTestGitCloner(realRunner),
+				nil,
+				lazy.From[environment.Manager](envManager),
+				nil,
+				nil,
+				nil)
 			err := i.writeCoreAssets(context.Background(), azdCtx)
 			require.NoError(t, err)
 
@@ -710,6 +910,17 @@ func TestInitializer_writeFileSafe(t *testing.T) {
 	}
 }
 
+// newTestGitCloner creates a template cloner without a configured token nor a GitHub CLI.
+func newTestGitCloner(runner exec.CommandRunner) *templates.GitCloner {
+	return templates.NewGitCloner(
+		git.NewGitCli(runner),
+		config.NewUserConfigManager(mockconfig.NewMockConfigManager()),
+		lazy.NewLazy(func() (github.GitHubCli, error) {
+			return nil, errors.New("GitHub CLI is not available in tests")
+		}),
+	)
+}
+
 func mockGitClone(t *testing.T, mockContext *mocks.MockContext, templatePath string, testCase testCase) {
 	realRunner := exec.NewCommandRunner(nil)
 
@@ -729,6 +940,10 @@ func mockGitClone(t *testing.T, mockContext *mocks.MockContext, templatePath str
 				_, err = realRunner.Run(*mockContext.Context, gitArgs.AppendParams("add", "*"))
 				require.NoError(t, err)
 
+				_, err = realRunner.Run(*mockContext.Context, gitArgs.AppendParams(
+					"-c", "user.name=azd", "-c", "user.email=azd@example.com", "commit", "-m", "template"))
+				require.NoError(t, err)
+
 				for _, file := range testCase.executableFiles {
 					_, err = realRunner.Run(
 						*mockContext.Context,
@@ -753,3 +968,29 @@ func mockGitClone(t *testing.T, mockContext *mocks.MockContext, templatePath str
 			return realRunner.Run(*mockContext.Context, args)
 		})
 }
+
+func Test_templateSubdirectory(t *testing.T) {
+	repositoryDir := t.TempDir()
+	writeTestFiles(t, repositoryDir, map[string]string{
+		"templates/api/azure.yaml":     "name: api\n",
+		"templates/api/scripts/run.sh": "",
+		"templates/web/azure.yaml":     "name: web\n",
+	})
+	executableFiles := []string{"templates/api/scripts/run.sh", "templates/web/run.sh", "run.sh"}
+
+	dir, files, err := templateSubdirectory(repositoryDir, "", executableFiles)
+	require.NoError(t, err)
+	require.Equal(t, repositoryDir, dir)
+	require.Equal(t, executableFiles, files)
+
+	dir, files, err = templateSubdirectory(repositoryDir, "templates/api/", executableFiles)
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(repositoryDir, "templates", "api"), dir)
+	require.Equal(t, []string{"scripts/run.sh"}, files)
+
+	_, _, err = templateSubdirectory(repositoryDir, "templates/missing", executableFiles)
+	require.ErrorContains(t, err, "was not found")
+
+	_, _, err = templateSubdirectory(repositoryDir, "../outside", executableFiles)
+	require.ErrorContains(t, err, "outside of the repository")
+}
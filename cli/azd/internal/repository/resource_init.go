@@ -0,0 +1,350 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/internal/appdetect"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/fatih/color"
+)
+
+const (
+	webSitesResourceType          = "microsoft.web/sites"
+	containerAppsResourceType     = "microsoft.app/containerapps"
+	managedClustersResourceType   = "microsoft.containerservice/managedclusters"
+	containerRegistryResourceType = "microsoft.containerregistry/registries"
+
+	// the API version used to read the kind of web sites, which tells function apps apart from app services
+	webSitesApiVersion = "2022-03-01"
+)
+
+// emptyParametersFile is the parameters file of the decompiled template, whose parameters all have default values.
+const emptyParametersFile = `{
+  "$schema": "https://schema.management.azure.com/schemas/2019-04-01/deploymentParameters.json#",
+  "contentVersion": "1.0.0.0",
+  "parameters": {}
+}
+`
+
+// resourceService is a service of the project, hosted by an existing Azure resource.
+type resourceService struct {
+	Name     string
+	Host     project.ServiceTargetKind
+	Resource azcli.AzCliResource
+}
+
+// InitFromResourceGroup initializes the project file and the infrastructure of the project from the resources of an
+// existing resource group, so that azd deploys to the existing resources without provisioning them again.
+//
+// App Services, Function Apps, Container Apps and AKS clusters are mapped to services, the ARM template of the resource
+// group is exported and decompiled to Bicep, and the subscription, location and resource group are set in env.
+func (i *Initializer) InitFromResourceGroup(
+	ctx context.Context,
+	azdCtx *azdcontext.AzdContext,
+	env *environment.Environment,
+	resourceGroup string) error {
+	if _, err := os.Stat(azdCtx.ProjectPath()); err == nil {
+		return &azcli.ErrorWithSuggestion{
+			Err: fmt.Errorf("%s already exists", azdcontext.ProjectFileName),
+			Suggestion: "Run " + output.WithHighLightFormat("azd init --from-resource-group") +
+				" in a directory without an azd project.",
+		}
+	}
+
+	subscriptionId := env.GetSubscriptionId()
+
+	title := "Listing resources in resource group " + output.WithHighLightFormat(resourceGroup)
+	i.console.ShowSpinner(ctx, title, input.Step)
+	group, services, registries, err := i.listResourceGroupServices(ctx, subscriptionId, resourceGroup)
+	i.console.StopSpinner(ctx, title, input.GetStepResultFormat(err))
+	if err != nil {
+		return err
+	}
+
+	if len(services) == 0 {
+		return &azcli.ErrorWithSuggestion{
+			Err: fmt.Errorf("no services found in resource group '%s'", resourceGroup),
+			Suggestion: "azd maps App Services, Function Apps, Container Apps and AKS clusters to services. " +
+				"Ensure the resource group contains at least one of them.",
+		}
+	}
+
+	i.console.Message(ctx, "\n"+output.WithBold("Detected services:")+"\n")
+	for _, svc := range services {
+		i.console.Message(ctx, "  "+color.BlueString(svc.Name)+" "+output.WithGrayFormat("(host: %s)", svc.Host))
+		i.console.Message(ctx, "  "+"Hosted by: "+output.WithHighLightFormat(svc.Resource.Name))
+		i.console.Message(ctx, "")
+	}
+
+	confirm, err := i.console.Confirm(ctx, input.ConsoleOptions{
+		Message:      "Continue initializing your app with these services?",
+		DefaultValue: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	if !confirm {
+		return errors.New("initialization cancelled")
+	}
+
+	config := project.ProjectConfig{
+		Name: filepath.Base(azdCtx.ProjectDirectory()),
+		Metadata: &project.ProjectMetadata{
+			Template: fmt.Sprintf("%s@%s", InitGenTemplateId, internal.VersionInfo().Version),
+		},
+		Services: map[string]*project.ServiceConfig{},
+	}
+
+	for _, svc := range services {
+		serviceConfig, err := i.promptServiceProject(ctx, azdCtx.ProjectDirectory(), svc)
+		if err != nil {
+			return err
+		}
+
+		config.Services[svc.Name] = serviceConfig
+	}
+
+	i.console.Message(ctx, "\n"+output.WithBold("Generating files to run your app on Azure:")+"\n")
+
+	if err := project.Save(ctx, &config, azdCtx.ProjectPath()); err != nil {
+		return fmt.Errorf("generating %s: %w", azdcontext.ProjectFileName, err)
+	}
+
+	i.console.MessageUxItem(ctx, &ux.DoneMessage{
+		Message: "Generating " + output.WithHighLightFormat("./"+azdcontext.ProjectFileName),
+	})
+
+	warnings, err := i.exportInfra(ctx, azdCtx, subscriptionId, resourceGroup)
+	if err != nil {
+		return err
+	}
+
+	if warnings != "" {
+		log.Printf("decompiling the template of resource group %s: %s", resourceGroup, warnings)
+		i.console.MessageUxItem(ctx, &ux.WarningMessage{
+			Description: "Some resources could not be fully decompiled to Bicep. Review " +
+				output.WithHighLightFormat("./infra/main.bicep") + " before running azd provision.",
+		})
+	}
+
+	env.SetSubscriptionId(subscriptionId)
+	env.SetLocation(group.Location)
+	env.DotenvSet(environment.ResourceGroupEnvVarName, resourceGroup)
+
+	for _, svc := range services {
+		if svc.Host == project.AksTarget {
+			env.DotenvSet(environment.AksClusterEnvVarName, svc.Resource.Name)
+			break
+		}
+	}
+
+	if len(registries) > 0 {
+		if len(registries) > 1 {
+			log.Printf("found %d container registries, using %s", len(registries), registries[0].Name)
+		}
+
+		env.DotenvSet(environment.ContainerRegistryEndpointEnvVarName,
+			fmt.Sprintf("%s.%s", registries[0].Name, i.cloud.ContainerRegistryEndpointSuffix))
+	}
+
+	envManager, err := i.lazyEnvManager.GetValue()
+	if err != nil {
+		return err
+	}
+
+	if err := envManager.Save(ctx, env); err != nil {
+		return fmt.Errorf("saving environment: %w", err)
+	}
+
+	return i.writeCoreAssets(ctx, azdCtx)
+}
+
+// listResourceGroupServices lists the resources of the resource group which host services, sorted by name, and its
+// container registries.
+func (i *Initializer) listResourceGroupServices(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+) (azcli.AzCliResource, []resourceService, []azcli.AzCliResource, error) {
+	group, err := i.azCli.GetResourceGroup(ctx, subscriptionId, resourceGroup)
+	if err != nil {
+		return azcli.AzCliResource{}, nil, nil, err
+	}
+
+	resources, err := i.azCli.ListResourceGroupResources(ctx, subscriptionId, resourceGroup, nil)
+	if err != nil {
+		return azcli.AzCliResource{}, nil, nil, fmt.Errorf("listing resources: %w", err)
+	}
+
+	services := []resourceService{}
+	registries := []azcli.AzCliResource{}
+	for _, resource := range resources {
+		var host project.ServiceTargetKind
+		switch strings.ToLower(resource.Type) {
+		case webSitesResourceType:
+			site, err := i.azCli.GetResource(ctx, subscriptionId, resource.Id, webSitesApiVersion)
+			if err != nil {
+				return azcli.AzCliResource{}, nil, nil, err
+			}
+
+			host = project.AppServiceTarget
+			if strings.Contains(strings.ToLower(site.Kind), "functionapp") {
+				host = project.AzureFunctionTarget
+			}
+		case containerAppsResourceType:
+			host = project.ContainerAppTarget
+		case managedClustersResourceType:
+			host = project.AksTarget
+		case containerRegistryResourceType:
+			registries = append(registries, resource)
+			continue
+		default:
+			continue
+		}
+
+		services = append(services, resourceService{
+			Name:     strings.ToLower(resource.Name),
+			Host:     host,
+			Resource: resource,
+		})
+	}
+
+	slices.SortFunc(services, func(a, b resourceService) int {
+		if a.Name != b.Name {
+			return strings.Compare(a.Name, b.Name)
+		}
+
+		return strings.Compare(string(a.Host), string(b.Host))
+	})
+
+	// resources of different types may share a name, service names must be unique
+	names := map[string]struct{}{}
+	for idx := range services {
+		if _, has := names[services[idx].Name]; has {
+			services[idx].Name = fmt.Sprintf("%s-%s", services[idx].Name, services[idx].Host)
+		}
+		names[services[idx].Name] = struct{}{}
+	}
+
+	return group, services, registries, nil
+}
+
+// promptServiceProject prompts for the directory of the code of the service, and detects its language.
+func (i *Initializer) promptServiceProject(
+	ctx context.Context,
+	root string,
+	svc resourceService) (*project.ServiceConfig, error) {
+	dir, err := i.console.Prompt(ctx, input.ConsoleOptions{
+		Message:      fmt.Sprintf("Enter the directory of the code of '%s'", svc.Name),
+		DefaultValue: filepath.Join("src", svc.Name),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	path := dir
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(root, path)
+	}
+
+	serviceConfig := &project.ServiceConfig{
+		RelativePath: filepath.ToSlash(relSafe(root, path)),
+		Host:         svc.Host,
+		ResourceName: osutil.NewExpandableString(svc.Resource.Name),
+	}
+
+	if svc.Host == project.AksTarget {
+		// deployments target the cluster, rather than a resource named after the service
+		serviceConfig.ResourceName = osutil.NewExpandableString("")
+	}
+
+	if _, err := os.Stat(path); err == nil {
+		prj, err := appdetect.DetectDirectory(ctx, path)
+		if err != nil {
+			return nil, fmt.Errorf("detecting project in %s: %w", dir, err)
+		}
+
+		if prj != nil {
+			if language, supported := languageMap[prj.Language]; supported {
+				serviceConfig.Language = language
+			}
+
+			if prj.Docker != nil {
+				serviceConfig.Docker = project.DockerProjectOptions{Path: relSafe(path, prj.Docker.Path)}
+			}
+		}
+	}
+
+	if serviceConfig.Language == "" {
+		i.console.MessageUxItem(ctx, &ux.WarningMessage{
+			Description: fmt.Sprintf(
+				"The language of '%s' could not be detected. Set 'language' of the service in %s.",
+				svc.Name, azdcontext.ProjectFileName),
+		})
+	}
+
+	return serviceConfig, nil
+}
+
+// exportInfra exports the ARM template of the resource group, and decompiles it to infra/main.bicep. The warnings of
+// the decompilation are returned.
+func (i *Initializer) exportInfra(
+	ctx context.Context,
+	azdCtx *azdcontext.AzdContext,
+	subscriptionId string,
+	resourceGroup string) (string, error) {
+	var err error
+	title := "Generating Infrastructure as Code files in " + output.WithHighLightFormat("./infra")
+	i.console.ShowSpinner(ctx, title, input.Step)
+	defer func() { i.console.StopSpinner(ctx, title, input.GetStepResultFormat(err)) }()
+
+	template, err := i.azCli.ExportResourceGroupTemplate(ctx, subscriptionId, resourceGroup)
+	if err != nil {
+		return "", err
+	}
+
+	staging, err := os.MkdirTemp("", "azd-infra")
+	if err != nil {
+		return "", fmt.Errorf("mkdir temp: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(staging) }()
+
+	templatePath := filepath.Join(staging, "main.json")
+	if err = os.WriteFile(templatePath, template, osutil.PermissionFile); err != nil {
+		return "", err
+	}
+
+	bicepCli, err := i.lazyBicepCli.GetValue()
+	if err != nil {
+		return "", err
+	}
+
+	infra := filepath.Join(azdCtx.ProjectDirectory(), "infra")
+	if err = os.MkdirAll(infra, osutil.PermissionDirectory); err != nil {
+		return "", err
+	}
+
+	warnings, err := bicepCli.Decompile(ctx, templatePath, filepath.Join(infra, "main.bicep"))
+	if err != nil {
+		return "", err
+	}
+
+	err = os.WriteFile(filepath.Join(infra, "main.parameters.json"), []byte(emptyParametersFile), osutil.PermissionFile)
+	return warnings, err
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/stretchr/testify/require"
+)
+
+type testResourceGroupAzCli struct {
+	azcli.AzCli
+	resources []azcli.AzCliResource
+	kinds     map[string]string
+}
+
+func (cli *testResourceGroupAzCli) GetResourceGroup(
+	ctx context.Context, subscriptionId string, resourceGroupName string) (azcli.AzCliResource, error) {
+	return azcli.AzCliResource{Name: resourceGroupName, Location: "westus2"}, nil
+}
+
+func (cli *testResourceGroupAzCli) ListResourceGroupResources(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	listOptions *azcli.ListResourceGroupResourcesOptions,
+) ([]azcli.AzCliResource, error) {
+	return cli.resources, nil
+}
+
+func (cli *testResourceGroupAzCli) GetResource(
+	ctx context.Context,
+	subscriptionId string,
+	resourceId string,
+	apiVersion string) (azcli.AzCliResourceExtended, error) {
+	return azcli.AzCliResourceExtended{Kind: cli.kinds[resourceId]}, nil
+}
+
+func TestInitializer_listResourceGroupServices(t *testing.T) {
+	azCli := &testResourceGroupAzCli{
+		resources: []azcli.AzCliResource{
+			{Id: "/sites/web", Name: "Web", Type: "Microsoft.Web/sites"},
+			{Id: "/sites/func", Name: "func", Type: "Microsoft.Web/sites"},
+			{Id: "/containerApps/api", Name: "api", Type: "Microsoft.App/containerApps"},
+			{Id: "/managedClusters/api", Name: "api", Type: "Microsoft.ContainerService/managedClusters"},
+			{Id: "/registries/acr", Name: "acr", Type: "Microsoft.ContainerRegistry/registries"},
+			{Id: "/vaults/kv", Name: "kv", Type: "Microsoft.KeyVault/vaults"},
+		},
+		kinds: map[string]string{
+			"/sites/web":  "app,linux",
+			"/sites/func": "functionapp,linux",
+		},
+	}
+
+	i := &Initializer{azCli: azCli}
+	group, services, registries, err := i.listResourceGroupServices(context.Background(), "sub", "rg-app")
+	require.NoError(t, err)
+	require.Equal(t, "westus2", group.Location)
+	require.Len(t, registries, 1)
+	require.Equal(t, "acr", registries[0].Name)
+
+	names := map[string]project.ServiceTargetKind{}
+	for _, svc := range services {
+		names[svc.Name] = svc.Host
+	}
+
+	require.Len(t, services, 4)
+	require.Equal(t, project.AppServiceTarget, names["web"])
+	require.Equal(t, project.AzureFunctionTarget, names["func"])
+	require.Equal(t, project.AksTarget, names["api"])
+	require.Equal(t, project.ContainerAppTarget, names["api-containerapp"])
+}
@@ -0,0 +1,455 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/pkg/templates"
+)
+
+// TemplateChangeKind describes how a file of the project is changed by a template upgrade.
+type TemplateChangeKind string
+
+const (
+	// The file was added to the template.
+	TemplateChangeAdded TemplateChangeKind = "added"
+	// The file was changed in the template and wasn't changed locally.
+	TemplateChangeUpdated TemplateChangeKind = "updated"
+	// The file was changed both in the template and locally, and the changes were merged without conflicts.
+	TemplateChangeMerged TemplateChangeKind = "merged"
+	// The file was removed from the template and wasn't changed locally.
+	TemplateChangeDeleted TemplateChangeKind = "deleted"
+	// The changes of the template conflict with the local changes and need to be resolved.
+	TemplateChangeConflict TemplateChangeKind = "conflict"
+)
+
+// TemplateChange is a change to a file of the project.
+type TemplateChange struct {
+	// Path is the slash separated path of the file, relative to the project directory.
+	Path string             `json:"path"`
+	Kind TemplateChangeKind `json:"kind"`
+	// Reason explains conflicts which aren't marked in the contents of the file.
+	Reason string `json:"reason,omitempty"`
+}
+
+// TemplateUpgrade is the result of merging the changes of a template into a project.
+type TemplateUpgrade struct {
+	Repository string           `json:"repository"`
+	Ref        string           `json:"ref,omitempty"`
+	FromCommit string           `json:"fromCommit"`
+	ToCommit   string           `json:"toCommit"`
+	Changes    []TemplateChange `json:"changes"`
+}
+
+// Conflicts returns the changes which need to be resolved.
+func (u *TemplateUpgrade) Conflicts() []TemplateChange {
+	conflicts := []TemplateChange{}
+	for _, change := range u.Changes {
+		if change.Kind == TemplateChangeConflict {
+			conflicts = append(conflicts, change)
+		}
+	}
+
+	return conflicts
+}
+
+// Upgrade merges the changes of the template the project was initialized from into the project.
+//
+// The changes between the commit recorded in the project and the latest commit of ref, or of the recorded ref when
+// empty, are merged with the local changes of each file using a three-way merge. Conflicting changes are left in the
+// files with conflict markers for review. When preview is set, the changes are computed without modifying the project.
+func (i *Initializer) Upgrade(
+	ctx context.Context,
+	azdCtx *azdcontext.AzdContext,
+	ref string,
+	preview bool) (upgrade *TemplateUpgrade, err error) {
+	prj, err := project.Load(ctx, azdCtx.ProjectPath())
+	if err != nil {
+		return nil, err
+	}
+
+	var source *project.TemplateSource
+	if prj.Metadata != nil {
+		source = prj.Metadata.TemplateSource
+	}
+	if source == nil || source.Repository == "" || source.Commit == "" {
+		return nil, errors.New(
+			"the project doesn't record the template it was initialized from. " +
+				"Only projects initialized from a template repository with `azd init` can be upgraded")
+	}
+
+	if ref == "" {
+		ref = source.Ref
+	}
+
+	stepMessage := fmt.Sprintf("Fetching template changes from %s", source.Repository)
+	i.console.ShowSpinner(ctx, stepMessage, input.Step)
+	defer func() {
+		i.console.StopSpinner(ctx, stepMessage, input.GetStepResultFormat(err))
+	}()
+
+	staging, err := os.MkdirTemp("", "az-dev-template-upgrade")
+	if err != nil {
+		return nil, fmt.Errorf("creating temp folder: %w", err)
+	}
+	defer func() {
+		_ = os.RemoveAll(staging)
+	}()
+
+	latestDir := filepath.Join(staging, "latest")
+	baseDir := filepath.Join(staging, "base")
+
+	if err = i.gitCloner.Clone(ctx, source.Repository, ref, latestDir); err != nil {
+		return nil, fmt.Errorf("fetching template: %w", err)
+	}
+
+	latestCommit, err := i.gitCli.GetCommitHash(ctx, latestDir, "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("resolving template commit: %w", err)
+	}
+
+	upgrade = &TemplateUpgrade{
+		Repository: source.Repository,
+		Ref:        ref,
+		FromCommit: source.Commit,
+		ToCommit:   latestCommit,
+		Changes:    []TemplateChange{},
+	}
+
+	if latestCommit == source.Commit {
+		return upgrade, nil
+	}
+
+	if err = i.gitCli.AddWorktree(ctx, latestDir, baseDir, source.Commit); err != nil {
+		return nil, fmt.Errorf("fetching original template commit: %w", err)
+	}
+
+	if source.Subdirectory != "" {
+		if baseDir, _, err = templateSubdirectory(baseDir, source.Subdirectory, nil); err != nil {
+			return nil, err
+		}
+		if latestDir, _, err = templateSubdirectory(latestDir, source.Subdirectory, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	i.console.StopSpinner(ctx, stepMessage, input.StepDone)
+
+	// Both trees are rendered with the values the project was initialized with, so that only the changes of the
+	// template are merged. Parameters added by the latest template are prompted for.
+	baseValues, err := i.upgradeTemplateValues(ctx, baseDir, source.Values)
+	if err != nil {
+		return nil, fmt.Errorf("rendering original template: %w", err)
+	}
+
+	latestValues, err := i.upgradeTemplateValues(ctx, latestDir, baseValues)
+	if err != nil {
+		return nil, err
+	}
+
+	projectDir := azdCtx.ProjectDirectory()
+	projectFile, err := filepath.Rel(projectDir, azdCtx.ProjectPath())
+	if err != nil {
+		return nil, err
+	}
+
+	// The project file is merged with the new template source already recorded, so that it's updated even when the
+	// template file itself has conflicts.
+	projectFileContents, err := os.ReadFile(azdCtx.ProjectPath())
+	if err != nil {
+		return nil, fmt.Errorf("reading project file: %w", err)
+	}
+	projectFileContents, err = project.SetTemplateSource(projectFileContents, &project.TemplateSource{
+		Repository:   source.Repository,
+		Ref:          ref,
+		Subdirectory: source.Subdirectory,
+		Commit:       latestCommit,
+		Values:       latestValues,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	merger := &templateMerger{
+		initializer: i,
+		scratchDir:  filepath.Join(staging, "merge"),
+		labels:      [3]string{"local", shortCommit(source.Commit), shortCommit(latestCommit)},
+		local: map[string][]byte{
+			filepath.ToSlash(projectFile): projectFileContents,
+		},
+	}
+	if err := os.MkdirAll(merger.scratchDir, osutil.PermissionDirectory); err != nil {
+		return nil, err
+	}
+
+	paths, err := templateFiles(baseDir, latestDir)
+	if err != nil {
+		return nil, err
+	}
+
+	writes := map[string]*fileWrite{}
+	for _, path := range paths {
+		change, write, err := merger.merge(ctx, path,
+			filepath.Join(baseDir, filepath.FromSlash(path)),
+			filepath.Join(latestDir, filepath.FromSlash(path)),
+			filepath.Join(projectDir, filepath.FromSlash(path)))
+		if err != nil {
+			return nil, fmt.Errorf("merging %s: %w", path, err)
+		}
+
+		if change != nil {
+			upgrade.Changes = append(upgrade.Changes, *change)
+		}
+		if write != nil {
+			writes[path] = write
+		}
+	}
+
+	if preview {
+		return upgrade, nil
+	}
+
+	if _, has := writes[filepath.ToSlash(projectFile)]; !has {
+		writes[filepath.ToSlash(projectFile)] = &fileWrite{contents: projectFileContents, mode: osutil.PermissionFile}
+	}
+
+	for path, write := range writes {
+		target := filepath.Join(projectDir, filepath.FromSlash(path))
+		if write.remove {
+			if err := os.Remove(target); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return nil, fmt.Errorf("removing %s: %w", path, err)
+			}
+			continue
+		}
+
+		if err := os.MkdirAll(filepath.Dir(target), osutil.PermissionDirectory); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(target, write.contents, write.mode); err != nil {
+			return nil, fmt.Errorf("writing %s: %w", path, err)
+		}
+	}
+
+	return upgrade, nil
+}
+
+// upgradeTemplateValues applies the template manifest of dir, if any, with the values recorded in the project. Values
+// of parameters the manifest doesn't declare are ignored and values of new parameters are prompted for.
+func (i *Initializer) upgradeTemplateValues(
+	ctx context.Context,
+	dir string,
+	recorded map[string]string) (map[string]string, error) {
+	manifest, err := templates.LoadManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	assignments := map[string]string{}
+	if manifest != nil {
+		for name, value := range recorded {
+			if _, has := manifest.Parameter(name); has {
+				assignments[name] = value
+			}
+		}
+	}
+
+	values, _, err := i.applyTemplateManifest(ctx, dir, assignments, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+// fileWrite is a pending update of a project file.
+type fileWrite struct {
+	contents []byte
+	mode     fs.FileMode
+	remove   bool
+}
+
+type templateMerger struct {
+	initializer *Initializer
+	scratchDir  string
+	labels      [3]string
+	// local overrides the contents of project files.
+	local map[string][]byte
+}
+
+// merge computes the change of the project file at path from the versions of the file in the original and latest
+// template, and the local version.
+func (m *templateMerger) merge(
+	ctx context.Context,
+	path string,
+	basePath string,
+	latestPath string,
+	localPath string) (*TemplateChange, *fileWrite, error) {
+	base, hasBase, err := readOptionalFile(basePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	latest, hasLatest, err := readOptionalFile(latestPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	local, hasLocal := m.local[path]
+	if !hasLocal {
+		local, hasLocal, err = readOptionalFile(localPath)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	switch {
+	case hasBase && hasLatest && bytes.Equal(base, latest):
+		return nil, nil, nil
+	case hasLatest && hasLocal && bytes.Equal(local, latest):
+		return nil, nil, nil
+	case !hasLatest:
+		// removed from the template
+		if !hasLocal {
+			return nil, nil, nil
+		}
+		if bytes.Equal(local, base) {
+			return &TemplateChange{Path: path, Kind: TemplateChangeDeleted}, &fileWrite{remove: true}, nil
+		}
+		return &TemplateChange{
+			Path:   path,
+			Kind:   TemplateChangeConflict,
+			Reason: "the file was removed from the template but changed locally",
+		}, nil, nil
+	case !hasLocal:
+		if !hasBase {
+			return m.write(path, latestPath, latest, TemplateChangeAdded)
+		}
+		return &TemplateChange{
+			Path:   path,
+			Kind:   TemplateChangeConflict,
+			Reason: "the file was changed in the template but removed locally",
+		}, nil, nil
+	case hasBase && bytes.Equal(local, base):
+		return m.write(path, latestPath, latest, TemplateChangeUpdated)
+	}
+
+	if isBinary(base) || isBinary(latest) || isBinary(local) {
+		return &TemplateChange{
+			Path:   path,
+			Kind:   TemplateChangeConflict,
+			Reason: "the binary file was changed both in the template and locally",
+		}, nil, nil
+	}
+
+	merged, conflict, err := m.mergeFile(ctx, local, base, latest)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	kind := TemplateChangeMerged
+	if conflict {
+		kind = TemplateChangeConflict
+	}
+
+	return m.write(path, localPath, []byte(merged), kind)
+}
+
+// write creates the change of a file to contents, keeping the permissions of the file at modePath.
+func (m *templateMerger) write(
+	path string,
+	modePath string,
+	contents []byte,
+	kind TemplateChangeKind) (*TemplateChange, *fileWrite, error) {
+	mode := osutil.PermissionFile
+	if info, err := os.Stat(modePath); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	return &TemplateChange{Path: path, Kind: kind}, &fileWrite{contents: contents, mode: mode}, nil
+}
+
+func (m *templateMerger) mergeFile(ctx context.Context, local, base, latest []byte) (string, bool, error) {
+	files := [3]string{}
+	for i, contents := range [][]byte{local, base, latest} {
+		files[i] = filepath.Join(m.scratchDir, fmt.Sprintf("%d", i))
+		if err := os.WriteFile(files[i], contents, osutil.PermissionFile); err != nil {
+			return "", false, err
+		}
+	}
+
+	return m.initializer.gitCli.MergeFile(ctx, files[0], files[1], files[2], m.labels)
+}
+
+// templateFiles returns the slash separated relative paths of the files in any of the template directories.
+func templateFiles(dirs ...string) ([]string, error) {
+	found := map[string]struct{}{}
+	for _, dir := range dirs {
+		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if d.Name() == ".git" {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+
+			if d.IsDir() {
+				return nil
+			}
+
+			rel, err := filepath.Rel(dir, path)
+			if err != nil {
+				return err
+			}
+
+			found[filepath.ToSlash(rel)] = struct{}{}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	paths := make([]string, 0, len(found))
+	for path := range found {
+		paths = append(paths, path)
+	}
+
+	slices.Sort(paths)
+	return paths, nil
+}
+
+func readOptionalFile(path string) ([]byte, bool, error) {
+	contents, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	return contents, true, nil
+}
+
+func isBinary(contents []byte) bool {
+	return bytes.IndexByte(contents, 0) >= 0
+}
+
+func shortCommit(commit string) string {
+	if len(commit) > 7 {
+		return commit[:7]
+	}
+
+	return commit
+}
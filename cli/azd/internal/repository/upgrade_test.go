@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/lazy"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/dotnet"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockenv"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockinput"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Initializer_Upgrade(t *testing.T) {
+	runner := exec.NewCommandRunner(nil)
+	templateDir := t.TempDir()
+
+	writeTestFiles(t, templateDir, map[string]string{
+		"azure.yaml":         "name: app\nservices:\n  api:\n    project: ./src/api\n    language: py\n    host: containerapp\n",
+		"README.md":          "line1\nline2\nline3\nline4\nline5\n",
+		"infra/main.bicep":   "param location string\n",
+		"infra/old.bicep":    "// old\n",
+		"infra/custom.bicep": "// custom\n",
+		"src/api/app.py":     "print('v1')\n",
+	})
+	baseCommit := gitCommitAll(t, runner, templateDir)
+
+	// the files of the original template, which projects are initialized from
+	baseDir := t.TempDir()
+	copyTemplate(t, templateDir, baseDir)
+	require.NoError(t, os.RemoveAll(filepath.Join(baseDir, ".git")))
+
+	newProject := func(t *testing.T) *azdcontext.AzdContext {
+		projectDir := t.TempDir()
+		copyTemplate(t, baseDir, projectDir)
+
+		writeTestFiles(t, projectDir, map[string]string{
+			"README.md":          "LOCAL line1\nline2\nline3\nline4\nline5\n",
+			"infra/custom.bicep": "// mine\n",
+			"src/api/app.py":     "print('local')\n",
+		})
+
+		azdCtx := azdcontext.NewAzdContextWithDirectory(projectDir)
+		err := project.SaveTemplateSource(azdCtx.ProjectPath(), &project.TemplateSource{
+			Repository: templateDir,
+			Commit:     baseCommit,
+		})
+		require.NoError(t, err)
+
+		return azdCtx
+	}
+
+	newInitializer := func() *Initializer {
+		return NewInitializer(
+			mockinput.NewMockConsole(),
+			git.NewGitCli(runner),
+			newTestGitCloner(runner),
+			dotnet.NewDotNetCli(runner),
+			lazy.From[environment.Manager](&mockenv.MockEnvManager{}),
+			nil,
+			nil,
+			nil,
+		)
+	}
+
+	t.Run("UpToDate", func(t *testing.T) {
+		azdCtx := newProject(t)
+
+		upgrade, err := newInitializer().Upgrade(context.Background(), azdCtx, "", false)
+		require.NoError(t, err)
+		require.Equal(t, baseCommit, upgrade.ToCommit)
+		require.Empty(t, upgrade.Changes)
+	})
+
+	writeTestFiles(t, templateDir, map[string]string{
+		"README.md":        "line1\nline2\nline3\nline4\nline5 v2\n",
+		"infra/main.bicep": "param location string\nparam name string\n",
+		"infra/new.bicep":  "// new\n",
+		"src/api/app.py":   "print('v2')\n",
+	})
+	require.NoError(t, os.Remove(filepath.Join(templateDir, "infra", "old.bicep")))
+	require.NoError(t, os.Remove(filepath.Join(templateDir, "infra", "custom.bicep")))
+	latestCommit := gitCommitAll(t, runner, templateDir)
+
+	expectedChanges := []TemplateChange{
+		{Path: "README.md", Kind: TemplateChangeMerged},
+		{
+			Path:   "infra/custom.bicep",
+			Kind:   TemplateChangeConflict,
+			Reason: "the file was removed from the template but changed locally",
+		},
+		{Path: "infra/main.bicep", Kind: TemplateChangeUpdated},
+		{Path: "infra/new.bicep", Kind: TemplateChangeAdded},
+		{Path: "infra/old.bicep", Kind: TemplateChangeDeleted},
+		{Path: "src/api/app.py", Kind: TemplateChangeConflict},
+	}
+
+	t.Run("Preview", func(t *testing.T) {
+		azdCtx := newProject(t)
+		projectFile, err := os.ReadFile(azdCtx.ProjectPath())
+		require.NoError(t, err)
+
+		upgrade, err := newInitializer().Upgrade(context.Background(), azdCtx, "", true)
+		require.NoError(t, err)
+		require.Equal(t, baseCommit, upgrade.FromCommit)
+		require.Equal(t, latestCommit, upgrade.ToCommit)
+		require.Equal(t, expectedChanges, upgrade.Changes)
+
+		verifyFileContent(t, azdCtx.ProjectPath(), string(projectFile))
+		verifyFileContent(t, filepath.Join(azdCtx.ProjectDirectory(), "src", "api", "app.py"), "print('local')\n")
+		require.FileExists(t, filepath.Join(azdCtx.ProjectDirectory(), "infra", "old.bicep"))
+	})
+
+	t.Run("Apply", func(t *testing.T) {
+		azdCtx := newProject(t)
+		projectDir := azdCtx.ProjectDirectory()
+
+		upgrade, err := newInitializer().Upgrade(context.Background(), azdCtx, "", false)
+		require.NoError(t, err)
+		require.Equal(t, expectedChanges, upgrade.Changes)
+		require.Len(t, upgrade.Conflicts(), 2)
+
+		verifyFileContent(t, filepath.Join(projectDir, "README.md"), "LOCAL line1\nline2\nline3\nline4\nline5 v2\n")
+		verifyFileContent(t, filepath.Join(projectDir, "infra", "main.bicep"), "param location string\nparam name string\n")
+		verifyFileContent(t, filepath.Join(projectDir, "infra", "new.bicep"), "// new\n")
+		verifyFileContent(t, filepath.Join(projectDir, "infra", "custom.bicep"), "// mine\n")
+		require.NoFileExists(t, filepath.Join(projectDir, "infra", "old.bicep"))
+
+		app := readFile(t, filepath.Join(projectDir, "src", "api", "app.py"))
+		require.Contains(t, app, "<<<<<<< local\nprint('local')\n")
+		require.Contains(t, app, "print('v2')\n>>>>>>> "+latestCommit[:7])
+
+		prj, err := project.Load(context.Background(), azdCtx.ProjectPath())
+		require.NoError(t, err)
+		require.Equal(t, latestCommit, prj.Metadata.TemplateSource.Commit)
+		require.Equal(t, templateDir, prj.Metadata.TemplateSource.Repository)
+		require.Equal(t, "app", prj.Name)
+	})
+}
+
+func writeTestFiles(t *testing.T, dir string, files map[string]string) {
+	for name, contents := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), osutil.PermissionDirectory))
+		require.NoError(t, os.WriteFile(path, []byte(contents), osutil.PermissionFile))
+	}
+}
+
+// gitCommitAll commits all the files of dir, initializing a repository if needed, and returns the commit SHA.
+func gitCommitAll(t *testing.T, runner exec.CommandRunner, dir string) string {
+	gitArgs := exec.NewRunArgs("git", "-C", dir)
+
+	_, err := runner.Run(context.Background(), gitArgs.AppendParams("init"))
+	require.NoError(t, err)
+
+	_, err = runner.Run(context.Background(), gitArgs.AppendParams("add", "-A"))
+	require.NoError(t, err)
+
+	_, err = runner.Run(context.Background(), gitArgs.AppendParams(
+		"-c", "user.name=azd", "-c", "user.email=azd@example.com", "commit", "-m", "template"))
+	require.NoError(t, err)
+
+	res, err := runner.Run(context.Background(), gitArgs.AppendParams("rev-parse", "HEAD"))
+	require.NoError(t, err)
+
+	return strings.TrimSpace(res.Stdout)
+}
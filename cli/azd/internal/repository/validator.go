@@ -0,0 +1,544 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/ext"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/jsonschema"
+	"github.com/azure/azure-dev/cli/azd/pkg/lazy"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/bicep"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/terraform"
+	"github.com/azure/azure-dev/schemas"
+	"gopkg.in/yaml.v3"
+)
+
+// ValidationStatus is the outcome of a template validation check.
+type ValidationStatus string
+
+const (
+	ValidationPassed  ValidationStatus = "passed"
+	ValidationWarning ValidationStatus = "warning"
+	ValidationFailed  ValidationStatus = "failed"
+	ValidationSkipped ValidationStatus = "skipped"
+)
+
+// ValidationCheck is the result of one of the checks of a template validation.
+type ValidationCheck struct {
+	Name     string           `json:"name"`
+	Status   ValidationStatus `json:"status"`
+	Messages []string         `json:"messages,omitempty"`
+}
+
+// TemplateValidationReport is the result of the validation of a template.
+type TemplateValidationReport struct {
+	Template string            `json:"template"`
+	Valid    bool              `json:"valid"`
+	Checks   []ValidationCheck `json:"checks"`
+}
+
+// TemplateValidator checks templates before they are published.
+type TemplateValidator struct {
+	lazyBicepCli *lazy.Lazy[bicep.BicepCli]
+	terraformCli terraform.TerraformCli
+}
+
+func NewTemplateValidator(
+	lazyBicepCli *lazy.Lazy[bicep.BicepCli],
+	terraformCli terraform.TerraformCli,
+) *TemplateValidator {
+	return &TemplateValidator{
+		lazyBicepCli: lazyBicepCli,
+		terraformCli: terraformCli,
+	}
+}
+
+// Validate validates the template in the directory templateDir. The template is reported as template.
+//
+// The project file is loaded and checked against the azure.yaml schema, the infrastructure is compiled with Bicep or
+// validated with Terraform, the host of each service is checked to have a resource tagged with its name and the
+// scripts referenced by hooks are checked to exist. Checks which depend on a failed check are skipped.
+func (v *TemplateValidator) Validate(
+	ctx context.Context, templateDir string, template string) (*TemplateValidationReport, error) {
+	report := &TemplateValidationReport{Template: template}
+	azdCtx := azdcontext.NewAzdContextWithDirectory(templateDir)
+
+	prj, projectCheck := v.validateProject(ctx, azdCtx)
+	report.Checks = append(report.Checks, projectCheck)
+
+	schemaCheck, err := v.validateSchema(azdCtx)
+	if err != nil {
+		return nil, err
+	}
+	report.Checks = append(report.Checks, schemaCheck)
+
+	if prj == nil {
+		for _, name := range []string{"infra", "serviceTags", "hooks"} {
+			report.Checks = append(report.Checks, ValidationCheck{
+				Name:     name,
+				Status:   ValidationSkipped,
+				Messages: []string{"the project file couldn't be loaded"},
+			})
+		}
+	} else {
+		infraCheck, tags := v.validateInfra(ctx, prj)
+		report.Checks = append(report.Checks, infraCheck, validateServiceTags(prj, tags), validateHooks(prj))
+	}
+
+	report.Valid = !slices.ContainsFunc(report.Checks, func(check ValidationCheck) bool {
+		return check.Status == ValidationFailed
+	})
+
+	return report, nil
+}
+
+func (v *TemplateValidator) validateProject(
+	ctx context.Context, azdCtx *azdcontext.AzdContext) (*project.ProjectConfig, ValidationCheck) {
+	check := ValidationCheck{Name: "project", Status: ValidationPassed}
+
+	prj, err := project.Load(ctx, azdCtx.ProjectPath())
+	if err != nil {
+		check.Status = ValidationFailed
+		check.Messages = []string{err.Error()}
+		return nil, check
+	}
+
+	if len(prj.Services) == 0 {
+		check.Status = ValidationWarning
+		check.Messages = []string{"the project doesn't declare any service"}
+	}
+
+	return prj, check
+}
+
+// schemaAnnotationRegex matches the yaml-language-server annotation which sets the JSON schema of a YAML file.
+var schemaAnnotationRegex = regexp.MustCompile(`(?m)^#\s*yaml-language-server:\s*\$schema=(\S+)\s*$`)
+
+// azureYamlSchemas are the JSON schemas of azure.yaml by their version, the directory of the schema in the schemas
+// folder of the repository, ex) .../schemas/alpha/azure.yaml.json.
+var azureYamlSchemas = map[string][]byte{
+	"v1.0":  schemas.AzureYamlV1,
+	"alpha": schemas.AzureYamlAlpha,
+}
+
+// projectSchema returns the JSON schema set by the yaml-language-server annotation of the project file, or the v1.0
+// schema when there is none. An error is returned when the annotation doesn't reference a schema of azure.yaml.
+func projectSchema(contents []byte) ([]byte, error) {
+	match := schemaAnnotationRegex.FindSubmatch(contents)
+	if match == nil {
+		return schemas.AzureYamlV1, nil
+	}
+
+	schemaUrl := string(match[1])
+	parsed, err := url.Parse(schemaUrl)
+	if err != nil {
+		return nil, fmt.Errorf("parsing the schema url '%s': %w", schemaUrl, err)
+	}
+
+	segments := strings.Split(parsed.Path, "/")
+	if n := len(segments); n >= 3 && segments[n-3] == "schemas" && segments[n-1] == "azure.yaml.json" {
+		if schema, has := azureYamlSchemas[segments[n-2]]; has {
+			return schema, nil
+		}
+	}
+
+	return nil, fmt.Errorf("the schema '%s' isn't a known azure.yaml schema", schemaUrl)
+}
+
+// validateSchema checks the project file against the JSON schema of azure.yaml referenced by its yaml-language-server
+// annotation.
+func (v *TemplateValidator) validateSchema(azdCtx *azdcontext.AzdContext) (ValidationCheck, error) {
+	check := ValidationCheck{Name: "schema", Status: ValidationPassed}
+
+	contents, err := os.ReadFile(azdCtx.ProjectPath())
+	if err != nil {
+		check.Status = ValidationSkipped
+		check.Messages = []string{err.Error()}
+		return check, nil
+	}
+
+	var document any
+	if err := yaml.Unmarshal(contents, &document); err != nil {
+		check.Status = ValidationFailed
+		check.Messages = []string{fmt.Sprintf("parsing %s: %s", azdcontext.ProjectFileName, err)}
+		return check, nil
+	}
+
+	schemaContents, err := projectSchema(contents)
+	if err != nil {
+		check.Status = ValidationFailed
+		check.Messages = []string{err.Error()}
+		return check, nil
+	}
+
+	schema, err := jsonschema.Compile(schemaContents)
+	if err != nil {
+		return check, err
+	}
+
+	for _, validationErr := range schema.Validate(document) {
+		check.Status = ValidationFailed
+		check.Messages = append(check.Messages, validationErr.String())
+	}
+
+	return check, nil
+}
+
+// validateInfra compiles or validates the infrastructure of the project and returns the values of the azd-service-name
+// tags it declares.
+func (v *TemplateValidator) validateInfra(ctx context.Context, prj *project.ProjectConfig) (ValidationCheck, *serviceTags) {
+	check := ValidationCheck{Name: "infra", Status: ValidationPassed}
+	fail := func(err error) (ValidationCheck, *serviceTags) {
+		check.Status = ValidationFailed
+		check.Messages = append(check.Messages, err.Error())
+		return check, nil
+	}
+
+	infraPath := prj.Infra.Path
+	if infraPath == "" {
+		infraPath = project.DefaultPath
+	}
+	if !filepath.IsAbs(infraPath) {
+		infraPath = filepath.Join(prj.Path, infraPath)
+	}
+	module := prj.Infra.Module
+	if module == "" {
+		module = project.DefaultModule
+	}
+
+	if _, err := os.Stat(infraPath); err != nil {
+		check.Status = ValidationSkipped
+		check.Messages = []string{fmt.Sprintf("the infrastructure folder '%s' was not found", prj.Infra.Path)}
+		return check, nil
+	}
+
+	switch prj.Infra.Provider {
+	case provisioning.Terraform:
+		if err := v.validateTerraform(ctx, infraPath); err != nil {
+			return fail(err)
+		}
+
+		tags, err := terraformServiceTags(infraPath)
+		if err != nil {
+			return fail(err)
+		}
+
+		return check, tags
+	case provisioning.Bicep, provisioning.NotSpecified:
+		bicepCli, err := v.lazyBicepCli.GetValue()
+		if err != nil {
+			return fail(err)
+		}
+
+		result, err := bicepCli.Build(ctx, filepath.Join(infraPath, module+".bicep"))
+		if err != nil {
+			return fail(err)
+		}
+
+		var template map[string]any
+		if err := json.Unmarshal([]byte(result.Compiled), &template); err != nil {
+			return fail(fmt.Errorf("parsing compiled template: %w", err))
+		}
+
+		tags := &serviceTags{}
+		tags.collectArm(template, nil)
+		return check, tags
+	default:
+		check.Status = ValidationSkipped
+		check.Messages = []string{fmt.Sprintf("infrastructure provider '%s' isn't validated", prj.Infra.Provider)}
+		return check, nil
+	}
+}
+
+// validateTerraform initializes and validates the Terraform module at infraPath without changing the files of the
+// template: the providers and modules are downloaded to a temporary data directory and the dependency lock file written
+// by the initialization is restored.
+func (v *TemplateValidator) validateTerraform(ctx context.Context, infraPath string) (err error) {
+	dataDir, err := os.MkdirTemp("", "azd-terraform-validate")
+	if err != nil {
+		return fmt.Errorf("creating temp folder: %w", err)
+	}
+	defer os.RemoveAll(dataDir)
+
+	lockPath := filepath.Join(infraPath, ".terraform.lock.hcl")
+	lock, err := os.ReadFile(lockPath)
+	hasLock := err == nil
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("reading the dependency lock file: %w", err)
+	}
+
+	defer func() {
+		var restoreErr error
+		if hasLock {
+			restoreErr = os.WriteFile(lockPath, lock, osutil.PermissionFile)
+		} else if removeErr := os.Remove(lockPath); !errors.Is(removeErr, os.ErrNotExist) {
+			restoreErr = removeErr
+		}
+
+		if restoreErr != nil && err == nil {
+			err = fmt.Errorf("restoring the dependency lock file: %w", restoreErr)
+		}
+	}()
+
+	v.terraformCli.SetEnv([]string{fmt.Sprintf("TF_DATA_DIR=%s", dataDir)})
+
+	if _, err := v.terraformCli.Init(ctx, infraPath, "-backend=false"); err != nil {
+		return err
+	}
+
+	if _, err := v.terraformCli.Validate(ctx, infraPath); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// taggedHosts are the hosts which find the resource of a service by its azd-service-name tag.
+var taggedHosts = []project.ServiceTargetKind{
+	project.AppServiceTarget,
+	project.ContainerAppTarget,
+	project.AzureFunctionTarget,
+	project.StaticWebAppTarget,
+	project.SpringAppTarget,
+}
+
+func validateServiceTags(prj *project.ProjectConfig, tags *serviceTags) ValidationCheck {
+	check := ValidationCheck{Name: "serviceTags", Status: ValidationPassed}
+	if tags == nil {
+		check.Status = ValidationSkipped
+		check.Messages = []string{"the infrastructure couldn't be inspected"}
+		return check
+	}
+
+	for _, name := range sortedKeys(prj.Services) {
+		service := prj.Services[name]
+		if !slices.Contains(taggedHosts, service.Host) || service.ResourceName != osutil.NewExpandableString("") ||
+			slices.Contains(tags.names, name) {
+			continue
+		}
+
+		if tags.unresolved {
+			if check.Status == ValidationPassed {
+				check.Status = ValidationWarning
+			}
+			check.Messages = append(check.Messages, fmt.Sprintf(
+				"service '%s' (host: %s): no resource with a literal '%s: %s' tag was found and some tags are "+
+					"computed, so it couldn't be verified", name, service.Host, azure.TagKeyAzdServiceName, name))
+			continue
+		}
+
+		check.Status = ValidationFailed
+		check.Messages = append(check.Messages, fmt.Sprintf(
+			"service '%s' (host: %s): no resource is tagged with '%s: %s'",
+			name, service.Host, azure.TagKeyAzdServiceName, name))
+	}
+
+	return check
+}
+
+// scriptPathRegex matches hooks which run a script file rather than an inline script.
+var scriptPathRegex = regexp.MustCompile(`^[^\s]+\.(sh|ps1)$`)
+
+func validateHooks(prj *project.ProjectConfig) ValidationCheck {
+	check := ValidationCheck{Name: "hooks", Status: ValidationPassed}
+
+	validate := func(owner string, dir string, hooks map[string]*ext.HookConfig) {
+		for _, name := range sortedKeys(hooks) {
+			for _, hook := range []*ext.HookConfig{hooks[name], hooks[name].Windows, hooks[name].Posix} {
+				if hook == nil || !scriptPathRegex.MatchString(hook.Run) {
+					continue
+				}
+
+				if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(hook.Run))); err != nil {
+					check.Status = ValidationFailed
+					check.Messages = append(check.Messages,
+						fmt.Sprintf("%s hook '%s': script '%s' was not found", owner, name, hook.Run))
+				}
+			}
+		}
+	}
+
+	validate("project", prj.Path, prj.Hooks)
+	for _, name := range sortedKeys(prj.Services) {
+		service := prj.Services[name]
+		validate(fmt.Sprintf("service '%s'", name), service.Path(), service.Hooks)
+	}
+
+	return check
+}
+
+// serviceTags are the values of the azd-service-name tags declared by the infrastructure.
+type serviceTags struct {
+	names []string
+	// unresolved is set when some tags are computed from values which can't be resolved statically.
+	unresolved bool
+}
+
+func (t *serviceTags) add(name string) {
+	if !slices.Contains(t.names, name) {
+		t.names = append(t.names, name)
+	}
+}
+
+var (
+	armParameterRegex  = regexp.MustCompile(`^\[parameters\('([^']+)'\)\]$`)
+	armServiceTagRegex = regexp.MustCompile(
+		`'` + azure.TagKeyAzdServiceName + `',\s*(?:'([^']*)'|parameters\('([^']+)'\))`)
+)
+
+// collectArm collects the tags of the resources of an ARM template, and of its nested deployments, resolving the
+// parameters of the template from the values of params.
+func (t *serviceTags) collectArm(template map[string]any, params map[string]any) {
+	definitions, _ := template["parameters"].(map[string]any)
+	parameter := func(name string) (string, bool) {
+		if value, has := params[name].(string); has {
+			return value, true
+		}
+		if definition, has := definitions[name].(map[string]any); has {
+			if value, has := definition["defaultValue"].(string); has && !strings.HasPrefix(value, "[") {
+				return value, true
+			}
+		}
+		return "", false
+	}
+	resolve := func(expression string) {
+		if !strings.HasPrefix(expression, "[") {
+			t.add(expression)
+		} else if match := armParameterRegex.FindStringSubmatch(expression); match != nil {
+			if value, has := parameter(match[1]); has {
+				t.add(value)
+			} else {
+				t.unresolved = true
+			}
+		} else {
+			t.unresolved = true
+		}
+	}
+
+	// resources is an array, or a map with symbolic names in templates with languageVersion 2.0
+	var resources []any
+	switch typed := template["resources"].(type) {
+	case []any:
+		resources = typed
+	case map[string]any:
+		for _, name := range sortedKeys(typed) {
+			resources = append(resources, typed[name])
+		}
+	}
+
+	for _, item := range resources {
+		resource, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		switch tags := resource["tags"].(type) {
+		case map[string]any:
+			if value, has := tags[azure.TagKeyAzdServiceName].(string); has {
+				resolve(value)
+			}
+		case string:
+			for _, match := range armServiceTagRegex.FindAllStringSubmatch(tags, -1) {
+				if match[2] == "" {
+					t.add(match[1])
+				} else if value, has := parameter(match[2]); has {
+					t.add(value)
+				} else {
+					t.unresolved = true
+				}
+			}
+		}
+
+		if resource["type"] != "Microsoft.Resources/deployments" {
+			continue
+		}
+
+		properties, _ := resource["properties"].(map[string]any)
+		nested, has := properties["template"].(map[string]any)
+		if !has {
+			continue
+		}
+
+		nestedParams := map[string]any{}
+		values, _ := properties["parameters"].(map[string]any)
+		for name, item := range values {
+			value, _ := item.(map[string]any)
+			expression, ok := value["value"].(string)
+			if !ok {
+				continue
+			}
+
+			if !strings.HasPrefix(expression, "[") {
+				nestedParams[name] = expression
+			} else if match := armParameterRegex.FindStringSubmatch(expression); match != nil {
+				if resolved, has := parameter(match[1]); has {
+					nestedParams[name] = resolved
+				}
+			}
+		}
+
+		t.collectArm(nested, nestedParams)
+	}
+}
+
+var terraformServiceTagRegex = regexp.MustCompile(
+	`"?` + azure.TagKeyAzdServiceName + `"?\s*[=:]\s*("([^"$]*)")?`)
+
+// terraformServiceTags collects the tags of the Terraform files of dir and its subdirectories.
+func terraformServiceTags(dir string) (*serviceTags, error) {
+	tags := &serviceTags{}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && d.Name() == ".terraform" {
+			return filepath.SkipDir
+		}
+		if d.IsDir() || filepath.Ext(path) != ".tf" {
+			return nil
+		}
+
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		for _, match := range terraformServiceTagRegex.FindAllStringSubmatch(string(contents), -1) {
+			if match[1] == "" {
+				tags.unresolved = true
+			} else {
+				tags.add(match[2])
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
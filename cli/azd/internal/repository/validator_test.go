@@ -0,0 +1,275 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/lazy"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/bicep"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/terraform"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockexec"
+	"github.com/azure/azure-dev/schemas"
+	"github.com/stretchr/testify/require"
+)
+
+// compiledTemplate is the compiled main.bicep of the tests, where the api is tagged by a module with a parameter.
+const compiledTemplate = `{
+	"parameters": { "tags": { "type": "object", "defaultValue": {} } },
+	"resources": [
+		{
+			"type": "Microsoft.Web/sites",
+			"name": "web",
+			"tags": { "azd-service-name": "web" }
+		},
+		{
+			"type": "Microsoft.Resources/deployments",
+			"name": "api",
+			"properties": {
+				"parameters": { "serviceName": { "value": "api" } },
+				"template": {
+					"parameters": { "serviceName": { "type": "string" }, "tags": { "type": "object" } },
+					"resources": [
+						{
+							"type": "Microsoft.App/containerApps",
+							"name": "api",
+							"tags": "[union(parameters('tags'), createObject('azd-service-name', parameters('serviceName')))]"
+						}
+					]
+				}
+			}
+		}
+	]
+}`
+
+func Test_TemplateValidator_Validate(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		templateDir := t.TempDir()
+		writeTestFiles(t, templateDir, map[string]string{
+			"azure.yaml": `name: app
+hooks:
+  postprovision:
+    run: ./scripts/post.sh
+services:
+  api:
+    project: ./src/api
+    language: py
+    host: containerapp
+  web:
+    project: ./src/web
+    language: js
+    host: appservice
+`,
+			"infra/main.bicep": "",
+			"scripts/post.sh":  "",
+			"src/api/app.py":   "",
+			"src/web/index.js": "",
+		})
+
+		validator := NewTemplateValidator(newTestBicepCli(compiledTemplate), nil)
+		report, err := validator.Validate(context.Background(), templateDir, "app")
+		require.NoError(t, err)
+		require.True(t, report.Valid, "%+v", report.Checks)
+		for _, check := range report.Checks {
+			require.Equal(t, ValidationPassed, check.Status, check.Name)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		templateDir := t.TempDir()
+		writeTestFiles(t, templateDir, map[string]string{
+			"azure.yaml": `name: app
+bogus: true
+services:
+  api:
+    project: ./src/api
+    language: py
+    host: containerapp
+    hooks:
+      predeploy:
+        posix:
+          run: ./missing.sh
+        windows:
+          run: ./missing.ps1
+  worker:
+    project: ./src/worker
+    language: py
+    host: function
+`,
+			"infra/main.bicep":    "",
+			"src/api/app.py":      "",
+			"src/worker/main.py":  "",
+			"src/api/missing.ps1": "",
+		})
+
+		validator := NewTemplateValidator(newTestBicepCli(compiledTemplate), nil)
+		report, err := validator.Validate(context.Background(), templateDir, "app")
+		require.NoError(t, err)
+		require.False(t, report.Valid)
+
+		require.Equal(t, []ValidationCheck{
+			{Name: "project", Status: ValidationPassed},
+			{Name: "schema", Status: ValidationFailed, Messages: []string{"additionalProperties 'bogus' not allowed"}},
+			{Name: "infra", Status: ValidationPassed},
+			{
+				Name:     "serviceTags",
+				Status:   ValidationFailed,
+				Messages: []string{"service 'worker' (host: function): no resource is tagged with 'azd-service-name: worker'"},
+			},
+			{
+				Name:     "hooks",
+				Status:   ValidationFailed,
+				Messages: []string{"service 'api' hook 'predeploy': script './missing.sh' was not found"},
+			},
+		}, report.Checks)
+	})
+
+	t.Run("MissingProject", func(t *testing.T) {
+		validator := NewTemplateValidator(newTestBicepCli(compiledTemplate), nil)
+		report, err := validator.Validate(context.Background(), t.TempDir(), "app")
+		require.NoError(t, err)
+		require.False(t, report.Valid)
+		require.Equal(t, ValidationFailed, report.Checks[0].Status)
+		require.Equal(t, ValidationSkipped, report.Checks[2].Status)
+	})
+
+	t.Run("Terraform", func(t *testing.T) {
+		templateDir := t.TempDir()
+		writeTestFiles(t, templateDir, map[string]string{
+			"azure.yaml": `name: app
+infra:
+  provider: terraform
+services:
+  api:
+    project: ./src/api
+    language: py
+    host: containerapp
+  web:
+    project: ./src/web
+    language: js
+    host: staticwebapp
+`,
+			"infra/main.tf": `resource "azurerm_static_web_app" "web" {
+  tags = merge(local.tags, { azd-service-name : "web" })
+}
+`,
+			"infra/modules/app.tf": `resource "azurerm_container_app" "app" {
+  tags = merge(local.tags, { "azd-service-name" = var.service_name })
+}
+`,
+			"src/api/app.py":   "",
+			"src/web/index.js": "",
+		})
+
+		runner := mockexec.NewMockCommandRunner()
+		var commands []string
+		var dataDirs []string
+		runner.When(func(args exec.RunArgs, command string) bool {
+			return args.Cmd == "terraform"
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			commands = append(commands, args.Args[1])
+			dataDirs = append(dataDirs, args.Env...)
+
+			// init writes the dependency lock file of the module
+			if args.Args[1] == "init" {
+				modulePath := strings.TrimPrefix(args.Args[0], "-chdir=")
+				err := os.WriteFile(filepath.Join(modulePath, ".terraform.lock.hcl"), []byte("upgraded"), 0600)
+				if err != nil {
+					return exec.RunResult{}, err
+				}
+			}
+
+			return exec.NewRunResult(0, "", ""), nil
+		})
+
+		validator := NewTemplateValidator(nil, terraform.NewTerraformCli(runner))
+		report, err := validator.Validate(context.Background(), templateDir, "app")
+		require.NoError(t, err)
+		require.True(t, report.Valid, "%+v", report.Checks)
+		require.Equal(t, []string{"init", "validate"}, commands)
+
+		// the providers are downloaded outside of the template, which is left unchanged
+		require.Len(t, dataDirs, 2)
+		require.Equal(t, dataDirs[0], dataDirs[1])
+		require.True(t, strings.HasPrefix(dataDirs[0], "TF_DATA_DIR="))
+		require.NoDirExists(t, strings.TrimPrefix(dataDirs[0], "TF_DATA_DIR="))
+		require.NoFileExists(t, filepath.Join(templateDir, "infra", ".terraform.lock.hcl"))
+
+		// an existing lock file is restored
+		writeTestFiles(t, templateDir, map[string]string{"infra/.terraform.lock.hcl": "locked"})
+		_, err = validator.Validate(context.Background(), templateDir, "app")
+		require.NoError(t, err)
+		lock, err := os.ReadFile(filepath.Join(templateDir, "infra", ".terraform.lock.hcl"))
+		require.NoError(t, err)
+		require.Equal(t, "locked", string(lock))
+
+		tagsCheck := report.Checks[slices.IndexFunc(report.Checks, func(c ValidationCheck) bool {
+			return c.Name == "serviceTags"
+		})]
+		require.Equal(t, ValidationWarning, tagsCheck.Status)
+		require.Len(t, tagsCheck.Messages, 1)
+		require.Contains(t, tagsCheck.Messages[0], "service 'api'")
+	})
+}
+
+func Test_projectSchema(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		expected []byte
+	}{
+		{"NoAnnotation", "name: app\n", schemas.AzureYamlV1},
+		{
+			"V1",
+			"# yaml-language-server: $schema=https://raw.githubusercontent.com/Azure/azure-dev/main/schemas/v1.0/azure.yaml.json\n",
+			schemas.AzureYamlV1,
+		},
+		{
+			"Alpha",
+			"# yaml-language-server: $schema=https://raw.githubusercontent.com/Azure/azure-dev/main/schemas/alpha/azure.yaml.json\n",
+			schemas.AzureYamlAlpha,
+		},
+		{
+			"OtherComment",
+			"# see https://raw.githubusercontent.com/Azure/azure-dev/main/schemas/alpha/azure.yaml.json\nname: app\n",
+			schemas.AzureYamlV1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := projectSchema([]byte(tt.contents))
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, schema)
+		})
+	}
+
+	t.Run("Unknown", func(t *testing.T) {
+		_, err := projectSchema([]byte("# yaml-language-server: $schema=https://example.com/schemas/v2/azure.yaml.json\n"))
+		require.ErrorContains(t, err, "isn't a known azure.yaml schema")
+	})
+}
+
+type testBicepCli struct {
+	compiled string
+}
+
+func (cli *testBicepCli) Build(ctx context.Context, file string) (bicep.BuildResult, error) {
+	return bicep.BuildResult{Compiled: cli.compiled}, nil
+}
+
+func (cli *testBicepCli) BuildBicepParam(ctx context.Context, file string, env []string) (bicep.BuildResult, error) {
+	return bicep.BuildResult{Compiled: cli.compiled}, nil
+}
+
+func (cli *testBicepCli) Decompile(ctx context.Context, file string, outFile string) (string, error) {
+	return "", nil
+}
+
+func newTestBicepCli(compiled string) *lazy.Lazy[bicep.BicepCli] {
+	return lazy.From[bicep.BicepCli](&testBicepCli{compiled: compiled})
+}
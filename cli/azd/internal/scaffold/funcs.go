@@ -77,6 +77,19 @@ func AlphaSnakeUpper(name string) string {
 	return sb.String()
 }
 
+// TerraformName returns a name in lower-snake case alphanumeric, suitable for a Terraform resource or variable name.
+//
+// Non-alphanumeric characters are discarded, while consecutive separators ('-', '_', and '.') are treated
+// as a single underscore separator. Names that start with a digit are prefixed with an underscore.
+func TerraformName(name string) string {
+	res := strings.ToLower(AlphaSnakeUpper(name))
+	if len(res) > 0 && '0' <= res[0] && res[0] <= '9' {
+		res = "_" + res
+	}
+
+	return res
+}
+
 func isAsciiAlphaNumeric(c byte) bool {
 	return ('0' <= c && c <= '9') || ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z')
 }
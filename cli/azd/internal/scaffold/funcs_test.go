@@ -65,6 +65,25 @@ func Test_AlphaUpperSnake(t *testing.T) {
 	}
 }
 
+func Test_TerraformName(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"lowercase separators", "this-is-My-var-123", "this_is_my_var_123"},
+		{"allowed characters", "myVar!#%^", "myvar"},
+		{"dash at front or end", "--my-var--", "my_var"},
+		{"leading digit", "1-api", "_1_api"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := TerraformName(tt.in)
+			assert.Equal(t, tt.want, actual)
+		})
+	}
+}
+
 func Test_EnvFormat(t *testing.T) {
 	tests := []struct {
 		name     string
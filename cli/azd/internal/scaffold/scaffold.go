@@ -16,6 +16,7 @@ import (
 
 const baseRoot = "scaffold/base"
 const templateRoot = "scaffold/templates"
+const terraformRoot = "scaffold/terraform"
 
 // Copy base assets to the target directory.
 func CopyBase(targetDir string) error {
@@ -49,18 +50,19 @@ func copyFS(embedFs fs.FS, root string, target string) error {
 	})
 }
 
+var funcMap = template.FuncMap{
+	"bicepName":         BicepName,
+	"terraformName":     TerraformName,
+	"containerAppInfix": ContainerAppInfix,
+	"upper":             strings.ToUpper,
+	"lower":             strings.ToLower,
+	"formatParam":       FormatParameter,
+}
+
 // Load loads all templates as a template.Template.
 //
 // To execute a named template, call Execute with the defined name.
 func Load() (*template.Template, error) {
-	funcMap := template.FuncMap{
-		"bicepName":         BicepName,
-		"containerAppInfix": ContainerAppInfix,
-		"upper":             strings.ToUpper,
-		"lower":             strings.ToLower,
-		"formatParam":       FormatParameter,
-	}
-
 	t, err := template.New("templates").
 		Option("missingkey=error").
 		Funcs(funcMap).
@@ -73,6 +75,23 @@ func Load() (*template.Template, error) {
 	return t, nil
 }
 
+// LoadTerraform loads all Terraform templates as a template.Template.
+//
+// The Terraform templates define the same set of top-level names as the templates returned by Load where applicable,
+// such as next-steps.md, so that either can be executed with Execute.
+func LoadTerraform() (*template.Template, error) {
+	t, err := template.New("templates").
+		Option("missingkey=error").
+		Funcs(funcMap).
+		ParseFS(resources.ScaffoldTerraformTemplates,
+			path.Join(terraformRoot, "*"))
+	if err != nil {
+		return nil, fmt.Errorf("parsing terraform templates: %w", err)
+	}
+
+	return t, nil
+}
+
 // Execute applies the template associated with t that has the given name
 // to the specified data object and writes the output to the dest path on the filesystem.
 func Execute(
@@ -129,10 +148,65 @@ func ExecInfra(
 		}
 	}
 
+	if spec.DbMySql != nil {
+		err = Execute(t, "db-mysql.bicep", spec.DbMySql, filepath.Join(infraApp, "db-mysql.bicep"))
+		if err != nil {
+			return fmt.Errorf("scaffolding mysql: %w", err)
+		}
+	}
+
+	if spec.DbSqlServer != nil {
+		err = Execute(t, "db-sqlserver.bicep", spec.DbSqlServer, filepath.Join(infraApp, "db-sqlserver.bicep"))
+		if err != nil {
+			return fmt.Errorf("scaffolding sql server: %w", err)
+		}
+	}
+
+	if spec.DbRedis != nil {
+		err = Execute(t, "db-redis.bicep", spec.DbRedis, filepath.Join(infraApp, "db-redis.bicep"))
+		if err != nil {
+			return fmt.Errorf("scaffolding redis: %w", err)
+		}
+	}
+
+	if spec.ServiceBus != nil {
+		err = Execute(t, "messaging-servicebus.bicep", spec.ServiceBus,
+			filepath.Join(infraApp, "messaging-servicebus.bicep"))
+		if err != nil {
+			return fmt.Errorf("scaffolding service bus: %w", err)
+		}
+	}
+
+	if spec.EventHubs != nil {
+		err = Execute(t, "messaging-eventhubs.bicep", spec.EventHubs, filepath.Join(infraApp, "messaging-eventhubs.bicep"))
+		if err != nil {
+			return fmt.Errorf("scaffolding event hubs: %w", err)
+		}
+	}
+
+	if spec.StorageAccount != nil {
+		err = Execute(t, "storage.bicep", spec.StorageAccount, filepath.Join(infraApp, "storage.bicep"))
+		if err != nil {
+			return fmt.Errorf("scaffolding storage: %w", err)
+		}
+	}
+
+	if spec.UsesAppServicePlan() {
+		err = Execute(t, "appservice-plan.bicep", spec, filepath.Join(infraRoot, "shared", "appservice-plan.bicep"))
+		if err != nil {
+			return fmt.Errorf("scaffolding app service plan: %w", err)
+		}
+	}
+
 	for _, svc := range spec.Services {
-		err = Execute(t, "host-containerapp.bicep", svc, filepath.Join(infraApp, svc.Name+".bicep"))
+		host := svc.HostKind()
+		if host != HostContainerApp && svc.Runtime == nil {
+			return fmt.Errorf("service %s hosted by %s requires a runtime", svc.Name, host.Display())
+		}
+
+		err = Execute(t, "host-"+string(host)+".bicep", svc, filepath.Join(infraApp, svc.Name+".bicep"))
 		if err != nil {
-			return fmt.Errorf("scaffolding containerapp: %w", err)
+			return fmt.Errorf("scaffolding %s: %w", host, err)
 		}
 	}
 
@@ -149,6 +223,94 @@ func ExecInfra(
 	return nil
 }
 
+// ExecInfraTerraform scaffolds Terraform infrastructure files for the given spec, using the templates loaded by
+// LoadTerraform in t. The resulting files are written to the target directory.
+func ExecInfraTerraform(
+	t *template.Template,
+	spec InfraSpec,
+	target string) error {
+	if err := os.MkdirAll(target, osutil.PermissionDirectory); err != nil {
+		return err
+	}
+
+	if spec.DbCosmosMongo != nil {
+		err := Execute(t, "db-cosmos-mongo.tf", spec.DbCosmosMongo, filepath.Join(target, "db-cosmos-mongo.tf"))
+		if err != nil {
+			return fmt.Errorf("scaffolding cosmos mongodb: %w", err)
+		}
+	}
+
+	if spec.DbPostgres != nil {
+		err := Execute(t, "db-postgres.tf", spec.DbPostgres, filepath.Join(target, "db-postgres.tf"))
+		if err != nil {
+			return fmt.Errorf("scaffolding postgres: %w", err)
+		}
+	}
+
+	if spec.DbMySql != nil {
+		err := Execute(t, "db-mysql.tf", spec.DbMySql, filepath.Join(target, "db-mysql.tf"))
+		if err != nil {
+			return fmt.Errorf("scaffolding mysql: %w", err)
+		}
+	}
+
+	if spec.DbSqlServer != nil {
+		err := Execute(t, "db-sqlserver.tf", spec.DbSqlServer, filepath.Join(target, "db-sqlserver.tf"))
+		if err != nil {
+			return fmt.Errorf("scaffolding sql server: %w", err)
+		}
+	}
+
+	if spec.DbRedis != nil {
+		err := Execute(t, "db-redis.tf", spec.DbRedis, filepath.Join(target, "db-redis.tf"))
+		if err != nil {
+			return fmt.Errorf("scaffolding redis: %w", err)
+		}
+	}
+
+	if spec.ServiceBus != nil {
+		err := Execute(t, "messaging-servicebus.tf", spec.ServiceBus, filepath.Join(target, "messaging-servicebus.tf"))
+		if err != nil {
+			return fmt.Errorf("scaffolding service bus: %w", err)
+		}
+	}
+
+	if spec.EventHubs != nil {
+		err := Execute(t, "messaging-eventhubs.tf", spec.EventHubs, filepath.Join(target, "messaging-eventhubs.tf"))
+		if err != nil {
+			return fmt.Errorf("scaffolding event hubs: %w", err)
+		}
+	}
+
+	if spec.StorageAccount != nil {
+		err := Execute(t, "storage.tf", spec.StorageAccount, filepath.Join(target, "storage.tf"))
+		if err != nil {
+			return fmt.Errorf("scaffolding storage: %w", err)
+		}
+	}
+
+	for _, svc := range spec.Services {
+		host := svc.HostKind()
+		if host != HostContainerApp && svc.Runtime == nil {
+			return fmt.Errorf("service %s hosted by %s requires a runtime", svc.Name, host.Display())
+		}
+
+		err := Execute(t, "app-"+string(host)+".tf", svc, filepath.Join(target, "app-"+svc.Name+".tf"))
+		if err != nil {
+			return fmt.Errorf("scaffolding %s: %w", host, err)
+		}
+	}
+
+	for _, name := range []string{"provider.tf", "variables.tf", "main.tf", "outputs.tf", "main.tfvars.json"} {
+		err := Execute(t, name, spec, filepath.Join(target, name))
+		if err != nil {
+			return fmt.Errorf("scaffolding %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
 func preExecExpand(spec *InfraSpec) {
 	// postgres requires specific password seeding parameters
 	if spec.DbPostgres != nil {
@@ -161,10 +323,32 @@ func preExecExpand(spec *InfraSpec) {
 			})
 	}
 
+	if spec.DbMySql != nil {
+		spec.Parameters = append(spec.Parameters,
+			Parameter{
+				Name:   "mysqlDatabasePassword",
+				Value:  "$(secretOrRandomPassword ${AZURE_KEY_VAULT_NAME} mysqlDatabasePassword)",
+				Type:   "string",
+				Secret: true,
+			})
+	}
+
+	if spec.DbSqlServer != nil {
+		spec.Parameters = append(spec.Parameters,
+			Parameter{
+				Name:   "sqlDatabasePassword",
+				Value:  "$(secretOrRandomPassword ${AZURE_KEY_VAULT_NAME} sqlDatabasePassword)",
+				Type:   "string",
+				Secret: true,
+			})
+	}
+
 	for _, svc := range spec.Services {
 		// containerapp requires a global '_exist' parameter for each service
-		spec.Parameters = append(spec.Parameters,
-			containerAppExistsParameter(svc.Name))
+		if svc.HostKind() == HostContainerApp {
+			spec.Parameters = append(spec.Parameters,
+				containerAppExistsParameter(svc.Name))
+		}
 		spec.Parameters = append(spec.Parameters,
 			serviceDefPlaceholder(svc.Name))
 	}
@@ -2,6 +2,8 @@ package scaffold
 
 import (
 	"context"
+	"encoding/json"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
@@ -9,123 +11,255 @@ import (
 
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/bicep"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/terraform"
 	"github.com/azure/azure-dev/cli/azd/test/mocks/mockinput"
+	"github.com/azure/azure-dev/cli/azd/test/snapshot"
 	"github.com/stretchr/testify/require"
 )
 
-// Verify that the scaffolded infrastructure is valid bicep and free of lint errors.
-//
-// To have generated files saved under ./testdata, set SCAFFOLD_SAVE=true.
-func TestExecInfra(t *testing.T) {
-	template, err := Load()
-	require.NoError(t, err)
-
-	tests := []struct {
-		name string
-		spec InfraSpec
-	}{
-		{
-			"API only",
-			InfraSpec{
-				Services: []ServiceSpec{
-					{
-						Name: "api",
-						Port: 3100,
-					},
+var specTests = []struct {
+	name string
+	spec InfraSpec
+}{
+	{
+		"API only",
+		InfraSpec{
+			Services: []ServiceSpec{
+				{
+					Name: "api",
+					Port: 3100,
 				},
 			},
 		},
-		{
-			"Web only",
-			InfraSpec{
-				Services: []ServiceSpec{
-					{
-						Name:     "web",
-						Port:     3100,
-						Frontend: &Frontend{},
-					},
+	},
+	{
+		"Web only",
+		InfraSpec{
+			Services: []ServiceSpec{
+				{
+					Name:     "web",
+					Port:     3100,
+					Frontend: &Frontend{},
 				},
 			},
 		},
-		{
-			"API and web",
-			InfraSpec{
-				Services: []ServiceSpec{
-					{
-						Name: "api",
-						Port: 3100,
-						Backend: &Backend{
-							Frontends: []ServiceReference{
-								{
-									Name: "web",
-								},
+	},
+	{
+		"API and web",
+		InfraSpec{
+			Services: []ServiceSpec{
+				{
+					Name: "api",
+					Port: 3100,
+					Backend: &Backend{
+						Frontends: []ServiceReference{
+							{
+								Name: "web",
 							},
 						},
 					},
-					{
-						Name: "web",
-						Port: 3101,
-						Frontend: &Frontend{
-							Backends: []ServiceReference{
-								{
-									Name: "api",
-								},
+				},
+				{
+					Name: "web",
+					Port: 3101,
+					Frontend: &Frontend{
+						Backends: []ServiceReference{
+							{
+								Name: "api",
 							},
 						},
 					},
 				},
 			},
 		},
-		{
-			"API with Postgres",
-			InfraSpec{
-				DbPostgres: &DatabasePostgres{
-					DatabaseName: "appdb",
-					DatabaseUser: "appuser",
+	},
+	{
+		"API with Postgres",
+		InfraSpec{
+			DbPostgres: &DatabasePostgres{
+				DatabaseName: "appdb",
+				DatabaseUser: "appuser",
+			},
+			Services: []ServiceSpec{
+				{
+					Name: "api",
+					Port: 3100,
+					DbPostgres: &DatabaseReference{
+						DatabaseName: "appdb",
+					},
 				},
-				Services: []ServiceSpec{
-					{
-						Name: "api",
-						Port: 3100,
-						DbPostgres: &DatabaseReference{
-							DatabaseName: "appdb",
-						},
+			},
+		},
+	},
+	{
+		"API with MongoDB",
+		InfraSpec{
+			DbCosmosMongo: &DatabaseCosmosMongo{
+				DatabaseName: "appdb",
+			},
+			Services: []ServiceSpec{
+				{
+					Name: "api",
+					Port: 3100,
+					DbCosmosMongo: &DatabaseReference{
+						DatabaseName: "appdb",
 					},
 				},
 			},
 		},
-		{
-			"API with MongoDB",
-			InfraSpec{
-				DbCosmosMongo: &DatabaseCosmosMongo{
-					DatabaseName: "appdb",
+	},
+	{
+		"API with Redis",
+		InfraSpec{
+			DbRedis: &DatabaseRedis{},
+			Services: []ServiceSpec{
+				{
+					Name: "api",
+					Port: 3100,
+					DbRedis: &DatabaseReference{
+						DatabaseName: "redis",
+					},
 				},
-				Services: []ServiceSpec{
-					{
-						Name: "api",
-						Port: 3100,
-						DbCosmosMongo: &DatabaseReference{
-							DatabaseName: "appdb",
-						},
+			},
+		},
+	},
+	{
+		"API with MySQL",
+		InfraSpec{
+			DbMySql: &DatabaseMySql{
+				DatabaseName: "appdb",
+			},
+			Services: []ServiceSpec{
+				{
+					Name: "api",
+					Port: 3100,
+					DbMySql: &DatabaseReference{
+						DatabaseName: "appdb",
 					},
 				},
 			},
 		},
-		{
-			"API with Redis",
-			InfraSpec{
-				Services: []ServiceSpec{
-					{
-						Name: "api",
-						Port: 3100,
-						DbRedis: &DatabaseReference{
-							DatabaseName: "redis",
+	},
+	{
+		"API with SQL Server",
+		InfraSpec{
+			DbSqlServer: &DatabaseSqlServer{
+				DatabaseName: "appdb",
+			},
+			Services: []ServiceSpec{
+				{
+					Name: "api",
+					Port: 3100,
+					DbSqlServer: &DatabaseReference{
+						DatabaseName: "appdb",
+					},
+				},
+			},
+		},
+	},
+	{
+		"Worker with messaging and storage",
+		InfraSpec{
+			ServiceBus:     &ServiceBus{},
+			EventHubs:      &EventHubs{},
+			StorageAccount: &StorageAccount{},
+			Services: []ServiceSpec{
+				{
+					Name:           "worker",
+					Port:           0,
+					ServiceBus:     &ServiceBus{},
+					EventHubs:      &EventHubs{},
+					StorageAccount: &StorageAccount{},
+				},
+			},
+		},
+	},
+	{
+		"API on App Service with Postgres",
+		InfraSpec{
+			DbPostgres: &DatabasePostgres{
+				DatabaseName: "appdb",
+				DatabaseUser: "appuser",
+			},
+			Services: []ServiceSpec{
+				{
+					Name:    "api",
+					Port:    80,
+					Host:    HostAppService,
+					Runtime: &Runtime{Name: "python", Version: "3.11"},
+					DbPostgres: &DatabaseReference{
+						DatabaseName: "appdb",
+					},
+				},
+			},
+		},
+	},
+	{
+		"Functions API and web on App Service",
+		InfraSpec{
+			ServiceBus: &ServiceBus{},
+			Services: []ServiceSpec{
+				{
+					Name:    "api",
+					Port:    80,
+					Host:    HostFunction,
+					Runtime: &Runtime{Name: "dotnet", Version: "8.0"},
+					Backend: &Backend{
+						Frontends: []ServiceReference{
+							{
+								Name: "web",
+								Host: HostAppService,
+							},
+						},
+					},
+					ServiceBus: &ServiceBus{},
+				},
+				{
+					Name:    "web",
+					Port:    80,
+					Host:    HostAppService,
+					Runtime: &Runtime{Name: "node", Version: "20"},
+					Frontend: &Frontend{
+						Backends: []ServiceReference{
+							{
+								Name: "api",
+								Host: HostFunction,
+							},
 						},
 					},
 				},
 			},
 		},
-	}
+	},
+	{
+		"API on Container Apps and worker on Functions",
+		InfraSpec{
+			StorageAccount: &StorageAccount{},
+			Services: []ServiceSpec{
+				{
+					Name: "api",
+					Port: 3100,
+				},
+				{
+					Name:           "worker",
+					Port:           0,
+					Host:           HostFunction,
+					Runtime:        &Runtime{Name: "java", Version: "17"},
+					StorageAccount: &StorageAccount{},
+				},
+			},
+		},
+	},
+}
+
+// Verify that the scaffolded infrastructure is valid bicep and free of lint errors.
+//
+// To have generated files saved under ./testdata, set SCAFFOLD_SAVE=true.
+func TestExecInfra(t *testing.T) {
+	template, err := Load()
+	require.NoError(t, err)
+
+	tests := specTests
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			dir := t.TempDir()
@@ -158,3 +292,76 @@ func TestExecInfra(t *testing.T) {
 		})
 	}
 }
+
+// Verify that the Terraform infrastructure is scaffolded for all specs.
+//
+// To have generated files saved under ./testdata, set SCAFFOLD_SAVE=true.
+func TestExecInfraTerraform(t *testing.T) {
+	template, err := LoadTerraform()
+	require.NoError(t, err)
+
+	for _, tt := range specTests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			err := ExecInfraTerraform(
+				template,
+				tt.spec,
+				dir)
+			require.NoError(t, err)
+
+			require.FileExists(t, filepath.Join(dir, "main.tf"))
+			require.FileExists(t, filepath.Join(dir, "main.tfvars.json"))
+			for _, svc := range tt.spec.Services {
+				require.FileExists(t, filepath.Join(dir, "app-"+svc.Name+".tf"))
+			}
+
+			contents, err := os.ReadFile(filepath.Join(dir, "main.tfvars.json"))
+			require.NoError(t, err)
+			require.True(t, json.Valid(contents), "main.tfvars.json is not valid json")
+
+			// The generated files are compared with their snapshots, ex) TestExecInfraTerraform-API_only-main.tf.snap
+			err = fs.WalkDir(os.DirFS(dir), ".", func(path string, d fs.DirEntry, err error) error {
+				if err != nil || d.IsDir() {
+					return err
+				}
+
+				contents, err := os.ReadFile(filepath.Join(dir, path))
+				if err != nil {
+					return err
+				}
+
+				t.Run(path, func(t *testing.T) {
+					snapshot.SnapshotT(t, string(contents))
+				})
+				return nil
+			})
+			require.NoError(t, err)
+
+			if v := os.Getenv("SCAFFOLD_SAVE"); v != "" {
+				dest := filepath.Join("testdata", "terraform", strings.ReplaceAll(t.Name(), "/", "-"))
+				err := os.MkdirAll(dest, 0700)
+				require.NoError(t, err)
+
+				err = copyFS(os.DirFS(filepath.Dir(dir)), filepath.Base(dir), dest)
+				require.NoError(t, err)
+			}
+
+			if testing.Short() {
+				return
+			}
+
+			// Validating the configuration requires its providers, which terraform init downloads
+			ctx := context.Background()
+			cli := terraform.NewTerraformCli(exec.NewCommandRunner(nil))
+			if err := cli.CheckInstalled(ctx); err != nil {
+				t.Skipf("terraform isn't installed: %v", err)
+			}
+
+			_, err = cli.Init(ctx, dir, "-backend=false", "-input=false")
+			require.NoError(t, err)
+
+			_, err = cli.Validate(ctx, dir)
+			require.NoError(t, err)
+		})
+	}
+}
@@ -12,6 +12,14 @@ type InfraSpec struct {
 	// Databases to create
 	DbPostgres    *DatabasePostgres
 	DbCosmosMongo *DatabaseCosmosMongo
+	DbMySql       *DatabaseMySql
+	DbSqlServer   *DatabaseSqlServer
+	DbRedis       *DatabaseRedis
+
+	// Messaging and storage resources to create
+	ServiceBus     *ServiceBus
+	EventHubs      *EventHubs
+	StorageAccount *StorageAccount
 }
 
 type Parameter struct {
@@ -30,10 +38,111 @@ type DatabaseCosmosMongo struct {
 	DatabaseName string
 }
 
+type DatabaseMySql struct {
+	DatabaseName string
+}
+
+type DatabaseSqlServer struct {
+	DatabaseName string
+}
+
+// DatabaseRedis is an Azure Cache for Redis instance.
+type DatabaseRedis struct {
+}
+
+// ServiceBus is an Azure Service Bus namespace.
+type ServiceBus struct {
+}
+
+// EventHubs is an Azure Event Hubs namespace.
+type EventHubs struct {
+}
+
+// StorageAccount is an Azure Storage account.
+type StorageAccount struct {
+}
+
+// HostKind is the kind of Azure resource that hosts a service.
+type HostKind string
+
+const (
+	HostContainerApp HostKind = "containerapp"
+	HostAppService   HostKind = "appservice"
+	HostFunction     HostKind = "function"
+)
+
+// Display returns the display name of the host.
+func (h HostKind) Display() string {
+	switch h {
+	case HostContainerApp:
+		return "Azure Container Apps"
+	case HostAppService:
+		return "Azure App Service"
+	case HostFunction:
+		return "Azure Functions"
+	}
+
+	return ""
+}
+
+// Runtime is the language runtime of a service hosted as code, rather than as a container.
+type Runtime struct {
+	// The name of the runtime: python, node, dotnet or java.
+	Name    string
+	Version string
+}
+
+// AppServiceFxVersion returns the Linux runtime stack of the runtime on App Service.
+func (r Runtime) AppServiceFxVersion() string {
+	switch r.Name {
+	case "python":
+		return "PYTHON|" + r.Version
+	case "node":
+		return "NODE|" + r.Version + "-lts"
+	case "dotnet":
+		return "DOTNETCORE|" + r.Version
+	case "java":
+		return fmt.Sprintf("JAVA|%s-java%s", r.Version, r.Version)
+	}
+
+	return ""
+}
+
+// FunctionsFxVersion returns the Linux runtime stack of the runtime on Functions.
+func (r Runtime) FunctionsFxVersion() string {
+	switch r.Name {
+	case "python":
+		return "Python|" + r.Version
+	case "node":
+		return "Node|" + r.Version
+	case "dotnet":
+		return "DOTNET-ISOLATED|" + r.Version
+	case "java":
+		return "Java|" + r.Version
+	}
+
+	return ""
+}
+
+// FunctionsWorkerRuntime returns the language worker of the runtime on Functions.
+func (r Runtime) FunctionsWorkerRuntime() string {
+	if r.Name == "dotnet" {
+		return "dotnet-isolated"
+	}
+
+	return r.Name
+}
+
 type ServiceSpec struct {
 	Name string
 	Port int
 
+	// The kind of host of the service. Services without a host are hosted by Azure Container Apps.
+	Host HostKind
+
+	// The runtime of services hosted by App Service or Functions.
+	Runtime *Runtime
+
 	// Front-end properties.
 	Frontend *Frontend
 
@@ -43,7 +152,28 @@ type ServiceSpec struct {
 	// Connection to a database
 	DbPostgres    *DatabaseReference
 	DbCosmosMongo *DatabaseReference
+	DbMySql       *DatabaseReference
+	DbSqlServer   *DatabaseReference
 	DbRedis       *DatabaseReference
+
+	// Connection to messaging and storage resources, authenticated with the managed identity of the service
+	ServiceBus     *ServiceBus
+	EventHubs      *EventHubs
+	StorageAccount *StorageAccount
+}
+
+// HostKind returns the kind of host of the service.
+func (s ServiceSpec) HostKind() HostKind {
+	if s.Host == "" {
+		return HostContainerApp
+	}
+
+	return s.Host
+}
+
+// UsesIdentity reports whether the service connects to any resource using its managed identity.
+func (s ServiceSpec) UsesIdentity() bool {
+	return s.ServiceBus != nil || s.EventHubs != nil || s.StorageAccount != nil
 }
 
 type Frontend struct {
@@ -56,6 +186,52 @@ type Backend struct {
 
 type ServiceReference struct {
 	Name string
+
+	// The kind of host of the referenced service.
+	Host HostKind
+}
+
+// HostKind returns the kind of host of the referenced service.
+func (r ServiceReference) HostKind() HostKind {
+	if r.Host == "" {
+		return HostContainerApp
+	}
+
+	return r.Host
+}
+
+// UsesHost reports whether any service of the spec is hosted by the given kind of host.
+func (s InfraSpec) UsesHost(host string) bool {
+	for _, svc := range s.Services {
+		if string(svc.HostKind()) == host {
+			return true
+		}
+	}
+
+	return false
+}
+
+// UsesAppServicePlan reports whether any service of the spec is hosted by App Service or Functions.
+func (s InfraSpec) UsesAppServicePlan() bool {
+	return s.UsesHost(string(HostAppService)) || s.UsesHost(string(HostFunction))
+}
+
+// AllowsAppServiceOrigins reports whether a backend of the spec allows the origin of a frontend hosted by App Service or
+// Functions, whose host name is in a domain that depends on the cloud.
+func (s InfraSpec) AllowsAppServiceOrigins() bool {
+	for _, svc := range s.Services {
+		if svc.Backend == nil {
+			continue
+		}
+
+		for _, frontend := range svc.Backend.Frontends {
+			if frontend.HostKind() != HostContainerApp {
+				return true
+			}
+		}
+	}
+
+	return false
 }
 
 type DatabaseReference struct {
@@ -85,6 +85,7 @@ func (s *environmentService) CreateEnvironmentAsync(
 			filepath.Base(c.azdContext.ProjectDirectory()),
 			manifest,
 			hosts[0].Path,
+			"",
 		)
 		if err != nil {
 			return false, fmt.Errorf("generating project artifacts: %w", err)
@@ -9,6 +9,7 @@ import (
 	"io/fs"
 	"log"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
 	"slices"
@@ -57,7 +58,9 @@ func init() {
 				"removeDot": func(src string) string {
 					return strings.ReplaceAll(src, ".", "")
 				},
-				"envFormat": scaffold.EnvFormat,
+				"envFormat":             scaffold.EnvFormat,
+				"kubernetesPortName":    kubernetesPortName,
+				"kubernetesServicePort": kubernetesServicePort,
 			},
 		).
 		ParseFS(resources.AppHostTemplates, "apphost/templates/*")
@@ -68,6 +71,27 @@ func init() {
 	genTemplates = tmpl
 }
 
+// genTerraformTemplates is the collection of templates that are used when generating Terraform infrastructure files
+// from a manifest.
+var genTerraformTemplates *template.Template
+
+func init() {
+	tmpl, err := template.New("templates").
+		Option("missingkey=error").
+		Funcs(
+			template.FuncMap{
+				"terraformName":   scaffold.TerraformName,
+				"alphaSnakeUpper": scaffold.AlphaSnakeUpper,
+			},
+		).
+		ParseFS(resources.AppHostTerraformTemplates, "apphost/terraform/*")
+	if err != nil {
+		panic("failed to parse terraform generator templates: " + err.Error())
+	}
+
+	genTerraformTemplates = tmpl
+}
+
 type ContentsAndMode struct {
 	Contents string
 	Mode     fs.FileMode
@@ -131,11 +155,27 @@ func ContainerAppManifestTemplateForProject(
 	return buf.String(), nil
 }
 
+// Warnings returns a message for each resource of the given manifest that azd can't generate infrastructure for as
+// described by the manifest, such as executables and resources of unknown types.
+func Warnings(manifest *Manifest) ([]string, error) {
+	generator := newInfraGenerator()
+
+	if err := generator.LoadManifest(manifest); err != nil {
+		return nil, err
+	}
+
+	slices.Sort(generator.warnings)
+	return generator.warnings, nil
+}
+
 // BicepTemplate returns a filesystem containing the generated bicep files for the given manifest. These files represent
 // the shared infrastructure that would normally be under the `infra/` folder for the given manifest.
 func BicepTemplate(manifest *Manifest) (*memfs.FS, error) {
-	generator := newInfraGenerator()
+	return bicepTemplate(manifest, newInfraGenerator())
+}
 
+// bicepTemplate generates the bicep files for the given manifest using the given generator.
+func bicepTemplate(manifest *Manifest, generator *infraGenerator) (*memfs.FS, error) {
 	if err := generator.LoadManifest(manifest); err != nil {
 		return nil, err
 	}
@@ -213,6 +253,70 @@ func BicepTemplate(manifest *Manifest) (*memfs.FS, error) {
 	return fs, nil
 }
 
+// TerraformTemplate returns a filesystem containing the generated Terraform files for the given manifest. These files
+// describe the same shared resources as the files generated by BicepTemplate, with outputs of the same names.
+//
+// Manifests that require resources which are only supported with Bicep, such as containers deployed as part of the
+// infrastructure, Dapr components, Bicep modules, input parameters, Event Hubs, SignalR, AI Search or OpenAI, return
+// an error.
+func TerraformTemplate(manifest *Manifest) (*memfs.FS, error) {
+	generator := newInfraGenerator()
+
+	if err := generator.LoadManifest(manifest); err != nil {
+		return nil, err
+	}
+
+	if err := generator.Compile(); err != nil {
+		return nil, err
+	}
+
+	context := generator.bicepContext
+	var unsupported []string
+	for name := range context.ContainerApps {
+		unsupported = append(unsupported, name)
+	}
+	for name := range context.ContainerAppEnvironmentServices {
+		unsupported = append(unsupported, name)
+	}
+	for name := range context.DaprComponents {
+		unsupported = append(unsupported, name)
+	}
+	for name := range context.BicepModules {
+		unsupported = append(unsupported, name)
+	}
+	for name := range context.InputParameters {
+		unsupported = append(unsupported, name)
+	}
+	for name := range context.EventHubs {
+		unsupported = append(unsupported, name)
+	}
+	for name := range context.SignalRs {
+		unsupported = append(unsupported, name)
+	}
+	for name := range context.SearchServices {
+		unsupported = append(unsupported, name)
+	}
+	for name := range context.OpenAIs {
+		unsupported = append(unsupported, name)
+	}
+
+	if len(unsupported) > 0 {
+		slices.Sort(unsupported)
+		return nil, fmt.Errorf(
+			"generating terraform is not supported for resources: %s. Use bicep to provision these resources",
+			strings.Join(unsupported, ", "))
+	}
+
+	fs := memfs.New()
+	for _, name := range []string{"provider.tf", "variables.tf", "main.tf", "outputs.tf", "main.tfvars.json"} {
+		if err := executeToFS(fs, genTerraformTemplates, name, name, context); err != nil {
+			return nil, fmt.Errorf("generating infra/%s: %w", name, err)
+		}
+	}
+
+	return fs, nil
+}
+
 func inputMetadata(config InputDefaultGenerate) (string, error) {
 	finalLength := convert.ToValueWithDefault(config.MinLength, 0)
 	clusterLength := convert.ToValueWithDefault(config.MinLower, 0) +
@@ -253,13 +357,15 @@ func inputMetadata(config InputDefaultGenerate) (string, error) {
 }
 
 // GenerateProjectArtifacts generates all the artifacts to manage a project with `azd`. The azure.yaml file as well as
-// a helpful next-steps.md file.
+// a helpful next-steps.md file. When infraProvider is set, it is written as the infrastructure provider of the project
+// and, for terraform, the infrastructure files are generated under infra.
 func GenerateProjectArtifacts(
 	ctx context.Context,
 	projectDir string,
 	projectName string,
 	manifest *Manifest,
 	appHostProject string,
+	infraProvider string,
 ) (map[string]ContentsAndMode, error) {
 	appHostRel, err := filepath.Rel(projectDir, appHostProject)
 	if err != nil {
@@ -283,6 +389,7 @@ func GenerateProjectArtifacts(
 		Services: map[string]string{
 			"app": fmt.Sprintf(".%s%s", string(filepath.Separator), appHostRel),
 		},
+		InfraProvider: infraProvider,
 	}
 
 	if err := executeToFS(generatedFS, genTemplates, "azure.yaml", "azure.yaml", projectFileContext); err != nil {
@@ -293,6 +400,34 @@ func GenerateProjectArtifacts(
 		return nil, fmt.Errorf("generating next-steps.md: %w", err)
 	}
 
+	if infraProvider == "terraform" {
+		infraFS, err := TerraformTemplate(manifest)
+		if err != nil {
+			return nil, err
+		}
+
+		entries, err := fs.ReadDir(infraFS, ".")
+		if err != nil {
+			return nil, err
+		}
+
+		if err := generatedFS.MkdirAll("infra", osutil.PermissionDirectory); err != nil {
+			return nil, fmt.Errorf("creating directory: %w", err)
+		}
+
+		for _, entry := range entries {
+			contents, err := fs.ReadFile(infraFS, entry.Name())
+			if err != nil {
+				return nil, err
+			}
+
+			err = generatedFS.WriteFile(path.Join("infra", entry.Name()), contents, osutil.PermissionFile)
+			if err != nil {
+				return nil, fmt.Errorf("writing file: %w", err)
+			}
+		}
+	}
+
 	files := make(map[string]ContentsAndMode)
 
 	err = fs.WalkDir(generatedFS, ".", func(path string, d fs.DirEntry, err error) error {
@@ -336,6 +471,14 @@ type infraGenerator struct {
 	// keeps the value from value.v0 resources if provided.
 	valueStrings  map[string]string
 	resourceTypes map[string]string
+	// placeholders holds the resources azd can't generate infrastructure for, keyed by name. Any reference to one of
+	// these resources resolves to the input parameter of the same name, which is added in place of the resource.
+	placeholders map[string]string
+	// warnings collects the messages about resources that were not generated as described by the manifest.
+	warnings []string
+	// kubernetes is set when the services of the app host are deployed to an AKS cluster instead of a container apps
+	// environment.
+	kubernetes bool
 
 	bicepContext                 genBicepTemplateContext
 	containerAppTemplateContexts map[string]genContainerAppManifestTemplateContext
@@ -347,6 +490,10 @@ func newInfraGenerator() *infraGenerator {
 			AppInsights:                     make(map[string]genAppInsight),
 			ContainerAppEnvironmentServices: make(map[string]genContainerAppEnvironmentServices),
 			ServiceBuses:                    make(map[string]genServiceBus),
+			EventHubs:                       make(map[string]genEventHubs),
+			SignalRs:                        make(map[string]genSignalR),
+			SearchServices:                  make(map[string]genSearch),
+			OpenAIs:                         make(map[string]genOpenAI),
 			StorageAccounts:                 make(map[string]genStorageAccount),
 			KeyVaults:                       make(map[string]genKeyVault),
 			ContainerApps:                   make(map[string]genContainerApp),
@@ -364,7 +511,9 @@ func newInfraGenerator() *infraGenerator {
 		dockerfiles:                  make(map[string]genDockerfile),
 		projects:                     make(map[string]genProject),
 		connectionStrings:            make(map[string]string),
+		valueStrings:                 make(map[string]string),
 		resourceTypes:                make(map[string]string),
+		placeholders:                 make(map[string]string),
 		containerAppTemplateContexts: make(map[string]genContainerAppManifestTemplateContext),
 	}
 }
@@ -444,6 +593,14 @@ func (b *infraGenerator) LoadManifest(m *Manifest) error {
 		switch comp.Type {
 		case "azure.servicebus.v0":
 			b.addServiceBus(name, comp.Queues, comp.Topics)
+		case "azure.eventhubs.v0":
+			b.addEventHubs(name, comp.Hubs)
+		case "azure.signalr.v0":
+			b.addSignalR(name)
+		case "azure.search.v0":
+			b.addSearch(name)
+		case "azure.openai.v0":
+			b.addOpenAI(name, comp.Deployments)
 		case "azure.appinsights.v0":
 			b.addAppInsights(name)
 		case "project.v0":
@@ -503,6 +660,18 @@ func (b *infraGenerator) LoadManifest(m *Manifest) error {
 				// a part of a server and it should not be created as a separate resource.
 				b.addContainerAppService(name, "postgres")
 			}
+		case "mysql.server.v0", "mongodb.server.v0", "kafka.server.v0", "rabbitmq.server.v0":
+			b.addContainerServer(m, name, comp.Type)
+		case "mysql.database.v0", "mongodb.database.v0":
+			if comp.Parent == nil || *comp.Parent == "" {
+				return fmt.Errorf("database resource %s does not have a parent", name)
+			}
+			if comp.ConnectionString == nil {
+				b.connectionStrings[name] = containerDatabaseConnectionString(comp.Type, *comp.Parent, name)
+			}
+		case "executable.v0":
+			b.addPlaceholder(name, comp, fmt.Sprintf(
+				"%s is an executable, which only runs on your machine and is not deployed to Azure.", name))
 		case "parameter.v0":
 			if err := b.addInputParameter(name, comp); err != nil {
 				return fmt.Errorf("adding bicep parameter from resource %s (%s): %w", name, comp.Type, err)
@@ -528,7 +697,9 @@ func (b *infraGenerator) LoadManifest(m *Manifest) error {
 					comp.Type)
 				continue
 			}
-			return fmt.Errorf("unsupported resource type: %s", comp.Type)
+
+			b.addPlaceholder(name, comp, fmt.Sprintf(
+				"%s has the resource type %s, which is not supported by azd.", name, comp.Type))
 		}
 	}
 
@@ -536,6 +707,11 @@ func (b *infraGenerator) LoadManifest(m *Manifest) error {
 }
 
 func (b *infraGenerator) requireCluster() {
+	if b.kubernetes {
+		b.bicepContext.HasKubernetesCluster = true
+		return
+	}
+
 	b.requireLogAnalyticsWorkspace()
 	b.bicepContext.HasContainerEnvironment = true
 }
@@ -578,6 +754,146 @@ func (b *infraGenerator) addServiceBus(name string, queues, topics *[]string) {
 	b.bicepContext.ServiceBuses[name] = genServiceBus{Queues: *queues, Topics: *topics}
 }
 
+func (b *infraGenerator) addEventHubs(name string, hubs *[]string) {
+	if hubs == nil {
+		hubs = &[]string{}
+	}
+
+	b.bicepContext.EventHubs[name] = genEventHubs{Hubs: *hubs}
+}
+
+func (b *infraGenerator) addSignalR(name string) {
+	b.bicepContext.SignalRs[name] = genSignalR{}
+}
+
+func (b *infraGenerator) addSearch(name string) {
+	b.bicepContext.SearchServices[name] = genSearch{}
+}
+
+func (b *infraGenerator) addOpenAI(name string, deployments []OpenAIDeployment) {
+	b.bicepContext.OpenAIs[name] = genOpenAI{Deployments: deployments}
+}
+
+// containerServer describes how a server resource, which Azure has no managed offering for that azd provisions, runs as
+// a container app in the environment. Strings may contain expressions, where {name} is the name of the resource.
+type containerServer struct {
+	image            string
+	port             int
+	env              map[string]string
+	connectionString string
+	// when true, a password is generated for the server and is available as the {name-password.value} expression.
+	password bool
+}
+
+var containerServers = map[string]containerServer{
+	"mysql.server.v0": {
+		image: "mysql:8.3",
+		port:  3306,
+		env: map[string]string{
+			"MYSQL_ROOT_PASSWORD": "{name-password.value}",
+		},
+		connectionString: "Server={name.bindings.tcp.host};Port={name.bindings.tcp.port};" +
+			"User ID=root;Password={name-password.value}",
+		password: true,
+	},
+	"mongodb.server.v0": {
+		image:            "mongo:7.0",
+		port:             27017,
+		connectionString: "mongodb://{name.bindings.tcp.host}:{name.bindings.tcp.port}",
+	},
+	"kafka.server.v0": {
+		image: "confluentinc/confluent-local:7.6.0",
+		port:  9092,
+		env: map[string]string{
+			"KAFKA_ADVERTISED_LISTENERS": "PLAINTEXT://localhost:29092,PLAINTEXT_HOST://{name.bindings.tcp.host}:9092",
+		},
+		connectionString: "{name.bindings.tcp.host}:{name.bindings.tcp.port}",
+	},
+	"rabbitmq.server.v0": {
+		image: "rabbitmq:3",
+		port:  5672,
+		env: map[string]string{
+			// the default guest user of RabbitMQ can only connect from localhost.
+			"RABBITMQ_DEFAULT_USER": "azd",
+			"RABBITMQ_DEFAULT_PASS": "{name-password.value}",
+		},
+		connectionString: "amqp://azd:{name-password.value}@{name.bindings.tcp.host}:{name.bindings.tcp.port}",
+		password:         true,
+	},
+}
+
+// addContainerServer adds a server resource of the given type as a container app, reachable over TCP from the other
+// container apps in the environment.
+func (b *infraGenerator) addContainerServer(m *Manifest, name string, resourceType string) {
+	server := containerServers[resourceType]
+	expand := func(s string) string {
+		return strings.ReplaceAll(s, "{name", "{"+name)
+	}
+
+	if server.password {
+		passwordName := name + "-password"
+		// newer manifests describe the password as a parameter.v0 resource of the same name.
+		if _, has := m.Resources[passwordName]; !has {
+			b.resourceTypes[passwordName] = "parameter.v0"
+			b.bicepContext.InputParameters[passwordName] = Input{
+				Type:   "string",
+				Secret: true,
+				Default: &InputDefault{
+					Generate: &InputDefaultGenerate{
+						MinLength: to.Ptr(uint(22)),
+						Special:   to.Ptr(false),
+					},
+				},
+			}
+		}
+	}
+
+	env := make(map[string]string)
+	for k, v := range server.env {
+		env[k] = expand(v)
+	}
+
+	var bindings custommaps.WithOrder[Binding]
+	bindings.Set("tcp", &Binding{
+		Scheme:     "tcp",
+		Protocol:   "tcp",
+		Transport:  "tcp",
+		TargetPort: to.Ptr(server.port),
+	})
+
+	if _, has := b.connectionStrings[name]; !has {
+		b.connectionStrings[name] = expand(server.connectionString)
+	}
+
+	b.addContainer(name, server.image, env, bindings, nil, nil)
+}
+
+// containerDatabaseConnectionString returns the connection string of a database on a server added by
+// addContainerServer.
+func containerDatabaseConnectionString(resourceType string, server string, name string) string {
+	if resourceType == "mongodb.database.v0" {
+		return fmt.Sprintf("{%s.connectionString}/%s", server, name)
+	}
+
+	return fmt.Sprintf("{%s.connectionString};Database=%s", server, name)
+}
+
+// addPlaceholder records a warning for a resource azd can't generate infrastructure for. Unless the resource has a
+// connection string, an input parameter of the same name is added in its place, so references to the resource resolve
+// to a value provided when provisioning.
+func (b *infraGenerator) addPlaceholder(name string, comp *Resource, reason string) {
+	if comp.ConnectionString != nil {
+		b.warnings = append(b.warnings, reason+" Its connection string is used as is.")
+		return
+	}
+
+	b.placeholders[name] = comp.Type
+	b.bicepContext.InputParameters[name] = Input{Type: "string", Secret: true}
+	b.warnings = append(b.warnings, reason+fmt.Sprintf(
+		" References to it resolve to the parameter %s, which is added in its place.",
+		strings.ReplaceAll(name, "-", "_")))
+}
+
 func (b *infraGenerator) addInputParameter(name string, comp *Resource) error {
 	pValue := comp.Value
 
@@ -950,6 +1266,12 @@ var jsonSimpleKeyRegex = regexp.MustCompile(`"([a-zA-Z0-9]*)":`)
 // called the context objects on the infraGenerator can be passed to the text templates to generate the required
 // infrastructure.
 func (b *infraGenerator) Compile() error {
+	if b.kubernetes {
+		if err := b.checkKubernetesSupport(); err != nil {
+			return err
+		}
+	}
+
 	for name, container := range b.containers {
 		cs := genContainerApp{
 			Image:   container.Image,
@@ -1166,6 +1488,10 @@ func (b infraGenerator) evalBindingRef(v string, emitType inputEmitType) (string
 		return res, nil
 	}
 
+	if _, has := b.placeholders[resource]; has {
+		return b.evalParameterRef(resource, emitType), nil
+	}
+
 	if strings.HasPrefix(prop, "inputs.") {
 		parts := strings.Split(prop[len("inputs."):], ".")
 
@@ -1184,7 +1510,8 @@ func (b infraGenerator) evalBindingRef(v string, emitType inputEmitType) (string
 	}
 
 	switch {
-	case targetType == "project.v0" || targetType == "container.v0" || targetType == "dockerfile.v0":
+	case targetType == "project.v0" || targetType == "container.v0" || targetType == "dockerfile.v0" ||
+		isContainerServer(targetType):
 		if !strings.HasPrefix(prop, "bindings.") {
 			return "", fmt.Errorf("unsupported property referenced in binding expression: %s for %s", prop, targetType)
 		}
@@ -1196,20 +1523,21 @@ func (b infraGenerator) evalBindingRef(v string, emitType inputEmitType) (string
 				"bindings.<binding-name>.<property> but was: %s", v)
 		}
 
-		var binding *Binding
-		var has bool
+		var bindings custommaps.WithOrder[Binding]
+		// defaultPort is the port of the ingress when its bindings don't set one, which matches Compile.
+		defaultPort := 80
 
 		if targetType == "project.v0" {
-			bindings := b.projects[resource].Bindings
-			binding, has = bindings.Get(parts[0])
-		} else if targetType == "container.v0" {
-			bindings := b.containers[resource].Bindings
-			binding, has = bindings.Get(parts[0])
+			bindings = b.projects[resource].Bindings
+			defaultPort = 8080
 		} else if targetType == "dockerfile.v0" {
-			bindings := b.dockerfiles[resource].Bindings
-			binding, has = bindings.Get(parts[0])
+			bindings = b.dockerfiles[resource].Bindings
+		} else {
+			bindings = b.containers[resource].Bindings
 		}
 
+		binding, has := bindings.Get(parts[0])
+
 		if !has {
 			return "", fmt.Errorf("unknown binding referenced in binding expression: %s for resource %s", parts[0], resource)
 		}
@@ -1232,6 +1560,10 @@ func (b infraGenerator) evalBindingRef(v string, emitType inputEmitType) (string
 			}
 			return fmt.Sprintf(`%d`, *binding.TargetPort), nil
 		case "url":
+			if b.kubernetes {
+				return kubernetesBindingUrl(resource, bindings, binding, defaultPort)
+			}
+
 			var urlFormatString string
 
 			if binding.External {
@@ -1272,6 +1604,33 @@ func (b infraGenerator) evalBindingRef(v string, emitType inputEmitType) (string
 		default:
 			return "", errUnsupportedProperty("azure.servicebus.v0", prop)
 		}
+	case targetType == "mysql.database.v0" || targetType == "mongodb.database.v0":
+		// the connection string of a database is resolved above, from the connection string of its server.
+		return "", errUnsupportedProperty(targetType, prop)
+	case targetType == "azure.eventhubs.v0":
+		switch prop {
+		case "connectionString":
+			return fmt.Sprintf("{{ urlHost .Env.SERVICE_BINDING_%s_ENDPOINT }}", scaffold.AlphaSnakeUpper(resource)), nil
+		default:
+			return "", errUnsupportedProperty(targetType, prop)
+		}
+	case targetType == "azure.signalr.v0":
+		switch prop {
+		case "connectionString":
+			return fmt.Sprintf(
+				"Endpoint=https://{{ .Env.SERVICE_BINDING_%s_HOST_NAME }};"+
+					"AuthType=azure.msi;ClientId={{ .Env.MANAGED_IDENTITY_CLIENT_ID }}",
+				scaffold.AlphaSnakeUpper(resource)), nil
+		default:
+			return "", errUnsupportedProperty(targetType, prop)
+		}
+	case targetType == "azure.search.v0" || targetType == "azure.openai.v0":
+		switch prop {
+		case "connectionString":
+			return fmt.Sprintf("Endpoint={{ .Env.SERVICE_BINDING_%s_ENDPOINT }}", scaffold.AlphaSnakeUpper(resource)), nil
+		default:
+			return "", errUnsupportedProperty(targetType, prop)
+		}
 	case targetType == "azure.appinsights.v0":
 		switch prop {
 		case "connectionString":
@@ -1319,20 +1678,7 @@ func (b infraGenerator) evalBindingRef(v string, emitType inputEmitType) (string
 			return "", fmt.Errorf("unexpected output type %s", string(emitType))
 		}
 	case targetType == "parameter.v0":
-		param := b.bicepContext.InputParameters[resource]
-		inputType := "parameter"
-		if param.Secret {
-			inputType = "securedParameter"
-		}
-		replaceDash := strings.ReplaceAll(resource, "-", "_")
-		switch emitType {
-		case inputEmitTypeBicep:
-			return fmt.Sprintf("{{%s}}", replaceDash), nil
-		case inputEmitTypeYaml:
-			return fmt.Sprintf(`{{ %s "%s" }}`, inputType, replaceDash), nil
-		default:
-			panic(fmt.Sprintf("unexpected parameter %s", string(emitType)))
-		}
+		return b.evalParameterRef(resource, emitType), nil
 	default:
 		ignore, err := strconv.ParseBool(os.Getenv("AZD_DEBUG_DOTNET_APPHOST_IGNORE_UNSUPPORTED_RESOURCES"))
 		if err == nil && ignore {
@@ -1346,6 +1692,30 @@ func (b infraGenerator) evalBindingRef(v string, emitType inputEmitType) (string
 	}
 }
 
+// evalParameterRef evaluates a reference to the value of the input parameter with the given name.
+func (b infraGenerator) evalParameterRef(name string, emitType inputEmitType) string {
+	param := b.bicepContext.InputParameters[name]
+	inputType := "parameter"
+	if param.Secret {
+		inputType = "securedParameter"
+	}
+	replaceDash := strings.ReplaceAll(name, "-", "_")
+	switch emitType {
+	case inputEmitTypeBicep:
+		return fmt.Sprintf("{{%s}}", replaceDash)
+	case inputEmitTypeYaml:
+		return fmt.Sprintf(`{{ %s "%s" }}`, inputType, replaceDash)
+	default:
+		panic(fmt.Sprintf("unexpected parameter %s", string(emitType)))
+	}
+}
+
+// isContainerServer reports whether resources of the given type are added as containers by addContainerServer.
+func isContainerServer(resourceType string) bool {
+	_, has := containerServers[resourceType]
+	return has
+}
+
 // buildEnvBlock creates the environment map in the template context. It does this by copying the values from the given map,
 // evaluating any binding expressions that are present. It writes the result of the evaluation after calling json.Marshal
 // so the values may be emitted into YAML as is without worrying about escaping.
@@ -1398,7 +1768,9 @@ func (b *infraGenerator) buildEnvBlock(env map[string]string, manifestCtx *genCo
 			// If the resolved value is not complex, it can become a direct reference to key vault secret, otherwise it
 			// is set as a secret within the container app.
 			if strings.Contains(resolvedValue, "{{ secretOutput ") {
-				if isComplexExp, _ := isComplexExpression(resolvedValue); !isComplexExp {
+				// Kubernetes secrets can't reference a key vault secret, so the value is always pulled during
+				// deployment.
+				if isComplexExp, _ := isComplexExpression(resolvedValue); !isComplexExp && !b.kubernetes {
 					removeBrackets := strings.ReplaceAll(
 						strings.ReplaceAll(resolvedValue, " }}'", "'"), "{{ secretOutput ", "")
 					manifestCtx.KeyVaultSecrets[k] = removeBrackets
@@ -0,0 +1,171 @@
+package apphost
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/custommaps"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/resources"
+	"github.com/psanford/memfs"
+	"golang.org/x/exp/maps"
+)
+
+// newKubernetesInfraGenerator returns a generator for app hosts whose services are deployed to an AKS cluster.
+func newKubernetesInfraGenerator() *infraGenerator {
+	generator := newInfraGenerator()
+	generator.kubernetes = true
+	return generator
+}
+
+// KubernetesBicepTemplate returns a filesystem containing the generated bicep files for the given manifest, when the
+// services of the app host are deployed to an AKS cluster. An AKS cluster, with the application routing add-on enabled
+// and pull access to the container registry, is provisioned in place of the container apps environment.
+//
+// Resources that can only be provisioned as container apps, such as containers, Redis and Postgres services and Dapr
+// components, are not supported and return an error.
+func KubernetesBicepTemplate(manifest *Manifest) (*memfs.FS, error) {
+	return bicepTemplate(manifest, newKubernetesInfraGenerator())
+}
+
+// KubernetesManifestTemplateForProject returns the Kubernetes manifest template for a given project. The manifest
+// contains a Deployment, a Service and an Ingress for the bindings of the project and a Secret for its connection
+// strings. Like the template returned by [ContainerAppManifestTemplateForProject], it has to be evaluated before it is
+// applied to the cluster.
+func KubernetesManifestTemplateForProject(manifest *Manifest, projectName string) (string, error) {
+	generator := newKubernetesInfraGenerator()
+
+	if err := generator.LoadManifest(manifest); err != nil {
+		return "", err
+	}
+
+	if err := generator.Compile(); err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+
+	err := genTemplates.ExecuteTemplate(&buf, "k8s.tmpl.yaml", generator.containerAppTemplateContexts[projectName])
+	if err != nil {
+		return "", fmt.Errorf("executing template: %w", err)
+	}
+
+	return buf.String(), nil
+}
+
+// HelmChartForProject returns a filesystem containing a Helm chart for a given project. The chart describes the same
+// resources as the manifest returned by [KubernetesManifestTemplateForProject]. All the values of the project are set in
+// the values.tmpl.yaml file, which has to be evaluated (and written as values.yaml) before the chart is installed.
+func HelmChartForProject(manifest *Manifest, projectName string) (*memfs.FS, error) {
+	generator := newKubernetesInfraGenerator()
+
+	if err := generator.LoadManifest(manifest); err != nil {
+		return nil, err
+	}
+
+	if err := generator.Compile(); err != nil {
+		return nil, err
+	}
+
+	chartFS := memfs.New()
+	manifestCtx := generator.containerAppTemplateContexts[projectName]
+
+	if err := executeToFS(chartFS, genTemplates, "Chart.yaml", "Chart.yaml", manifestCtx); err != nil {
+		return nil, fmt.Errorf("generating Chart.yaml: %w", err)
+	}
+
+	if err := executeToFS(chartFS, genTemplates, "values.tmpl.yaml", "values.tmpl.yaml", manifestCtx); err != nil {
+		return nil, fmt.Errorf("generating values.tmpl.yaml: %w", err)
+	}
+
+	if err := chartFS.MkdirAll("templates", osutil.PermissionDirectory); err != nil {
+		return nil, fmt.Errorf("creating directory: %w", err)
+	}
+
+	entries, err := fs.ReadDir(resources.AppHostHelmTemplates, "apphost/helm/templates")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		contents, err := fs.ReadFile(resources.AppHostHelmTemplates, path.Join("apphost/helm/templates", entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		err = chartFS.WriteFile(path.Join("templates", entry.Name()), contents, osutil.PermissionFile)
+		if err != nil {
+			return nil, fmt.Errorf("writing file: %w", err)
+		}
+	}
+
+	return chartFS, nil
+}
+
+// checkKubernetesSupport returns an error when the loaded manifest has resources which can only be provisioned as
+// container apps.
+func (b *infraGenerator) checkKubernetesSupport() error {
+	var unsupported []string
+	unsupported = append(unsupported, maps.Keys(b.containers)...)
+	unsupported = append(unsupported, maps.Keys(b.bicepContext.ContainerAppEnvironmentServices)...)
+	unsupported = append(unsupported, maps.Keys(b.bicepContext.DaprComponents)...)
+	unsupported = append(unsupported, maps.Keys(b.dapr)...)
+
+	if len(unsupported) > 0 {
+		slices.Sort(unsupported)
+		return fmt.Errorf(
+			"deploying to AKS is not supported for resources: %s. Use Azure Container Apps to host these resources",
+			strings.Join(unsupported, ", "))
+	}
+
+	return nil
+}
+
+// kubernetesBindingUrl returns the URL other services of the cluster use to reach the given binding of a resource. The
+// Service generated for the resource exposes its HTTP ingress on port 80 and every other port as is.
+func kubernetesBindingUrl(
+	resource string, bindings custommaps.WithOrder[Binding], binding *Binding, defaultPort int,
+) (string, error) {
+	port := defaultPort
+	if binding.TargetPort != nil {
+		port = *binding.TargetPort
+	}
+
+	if binding.Scheme == acaIngressSchemaTcp {
+		return fmt.Sprintf("tcp://%s:%d", resource, port), nil
+	}
+
+	ingress, err := buildAcaIngress(bindings, defaultPort)
+	if err != nil {
+		return "", err
+	}
+
+	// TLS is not terminated inside of the cluster, so services always reach each other over plain HTTP.
+	if ingress.Transport != acaIngressSchemaTcp && ingress.TargetPort == port {
+		return fmt.Sprintf("http://%s", resource), nil
+	}
+
+	return fmt.Sprintf("http://%s:%d", resource, port), nil
+}
+
+// kubernetesPortName returns the name of the port the main ingress of a resource is exposed as.
+func kubernetesPortName(ingress *genContainerAppIngress) string {
+	if ingress.Transport == acaIngressSchemaTcp {
+		return "tcp"
+	}
+
+	return "http"
+}
+
+// kubernetesServicePort returns the port the Service of a resource exposes its main ingress on.
+func kubernetesServicePort(ingress *genContainerAppIngress) int {
+	if ingress.Transport == acaIngressSchemaTcp {
+		return ingress.TargetPort
+	}
+
+	return 80
+}
@@ -35,6 +35,12 @@ var aspireEscapingManifest []byte
 //go:embed testdata/aspire-container.json
 var aspireContainerManifest []byte
 
+//go:embed testdata/aspire-extended.json
+var aspireExtendedManifest []byte
+
+//go:embed testdata/aspire-kubernetes.json
+var aspireKubernetesManifest []byte
+
 // mockPublishManifest mocks the dotnet run --publisher manifest command to return a fixed manifest.
 func mockPublishManifest(mockCtx *mocks.MockContext, manifest []byte, files map[string]string) {
 	mockCtx.CommandRunner.When(func(args exec.RunArgs, command string) bool {
@@ -119,6 +125,54 @@ func TestAspireStorageGeneration(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestAspireTerraformGeneration(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping due to EOL issues on Windows with the baselines")
+	}
+
+	ctx := context.Background()
+	mockCtx := mocks.NewMockContext(ctx)
+	mockPublishManifest(mockCtx, aspireStorageManifest, nil)
+	mockCli := dotnet.NewDotNetCli(mockCtx.CommandRunner)
+
+	m, err := ManifestFromAppHost(ctx, filepath.Join("testdata", "AspireDocker.AppHost.csproj"), mockCli, "")
+	require.NoError(t, err)
+
+	files, err := TerraformTemplate(m)
+	require.NoError(t, err)
+
+	err = fs.WalkDir(files, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		contents, err := fs.ReadFile(files, path)
+		if err != nil {
+			return err
+		}
+		t.Run(path, func(t *testing.T) {
+			snapshot.SnapshotT(t, string(contents))
+		})
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestAspireTerraformGenerationUnsupported(t *testing.T) {
+	ctx := context.Background()
+	mockCtx := mocks.NewMockContext(ctx)
+	mockPublishManifest(mockCtx, aspireContainerManifest, nil)
+	mockCli := dotnet.NewDotNetCli(mockCtx.CommandRunner)
+
+	m, err := ManifestFromAppHost(ctx, filepath.Join("testdata", "AspireDocker.AppHost.csproj"), mockCli, "")
+	require.NoError(t, err)
+
+	_, err = TerraformTemplate(m)
+	require.ErrorContains(t, err, "generating terraform is not supported for resources")
+}
+
 func TestAspireBicepGeneration(t *testing.T) {
 	if runtime.GOOS == "windows" {
 		t.Skip("Skipping due to EOL issues on Windows with the baselines")
@@ -247,6 +301,126 @@ func TestAspireContainerGeneration(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestAspireExtendedGeneration(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping due to EOL issues on Windows with the baselines")
+	}
+
+	ctx := context.Background()
+	mockCtx := mocks.NewMockContext(ctx)
+	mockPublishManifest(mockCtx, aspireExtendedManifest, nil)
+	mockCli := dotnet.NewDotNetCli(mockCtx.CommandRunner)
+
+	m, err := ManifestFromAppHost(ctx, filepath.Join("testdata", "AspireDocker.AppHost.csproj"), mockCli, "")
+	require.NoError(t, err)
+
+	files, err := BicepTemplate(m)
+	require.NoError(t, err)
+
+	err = fs.WalkDir(files, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		contents, err := fs.ReadFile(files, path)
+		if err != nil {
+			return err
+		}
+		t.Run(path, func(t *testing.T) {
+			snapshot.SnapshotT(t, string(contents))
+		})
+		return nil
+	})
+	require.NoError(t, err)
+
+	t.Run("api", func(t *testing.T) {
+		tmpl, err := ContainerAppManifestTemplateForProject(m, "api")
+		require.NoError(t, err)
+		snapshot.SnapshotT(t, tmpl)
+	})
+
+	warnings, err := Warnings(m)
+	require.NoError(t, err)
+	require.Len(t, warnings, 2)
+	require.Contains(t, warnings[0], "cache has the resource type garnet.server.v0")
+	require.Contains(t, warnings[1], "worker is an executable")
+
+	_, err = TerraformTemplate(m)
+	require.Error(t, err)
+}
+
+func TestAspireKubernetesGeneration(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping due to EOL issues on Windows with the baselines")
+	}
+
+	ctx := context.Background()
+	mockCtx := mocks.NewMockContext(ctx)
+	mockPublishManifest(mockCtx, aspireKubernetesManifest, nil)
+	mockCli := dotnet.NewDotNetCli(mockCtx.CommandRunner)
+
+	m, err := ManifestFromAppHost(ctx, filepath.Join("testdata", "AspireDocker.AppHost.csproj"), mockCli, "")
+	require.NoError(t, err)
+
+	files, err := KubernetesBicepTemplate(m)
+	require.NoError(t, err)
+
+	err = fs.WalkDir(files, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		contents, err := fs.ReadFile(files, path)
+		if err != nil {
+			return err
+		}
+		t.Run(path, func(t *testing.T) {
+			snapshot.SnapshotT(t, string(contents))
+		})
+		return nil
+	})
+	require.NoError(t, err)
+
+	for _, name := range []string{"api", "web"} {
+		t.Run(name, func(t *testing.T) {
+			tmpl, err := KubernetesManifestTemplateForProject(m, name)
+			require.NoError(t, err)
+			snapshot.SnapshotT(t, tmpl)
+		})
+	}
+
+	t.Run("chart", func(t *testing.T) {
+		chart, err := HelmChartForProject(m, "api")
+		require.NoError(t, err)
+
+		values, err := fs.ReadFile(chart, "values.tmpl.yaml")
+		require.NoError(t, err)
+		snapshot.SnapshotT(t, string(values))
+
+		for _, name := range []string{"Chart.yaml", "templates/deployment.yaml", "templates/service.yaml"} {
+			_, err := fs.Stat(chart, name)
+			require.NoError(t, err)
+		}
+	})
+}
+
+func TestAspireKubernetesGenerationUnsupported(t *testing.T) {
+	ctx := context.Background()
+	mockCtx := mocks.NewMockContext(ctx)
+	mockPublishManifest(mockCtx, aspireDockerManifest, nil)
+	mockCli := dotnet.NewDotNetCli(mockCtx.CommandRunner)
+
+	m, err := ManifestFromAppHost(ctx, filepath.Join("testdata", "AspireDocker.AppHost.csproj"), mockCli, "")
+	require.NoError(t, err)
+
+	_, err = KubernetesBicepTemplate(m)
+	require.ErrorContains(t, err, "deploying to AKS is not supported for resources: mysqlabstract")
+}
+
 func TestBuildEnvResolveServiceToConnectionString(t *testing.T) {
 	// Create a mock infraGenerator instance
 	mockGenerator := &infraGenerator{
@@ -19,6 +19,18 @@ type genServiceBus struct {
 	Topics []string
 }
 
+type genEventHubs struct {
+	Hubs []string
+}
+
+type genSignalR struct{}
+
+type genSearch struct{}
+
+type genOpenAI struct {
+	Deployments []OpenAIDeployment
+}
+
 type genContainerAppEnvironmentServices struct {
 	Type string
 }
@@ -124,12 +136,17 @@ type genBicepModules struct {
 type genBicepTemplateContext struct {
 	HasContainerRegistry            bool
 	HasContainerEnvironment         bool
+	HasKubernetesCluster            bool
 	HasDaprStore                    bool
 	HasLogAnalyticsWorkspace        bool
 	RequiresPrincipalId             bool
 	RequiresStorageVolume           bool
 	AppInsights                     map[string]genAppInsight
 	ServiceBuses                    map[string]genServiceBus
+	EventHubs                       map[string]genEventHubs
+	SignalRs                        map[string]genSignalR
+	SearchServices                  map[string]genSearch
+	OpenAIs                         map[string]genOpenAI
 	StorageAccounts                 map[string]genStorageAccount
 	KeyVaults                       map[string]genKeyVault
 	ContainerAppEnvironmentServices map[string]genContainerAppEnvironmentServices
@@ -156,8 +173,9 @@ type genContainerAppManifestTemplateContext struct {
 }
 
 type genProjectFileContext struct {
-	Name     string
-	Services map[string]string
+	Name          string
+	Services      map[string]string
+	InfraProvider string
 }
 
 type genContainerAppManifestTemplateContextDapr struct {
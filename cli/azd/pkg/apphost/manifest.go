@@ -60,6 +60,12 @@ type Resource struct {
 	// Topics is optionally present on a azure.servicebus.v0 resource, and is a list of topic names to create.
 	Topics *[]string `json:"topics,omitempty"`
 
+	// Hubs is optionally present on a azure.eventhubs.v0 resource, and is a list of event hub names to create.
+	Hubs *[]string `json:"hubs,omitempty"`
+
+	// Deployments is optionally present on a azure.openai.v0 resource, and is a list of model deployments to create.
+	Deployments []OpenAIDeployment `json:"deployments,omitempty"`
+
 	// Some resources just represent connections to existing resources that need not be provisioned.  These resources have
 	// a "connectionString" property which is the connection string that should be used during binding.
 	ConnectionString *string `json:"connectionString,omitempty"`
@@ -99,6 +105,18 @@ type DaprComponentResourceMetadata struct {
 	Type *string `json:"type"`
 }
 
+type OpenAIDeployment struct {
+	Name         string              `json:"name"`
+	ModelName    string              `json:"modelName"`
+	ModelVersion string              `json:"modelVersion"`
+	Sku          OpenAIDeploymentSku `json:"sku"`
+}
+
+type OpenAIDeploymentSku struct {
+	Name     string `json:"name"`
+	Capacity int    `json:"capacity"`
+}
+
 type Reference struct {
 	Bindings []string `json:"bindings,omitempty"`
 }
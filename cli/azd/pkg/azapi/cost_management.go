@@ -0,0 +1,157 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/azure/azure-dev/cli/azd/pkg/account"
+)
+
+// The API version used for cost management queries.
+const costManagementApiVersion = "2023-03-01"
+
+// Cost is the accumulated cost of a scope over a period of time.
+type Cost struct {
+	Amount   float64
+	Currency string
+}
+
+// CostManagement queries the Azure Cost Management service.
+type CostManagement interface {
+	// ResourceGroupCostMonthToDate gets the actual cost of a resource group since the start of the current month.
+	ResourceGroupCostMonthToDate(ctx context.Context, subscriptionId string, resourceGroup string) (*Cost, error)
+}
+
+type costManagement struct {
+	credentialProvider account.SubscriptionCredentialProvider
+	armClientOptions   *arm.ClientOptions
+}
+
+func NewCostManagement(
+	credentialProvider account.SubscriptionCredentialProvider,
+	armClientOptions *arm.ClientOptions,
+) CostManagement {
+	return &costManagement{
+		credentialProvider: credentialProvider,
+		armClientOptions:   armClientOptions,
+	}
+}
+
+type costQuery struct {
+	Type      string           `json:"type"`
+	Timeframe string           `json:"timeframe"`
+	Dataset   costQueryDataset `json:"dataset"`
+}
+
+type costQueryDataset struct {
+	Granularity string                          `json:"granularity"`
+	Aggregation map[string]costQueryAggregation `json:"aggregation"`
+}
+
+type costQueryAggregation struct {
+	Name     string `json:"name"`
+	Function string `json:"function"`
+}
+
+type costQueryResult struct {
+	Properties struct {
+		Columns []struct {
+			Name string `json:"name"`
+		} `json:"columns"`
+		Rows [][]any `json:"rows"`
+	} `json:"properties"`
+}
+
+func (cm *costManagement) ResourceGroupCostMonthToDate(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+) (*Cost, error) {
+	credential, err := cm.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	pipeline, endpoint, err := newArmPipeline("costmanagement", credential, cm.armClientOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	path := fmt.Sprintf(
+		"/subscriptions/%s/resourceGroups/%s/providers/Microsoft.CostManagement/query", subscriptionId, resourceGroup)
+	req, err := runtime.NewRequest(ctx, http.MethodPost, runtime.JoinPaths(endpoint, path))
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	query := req.Raw().URL.Query()
+	query.Set("api-version", costManagementApiVersion)
+	req.Raw().URL.RawQuery = query.Encode()
+
+	body := costQuery{
+		Type:      "ActualCost",
+		Timeframe: "MonthToDate",
+		Dataset: costQueryDataset{
+			Granularity: "None",
+			Aggregation: map[string]costQueryAggregation{
+				"totalCost": {Name: "Cost", Function: "Sum"},
+			},
+		},
+	}
+	if err := runtime.MarshalAsJSON(req, body); err != nil {
+		return nil, err
+	}
+
+	response, err := pipeline.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("querying cost: %w", err)
+	}
+
+	if !runtime.HasStatusCode(response, http.StatusOK) {
+		return nil, fmt.Errorf("querying cost: %w", runtime.NewResponseError(response))
+	}
+
+	var result costQueryResult
+	if err := runtime.UnmarshalAsJSON(response, &result); err != nil {
+		return nil, err
+	}
+
+	return parseCostQueryResult(&result)
+}
+
+// parseCostQueryResult reads the total cost from the single row returned by a query without granularity. No rows are
+// returned when nothing was billed yet.
+func parseCostQueryResult(result *costQueryResult) (*Cost, error) {
+	cost := &Cost{}
+	if len(result.Properties.Rows) == 0 {
+		return cost, nil
+	}
+
+	row := result.Properties.Rows[0]
+	for i, column := range result.Properties.Columns {
+		if i >= len(row) {
+			break
+		}
+
+		switch {
+		case strings.EqualFold(column.Name, "totalCost"), strings.EqualFold(column.Name, "Cost"):
+			amount, ok := row[i].(float64)
+			if !ok {
+				return nil, errors.New("unexpected cost value in query result")
+			}
+			cost.Amount = amount
+		case strings.EqualFold(column.Name, "Currency"):
+			cost.Currency, _ = row[i].(string)
+		}
+	}
+
+	return cost, nil
+}
@@ -0,0 +1,453 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	armruntime "github.com/Azure/azure-sdk-for-go/sdk/azcore/arm/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/azure/azure-dev/cli/azd/pkg/account"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+)
+
+// The API version used for all deployment stack operations.
+//
+// The deployment stacks operations aren't part of armresources (up to v1.2.0) and are published in their own
+// armdeploymentstacks module, which isn't a dependency of azd yet. Until it is, the operations used by azd are called
+// through the ARM pipeline of azcore with the types below, which mirror the REST API of this version.
+const deploymentStacksApiVersion = "2024-03-01"
+
+var (
+	ErrDeploymentStackNotFound = errors.New("deployment stack not found")
+)
+
+// DeploymentStackActionOnUnmanage is the action taken on a resource that is no longer managed by a deployment stack.
+type DeploymentStackActionOnUnmanage string
+
+const (
+	DeploymentStackActionDelete DeploymentStackActionOnUnmanage = "delete"
+	DeploymentStackActionDetach DeploymentStackActionOnUnmanage = "detach"
+)
+
+// DeploymentStackDenySettingsMode defines which operations are denied on resources managed by a deployment stack.
+type DeploymentStackDenySettingsMode string
+
+const (
+	DeploymentStackDenyNone           DeploymentStackDenySettingsMode = "none"
+	DeploymentStackDenyDelete         DeploymentStackDenySettingsMode = "denyDelete"
+	DeploymentStackDenyWriteAndDelete DeploymentStackDenySettingsMode = "denyWriteAndDelete"
+)
+
+type DeploymentStackActionOnUnmanageSettings struct {
+	Resources        DeploymentStackActionOnUnmanage `json:"resources"`
+	ResourceGroups   DeploymentStackActionOnUnmanage `json:"resourceGroups,omitempty"`
+	ManagementGroups DeploymentStackActionOnUnmanage `json:"managementGroups,omitempty"`
+}
+
+type DeploymentStackDenySettings struct {
+	Mode               DeploymentStackDenySettingsMode `json:"mode"`
+	ExcludedPrincipals []string                        `json:"excludedPrincipals,omitempty"`
+	ExcludedActions    []string                        `json:"excludedActions,omitempty"`
+	ApplyToChildScopes bool                            `json:"applyToChildScopes,omitempty"`
+}
+
+// DeploymentStackOptions are the settings azd applies to a deployment stack on create or update.
+type DeploymentStackOptions struct {
+	ActionOnUnmanage DeploymentStackActionOnUnmanageSettings
+	DenySettings     DeploymentStackDenySettings
+}
+
+type DeploymentStackResourceReference struct {
+	Id         string `json:"id"`
+	Status     string `json:"status,omitempty"`
+	DenyStatus string `json:"denyStatus,omitempty"`
+}
+
+type DeploymentStackProperties struct {
+	Template          azure.RawArmTemplate                    `json:"template,omitempty"`
+	Parameters        azure.ArmParameters                     `json:"parameters,omitempty"`
+	ActionOnUnmanage  DeploymentStackActionOnUnmanageSettings `json:"actionOnUnmanage"`
+	DenySettings      DeploymentStackDenySettings             `json:"denySettings"`
+	Description       string                                  `json:"description,omitempty"`
+	ProvisioningState string                                  `json:"provisioningState,omitempty"`
+	DeploymentId      string                                  `json:"deploymentId,omitempty"`
+	Outputs           interface{}                             `json:"outputs,omitempty"`
+	Resources         []DeploymentStackResourceReference      `json:"resources,omitempty"`
+	DetachedResources []DeploymentStackResourceReference      `json:"detachedResources,omitempty"`
+	DeletedResources  []DeploymentStackResourceReference      `json:"deletedResources,omitempty"`
+	Error             *AzCliDeploymentErrorResponse           `json:"error,omitempty"`
+}
+
+// DeploymentStack is the ARM representation of a Microsoft.Resources/deploymentStacks resource.
+type DeploymentStack struct {
+	Id         string                    `json:"id,omitempty"`
+	Name       string                    `json:"name,omitempty"`
+	Location   string                    `json:"location,omitempty"`
+	Tags       map[string]*string        `json:"tags,omitempty"`
+	Properties DeploymentStackProperties `json:"properties"`
+}
+
+// DeploymentStacks manages Azure deployment stacks at subscription and resource group scope.
+type DeploymentStacks interface {
+	GetSubscriptionStack(ctx context.Context, subscriptionId string, stackName string) (*DeploymentStack, error)
+	GetResourceGroupStack(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroup string,
+		stackName string,
+	) (*DeploymentStack, error)
+	DeployStackToSubscription(
+		ctx context.Context,
+		subscriptionId string,
+		location string,
+		stackName string,
+		armTemplate azure.RawArmTemplate,
+		parameters azure.ArmParameters,
+		tags map[string]*string,
+		options DeploymentStackOptions,
+	) (*DeploymentStack, error)
+	DeployStackToResourceGroup(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroup string,
+		stackName string,
+		armTemplate azure.RawArmTemplate,
+		parameters azure.ArmParameters,
+		tags map[string]*string,
+		options DeploymentStackOptions,
+	) (*DeploymentStack, error)
+	DeleteSubscriptionStack(
+		ctx context.Context,
+		subscriptionId string,
+		stackName string,
+		actionOnUnmanage DeploymentStackActionOnUnmanageSettings,
+	) error
+	DeleteResourceGroupStack(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroup string,
+		stackName string,
+		actionOnUnmanage DeploymentStackActionOnUnmanageSettings,
+	) error
+}
+
+type deploymentStacks struct {
+	credentialProvider account.SubscriptionCredentialProvider
+	armClientOptions   *arm.ClientOptions
+}
+
+func NewDeploymentStacks(
+	credentialProvider account.SubscriptionCredentialProvider,
+	armClientOptions *arm.ClientOptions,
+) DeploymentStacks {
+	return &deploymentStacks{
+		credentialProvider: credentialProvider,
+		armClientOptions:   armClientOptions,
+	}
+}
+
+func (ds *deploymentStacks) GetSubscriptionStack(
+	ctx context.Context,
+	subscriptionId string,
+	stackName string,
+) (*DeploymentStack, error) {
+	return ds.get(ctx, subscriptionId, subscriptionStackPath(subscriptionId, stackName))
+}
+
+func (ds *deploymentStacks) GetResourceGroupStack(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	stackName string,
+) (*DeploymentStack, error) {
+	return ds.get(ctx, subscriptionId, resourceGroupStackPath(subscriptionId, resourceGroup, stackName))
+}
+
+func (ds *deploymentStacks) DeployStackToSubscription(
+	ctx context.Context,
+	subscriptionId string,
+	location string,
+	stackName string,
+	armTemplate azure.RawArmTemplate,
+	parameters azure.ArmParameters,
+	tags map[string]*string,
+	options DeploymentStackOptions,
+) (*DeploymentStack, error) {
+	stack := newDeploymentStack(armTemplate, parameters, tags, options)
+	stack.Location = location
+
+	result, err := ds.createOrUpdate(ctx, subscriptionId, subscriptionStackPath(subscriptionId, stackName), stack)
+	if err != nil {
+		return nil, fmt.Errorf("deploying stack to subscription:\n\nDeployment Error Details:\n%w", err)
+	}
+
+	return result, nil
+}
+
+func (ds *deploymentStacks) DeployStackToResourceGroup(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	stackName string,
+	armTemplate azure.RawArmTemplate,
+	parameters azure.ArmParameters,
+	tags map[string]*string,
+	options DeploymentStackOptions,
+) (*DeploymentStack, error) {
+	stack := newDeploymentStack(armTemplate, parameters, tags, options)
+
+	result, err := ds.createOrUpdate(
+		ctx, subscriptionId, resourceGroupStackPath(subscriptionId, resourceGroup, stackName), stack)
+	if err != nil {
+		return nil, fmt.Errorf("deploying stack to resource group:\n\nDeployment Error Details:\n%w", err)
+	}
+
+	return result, nil
+}
+
+func (ds *deploymentStacks) DeleteSubscriptionStack(
+	ctx context.Context,
+	subscriptionId string,
+	stackName string,
+	actionOnUnmanage DeploymentStackActionOnUnmanageSettings,
+) error {
+	return ds.delete(ctx, subscriptionId, subscriptionStackPath(subscriptionId, stackName), actionOnUnmanage)
+}
+
+func (ds *deploymentStacks) DeleteResourceGroupStack(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	stackName string,
+	actionOnUnmanage DeploymentStackActionOnUnmanageSettings,
+) error {
+	return ds.delete(
+		ctx, subscriptionId, resourceGroupStackPath(subscriptionId, resourceGroup, stackName), actionOnUnmanage)
+}
+
+func newDeploymentStack(
+	armTemplate azure.RawArmTemplate,
+	parameters azure.ArmParameters,
+	tags map[string]*string,
+	options DeploymentStackOptions,
+) *DeploymentStack {
+	denySettings := options.DenySettings
+	if denySettings.Mode == "" {
+		denySettings.Mode = DeploymentStackDenyNone
+	}
+
+	actionOnUnmanage := options.ActionOnUnmanage
+	if actionOnUnmanage.Resources == "" {
+		actionOnUnmanage.Resources = DeploymentStackActionDetach
+	}
+
+	return &DeploymentStack{
+		Tags: tags,
+		Properties: DeploymentStackProperties{
+			Template:         armTemplate,
+			Parameters:       parameters,
+			ActionOnUnmanage: actionOnUnmanage,
+			DenySettings:     denySettings,
+			Description:      "Deployment stack managed by the Azure Developer CLI",
+		},
+	}
+}
+
+func subscriptionStackPath(subscriptionId string, stackName string) string {
+	return fmt.Sprintf(
+		"/subscriptions/%s/providers/Microsoft.Resources/deploymentStacks/%s",
+		url.PathEscape(subscriptionId),
+		url.PathEscape(stackName),
+	)
+}
+
+func resourceGroupStackPath(subscriptionId string, resourceGroup string, stackName string) string {
+	return fmt.Sprintf(
+		"/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Resources/deploymentStacks/%s",
+		url.PathEscape(subscriptionId),
+		url.PathEscape(resourceGroup),
+		url.PathEscape(stackName),
+	)
+}
+
+func (ds *deploymentStacks) get(ctx context.Context, subscriptionId string, path string) (*DeploymentStack, error) {
+	pipeline, endpoint, err := ds.createPipeline(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := runtime.NewRequest(ctx, http.MethodGet, runtime.JoinPaths(endpoint, path))
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	setStackQuery(req.Raw(), nil)
+	req.Raw().Header.Set("Accept", "application/json")
+
+	response, err := pipeline.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if runtime.HasStatusCode(response, http.StatusNotFound) {
+		return nil, ErrDeploymentStackNotFound
+	}
+
+	if !runtime.HasStatusCode(response, http.StatusOK) {
+		return nil, fmt.Errorf("getting deployment stack: %w", runtime.NewResponseError(response))
+	}
+
+	var stack DeploymentStack
+	if err := runtime.UnmarshalAsJSON(response, &stack); err != nil {
+		return nil, fmt.Errorf("unmarshalling deployment stack: %w", err)
+	}
+
+	return &stack, nil
+}
+
+func (ds *deploymentStacks) createOrUpdate(
+	ctx context.Context,
+	subscriptionId string,
+	path string,
+	stack *DeploymentStack,
+) (*DeploymentStack, error) {
+	pipeline, endpoint, err := ds.createPipeline(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := runtime.NewRequest(ctx, http.MethodPut, runtime.JoinPaths(endpoint, path))
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	setStackQuery(req.Raw(), nil)
+	req.Raw().Header.Set("Accept", "application/json")
+
+	if err := runtime.MarshalAsJSON(req, stack); err != nil {
+		return nil, fmt.Errorf("marshalling deployment stack: %w", err)
+	}
+
+	response, err := pipeline.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if !runtime.HasStatusCode(response, http.StatusOK, http.StatusCreated) {
+		defer response.Body.Close()
+		return nil, createDeploymentError(runtime.NewResponseError(response))
+	}
+
+	poller, err := runtime.NewPoller[DeploymentStack](response, pipeline, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating deployment stack poller: %w", err)
+	}
+
+	result, err := poller.PollUntilDone(ctx, nil)
+	if err != nil {
+		return nil, createDeploymentError(err)
+	}
+
+	return &result, nil
+}
+
+func (ds *deploymentStacks) delete(
+	ctx context.Context,
+	subscriptionId string,
+	path string,
+	actionOnUnmanage DeploymentStackActionOnUnmanageSettings,
+) error {
+	pipeline, endpoint, err := ds.createPipeline(ctx, subscriptionId)
+	if err != nil {
+		return err
+	}
+
+	req, err := runtime.NewRequest(ctx, http.MethodDelete, runtime.JoinPaths(endpoint, path))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	setStackQuery(req.Raw(), map[string]string{
+		"unmanageAction.Resources":      string(actionOnUnmanage.Resources),
+		"unmanageAction.ResourceGroups": string(actionOnUnmanage.ResourceGroups),
+	})
+	req.Raw().Header.Set("Accept", "application/json")
+
+	response, err := pipeline.Do(req)
+	if err != nil {
+		return err
+	}
+
+	if runtime.HasStatusCode(response, http.StatusNoContent, http.StatusNotFound) {
+		response.Body.Close()
+		return nil
+	}
+
+	if !runtime.HasStatusCode(response, http.StatusOK, http.StatusAccepted) {
+		defer response.Body.Close()
+		return fmt.Errorf("deleting deployment stack: %w", runtime.NewResponseError(response))
+	}
+
+	poller, err := runtime.NewPoller[any](response, pipeline, nil)
+	if err != nil {
+		return fmt.Errorf("creating deployment stack poller: %w", err)
+	}
+
+	if _, err := poller.PollUntilDone(ctx, nil); err != nil {
+		return fmt.Errorf("deleting deployment stack: %w", err)
+	}
+
+	return nil
+}
+
+func (ds *deploymentStacks) createPipeline(
+	ctx context.Context,
+	subscriptionId string,
+) (runtime.Pipeline, string, error) {
+	credential, err := ds.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return runtime.Pipeline{}, "", err
+	}
+
+	return newArmPipeline("deploymentstacks", credential, ds.armClientOptions)
+}
+
+func newArmPipeline(
+	moduleName string,
+	credential azcore.TokenCredential,
+	options *arm.ClientOptions,
+) (runtime.Pipeline, string, error) {
+	if options == nil {
+		options = &arm.ClientOptions{}
+	}
+
+	endpoint := options.Cloud.Services[cloud.ResourceManager].Endpoint
+	if endpoint == "" {
+		endpoint = cloud.AzurePublic.Services[cloud.ResourceManager].Endpoint
+	}
+
+	pipeline, err := armruntime.NewPipeline(moduleName, "1.0.0", credential, runtime.PipelineOptions{}, options)
+	if err != nil {
+		return runtime.Pipeline{}, "", fmt.Errorf("failed creating HTTP pipeline: %w", err)
+	}
+
+	return pipeline, endpoint, nil
+}
+
+func setStackQuery(req *http.Request, extraQuery map[string]string) {
+	query := req.URL.Query()
+	query.Set("api-version", deploymentStacksApiVersion)
+	for key, value := range extraQuery {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+	req.URL.RawQuery = query.Encode()
+}
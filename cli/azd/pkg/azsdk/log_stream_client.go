@@ -0,0 +1,70 @@
+package azsdk
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	armruntime "github.com/Azure/azure-sdk-for-go/sdk/azcore/arm/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+)
+
+// LogStreamClient streams the application logs of an app service or function app from its Kudu (SCM) site
+// More info can be found at the following:
+// https://github.com/projectkudu/kudu/wiki/Diagnostic-Log-Stream
+type LogStreamClient struct {
+	hostName string
+	pipeline runtime.Pipeline
+}
+
+// Creates a new LogStreamClient instance
+func NewLogStreamClient(
+	hostName string,
+	credential azcore.TokenCredential,
+	options *arm.ClientOptions,
+) (*LogStreamClient, error) {
+	if options == nil {
+		options = &arm.ClientOptions{}
+	}
+
+	// We do not have a Resource provider to register
+	options.DisableRPRegistration = true
+
+	pipeline, err := armruntime.NewPipeline("log-stream", "1.0.0", credential, runtime.PipelineOptions{}, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed creating HTTP pipeline: %w", err)
+	}
+
+	return &LogStreamClient{
+		hostName: hostName,
+		pipeline: pipeline,
+	}, nil
+}
+
+// Opens the live log stream of the app. The stream stays open until it is closed or the context is cancelled.
+func (c *LogStreamClient) Stream(ctx context.Context) (io.ReadCloser, error) {
+	endpoint := fmt.Sprintf("https://%s/api/logstream", c.hostName)
+	req, err := runtime.NewRequest(ctx, http.MethodGet, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("creating log stream request: %w", err)
+	}
+
+	// The body is streamed to the caller instead of being read into memory
+	runtime.SkipBodyDownload(req)
+	req.Raw().Header.Set("Accept", "text/plain")
+
+	response, err := c.pipeline.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if !runtime.HasStatusCode(response, http.StatusOK) {
+		defer response.Body.Close()
+		return nil, runtime.NewResponseError(response)
+	}
+
+	return response.Body, nil
+}
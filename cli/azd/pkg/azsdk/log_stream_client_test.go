@@ -0,0 +1,56 @@
+package azsdk
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogStream(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodGet && request.URL.Host == "HOSTNAME" &&
+				request.URL.Path == "/api/logstream"
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			return &http.Response{
+				Request:    request,
+				StatusCode: http.StatusOK,
+				Header:     http.Header{},
+				Body:       io.NopCloser(strings.NewReader("line 1\nline 2\n")),
+			}, nil
+		})
+
+		client, err := NewLogStreamClient("HOSTNAME", &mocks.MockCredentials{}, mockContext.ArmClientOptions)
+		require.NoError(t, err)
+
+		stream, err := client.Stream(*mockContext.Context)
+		require.NoError(t, err)
+		defer stream.Close()
+
+		contents, err := io.ReadAll(stream)
+		require.NoError(t, err)
+		require.Equal(t, "line 1\nline 2\n", string(contents))
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodGet && request.URL.Path == "/api/logstream"
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			return mocks.CreateEmptyHttpResponse(request, http.StatusForbidden)
+		})
+
+		client, err := NewLogStreamClient("HOSTNAME", &mocks.MockCredentials{}, mockContext.ArmClientOptions)
+		require.NoError(t, err)
+
+		stream, err := client.Stream(*mockContext.Context)
+		require.Error(t, err)
+		require.Nil(t, stream)
+	})
+}
@@ -11,6 +11,9 @@ const (
 	// TagKeyAzdDeploymentStateParamHashName is the name of the key in the tags map of a deployment
 	// used to store the parameters hash.
 	TagKeyAzdDeploymentStateParamHashName = "azd-provision-param-hash"
+	// TagKeyAzdProvisionTargets is the name of the key in the tags map of a deployment
+	// used to store the modules and resources a partial deployment was limited to.
+	TagKeyAzdProvisionTargets = "azd-provision-targets"
 	// TagKeyAzdServiceName is the name of the key in the tags map of a resource
 	// used to store the azd service a resource is associated with.
 	TagKeyAzdServiceName = "azd-service-name"
@@ -4,6 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appcontainers/armappcontainers/v3"
@@ -44,6 +48,24 @@ type ContainerAppService interface {
 		resourceGroupName string,
 		appName string,
 	) ([]*armappcontainers.ContainerAppSecret, error)
+	// Opens the log streams of the specified container app. The caller is responsible for closing the streams.
+	OpenLogStreams(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		appName string,
+		options *LogStreamOptions,
+	) ([]io.ReadCloser, error)
+}
+
+// The options for streaming the logs of a container app
+type LogStreamOptions struct {
+	// Streams the system logs of the container app instead of the console logs of its containers
+	System bool
+	// Keeps the streams open to receive new logs
+	Follow bool
+	// The number of recent log lines to return, up to 300
+	TailLines int
 }
 
 // NewContainerAppService creates a new ContainerAppService
@@ -215,6 +237,145 @@ func (cas *containerAppService) ListSecrets(
 	return secretsResponse.Value, nil
 }
 
+// Opens the log streams of the specified container app. The system logs are streamed from the event stream endpoint
+// of the container app. The console logs are streamed for every container of every replica of the latest ready revision.
+func (cas *containerAppService) OpenLogStreams(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	appName string,
+	options *LogStreamOptions,
+) ([]io.ReadCloser, error) {
+	if options == nil {
+		options = &LogStreamOptions{}
+	}
+
+	containerApp, err := cas.getContainerApp(ctx, subscriptionId, resourceGroupName, appName)
+	if err != nil {
+		return nil, err
+	}
+
+	if containerApp.Properties == nil || containerApp.Properties.EventStreamEndpoint == nil {
+		return nil, fmt.Errorf("container app '%s' does not have an event stream endpoint", appName)
+	}
+
+	appClient, err := cas.createContainerAppsClient(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	tokenResponse, err := appClient.GetAuthToken(ctx, resourceGroupName, appName, nil)
+	if err != nil {
+		return nil, fmt.Errorf("getting auth token: %w", err)
+	}
+
+	if tokenResponse.Properties == nil || tokenResponse.Properties.Token == nil {
+		return nil, fmt.Errorf("auth token of container app '%s' is empty", appName)
+	}
+
+	token := *tokenResponse.Properties.Token
+	eventStreamEndpoint := *containerApp.Properties.EventStreamEndpoint
+
+	if options.System {
+		stream, err := cas.openLogStream(ctx, eventStreamEndpoint, token, options)
+		if err != nil {
+			return nil, err
+		}
+
+		return []io.ReadCloser{stream}, nil
+	}
+
+	revisionName := convert.ToValueWithDefault(containerApp.Properties.LatestReadyRevisionName, "")
+	if revisionName == "" {
+		return nil, fmt.Errorf("container app '%s' does not have a ready revision", appName)
+	}
+
+	replicasClient, err := cas.createReplicasClient(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	replicasResponse, err := replicasClient.ListReplicas(ctx, resourceGroupName, appName, revisionName, nil)
+	if err != nil {
+		return nil, fmt.Errorf("listing replicas of revision '%s': %w", revisionName, err)
+	}
+
+	// The console log streams are served from the same host as the event stream
+	baseUrl := eventStreamEndpoint
+	if index := strings.Index(baseUrl, "/subscriptions/"); index >= 0 {
+		baseUrl = baseUrl[:index]
+	}
+
+	streams := []io.ReadCloser{}
+	for _, replica := range replicasResponse.Value {
+		if replica.Properties == nil {
+			continue
+		}
+
+		for _, container := range replica.Properties.Containers {
+			endpoint := fmt.Sprintf(
+				"%s/subscriptions/%s/resourceGroups/%s/containerApps/%s/revisions/%s/replicas/%s/containers/%s/logstream",
+				baseUrl,
+				subscriptionId,
+				resourceGroupName,
+				appName,
+				revisionName,
+				*replica.Name,
+				*container.Name,
+			)
+
+			stream, err := cas.openLogStream(ctx, endpoint, token, options)
+			if err != nil {
+				for _, opened := range streams {
+					opened.Close()
+				}
+
+				return nil, err
+			}
+
+			streams = append(streams, stream)
+		}
+	}
+
+	return streams, nil
+}
+
+// Opens a log stream of the container app with the auth token of the container app
+func (cas *containerAppService) openLogStream(
+	ctx context.Context,
+	endpoint string,
+	token string,
+	options *LogStreamOptions,
+) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating log stream request: %w", err)
+	}
+
+	query := req.URL.Query()
+	query.Set("follow", strconv.FormatBool(options.Follow))
+	query.Set("output", "text")
+	if options.TailLines > 0 {
+		query.Set("tailLines", strconv.Itoa(options.TailLines))
+	}
+
+	req.URL.RawQuery = query.Encode()
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	req.Header.Set("User-Agent", cas.userAgent)
+
+	response, err := cas.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("opening log stream: %w", err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		defer response.Body.Close()
+		return nil, fmt.Errorf("opening log stream: unexpected status code %d", response.StatusCode)
+	}
+
+	return response.Body, nil
+}
+
 func (cas *containerAppService) syncSecrets(
 	ctx context.Context,
 	subscriptionId string,
@@ -337,6 +498,24 @@ func (cas *containerAppService) createContainerAppsClient(
 	return client, nil
 }
 
+func (cas *containerAppService) createReplicasClient(
+	ctx context.Context,
+	subscriptionId string,
+) (*armappcontainers.ContainerAppsRevisionReplicasClient, error) {
+	credential, err := cas.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := armappcontainers.NewContainerAppsRevisionReplicasClient(
+		subscriptionId, credential, cas.armClientOptions)
+	if err != nil {
+		return nil, fmt.Errorf("creating ContainerApps replicas client: %w", err)
+	}
+
+	return client, nil
+}
+
 func (cas *containerAppService) createRevisionsClient(
 	ctx context.Context,
 	subscriptionId string,
@@ -4,6 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appcontainers/armappcontainers/v3"
@@ -160,3 +163,111 @@ func Test_ContainerApp_AddRevision(t *testing.T) {
 	require.Equal(t, updatedImageName, *updatedContainerApp.Properties.Template.Containers[0].Image)
 	require.Equal(t, "azd-0", *updatedContainerApp.Properties.Template.RevisionSuffix)
 }
+
+func Test_ContainerApp_OpenLogStreams(t *testing.T) {
+	subscriptionId := "SUBSCRIPTION_ID"
+	location := "eastus2"
+	resourceGroup := "RESOURCE_GROUP"
+	appName := "APP_NAME"
+	revisionName := "APP_NAME--rev1"
+	eventStreamEndpoint := fmt.Sprintf(
+		"https://%s.azurecontainerapps.dev/subscriptions/%s/resourceGroups/%s/containerApps/%s/eventstream",
+		location,
+		subscriptionId,
+		resourceGroup,
+		appName,
+	)
+
+	containerApp := &armappcontainers.ContainerApp{
+		Location: &location,
+		Name:     &appName,
+		Properties: &armappcontainers.ContainerAppProperties{
+			EventStreamEndpoint:     &eventStreamEndpoint,
+			LatestReadyRevisionName: &revisionName,
+		},
+	}
+
+	replicas := &armappcontainers.ReplicaCollection{
+		Value: []*armappcontainers.Replica{
+			{
+				Name: convert.RefOf("replica1"),
+				Properties: &armappcontainers.ReplicaProperties{
+					Containers: []*armappcontainers.ReplicaContainer{
+						{Name: convert.RefOf("main")},
+						{Name: convert.RefOf("sidecar")},
+					},
+				},
+			},
+		},
+	}
+
+	setupMocks := func(mockContext *mocks.MockContext) *[]*http.Request {
+		_ = mockazsdk.MockContainerAppGet(mockContext, subscriptionId, resourceGroup, appName, containerApp)
+		_ = mockazsdk.MockContainerAppAuthToken(mockContext, subscriptionId, resourceGroup, appName, "TOKEN")
+		_ = mockazsdk.MockContainerAppReplicasList(
+			mockContext, subscriptionId, resourceGroup, appName, revisionName, replicas)
+
+		logRequests := []*http.Request{}
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.URL.Host == fmt.Sprintf("%s.azurecontainerapps.dev", location)
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			logRequests = append(logRequests, request)
+			return &http.Response{
+				Request:    request,
+				StatusCode: http.StatusOK,
+				Header:     http.Header{},
+				Body:       io.NopCloser(strings.NewReader("log line\n")),
+			}, nil
+		})
+
+		return &logRequests
+	}
+
+	t.Run("Console", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		logRequests := setupMocks(mockContext)
+
+		cas := NewContainerAppService(
+			mockContext.SubscriptionCredentialProvider,
+			mockContext.HttpClient,
+			clock.NewMock(),
+			mockContext.ArmClientOptions,
+		)
+		streams, err := cas.OpenLogStreams(
+			*mockContext.Context, subscriptionId, resourceGroup, appName, &LogStreamOptions{Follow: true, TailLines: 50})
+		require.NoError(t, err)
+		require.Len(t, streams, 2)
+		require.Len(t, *logRequests, 2)
+
+		request := (*logRequests)[1]
+		require.Equal(t, fmt.Sprintf(
+			"/subscriptions/%s/resourceGroups/%s/containerApps/%s/revisions/%s/replicas/replica1/containers/sidecar/logstream",
+			subscriptionId,
+			resourceGroup,
+			appName,
+			revisionName,
+		), request.URL.Path)
+		require.Equal(t, "true", request.URL.Query().Get("follow"))
+		require.Equal(t, "50", request.URL.Query().Get("tailLines"))
+		require.Equal(t, "Bearer TOKEN", request.Header.Get("Authorization"))
+	})
+
+	t.Run("System", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		logRequests := setupMocks(mockContext)
+
+		cas := NewContainerAppService(
+			mockContext.SubscriptionCredentialProvider,
+			mockContext.HttpClient,
+			clock.NewMock(),
+			mockContext.ArmClientOptions,
+		)
+		streams, err := cas.OpenLogStreams(
+			*mockContext.Context, subscriptionId, resourceGroup, appName, &LogStreamOptions{System: true})
+		require.NoError(t, err)
+		require.Len(t, streams, 1)
+		require.Len(t, *logRequests, 1)
+		require.True(t, strings.HasSuffix((*logRequests)[0].URL.Path, "/eventstream"))
+		require.Equal(t, "false", (*logRequests)[0].URL.Query().Get("follow"))
+	})
+}
@@ -32,6 +32,19 @@ func (b *WithOrder[T]) Get(key string) (*T, bool) {
 	return v, ok
 }
 
+// Set associates the given value with the given key. Keys that are not yet present are added after all existing keys.
+func (b *WithOrder[T]) Set(key string, value *T) {
+	if b.innerMap == nil {
+		b.innerMap = make(map[string]*T)
+	}
+
+	if _, has := b.innerMap[key]; !has {
+		b.keys = append(b.keys, key)
+	}
+
+	b.innerMap[key] = value
+}
+
 func (b *WithOrder[T]) UnmarshalJSON(data []byte) error {
 	if err := json.Unmarshal(data, &b.innerMap); err != nil {
 		return err
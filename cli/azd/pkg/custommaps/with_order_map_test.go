@@ -16,3 +16,17 @@ func TestBindingsMap(t *testing.T) {
 	assert.Len(t, keys, 3)
 	assert.Equal(t, []string{"a", "c", "b"}, keys)
 }
+
+func TestBindingsMapSet(t *testing.T) {
+	m := &WithOrder[int]{}
+	one, two, three := 1, 2, 3
+	m.Set("b", &one)
+	m.Set("a", &two)
+	m.Set("b", &three)
+
+	assert.Equal(t, []string{"b", "a"}, m.OrderedKeys())
+
+	v, has := m.Get("b")
+	assert.True(t, has)
+	assert.Equal(t, 3, *v)
+}
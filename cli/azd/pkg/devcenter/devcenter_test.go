@@ -3,8 +3,10 @@ package devcenter
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
 	"github.com/azure/azure-dev/cli/azd/pkg/devcentersdk"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
@@ -153,6 +155,42 @@ func (m *mockDevCenterManager) Outputs(
 	return nil, args.Error(1)
 }
 
+func (m *mockDevCenterManager) Environment(ctx context.Context, name string) (*devcentersdk.Environment, error) {
+	args := m.Called(ctx, name)
+	return args.Get(0).(*devcentersdk.Environment), args.Error(1)
+}
+
+func (m *mockDevCenterManager) SetExpiration(
+	ctx context.Context,
+	env *devcentersdk.Environment,
+	expirationDate *time.Time,
+) (*devcentersdk.Environment, error) {
+	args := m.Called(ctx, env, expirationDate)
+	return args.Get(0).(*devcentersdk.Environment), args.Error(1)
+}
+
+func (m *mockDevCenterManager) Redeploy(
+	ctx context.Context,
+	env *devcentersdk.Environment,
+	overrides map[string]string,
+) (map[string]any, error) {
+	args := m.Called(ctx, env, overrides)
+	return args.Get(0).(map[string]any), args.Error(1)
+}
+
+func (m *mockDevCenterManager) Operations(
+	ctx context.Context,
+	env *devcentersdk.Environment,
+) ([]*devcentersdk.EnvironmentOperation, error) {
+	args := m.Called(ctx, env)
+	return args.Get(0).([]*devcentersdk.EnvironmentOperation), args.Error(1)
+}
+
+func (m *mockDevCenterManager) Cost(ctx context.Context, env *devcentersdk.Environment) (*azapi.Cost, error) {
+	args := m.Called(ctx, env)
+	return args.Get(0).(*azapi.Cost), args.Error(1)
+}
+
 var mockDevCenterList []*devcentersdk.DevCenter = []*devcentersdk.DevCenter{
 	{
 		//nolint:lll
@@ -3,7 +3,9 @@ package devcenter
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"sync"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
 	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
@@ -56,6 +58,25 @@ type Manager interface {
 		ctx context.Context,
 		env *devcentersdk.Environment,
 	) (map[string]provisioning.OutputParameter, error)
+	// Environment gets the devcenter environment with the specified name for the configured project and user
+	Environment(ctx context.Context, name string) (*devcentersdk.Environment, error)
+	// SetExpiration sets the date the environment is automatically deleted. A nil date removes the expiration.
+	SetExpiration(
+		ctx context.Context,
+		env *devcentersdk.Environment,
+		expirationDate *time.Time,
+	) (*devcentersdk.Environment, error)
+	// Redeploy deploys the environment again with its current parameters merged with the specified overrides
+	// and returns the parameters used for the deployment
+	Redeploy(
+		ctx context.Context,
+		env *devcentersdk.Environment,
+		overrides map[string]string,
+	) (map[string]any, error)
+	// Operations gets the operation history of the environment, most recent first
+	Operations(ctx context.Context, env *devcentersdk.Environment) ([]*devcentersdk.EnvironmentOperation, error)
+	// Cost gets the cost of the environment resources since the start of the current month
+	Cost(ctx context.Context, env *devcentersdk.Environment) (*azapi.Cost, error)
 }
 
 // Manager provides a common set of methods for interactive with a devcenter and its environments
@@ -64,6 +85,7 @@ type manager struct {
 	client               devcentersdk.DevCenterClient
 	deploymentsService   azapi.Deployments
 	deploymentOperations azapi.DeploymentOperations
+	costManagement       azapi.CostManagement
 	portalUrlBase        string
 }
 
@@ -73,6 +95,7 @@ func NewManager(
 	client devcentersdk.DevCenterClient,
 	deploymentsService azapi.Deployments,
 	deploymentOperations azapi.DeploymentOperations,
+	costManagement azapi.CostManagement,
 	portalUrlBase string,
 ) Manager {
 	return &manager{
@@ -80,6 +103,7 @@ func NewManager(
 		client:               client,
 		deploymentsService:   deploymentsService,
 		deploymentOperations: deploymentOperations,
+		costManagement:       costManagement,
 		portalUrlBase:        string(portalUrlBase),
 	}
 }
@@ -328,3 +352,162 @@ func (m *manager) Outputs(
 
 	return outputs, nil
 }
+
+// Environment gets the devcenter environment with the specified name for the configured project and user
+func (m *manager) Environment(ctx context.Context, name string) (*devcentersdk.Environment, error) {
+	if err := m.config.EnsureValid(); err != nil {
+		return nil, fmt.Errorf("invalid devcenter configuration, %w", err)
+	}
+
+	environment, err := m.environmentClient(name).Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed getting environment '%s': %w", name, err)
+	}
+
+	return environment, nil
+}
+
+// SetExpiration sets the date the environment is automatically deleted. A nil date removes the expiration.
+func (m *manager) SetExpiration(
+	ctx context.Context,
+	env *devcentersdk.Environment,
+	expirationDate *time.Time,
+) (*devcentersdk.Environment, error) {
+	if expirationDate != nil && !expirationDate.After(time.Now()) {
+		return nil, fmt.Errorf("expiration date %s must be in the future", expirationDate.Format(time.RFC3339))
+	}
+
+	updated, err := m.environmentClient(env.Name).Patch(ctx, devcentersdk.EnvironmentPatch{
+		ExpirationDate: expirationDate,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed updating environment expiration: %w", err)
+	}
+
+	return updated, nil
+}
+
+// Redeploy deploys the environment again with its current parameters merged with the specified overrides.
+// Override values are converted to the types declared by the environment definition.
+func (m *manager) Redeploy(
+	ctx context.Context,
+	env *devcentersdk.Environment,
+	overrides map[string]string,
+) (map[string]any, error) {
+	envDef, err := m.client.
+		DevCenterByName(m.config.Name).
+		ProjectByName(m.config.Project).
+		CatalogByName(env.CatalogName).
+		EnvironmentDefinitionByName(env.EnvironmentDefinitionName).
+		Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed getting environment definition: %w", err)
+	}
+
+	parameters := map[string]any{}
+	for key, value := range env.Parameters {
+		parameters[key] = value
+	}
+
+	for key, value := range overrides {
+		index := slices.IndexFunc(envDef.Parameters, func(p devcentersdk.Parameter) bool {
+			return p.Id == key
+		})
+		if index < 0 {
+			return nil, fmt.Errorf(
+				"parameter '%s' is not defined by environment definition '%s'", key, envDef.Name)
+		}
+
+		param := envDef.Parameters[index]
+		if param.ReadOnly {
+			return nil, fmt.Errorf("parameter '%s' is read only", key)
+		}
+
+		typedValue, err := parseParameterValue(param, value)
+		if err != nil {
+			return nil, err
+		}
+
+		parameters[key] = typedValue
+	}
+
+	spec := devcentersdk.EnvironmentSpec{
+		CatalogName:               env.CatalogName,
+		EnvironmentDefinitionName: env.EnvironmentDefinitionName,
+		EnvironmentType:           env.EnvironmentType,
+		Parameters:                parameters,
+		ExpirationDate:            env.ExpirationDate,
+	}
+
+	if err := m.environmentClient(env.Name).Put(ctx, spec); err != nil {
+		return nil, fmt.Errorf("failed redeploying environment: %w", err)
+	}
+
+	return parameters, nil
+}
+
+// Operations gets the operation history of the environment, most recent first
+func (m *manager) Operations(
+	ctx context.Context,
+	env *devcentersdk.Environment,
+) ([]*devcentersdk.EnvironmentOperation, error) {
+	operations, err := m.environmentClient(env.Name).Operations().Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed getting environment operations: %w", err)
+	}
+
+	slices.SortStableFunc(operations.Value, func(x, y *devcentersdk.EnvironmentOperation) bool {
+		if x.StartTime == nil || y.StartTime == nil {
+			return y.StartTime == nil && x.StartTime != nil
+		}
+
+		return x.StartTime.After(*y.StartTime)
+	})
+
+	return operations.Value, nil
+}
+
+// Cost gets the cost of the environment resources since the start of the current month
+func (m *manager) Cost(ctx context.Context, env *devcentersdk.Environment) (*azapi.Cost, error) {
+	resourceGroupId, err := devcentersdk.NewResourceGroupId(env.ResourceGroupId)
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing resource group id: %w", err)
+	}
+
+	return m.costManagement.ResourceGroupCostMonthToDate(
+		ctx, resourceGroupId.SubscriptionId, resourceGroupId.Name)
+}
+
+func (m *manager) environmentClient(name string) *devcentersdk.EnvironmentItemRequestBuilder {
+	// The expiration date of the environment is only read and redeployed with the api version which supports it
+	return m.client.
+		DevCenterByName(m.config.Name).
+		ProjectByName(m.config.Project).
+		EnvironmentsByUser(m.config.User).
+		EnvironmentByName(name).
+		ApiVersion(devcentersdk.EnvironmentLifecycleApiVersion)
+}
+
+// parseParameterValue converts a command line value to the type of the environment definition parameter
+func parseParameterValue(param devcentersdk.Parameter, value string) (any, error) {
+	if len(param.Allowed) > 0 && !slices.Contains(param.Allowed, value) {
+		return nil, fmt.Errorf("invalid value '%s' for parameter '%s'. Allowed values are %v", value, param.Id, param.Allowed)
+	}
+
+	switch param.Type {
+	case devcentersdk.ParameterTypeBool:
+		boolValue, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert %s to bool: %w", param.Id, err)
+		}
+		return boolValue, nil
+	case devcentersdk.ParameterTypeInt:
+		numValue, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert %s to int: %w", param.Id, err)
+		}
+		return numValue, nil
+	default:
+		return value, nil
+	}
+}
@@ -0,0 +1,235 @@
+package devcenter
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resourcegraph/armresourcegraph"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
+	"github.com/azure/azure-dev/cli/azd/pkg/devcentersdk"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockdevcentersdk"
+	"github.com/stretchr/testify/require"
+)
+
+var mockLifecycleConfig = &Config{
+	Name:                  "DEV_CENTER_01",
+	Catalog:               "SampleCatalog",
+	Project:               "Project1",
+	EnvironmentType:       "Dev",
+	EnvironmentDefinition: "WebApp",
+	User:                  "me",
+}
+
+var mockLifecycleEnvironment = &devcentersdk.Environment{
+	Name:                      "env1",
+	EnvironmentType:           "Dev",
+	User:                      "11111111-1111-1111-1111-111111111111",
+	ProvisioningState:         devcentersdk.ProvisioningStateSucceeded,
+	ResourceGroupId:           "/subscriptions/SUBSCRIPTION_01/resourceGroups/Project1-env1",
+	CatalogName:               "SampleCatalog",
+	EnvironmentDefinitionName: "WebApp",
+	Parameters: map[string]any{
+		"environmentName": "env1",
+		"sku":             "B1",
+	},
+}
+
+func Test_Manager_SetExpiration(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		expiration := time.Now().Add(48 * time.Hour).UTC().Truncate(time.Second)
+
+		updated := *mockLifecycleEnvironment
+		updated.ExpirationDate = &expiration
+
+		mockdevcentersdk.MockDevCenterGraphQuery(mockContext, mockDevCenterList)
+		mockdevcentersdk.MockPatchEnvironment(mockContext, "Project1", "me", "env1", &updated)
+
+		manager := newManagerForTest(t, mockContext)
+		result, err := manager.SetExpiration(*mockContext.Context, mockLifecycleEnvironment, &expiration)
+		require.NoError(t, err)
+		require.True(t, expiration.Equal(*result.ExpirationDate))
+	})
+
+	t.Run("PastDate", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		expiration := time.Now().Add(-time.Hour)
+
+		manager := newManagerForTest(t, mockContext)
+		_, err := manager.SetExpiration(*mockContext.Context, mockLifecycleEnvironment, &expiration)
+		require.ErrorContains(t, err, "must be in the future")
+	})
+}
+
+func Test_Manager_Redeploy(t *testing.T) {
+	envDefinition := &devcentersdk.EnvironmentDefinition{
+		Name:        "WebApp",
+		CatalogName: "SampleCatalog",
+		Parameters: []devcentersdk.Parameter{
+			{Id: "environmentName", Type: devcentersdk.ParameterTypeString},
+			{Id: "sku", Type: devcentersdk.ParameterTypeString, Allowed: []string{"B1", "S1"}},
+			{Id: "instances", Type: devcentersdk.ParameterTypeInt},
+			{Id: "repoUrl", Type: devcentersdk.ParameterTypeString, ReadOnly: true},
+		},
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		mockdevcentersdk.MockDevCenterGraphQuery(mockContext, mockDevCenterList)
+		mockdevcentersdk.MockGetEnvironmentDefinition(mockContext, "Project1", "SampleCatalog", "WebApp", envDefinition)
+
+		var spec devcentersdk.EnvironmentSpec
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodPut
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			body, err := io.ReadAll(request.Body)
+			if err != nil {
+				return nil, err
+			}
+			if err := json.Unmarshal(body, &spec); err != nil {
+				return nil, err
+			}
+
+			response, err := mocks.CreateHttpResponseWithBody(request, http.StatusCreated, &devcentersdk.OperationStatus{
+				Status: "Succeeded",
+			})
+			response.Header.Set("Location", "https://DEV_CENTER_01.eastus2.devcenter.azure.com/operationstatuses/put")
+			return response, err
+		})
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return strings.HasSuffix(request.URL.Path, "/operationstatuses/put")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, &devcentersdk.OperationStatus{
+				Status: "Succeeded",
+			})
+		})
+
+		manager := newManagerForTest(t, mockContext)
+		parameters, err := manager.Redeploy(*mockContext.Context, mockLifecycleEnvironment, map[string]string{
+			"sku":       "S1",
+			"instances": "3",
+		})
+		require.NoError(t, err)
+
+		expected := map[string]any{
+			"environmentName": "env1",
+			"sku":             "S1",
+			"instances":       3,
+		}
+		require.Equal(t, expected, parameters)
+		require.Equal(t, "WebApp", spec.EnvironmentDefinitionName)
+		require.Equal(t, "Dev", spec.EnvironmentType)
+		require.Equal(t, "S1", spec.Parameters["sku"])
+		require.Equal(t, float64(3), spec.Parameters["instances"])
+	})
+
+	t.Run("InvalidOverrides", func(t *testing.T) {
+		overrides := []struct {
+			name      string
+			overrides map[string]string
+			errorText string
+		}{
+			{"UnknownParameter", map[string]string{"location": "eastus"}, "is not defined"},
+			{"ReadOnlyParameter", map[string]string{"repoUrl": "https://example.com"}, "is read only"},
+			{"NotAllowedValue", map[string]string{"sku": "P1"}, "Allowed values"},
+			{"InvalidType", map[string]string{"instances": "three"}, "failed to convert"},
+		}
+
+		for _, test := range overrides {
+			t.Run(test.name, func(t *testing.T) {
+				mockContext := mocks.NewMockContext(context.Background())
+				mockdevcentersdk.MockDevCenterGraphQuery(mockContext, mockDevCenterList)
+				mockdevcentersdk.MockGetEnvironmentDefinition(
+					mockContext, "Project1", "SampleCatalog", "WebApp", envDefinition)
+
+				manager := newManagerForTest(t, mockContext)
+				_, err := manager.Redeploy(*mockContext.Context, mockLifecycleEnvironment, test.overrides)
+				require.ErrorContains(t, err, test.errorText)
+			})
+		}
+	})
+}
+
+func Test_Manager_Operations(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	older := time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC)
+	newer := time.Date(2024, 3, 5, 10, 15, 0, 0, time.UTC)
+
+	mockdevcentersdk.MockDevCenterGraphQuery(mockContext, mockDevCenterList)
+	mockdevcentersdk.MockListEnvironmentOperations(mockContext, "Project1", "me", "env1",
+		[]*devcentersdk.EnvironmentOperation{
+			{OperationId: "1", Kind: devcentersdk.OperationKindDeploy, Status: "Failed", StartTime: &older},
+			{OperationId: "2", Kind: devcentersdk.OperationKindDeploy, Status: "Running"},
+			{OperationId: "3", Kind: devcentersdk.OperationKindDeploy, Status: "Succeeded", StartTime: &newer},
+		},
+	)
+
+	manager := newManagerForTest(t, mockContext)
+	operations, err := manager.Operations(*mockContext.Context, mockLifecycleEnvironment)
+	require.NoError(t, err)
+
+	ids := []string{}
+	for _, operation := range operations {
+		ids = append(ids, operation.OperationId)
+	}
+	require.Equal(t, []string{"3", "1", "2"}, ids)
+}
+
+func Test_Manager_Cost(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+
+	var queryPath string
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPost &&
+			strings.HasSuffix(request.URL.Path, "/providers/Microsoft.CostManagement/query")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		queryPath = request.URL.Path
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+			"properties": map[string]any{
+				"columns": []map[string]any{
+					{"name": "Cost", "type": "Number"},
+					{"name": "Currency", "type": "String"},
+				},
+				"rows": [][]any{{12.5, "USD"}},
+			},
+		})
+	})
+
+	manager := newManagerForTest(t, mockContext)
+	cost, err := manager.Cost(*mockContext.Context, mockLifecycleEnvironment)
+	require.NoError(t, err)
+	require.Equal(t, &azapi.Cost{Amount: 12.5, Currency: "USD"}, cost)
+	require.Equal(t,
+		"/subscriptions/SUBSCRIPTION_01/resourceGroups/Project1-env1/providers/Microsoft.CostManagement/query",
+		queryPath,
+	)
+}
+
+func newManagerForTest(t *testing.T, mockContext *mocks.MockContext) Manager {
+	resourceGraphClient, err := armresourcegraph.NewClient(mockContext.Credentials, mockContext.ArmClientOptions)
+	require.NoError(t, err)
+
+	devCenterClient, err := devcentersdk.NewDevCenterClient(
+		mockContext.Credentials,
+		mockContext.CoreClientOptions,
+		resourceGraphClient,
+		cloud.AzurePublic(),
+	)
+	require.NoError(t, err)
+
+	return NewManager(
+		mockLifecycleConfig,
+		devCenterClient,
+		azapi.NewDeployments(mockContext.SubscriptionCredentialProvider, mockContext.ArmClientOptions),
+		azapi.NewDeploymentOperations(mockContext.SubscriptionCredentialProvider, mockContext.ArmClientOptions),
+		azapi.NewCostManagement(mockContext.SubscriptionCredentialProvider, mockContext.ArmClientOptions),
+		cloud.AzurePublic().PortalUrlBase,
+	)
+}
@@ -10,6 +10,9 @@ import (
 const (
 	apiVersionName    = "api-version"
 	defaultApiVersion = "2023-04-01"
+	// EnvironmentLifecycleApiVersion is the api version of the expiration date, patch and operation history of
+	// environments, which aren't available in the default api version. Only the requests which depend on them use it.
+	EnvironmentLifecycleApiVersion = "2024-02-01"
 )
 
 type apiVersionPolicy struct {
@@ -27,12 +30,14 @@ func NewApiVersionPolicy(apiVersion *string) policy.Policy {
 	}
 }
 
-// Sets the custom user-agent string on the underlying request
+// Sets the api version on the underlying request, unless the request sets its own api version
 func (p *apiVersionPolicy) Do(req *policy.Request) (*http.Response, error) {
 	rawRequest := req.Raw()
 	queryString := rawRequest.URL.Query()
-	queryString.Set(apiVersionName, defaultApiVersion)
-	rawRequest.URL.RawQuery = queryString.Encode()
+	if !queryString.Has(apiVersionName) {
+		queryString.Set(apiVersionName, p.apiVersion)
+		rawRequest.URL.RawQuery = queryString.Encode()
+	}
 
 	return req.Next()
 }
@@ -11,6 +11,7 @@ import (
 
 type entityItemRequestInfo struct {
 	selectParams []string
+	apiVersion   string
 }
 
 type EntityItemRequestBuilder[T any] struct {
@@ -65,6 +66,10 @@ func (b *EntityItemRequestBuilder[T]) createRequest(
 		query.Set("$select", strings.Join(b.requestInfo.selectParams, ","))
 	}
 
+	if b.requestInfo.apiVersion != "" {
+		query.Set(apiVersionName, b.requestInfo.apiVersion)
+	}
+
 	raw.URL.RawQuery = query.Encode()
 
 	return req, err
@@ -75,3 +80,10 @@ func (b *EntityItemRequestBuilder[T]) Select(params []string) *T {
 
 	return b.builder
 }
+
+// ApiVersion overrides the default api version of the requests of the builder
+func (b *EntityItemRequestBuilder[T]) ApiVersion(apiVersion string) *T {
+	b.requestInfo.apiVersion = apiVersion
+
+	return b.builder
+}
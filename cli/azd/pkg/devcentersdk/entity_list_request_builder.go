@@ -9,8 +9,9 @@ import (
 )
 
 type entityListRequestInfo struct {
-	filter *string
-	top    *int
+	filter     *string
+	top        *int
+	apiVersion string
 }
 
 type EntityListRequestBuilder[T any] struct {
@@ -62,6 +63,10 @@ func (b *EntityListRequestBuilder[T]) createRequest(
 		query.Set("$top", fmt.Sprint((*b.requestInfo.top)))
 	}
 
+	if b.requestInfo.apiVersion != "" {
+		query.Set(apiVersionName, b.requestInfo.apiVersion)
+	}
+
 	raw.URL.RawQuery = query.Encode()
 
 	return req, err
@@ -78,3 +83,10 @@ func (b *EntityListRequestBuilder[T]) Top(count int) *T {
 
 	return b.builder
 }
+
+// ApiVersion overrides the default api version of the requests of the builder
+func (b *EntityListRequestBuilder[T]) ApiVersion(apiVersion string) *T {
+	b.requestInfo.apiVersion = apiVersion
+
+	return b.builder
+}
@@ -192,3 +192,84 @@ func (c *EnvironmentItemRequestBuilder) Delete(ctx context.Context) error {
 
 	return nil
 }
+
+// Patch updates the properties of an environment which don't require a new deployment, like its expiration date
+func (c *EnvironmentItemRequestBuilder) Patch(ctx context.Context, patch EnvironmentPatch) (*Environment, error) {
+	requestUrl := fmt.Sprintf("projects/%s/users/%s/environments/%s", c.projectName, c.userId, c.id)
+	c.ApiVersion(EnvironmentLifecycleApiVersion)
+	req, err := c.createRequest(ctx, http.MethodPatch, requestUrl)
+	if err != nil {
+		return nil, fmt.Errorf("failed creating request: %w", err)
+	}
+
+	err = SetHttpRequestBody(req, patch)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Raw().Header.Set("Content-Type", "application/merge-patch+json")
+
+	res, err := c.client.pipeline.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if !runtime.HasStatusCode(res, http.StatusOK) {
+		return nil, runtime.NewResponseError(res)
+	}
+
+	return httputil.ReadRawResponse[Environment](res)
+}
+
+func (c *EnvironmentItemRequestBuilder) Operations() *EnvironmentOperationListRequestBuilder {
+	return NewEnvironmentOperationListRequestBuilder(c.client, c.devCenter, c.projectName, c.userId, c.id)
+}
+
+// Environment Operations
+type EnvironmentOperationListRequestBuilder struct {
+	*EntityListRequestBuilder[EnvironmentOperationListRequestBuilder]
+	projectName     string
+	userId          string
+	environmentName string
+}
+
+func NewEnvironmentOperationListRequestBuilder(
+	c *devCenterClient,
+	devCenter *DevCenter,
+	projectName string,
+	userId string,
+	environmentName string,
+) *EnvironmentOperationListRequestBuilder {
+	builder := &EnvironmentOperationListRequestBuilder{}
+	builder.EntityListRequestBuilder = newEntityListRequestBuilder(builder, c, devCenter)
+	builder.ApiVersion(EnvironmentLifecycleApiVersion)
+	builder.projectName = projectName
+	builder.userId = userId
+	builder.environmentName = environmentName
+
+	return builder
+}
+
+func (c *EnvironmentOperationListRequestBuilder) Get(ctx context.Context) (*EnvironmentOperationListResponse, error) {
+	requestUrl := fmt.Sprintf(
+		"projects/%s/users/%s/environments/%s/operations",
+		c.projectName,
+		c.userId,
+		c.environmentName,
+	)
+	req, err := c.createRequest(ctx, http.MethodGet, requestUrl)
+	if err != nil {
+		return nil, fmt.Errorf("failed creating request: %w", err)
+	}
+
+	res, err := c.client.pipeline.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if !runtime.HasStatusCode(res, http.StatusOK) {
+		return nil, runtime.NewResponseError(res)
+	}
+
+	return httputil.ReadRawResponse[EnvironmentOperationListResponse](res)
+}
@@ -0,0 +1,178 @@
+package devcentersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resourcegraph/armresourcegraph"
+	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+const testDevCenterUri = "https://DEVCENTER.eastus2.devcenter.azure.com"
+
+func Test_Environment_Patch(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	client := newTestClient(t, mockContext)
+
+	var requestBody map[string]any
+	var contentType string
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPatch &&
+			request.URL.Path == "/projects/Project1/users/me/environments/env1"
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		contentType = request.Header.Get("Content-Type")
+		body, err := io.ReadAll(request.Body)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(body, &requestBody); err != nil {
+			return nil, err
+		}
+
+		return recordedResponse(request, "environment_patch.json")
+	})
+
+	expiration := time.Date(2024, 3, 12, 17, 0, 0, 0, time.UTC)
+	environment, err := client.
+		DevCenterByEndpoint(testDevCenterUri).
+		ProjectByName("Project1").
+		EnvironmentsByMe().
+		EnvironmentByName("env1").
+		Patch(*mockContext.Context, EnvironmentPatch{ExpirationDate: &expiration})
+
+	require.NoError(t, err)
+	require.Equal(t, "application/merge-patch+json", contentType)
+	require.Equal(t, "2024-03-12T17:00:00Z", requestBody["expirationDate"])
+	require.Equal(t, "env1", environment.Name)
+	require.Equal(t, "11111111-1111-1111-1111-111111111111", environment.User)
+	require.True(t, expiration.Equal(*environment.ExpirationDate))
+}
+
+func Test_Environment_Operations(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	client := newTestClient(t, mockContext)
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet &&
+			request.URL.Path == "/projects/Project1/users/me/environments/env1/operations"
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return recordedResponse(request, "environment_operations.json")
+	})
+
+	operations, err := client.
+		DevCenterByEndpoint(testDevCenterUri).
+		ProjectByName("Project1").
+		EnvironmentsByMe().
+		EnvironmentByName("env1").
+		Operations().
+		Get(*mockContext.Context)
+
+	require.NoError(t, err)
+	require.Len(t, operations.Value, 2)
+
+	latest := operations.Value[0]
+	require.Equal(t, OperationKindDeploy, latest.Kind)
+	require.Equal(t, "Succeeded", latest.Status)
+	require.Equal(t, "S1", latest.EnvironmentParameters["sku"])
+	require.Nil(t, latest.Error)
+
+	failed := operations.Value[1]
+	require.Equal(t, "Failed", failed.Status)
+	require.Equal(t, "DeploymentFailed", failed.Error.Code)
+	require.Equal(t, 3*time.Minute+10*time.Second, failed.EndTime.Sub(*failed.StartTime))
+}
+
+func Test_Environment_ApiVersion(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	client := newTestClient(t, mockContext)
+
+	apiVersions := []string{}
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet &&
+			request.URL.Path == "/projects/Project1/users/me/environments/env1"
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		apiVersions = append(apiVersions, request.URL.Query().Get(apiVersionName))
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, Environment{Name: "env1"})
+	})
+
+	environments := client.DevCenterByEndpoint(testDevCenterUri).ProjectByName("Project1").EnvironmentsByMe()
+
+	// The existing calls keep using the default api version, only the calls which need it opt into the newer one
+	_, err := environments.EnvironmentByName("env1").Get(*mockContext.Context)
+	require.NoError(t, err)
+
+	_, err = environments.EnvironmentByName("env1").ApiVersion(EnvironmentLifecycleApiVersion).Get(*mockContext.Context)
+	require.NoError(t, err)
+
+	require.Equal(t, []string{defaultApiVersion, EnvironmentLifecycleApiVersion}, apiVersions)
+}
+
+// newTestClient creates a client which resolves the dev center used by the recorded responses
+func newTestClient(t *testing.T, mockContext *mocks.MockContext) DevCenterClient {
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.URL.Path == "/providers/Microsoft.ResourceGraph/resources"
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		//nolint:lll
+		projectId := "/subscriptions/SUBSCRIPTION_ID/resourceGroups/RESOURCE_GROUP/providers/Microsoft.DevCenter/projects/Project1"
+		//nolint:lll
+		devCenterId := "/subscriptions/SUBSCRIPTION_ID/resourceGroups/RESOURCE_GROUP/providers/Microsoft.DevCenter/devcenters/DEVCENTER"
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armresourcegraph.ClientResourcesResponse{
+			QueryResponse: armresourcegraph.QueryResponse{
+				Data: []*GenericResource{
+					{
+						Id:   projectId,
+						Name: "Project1",
+						Type: "microsoft.devcenter/projects",
+						Properties: map[string]any{
+							"devCenterUri": testDevCenterUri + "/",
+							"devCenterId":  devCenterId,
+						},
+					},
+				},
+			},
+		})
+	})
+
+	resourceGraphClient, err := armresourcegraph.NewClient(mockContext.Credentials, mockContext.ArmClientOptions)
+	require.NoError(t, err)
+
+	client, err := NewDevCenterClient(
+		mockContext.Credentials,
+		mockContext.CoreClientOptions,
+		resourceGraphClient,
+		cloud.AzurePublic(),
+	)
+	require.NoError(t, err)
+
+	return client
+}
+
+// recordedResponse responds with a response body recorded from the dev center data plane API, which only returns
+// expiration dates and operations with the environment lifecycle api version
+func recordedResponse(request *http.Request, name string) (*http.Response, error) {
+	if request.URL.Query().Get(apiVersionName) != EnvironmentLifecycleApiVersion {
+		return nil, fmt.Errorf("unexpected api-version '%s'", request.URL.Query().Get(apiVersionName))
+	}
+
+	body, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		return nil, err
+	}
+
+	var value any
+	if err := json.Unmarshal(body, &value); err != nil {
+		return nil, err
+	}
+
+	return mocks.CreateHttpResponseWithBody(request, http.StatusOK, value)
+}
@@ -170,6 +170,7 @@ const (
 	ProvisioningStateSucceeded ProvisioningState = "Succeeded"
 	ProvisioningStateCreating  ProvisioningState = "Creating"
 	ProvisioningStateDeleting  ProvisioningState = "Deleting"
+	ProvisioningStateFailed    ProvisioningState = "Failed"
 )
 
 type Environment struct {
@@ -181,6 +182,7 @@ type Environment struct {
 	CatalogName               string
 	EnvironmentDefinitionName string
 	Parameters                map[string]any
+	ExpirationDate            *time.Time
 }
 
 type EnvironmentListResponse struct {
@@ -192,6 +194,13 @@ type EnvironmentSpec struct {
 	EnvironmentDefinitionName string         `json:"environmentDefinitionName"`
 	EnvironmentType           string         `json:"environmentType"`
 	Parameters                map[string]any `json:"parameters"`
+	ExpirationDate            *time.Time     `json:"expirationDate,omitempty"`
+}
+
+// EnvironmentPatch is the set of environment properties which can be updated without redeploying the environment
+type EnvironmentPatch struct {
+	// ExpirationDate is the date the environment is automatically deleted. A nil value removes the expiration.
+	ExpirationDate *time.Time `json:"expirationDate"`
 }
 
 type EnvironmentPutResponse struct {
@@ -205,3 +214,31 @@ type OperationStatus struct {
 	StartTime time.Time `json:"startTime"`
 	EndTime   time.Time `json:"endTime"`
 }
+
+type OperationKind string
+
+const (
+	OperationKindDeploy OperationKind = "Deploy"
+	OperationKindDelete OperationKind = "Delete"
+)
+
+type OperationError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// EnvironmentOperation is an entry of the operation history of an environment
+type EnvironmentOperation struct {
+	OperationId           string          `json:"operationId"`
+	Kind                  OperationKind   `json:"kind"`
+	Status                string          `json:"status"`
+	CreatedByObjectId     string          `json:"createdByObjectId"`
+	StartTime             *time.Time      `json:"startTime,omitempty"`
+	EndTime               *time.Time      `json:"endTime,omitempty"`
+	EnvironmentParameters map[string]any  `json:"environmentParameters,omitempty"`
+	Error                 *OperationError `json:"error,omitempty"`
+}
+
+type EnvironmentOperationListResponse struct {
+	Value []*EnvironmentOperation `json:"value"`
+}
@@ -4,7 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
@@ -13,6 +16,8 @@ import (
 
 type Cli struct {
 	commandRunner exec.CommandRunner
+	// The kube config of the cluster, the default kube config is used when empty
+	kubeConfig string
 }
 
 func NewCli(commandRunner exec.CommandRunner) *Cli {
@@ -49,10 +54,15 @@ func (cli *Cli) CheckInstalled(ctx context.Context) error {
 	return nil
 }
 
+// Sets the KUBECONFIG environment variable of the helm commands
+func (c *Cli) SetKubeConfig(kubeConfig string) {
+	c.kubeConfig = kubeConfig
+}
+
 // AddRepo adds a helm repo with the specified name and url
 func (c *Cli) AddRepo(ctx context.Context, repo *Repository) error {
 	runArgs := exec.NewRunArgs("helm", "repo", "add", repo.Name, repo.Url)
-	_, err := c.commandRunner.Run(ctx, runArgs)
+	_, err := c.run(ctx, runArgs)
 	if err != nil {
 		return fmt.Errorf("failed to add repo %s: %w", repo.Name, err)
 	}
@@ -63,7 +73,7 @@ func (c *Cli) AddRepo(ctx context.Context, repo *Repository) error {
 // UpdateRepo updates the helm repo with the specified name
 func (c *Cli) UpdateRepo(ctx context.Context, repoName string) error {
 	runArgs := exec.NewRunArgs("helm", "repo", "update", repoName)
-	_, err := c.commandRunner.Run(ctx, runArgs)
+	_, err := c.run(ctx, runArgs)
 	if err != nil {
 		return fmt.Errorf("failed to add repo %s: %w", repoName, err)
 	}
@@ -78,7 +88,7 @@ func (c *Cli) Install(ctx context.Context, release *Release) error {
 		runArgs = runArgs.AppendParams("--values", release.Values)
 	}
 
-	_, err := c.commandRunner.Run(ctx, runArgs)
+	_, err := c.run(ctx, runArgs)
 	if err != nil {
 		return fmt.Errorf("failed to install helm chart %s: %w", release.Chart, err)
 	}
@@ -90,24 +100,62 @@ func (c *Cli) Install(ctx context.Context, release *Release) error {
 // If the release did not previously exist, it will be installed
 func (c *Cli) Upgrade(ctx context.Context, release *Release) error {
 	runArgs := exec.NewRunArgs("helm", "upgrade", release.Name, release.Chart, "--install", "--wait")
-	if release.Version != "" {
-		runArgs = runArgs.AppendParams("--version", release.Version)
+	runArgs = appendReleaseParams(runArgs, release)
+
+	if release.Namespace != "" {
+		runArgs = runArgs.AppendParams("--create-namespace")
 	}
 
-	if release.Values != "" {
-		runArgs = runArgs.AppendParams("--values", release.Values)
+	_, err := c.run(ctx, runArgs)
+	if err != nil {
+		return fmt.Errorf("failed to install helm chart %s: %w", release.Chart, err)
 	}
 
-	if release.Namespace != "" {
-		runArgs = runArgs.AppendParams(
-			"--namespace", release.Namespace,
-			"--create-namespace",
+	return nil
+}
+
+// Diff writes the changes an upgrade of the helm release would make to the writer.
+// Requires the helm-diff plugin to be installed.
+func (c *Cli) Diff(ctx context.Context, release *Release, writer io.Writer) error {
+	runArgs := exec.NewRunArgs("helm", "diff", "upgrade", release.Name, release.Chart, "--allow-unreleased")
+	runArgs = appendReleaseParams(runArgs, release).WithStdOut(writer)
+
+	_, err := c.run(ctx, runArgs)
+	if err != nil {
+		return fmt.Errorf(
+			"failed to diff helm chart %s, ensure the helm-diff plugin is installed (%s): %w",
+			release.Chart,
+			"https://github.com/databus23/helm-diff",
+			err,
 		)
 	}
 
-	_, err := c.commandRunner.Run(ctx, runArgs)
+	return nil
+}
+
+// Rollback rolls back a helm release to its previous revision
+func (c *Cli) Rollback(ctx context.Context, release *Release) error {
+	runArgs := exec.NewRunArgs("helm", "rollback", release.Name, "--wait")
+	if release.Namespace != "" {
+		runArgs = runArgs.AppendParams("--namespace", release.Namespace)
+	}
+
+	_, err := c.run(ctx, runArgs)
 	if err != nil {
-		return fmt.Errorf("failed to install helm chart %s: %w", release.Chart, err)
+		return fmt.Errorf("failed to rollback helm release %s: %w", release.Name, err)
+	}
+
+	return nil
+}
+
+// RegistryLogin logs into the OCI registry with the specified host
+func (c *Cli) RegistryLogin(ctx context.Context, host string, username string, password string) error {
+	runArgs := exec.NewRunArgs("helm", "registry", "login", host, "--username", username, "--password-stdin").
+		WithStdIn(strings.NewReader(password))
+
+	_, err := c.run(ctx, runArgs)
+	if err != nil {
+		return fmt.Errorf("failed to login to registry %s: %w", host, err)
 	}
 
 	return nil
@@ -120,7 +168,7 @@ func (c *Cli) Status(ctx context.Context, release *Release) (*StatusResult, erro
 		runArgs = runArgs.AppendParams("--namespace", release.Namespace)
 	}
 
-	runResult, err := c.commandRunner.Run(ctx, runArgs)
+	runResult, err := c.run(ctx, runArgs)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query status for helm chart %s: %w", release.Chart, err)
 	}
@@ -133,6 +181,50 @@ func (c *Cli) Status(ctx context.Context, release *Release) (*StatusResult, erro
 	return result, nil
 }
 
+// run runs the helm command against the cluster of the kube config
+func (c *Cli) run(ctx context.Context, runArgs exec.RunArgs) (exec.RunResult, error) {
+	if c.kubeConfig != "" {
+		runArgs = runArgs.WithEnv(append(runArgs.Env, fmt.Sprintf("KUBECONFIG=%s", c.kubeConfig)))
+	}
+
+	return c.commandRunner.Run(ctx, runArgs)
+}
+
+// appendReleaseParams appends the parameters shared by the commands that install a helm release
+func appendReleaseParams(runArgs exec.RunArgs, release *Release) exec.RunArgs {
+	if release.Version != "" {
+		runArgs = runArgs.AppendParams("--version", release.Version)
+	}
+
+	if release.Values != "" {
+		runArgs = runArgs.AppendParams("--values", release.Values)
+	}
+
+	runArgs = appendSetParams(runArgs, "--set", release.Set)
+	runArgs = appendSetParams(runArgs, "--set-string", release.SetString)
+
+	if release.Namespace != "" {
+		runArgs = runArgs.AppendParams("--namespace", release.Namespace)
+	}
+
+	return runArgs
+}
+
+// appendSetParams appends a flag for each of the values, sorted by their path
+func appendSetParams(runArgs exec.RunArgs, flag string, values map[string]string) exec.RunArgs {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		runArgs = runArgs.AppendParams(flag, fmt.Sprintf("%s=%s", key, values[key]))
+	}
+
+	return runArgs
+}
+
 func (cli *Cli) getClientVersion(ctx context.Context) (string, error) {
 	runArgs := exec.NewRunArgs("helm", "version", "--template", "{{.Version}}")
 	versionResult, err := cli.commandRunner.Run(ctx, runArgs)
@@ -164,4 +256,6 @@ type StatusKind string
 const (
 	// StatusKindDeployed is the status of a helm release that has been deployed
 	StatusKindDeployed StatusKind = "deployed"
+	// StatusKindFailed is the status of a helm release that failed to deploy
+	StatusKindFailed StatusKind = "failed"
 )
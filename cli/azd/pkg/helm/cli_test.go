@@ -3,6 +3,7 @@ package helm
 import (
 	"context"
 	"errors"
+	"io"
 	"strings"
 	"testing"
 
@@ -351,6 +352,51 @@ func Test_Cli_Upgrade(t *testing.T) {
 		}, runArgs.Args)
 	})
 
+	t.Run("WithSet", func(t *testing.T) {
+		ran := false
+		var runArgs exec.RunArgs
+
+		releaseWithSet := *release
+		releaseWithSet.Set = map[string]string{
+			"replicaCount": "2",
+		}
+		releaseWithSet.SetString = map[string]string{
+			"image.tag":        "1.0",
+			"image.repository": "contoso.azurecr.io/app",
+		}
+
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.CommandRunner.
+			When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "helm upgrade")
+			}).
+			RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				ran = true
+				runArgs = args
+				return exec.NewRunResult(0, "", ""), nil
+			})
+
+		cli := NewCli(mockContext.CommandRunner)
+		err := cli.Upgrade(*mockContext.Context, &releaseWithSet)
+		require.True(t, ran)
+		require.NoError(t, err)
+
+		require.Equal(t, "helm", runArgs.Cmd)
+		require.Equal(t, []string{
+			"upgrade",
+			"test",
+			"test/chart",
+			"--install",
+			"--wait",
+			"--set",
+			"replicaCount=2",
+			"--set-string",
+			"image.repository=contoso.azurecr.io/app",
+			"--set-string",
+			"image.tag=1.0",
+		}, runArgs.Args)
+	})
+
 	t.Run("Failure", func(t *testing.T) {
 		ran := false
 
@@ -373,6 +419,121 @@ func Test_Cli_Upgrade(t *testing.T) {
 	})
 }
 
+func Test_Cli_Diff(t *testing.T) {
+	release := &Release{
+		Name:      "test",
+		Chart:     "oci://contoso.azurecr.io/charts/test",
+		Version:   "1.0.0",
+		Namespace: "test-namespace",
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		var runArgs exec.RunArgs
+
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.CommandRunner.
+			When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "helm diff upgrade")
+			}).
+			RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				runArgs = args
+				_, _ = args.StdOut.Write([]byte("diff"))
+				return exec.NewRunResult(0, "", ""), nil
+			})
+
+		output := &strings.Builder{}
+		cli := NewCli(mockContext.CommandRunner)
+		err := cli.Diff(*mockContext.Context, release, output)
+		require.NoError(t, err)
+		require.Equal(t, "diff", output.String())
+
+		require.Equal(t, []string{
+			"diff",
+			"upgrade",
+			"test",
+			"oci://contoso.azurecr.io/charts/test",
+			"--allow-unreleased",
+			"--version",
+			"1.0.0",
+			"--namespace",
+			"test-namespace",
+		}, runArgs.Args)
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.CommandRunner.
+			When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "helm diff upgrade")
+			}).
+			RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				return exec.NewRunResult(1, "", ""), errors.New(`unknown command "diff" for "helm"`)
+			})
+
+		cli := NewCli(mockContext.CommandRunner)
+		err := cli.Diff(*mockContext.Context, release, &strings.Builder{})
+		require.Error(t, err)
+		require.ErrorContains(t, err, "ensure the helm-diff plugin is installed")
+	})
+}
+
+func Test_Cli_Rollback(t *testing.T) {
+	var runArgs exec.RunArgs
+
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.CommandRunner.
+		When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "helm rollback")
+		}).
+		RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			runArgs = args
+			return exec.NewRunResult(0, "", ""), nil
+		})
+
+	cli := NewCli(mockContext.CommandRunner)
+	err := cli.Rollback(*mockContext.Context, &Release{Name: "test", Namespace: "test-namespace"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"rollback", "test", "--wait", "--namespace", "test-namespace"}, runArgs.Args)
+	require.Empty(t, runArgs.Env)
+
+	t.Run("KubeConfig", func(t *testing.T) {
+		cli.SetKubeConfig("/home/user/.kube/azd-dev-api")
+		err := cli.Rollback(*mockContext.Context, &Release{Name: "test"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"KUBECONFIG=/home/user/.kube/azd-dev-api"}, runArgs.Env)
+	})
+}
+
+func Test_Cli_RegistryLogin(t *testing.T) {
+	var runArgs exec.RunArgs
+
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.CommandRunner.
+		When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "helm registry login")
+		}).
+		RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			runArgs = args
+			return exec.NewRunResult(0, "", ""), nil
+		})
+
+	cli := NewCli(mockContext.CommandRunner)
+	err := cli.RegistryLogin(*mockContext.Context, "contoso.azurecr.io", "user", "password")
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"registry",
+		"login",
+		"contoso.azurecr.io",
+		"--username",
+		"user",
+		"--password-stdin",
+	}, runArgs.Args)
+
+	password, err := io.ReadAll(runArgs.StdIn)
+	require.NoError(t, err)
+	require.Equal(t, "password", string(password))
+}
+
 func Test_Cli_Status(t *testing.T) {
 	release := &Release{
 		Name: "test",
@@ -1,8 +1,12 @@
 package helm
 
+import "strings"
+
 type Config struct {
 	Repositories []*Repository `yaml:"repositories"`
 	Releases     []*Release    `yaml:"releases"`
+	// When set, the changes of each release are previewed with the helm-diff plugin before the release is upgraded
+	Diff bool `yaml:"diff,omitempty"`
 }
 
 type Repository struct {
@@ -16,4 +20,38 @@ type Release struct {
 	Version   string `yaml:"version"`
 	Namespace string `yaml:"namespace"`
 	Values    string `yaml:"values"`
+	// Values set on the command line of the release, keyed by the path of the value
+	Set map[string]string `yaml:"set,omitempty"`
+	// Values set on the command line as strings, ex) the container image of the service, whose tag may look like a number
+	SetString map[string]string `yaml:"-"`
+	// The paths of the values the container image of the service is set to
+	Image *ImageValues `yaml:"image,omitempty"`
+}
+
+// ImageValues are the paths of the values of a chart that hold the repository and the tag of the container image.
+type ImageValues struct {
+	Repository string `yaml:"repository,omitempty"`
+	Tag        string `yaml:"tag,omitempty"`
+}
+
+const (
+	// DefaultImageRepositoryValue is the path of the value the image repository is set to by default
+	DefaultImageRepositoryValue = "image.repository"
+	// DefaultImageTagValue is the path of the value the image tag is set to by default
+	DefaultImageTagValue = "image.tag"
+)
+
+// IsOci returns true when the chart of the release is stored in an OCI registry
+func (r *Release) IsOci() bool {
+	return strings.HasPrefix(r.Chart, "oci://")
+}
+
+// RegistryHost returns the host of the OCI registry the chart of the release is stored in
+func (r *Release) RegistryHost() string {
+	host := strings.TrimPrefix(r.Chart, "oci://")
+	if i := strings.Index(host, "/"); i >= 0 {
+		host = host[:i]
+	}
+
+	return host
 }
@@ -70,6 +70,7 @@ type BicepProvider struct {
 	azCli                 azcli.AzCli
 	deploymentsService    azapi.Deployments
 	deploymentOperations  azapi.DeploymentOperations
+	deploymentStacks      azapi.DeploymentStacks
 	prompters             prompt.Prompter
 	curPrincipal          CurrentPrincipalIdProvider
 	alphaFeatureManager   *alpha.FeatureManager
@@ -114,6 +115,20 @@ func (p *BicepProvider) Initialize(ctx context.Context, projectPath string, opti
 	}
 	p.ignoreDeploymentState = options.IgnoreDeploymentState
 
+	if p.useDeploymentStacks() {
+		if !p.alphaFeatureManager.IsEnabled(DeploymentStacksFeature) {
+			return ErrDeploymentStacksNotEnabled
+		}
+
+		if p.targeted() {
+			return ErrTargetsWithDeploymentStacks
+		}
+
+		if _, err := deploymentStackOptions(p.options.DeploymentStacks); err != nil {
+			return fmt.Errorf("validating infra.deploymentStacks: %w", err)
+		}
+	}
+
 	p.console.ShowSpinner(ctx, "Initialize bicep provider", input.Step)
 	err := p.EnsureEnv(ctx)
 	p.console.StopSpinner(ctx, "", input.Step)
@@ -260,7 +275,14 @@ func (p *BicepProvider) State(ctx context.Context, options *StateOptions) (*Stat
 
 	var deployment *armresources.DeploymentExtended
 
-	deployments, err := p.findCompletedDeployments(ctx, p.env.Name(), scope, options.Hint())
+	var deployments []*armresources.DeploymentExtended
+	if p.useDeploymentStacks() {
+		var stackDeployment *armresources.DeploymentExtended
+		stackDeployment, err = p.stackDeployment(ctx, scope)
+		deployments = []*armresources.DeploymentExtended{stackDeployment}
+	} else {
+		deployments, err = p.findCompletedDeployments(ctx, p.env.Name(), scope, options.Hint())
+	}
 	p.console.StopSpinner(ctx, "", input.StepDone)
 
 	if err != nil {
@@ -389,6 +411,14 @@ func (p *BicepProvider) plan(ctx context.Context) (*deploymentDetails, error) {
 		compileResult.Parameters = configuredParameters
 	}
 
+	if p.targeted() {
+		// the parsed template is kept as is, so outputs of the full template can still be resolved
+		compileResult.RawArmTemplate, err = targetedTemplate(compileResult.RawArmTemplate, p.options.Targets)
+		if err != nil {
+			return nil, fmt.Errorf("generating targeted template: %w", err)
+		}
+	}
+
 	deploymentScope, err := compileResult.Template.TargetScope()
 	if err != nil {
 		return nil, err
@@ -406,13 +436,18 @@ func (p *BicepProvider) plan(ctx context.Context) (*deploymentDetails, error) {
 }
 
 func (p *BicepProvider) deploymentScope(deploymentScope azure.DeploymentScope) (infra.Deployment, error) {
+	deploymentName := deploymentNameForEnv(p.env.Name(), p.clock)
+	if p.targeted() {
+		deploymentName = deploymentNameForEnv(p.env.Name()+"-targeted", p.clock)
+	}
+
 	if deploymentScope == azure.DeploymentScopeSubscription {
 		return infra.NewSubscriptionDeployment(
 			p.deploymentsService,
 			p.deploymentOperations,
 			p.env.GetLocation(),
 			p.env.GetSubscriptionId(),
-			deploymentNameForEnv(p.env.Name(), p.clock),
+			deploymentName,
 			p.portalUrlBase,
 		), nil
 	} else if deploymentScope == azure.DeploymentScopeResourceGroup {
@@ -421,7 +456,7 @@ func (p *BicepProvider) deploymentScope(deploymentScope azure.DeploymentScope) (
 			p.deploymentOperations,
 			p.env.GetSubscriptionId(),
 			p.env.Getenv(environment.ResourceGroupEnvVarName),
-			deploymentNameForEnv(p.env.Name(), p.clock),
+			deploymentName,
 			p.portalUrlBase,
 		), nil
 	}
@@ -591,7 +626,19 @@ func (p *BicepProvider) Deploy(ctx context.Context) (*DeployResult, error) {
 		logDS(parametersHashErr.Error())
 	}
 
-	if !p.ignoreDeploymentState && parametersHashErr == nil {
+	if p.options.FromPlan != "" {
+		if parametersHashErr != nil {
+			return nil, fmt.Errorf("hashing parameters: %w", parametersHashErr)
+		}
+
+		if err := p.verifySavedPlan(ctx, bicepDeploymentData, currentParamsHash); err != nil {
+			return nil, err
+		}
+	} else if p.targeted() {
+		logDS("Azure Deployment State is not used for targeted deployments.")
+	} else if p.useDeploymentStacks() {
+		logDS("Azure Deployment State is not supported when provisioning with deployment stacks.")
+	} else if !p.ignoreDeploymentState && parametersHashErr == nil {
 		deploymentState, err := p.deploymentState(ctx, bicepDeploymentData, currentParamsHash)
 		if err == nil {
 			deployment.Outputs = p.createOutputParameters(
@@ -651,20 +698,49 @@ func (p *BicepProvider) Deploy(ctx context.Context) (*DeployResult, error) {
 	if parametersHashErr == nil {
 		deploymentTags[azure.TagKeyAzdDeploymentStateParamHashName] = to.Ptr(currentParamsHash)
 	}
-	deployResult, err := p.deployModule(
-		ctx,
-		bicepDeploymentData.Target,
-		bicepDeploymentData.CompiledBicep.RawArmTemplate,
-		bicepDeploymentData.CompiledBicep.Parameters,
-		deploymentTags,
-	)
+	if p.targeted() {
+		// targeted deployments aren't tagged with the environment name, so the state of the environment keeps coming
+		// from the last full deployment.
+		deploymentTags = map[string]*string{
+			azure.TagKeyAzdProvisionTargets: to.Ptr(targetedDeploymentTag(p.options.Targets)),
+		}
+	}
+	var deployResult *armresources.DeploymentExtended
+	if p.useDeploymentStacks() {
+		deployResult, err = p.deployStack(
+			ctx,
+			bicepDeploymentData.Target,
+			bicepDeploymentData.CompiledBicep.RawArmTemplate,
+			bicepDeploymentData.CompiledBicep.Parameters,
+			deploymentTags,
+		)
+	} else {
+		deployResult, err = p.deployModule(
+			ctx,
+			bicepDeploymentData.Target,
+			bicepDeploymentData.CompiledBicep.RawArmTemplate,
+			bicepDeploymentData.CompiledBicep.Parameters,
+			deploymentTags,
+		)
+	}
 	if err != nil {
 		return nil, err
 	}
 
+	deploymentOutputs := deployResult.Properties.Outputs
+	if p.targeted() {
+		// the targeted template has no outputs, keep the ones from the last full deployment
+		deploymentOutputs = nil
+		if lastDeployment, err := p.latestDeploymentResult(ctx, bicepDeploymentData.Target); err == nil {
+			deploymentOutputs = lastDeployment.Properties.Outputs
+		} else {
+			log.Printf("no full deployment found to read outputs from: %v", err)
+		}
+	}
+
 	deployment.Outputs = p.createOutputParameters(
 		bicepDeploymentData.CompiledBicep.Template.Outputs,
-		azapi.CreateDeploymentOutput(deployResult.Properties.Outputs),
+		azapi.CreateDeploymentOutput(deploymentOutputs),
 	)
 
 	return &DeployResult{
@@ -681,6 +757,33 @@ func (p *BicepProvider) Preview(ctx context.Context) (*DeployPreviewResult, erro
 
 	p.console.ShowSpinner(ctx, "Generating infrastructure preview", input.Step)
 
+	preview, err := p.whatIf(ctx, bicepDeploymentData)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.options.PlanOutputPath != "" {
+		paramsHash, err := parametersHash(
+			bicepDeploymentData.CompiledBicep.Template.Parameters, bicepDeploymentData.CompiledBicep.Parameters)
+		if err != nil {
+			return nil, fmt.Errorf("hashing parameters: %w", err)
+		}
+
+		if err := p.savePlan(bicepDeploymentData, paramsHash, preview.Properties.Changes); err != nil {
+			return nil, err
+		}
+	}
+
+	return &DeployPreviewResult{
+		Preview: preview,
+	}, nil
+}
+
+// whatIf runs a what-if operation for the deployment and returns the changes it would apply.
+func (p *BicepProvider) whatIf(
+	ctx context.Context,
+	bicepDeploymentData *deploymentDetails,
+) (*DeploymentPreview, error) {
 	targetScope := bicepDeploymentData.Target
 	deployPreviewResult, err := targetScope.DeployPreview(
 		ctx,
@@ -728,12 +831,10 @@ func (p *BicepProvider) Preview(ctx context.Context) (*DeployPreviewResult, erro
 		})
 	}
 
-	return &DeployPreviewResult{
-		Preview: &DeploymentPreview{
-			Status: *deployPreviewResult.Status,
-			Properties: &DeploymentPreviewProperties{
-				Changes: changes,
-			},
+	return &DeploymentPreview{
+		Status: *deployPreviewResult.Status,
+		Properties: &DeploymentPreviewProperties{
+			Changes: changes,
 		},
 	}, nil
 }
@@ -825,12 +926,21 @@ func (p *BicepProvider) Destroy(ctx context.Context, options DestroyOptions) (*D
 	}
 
 	// TODO: Report progress, "Fetching resource groups"
-	deployments, err := p.findCompletedDeployments(ctx, p.env.Name(), scope, "")
-	if err != nil {
-		return nil, err
+	var deployments []*armresources.DeploymentExtended
+	if p.useDeploymentStacks() {
+		stackDeployment, err := p.stackDeployment(ctx, scope)
+		if err != nil {
+			return nil, err
+		}
+		deployments = []*armresources.DeploymentExtended{stackDeployment}
+	} else {
+		deployments, err = p.findCompletedDeployments(ctx, p.env.Name(), scope, "")
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	rgsFromDeployment := resourceGroupsToDelete(deployments[0])
+	rgsFromDeployment := resourceGroupsToDelete(deployments[0], p.useDeploymentStacks())
 
 	// TODO: Report progress, "Fetching resources"
 	groupedResources, err := p.getAllResourcesToDelete(ctx, rgsFromDeployment)
@@ -873,7 +983,11 @@ func (p *BicepProvider) Destroy(ctx context.Context, options DestroyOptions) (*D
 		return nil, fmt.Errorf("getting cognitive accounts to purge: %w", err)
 	}
 
-	if err := p.destroyResourceGroups(ctx, options, groupedResources, len(allResources)); err != nil {
+	if p.useDeploymentStacks() {
+		if err := p.destroyDeploymentStack(ctx, options, scope, groupedResources, len(allResources)); err != nil {
+			return nil, fmt.Errorf("deleting deployment stack: %w", err)
+		}
+	} else if err := p.destroyResourceGroups(ctx, options, groupedResources, len(allResources)); err != nil {
 		return nil, fmt.Errorf("deleting resource groups: %w", err)
 	}
 
@@ -946,6 +1060,11 @@ func (p *BicepProvider) Destroy(ctx context.Context, options DestroyOptions) (*D
 		)
 	}
 
+	// The deployment stack and its history were removed together with the resources, there is no provision state to void.
+	if p.useDeploymentStacks() {
+		return destroyResult, nil
+	}
+
 	var emptyTemplate json.RawMessage
 	if targetScope == azure.DeploymentScopeSubscription {
 		emptyTemplate = []byte(cEmptySubDeployTemplate)
@@ -1066,15 +1185,17 @@ func getDeploymentOptions(ctx context.Context, deployments []*armresources.Deplo
 }
 
 // resourceGroupsToDelete collects the resource groups from an existing deployment which should be removed as part of a
-// destroy operation.
-func resourceGroupsToDelete(deployment *armresources.DeploymentExtended) []string {
+// destroy operation. fromStack is set when the deployment represents a deployment stack.
+func resourceGroupsToDelete(deployment *armresources.DeploymentExtended, fromStack bool) []string {
 	// NOTE: it's possible for a deployment to list a resource group more than once. We're only interested in the
 	// unique set.
 	resourceGroups := map[string]struct{}{}
 
-	if *deployment.Properties.ProvisioningState == armresources.ProvisioningStateSucceeded {
+	if *deployment.Properties.ProvisioningState == armresources.ProvisioningStateSucceeded || fromStack {
 		// For a successful deployment, we can use the output resources property to see the resource groups that were
-		// provisioned from this.
+		// provisioned from this. Deployment stacks report the resources they manage as output resources whatever their
+		// provisioning state is, so they're used for failed stacks too. The output resources of other failed
+		// deployments may only be partial.
 		for _, resourceId := range deployment.Properties.OutputResources {
 			if resourceId != nil && resourceId.ID != nil {
 				resId, err := arm.ParseResourceID(*resourceId.ID)
@@ -2162,6 +2283,7 @@ func NewBicepProvider(
 	azCli azcli.AzCli,
 	deploymentsService azapi.Deployments,
 	deploymentOperations azapi.DeploymentOperations,
+	deploymentStacks azapi.DeploymentStacks,
 	envManager environment.Manager,
 	env *environment.Environment,
 	console input.Console,
@@ -2180,6 +2302,7 @@ func NewBicepProvider(
 		azCli:                azCli,
 		deploymentsService:   deploymentsService,
 		deploymentOperations: deploymentOperations,
+		deploymentStacks:     deploymentStacks,
 		prompters:            prompters,
 		curPrincipal:         curPrincipal,
 		alphaFeatureManager:  alphaFeatureManager,
@@ -340,11 +340,14 @@ func TestIsValueAssignableToParameterType(t *testing.T) {
 }
 
 func createBicepProvider(t *testing.T, mockContext *mocks.MockContext) *BicepProvider {
-	projectDir := "../../../../test/functional/testdata/samples/webapp"
-	options := Options{
+	return createBicepProviderWithOptions(t, mockContext, Options{
 		Path:   "infra",
 		Module: "main",
-	}
+	})
+}
+
+func createBicepProviderWithOptions(t *testing.T, mockContext *mocks.MockContext, options Options) *BicepProvider {
+	projectDir := "../../../../test/functional/testdata/samples/webapp"
 
 	env := environment.NewWithValues("test-env", map[string]string{
 		environment.LocationEnvVarName:       "westus2",
@@ -381,6 +384,7 @@ func createBicepProvider(t *testing.T, mockContext *mocks.MockContext) *BicepPro
 		azCli,
 		depService,
 		depOpService,
+		mockazcli.NewDeploymentStacksServiceFromMockContext(mockContext),
 		envManager,
 		env,
 		mockContext.Console,
@@ -737,7 +741,23 @@ func TestResourceGroupsFromDeployment(t *testing.T) {
 		err = json.Unmarshal(f, &deployment)
 		require.NoError(t, err)
 
-		require.Equal(t, []string{"matell-2508-rg"}, resourceGroupsToDelete(&deployment))
+		require.Equal(t, []string{"matell-2508-rg"}, resourceGroupsToDelete(&deployment, false))
+	})
+
+	t.Run("partial output resources of failed deployments ignored", func(t *testing.T) {
+		var deployment armresources.DeploymentExtended
+
+		f, err := os.ReadFile("testdata/failed-subscription-deployment.json")
+		require.NoError(t, err)
+
+		err = json.Unmarshal(f, &deployment)
+		require.NoError(t, err)
+
+		deployment.Properties.OutputResources = []*armresources.ResourceReference{
+			{ID: convert.RefOf("/subscriptions/sub-id/resourceGroups/partial-rg")},
+		}
+
+		require.Equal(t, []string{"matell-2508-rg"}, resourceGroupsToDelete(&deployment, false))
 	})
 
 	t.Run("duplicate resource groups ignored", func(t *testing.T) {
@@ -770,7 +790,7 @@ func TestResourceGroupsFromDeployment(t *testing.T) {
 			},
 		}
 
-		groups := resourceGroupsToDelete(&mockDeployment)
+		groups := resourceGroupsToDelete(&mockDeployment, false)
 
 		sort.Strings(groups)
 		require.Equal(t, []string{"groupA", "groupB", "groupC"}, groups)
@@ -928,6 +948,7 @@ func TestUserDefinedTypes(t *testing.T) {
 		nil,
 		nil,
 		nil,
+		nil,
 		&mockenv.MockEnvManager{},
 		env,
 		mockContext.Console,
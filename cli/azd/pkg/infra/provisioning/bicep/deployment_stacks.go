@@ -0,0 +1,287 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bicep
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra"
+	. "github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+)
+
+var DeploymentStacksFeature = alpha.MustFeatureKey("deployment.stacks")
+
+var ErrDeploymentStacksNotEnabled = fmt.Errorf(
+	"deployment stacks are currently under alpha support and need to be explicitly enabled."+
+		" Run `%s` to enable this feature.", alpha.GetEnableCommand(DeploymentStacksFeature),
+)
+
+// cDeploymentStackNameLengthMax is the maximum length of the name of a deployment stack in ARM.
+const cDeploymentStackNameLengthMax = 90
+
+// deploymentStackNameForEnv returns the name of the deployment stack that manages the resources of an environment.
+// Unlike deployments, the stack name is stable across provisions so that the stack can track resources over time.
+func deploymentStackNameForEnv(envName string) string {
+	name := fmt.Sprintf("azd-stack-%s", envName)
+	if len(name) <= cDeploymentStackNameLengthMax {
+		return name
+	}
+
+	return name[:cDeploymentStackNameLengthMax]
+}
+
+// useDeploymentStacks returns true when the project opted into provisioning with deployment stacks.
+func (p *BicepProvider) useDeploymentStacks() bool {
+	return p.options.DeploymentStacks != nil
+}
+
+// deploymentStackOptions converts the `infra.deploymentStacks` configuration into the settings applied to the stack.
+func deploymentStackOptions(options *DeploymentStacksOptions) (azapi.DeploymentStackOptions, error) {
+	stackOptions := azapi.DeploymentStackOptions{
+		ActionOnUnmanage: azapi.DeploymentStackActionOnUnmanageSettings{
+			Resources:      azapi.DeploymentStackActionDetach,
+			ResourceGroups: azapi.DeploymentStackActionDetach,
+		},
+		DenySettings: azapi.DeploymentStackDenySettings{
+			Mode: azapi.DeploymentStackDenyNone,
+		},
+	}
+
+	if options == nil {
+		return stackOptions, nil
+	}
+
+	if options.ActionOnUnmanage != nil {
+		resources, err := parseActionOnUnmanage(options.ActionOnUnmanage.Resources)
+		if err != nil {
+			return stackOptions, fmt.Errorf("invalid value for actionOnUnmanage.resources: %w", err)
+		}
+		resourceGroups, err := parseActionOnUnmanage(options.ActionOnUnmanage.ResourceGroups)
+		if err != nil {
+			return stackOptions, fmt.Errorf("invalid value for actionOnUnmanage.resourceGroups: %w", err)
+		}
+
+		if resources != "" {
+			stackOptions.ActionOnUnmanage.Resources = resources
+		}
+		if resourceGroups != "" {
+			stackOptions.ActionOnUnmanage.ResourceGroups = resourceGroups
+		}
+	}
+
+	if options.DenySettings != nil {
+		switch mode := azapi.DeploymentStackDenySettingsMode(options.DenySettings.Mode); mode {
+		case "":
+		case azapi.DeploymentStackDenyNone, azapi.DeploymentStackDenyDelete, azapi.DeploymentStackDenyWriteAndDelete:
+			stackOptions.DenySettings.Mode = mode
+		default:
+			return stackOptions, fmt.Errorf(
+				"invalid value for denySettings.mode: '%s'. Allowed values are '%s', '%s' and '%s'",
+				mode,
+				azapi.DeploymentStackDenyNone,
+				azapi.DeploymentStackDenyDelete,
+				azapi.DeploymentStackDenyWriteAndDelete,
+			)
+		}
+
+		stackOptions.DenySettings.ExcludedActions = options.DenySettings.ExcludedActions
+		stackOptions.DenySettings.ExcludedPrincipals = options.DenySettings.ExcludedPrincipals
+		stackOptions.DenySettings.ApplyToChildScopes = options.DenySettings.ApplyToChildScopes
+	}
+
+	return stackOptions, nil
+}
+
+func parseActionOnUnmanage(value string) (azapi.DeploymentStackActionOnUnmanage, error) {
+	switch action := azapi.DeploymentStackActionOnUnmanage(value); action {
+	case "", azapi.DeploymentStackActionDelete, azapi.DeploymentStackActionDetach:
+		return action, nil
+	default:
+		return "", fmt.Errorf(
+			"'%s'. Allowed values are '%s' and '%s'",
+			value,
+			azapi.DeploymentStackActionDelete,
+			azapi.DeploymentStackActionDetach,
+		)
+	}
+}
+
+// resourceGroupScoped is implemented by scopes and deployments which target a single resource group.
+type resourceGroupScoped interface {
+	ResourceGroupName() string
+}
+
+// deployStack creates or updates the deployment stack for the current environment within the scope of the target.
+// The result is returned as a deployment so callers can handle outputs the same way as a regular deployment.
+func (p *BicepProvider) deployStack(
+	ctx context.Context,
+	target infra.Scope,
+	armTemplate azure.RawArmTemplate,
+	armParameters azure.ArmParameters,
+	tags map[string]*string,
+) (*armresources.DeploymentExtended, error) {
+	stackOptions, err := deploymentStackOptions(p.options.DeploymentStacks)
+	if err != nil {
+		return nil, err
+	}
+
+	stackName := deploymentStackNameForEnv(p.env.Name())
+
+	var stack *azapi.DeploymentStack
+	if rgScope, ok := target.(resourceGroupScoped); ok {
+		stack, err = p.deploymentStacks.DeployStackToResourceGroup(
+			ctx,
+			target.SubscriptionId(),
+			rgScope.ResourceGroupName(),
+			stackName,
+			armTemplate,
+			armParameters,
+			tags,
+			stackOptions,
+		)
+	} else {
+		stack, err = p.deploymentStacks.DeployStackToSubscription(
+			ctx,
+			target.SubscriptionId(),
+			p.env.GetLocation(),
+			stackName,
+			armTemplate,
+			armParameters,
+			tags,
+			stackOptions,
+		)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return deploymentFromStack(stack), nil
+}
+
+// stackDeployment fetches the deployment stack for the current environment and returns it as a deployment.
+func (p *BicepProvider) stackDeployment(
+	ctx context.Context,
+	scope infra.Scope,
+) (*armresources.DeploymentExtended, error) {
+	stackName := deploymentStackNameForEnv(p.env.Name())
+
+	var stack *azapi.DeploymentStack
+	var err error
+	if rgScope, ok := scope.(resourceGroupScoped); ok {
+		stack, err = p.deploymentStacks.GetResourceGroupStack(
+			ctx, scope.SubscriptionId(), rgScope.ResourceGroupName(), stackName)
+	} else {
+		stack, err = p.deploymentStacks.GetSubscriptionStack(ctx, scope.SubscriptionId(), stackName)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("'%s': %w", stackName, err)
+	}
+
+	return deploymentFromStack(stack), nil
+}
+
+// deleteStack deletes the deployment stack for the current environment, together with every resource and resource group
+// it manages.
+func (p *BicepProvider) deleteStack(ctx context.Context, scope infra.Scope) error {
+	stackName := deploymentStackNameForEnv(p.env.Name())
+	deleteAll := azapi.DeploymentStackActionOnUnmanageSettings{
+		Resources:      azapi.DeploymentStackActionDelete,
+		ResourceGroups: azapi.DeploymentStackActionDelete,
+	}
+
+	if rgScope, ok := scope.(resourceGroupScoped); ok {
+		return p.deploymentStacks.DeleteResourceGroupStack(
+			ctx, scope.SubscriptionId(), rgScope.ResourceGroupName(), stackName, deleteAll)
+	}
+
+	return p.deploymentStacks.DeleteSubscriptionStack(ctx, scope.SubscriptionId(), stackName, deleteAll)
+}
+
+// destroyDeploymentStack confirms the deletion with the user and deletes the deployment stack along with the resources
+// it manages.
+func (p *BicepProvider) destroyDeploymentStack(
+	ctx context.Context,
+	options DestroyOptions,
+	scope infra.Scope,
+	groupedResources map[string][]azcli.AzCliResource,
+	resourceCount int,
+) error {
+	if !options.Force() {
+		p.console.MessageUxItem(ctx, &ux.MultilineMessage{
+			Lines: p.generateResourceGroupsToDelete(groupedResources)},
+		)
+		confirmDestroy, err := p.console.Confirm(ctx, input.ConsoleOptions{
+			Message: fmt.Sprintf(
+				"Total resources to %s: %d, are you sure you want to continue?",
+				output.WithErrorFormat("delete"),
+				resourceCount,
+			),
+			DefaultValue: false,
+		})
+
+		if err != nil {
+			return fmt.Errorf("prompting for delete confirmation: %w", err)
+		}
+
+		if !confirmDestroy {
+			return errors.New("user denied delete confirmation")
+		}
+	}
+
+	p.console.Message(ctx, output.WithGrayFormat("Deleting your resources can take some time.\n"))
+
+	message := fmt.Sprintf("Deleting deployment stack: %s",
+		output.WithHighLightFormat(deploymentStackNameForEnv(p.env.Name())),
+	)
+	p.console.ShowSpinner(ctx, message, input.Step)
+	err := p.deleteStack(ctx, scope)
+	p.console.StopSpinner(ctx, message, input.GetStepResultFormat(err))
+	if err != nil {
+		return err
+	}
+
+	// empty line at the end of the stack deletion
+	p.console.Message(ctx, "")
+	return nil
+}
+
+// deploymentFromStack maps a deployment stack to the deployment shape used across the provider. Every resource managed
+// by the stack is reported as an output resource, which includes resources the template no longer declares but which
+// the stack still tracks.
+func deploymentFromStack(stack *azapi.DeploymentStack) *armresources.DeploymentExtended {
+	provisioningState := armresources.ProvisioningStateFailed
+	if strings.EqualFold(stack.Properties.ProvisioningState, string(armresources.ProvisioningStateSucceeded)) {
+		provisioningState = armresources.ProvisioningStateSucceeded
+	}
+
+	outputResources := make([]*armresources.ResourceReference, 0, len(stack.Properties.Resources))
+	for _, resource := range stack.Properties.Resources {
+		outputResources = append(outputResources, &armresources.ResourceReference{
+			ID: to.Ptr(resource.Id),
+		})
+	}
+
+	return &armresources.DeploymentExtended{
+		ID:   to.Ptr(stack.Id),
+		Name: to.Ptr(stack.Name),
+		Tags: stack.Tags,
+		Properties: &armresources.DeploymentPropertiesExtended{
+			ProvisioningState: to.Ptr(provisioningState),
+			Outputs:           stack.Properties.Outputs,
+			OutputResources:   outputResources,
+		},
+	}
+}
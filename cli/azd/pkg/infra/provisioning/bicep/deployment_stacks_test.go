@@ -0,0 +1,188 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bicep
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	. "github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeploymentStackOptions(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		options, err := deploymentStackOptions(&DeploymentStacksOptions{})
+		require.NoError(t, err)
+		require.Equal(t, azapi.DeploymentStackActionDetach, options.ActionOnUnmanage.Resources)
+		require.Equal(t, azapi.DeploymentStackActionDetach, options.ActionOnUnmanage.ResourceGroups)
+		require.Equal(t, azapi.DeploymentStackDenyNone, options.DenySettings.Mode)
+	})
+
+	t.Run("Configured", func(t *testing.T) {
+		options, err := deploymentStackOptions(&DeploymentStacksOptions{
+			ActionOnUnmanage: &ActionOnUnmanageOptions{
+				Resources: "delete",
+			},
+			DenySettings: &DenySettingsOptions{
+				Mode:               "denyWriteAndDelete",
+				ExcludedPrincipals: []string{"PRINCIPAL_ID"},
+				ApplyToChildScopes: true,
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, azapi.DeploymentStackActionDelete, options.ActionOnUnmanage.Resources)
+		require.Equal(t, azapi.DeploymentStackActionDetach, options.ActionOnUnmanage.ResourceGroups)
+		require.Equal(t, azapi.DeploymentStackDenyWriteAndDelete, options.DenySettings.Mode)
+		require.Equal(t, []string{"PRINCIPAL_ID"}, options.DenySettings.ExcludedPrincipals)
+		require.True(t, options.DenySettings.ApplyToChildScopes)
+	})
+
+	t.Run("InvalidAction", func(t *testing.T) {
+		_, err := deploymentStackOptions(&DeploymentStacksOptions{
+			ActionOnUnmanage: &ActionOnUnmanageOptions{
+				ResourceGroups: "purge",
+			},
+		})
+		require.ErrorContains(t, err, "actionOnUnmanage.resourceGroups")
+	})
+
+	t.Run("InvalidDenyMode", func(t *testing.T) {
+		_, err := deploymentStackOptions(&DeploymentStacksOptions{
+			DenySettings: &DenySettingsOptions{
+				Mode: "denyAll",
+			},
+		})
+		require.ErrorContains(t, err, "denySettings.mode")
+	})
+}
+
+func TestDeploymentStackNameForEnv(t *testing.T) {
+	require.Equal(t, "azd-stack-dev", deploymentStackNameForEnv("dev"))
+	require.Len(t, deploymentStackNameForEnv(strings.Repeat("a", 100)), cDeploymentStackNameLengthMax)
+}
+
+func TestDeploymentFromStack(t *testing.T) {
+	tests := map[string]struct {
+		provisioningState string
+		expectedState     armresources.ProvisioningState
+	}{
+		"Succeeded": {provisioningState: "succeeded", expectedState: armresources.ProvisioningStateSucceeded},
+		// The resources a failed stack manages are still deleted by azd down
+		"Failed":   {provisioningState: "failed", expectedState: armresources.ProvisioningStateFailed},
+		"Canceled": {provisioningState: "canceled", expectedState: armresources.ProvisioningStateFailed},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			deployment := deploymentFromStack(&azapi.DeploymentStack{
+				Id:   "STACK_ID",
+				Name: "azd-stack-test-env",
+				Properties: azapi.DeploymentStackProperties{
+					ProvisioningState: test.provisioningState,
+					Resources: []azapi.DeploymentStackResourceReference{
+						{Id: "/subscriptions/SUBSCRIPTION_ID/resourceGroups/RESOURCE_GROUP"},
+						{Id: "/subscriptions/SUBSCRIPTION_ID/resourceGroups/OUT_OF_BAND_GROUP"},
+					},
+				},
+			})
+
+			require.Equal(t, test.expectedState, *deployment.Properties.ProvisioningState)
+			require.Len(t, deployment.Properties.OutputResources, 2)
+			require.ElementsMatch(
+				t, []string{"RESOURCE_GROUP", "OUT_OF_BAND_GROUP"}, resourceGroupsToDelete(deployment, true))
+		})
+	}
+}
+
+func TestBicepDestroyWithDeploymentStacks(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	err := mockContext.Config.Set("alpha.deployment.stacks", "on")
+	require.NoError(t, err)
+
+	prepareBicepMocks(mockContext)
+	prepareDestroyMocks(mockContext)
+
+	stack := azapi.DeploymentStack{
+		Id:   "/subscriptions/SUBSCRIPTION_ID/providers/Microsoft.Resources/deploymentStacks/azd-stack-test-env",
+		Name: "azd-stack-test-env",
+		Properties: azapi.DeploymentStackProperties{
+			ProvisioningState: "succeeded",
+			Outputs: map[string]interface{}{
+				"WEBSITE_URL": map[string]interface{}{"value": "http://myapp.azurewebsites.net", "type": "string"},
+			},
+			Resources: []azapi.DeploymentStackResourceReference{
+				{Id: "/subscriptions/SUBSCRIPTION_ID/resourceGroups/RESOURCE_GROUP"},
+			},
+		},
+	}
+	stackBytes, err := json.Marshal(stack)
+	require.NoError(t, err)
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.HasSuffix(
+			request.URL.Path,
+			"/subscriptions/SUBSCRIPTION_ID/providers/Microsoft.Resources/deploymentStacks/azd-stack-test-env",
+		)
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return &http.Response{
+			Request:    request,
+			Header:     http.Header{},
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBuffer(stackBytes)),
+		}, nil
+	})
+
+	var deleteQuery string
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodDelete && strings.HasSuffix(
+			request.URL.Path,
+			"/subscriptions/SUBSCRIPTION_ID/providers/Microsoft.Resources/deploymentStacks/azd-stack-test-env",
+		)
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		deleteQuery = request.URL.RawQuery
+		return httpRespondFn(request)
+	})
+
+	infraProvider := createBicepProviderWithOptions(t, mockContext, Options{
+		Path:             "infra",
+		Module:           "main",
+		DeploymentStacks: &DeploymentStacksOptions{},
+	})
+
+	destroyResult, err := infraProvider.Destroy(*mockContext.Context, NewDestroyOptions(true, true))
+	require.NoError(t, err)
+	require.NotNil(t, destroyResult)
+	require.Contains(t, destroyResult.InvalidatedEnvKeys, "WEBSITE_URL")
+
+	require.Contains(t, deleteQuery, "unmanageAction.Resources=delete")
+	require.Contains(t, deleteQuery, "unmanageAction.ResourceGroups=delete")
+
+	consoleOutput := mockContext.Console.Output()
+	require.Len(t, consoleOutput, 2)
+	require.Contains(t, consoleOutput[0], "Deleting your resources can take some time")
+}
+
+func TestDeploymentStacksRequireAlphaFeature(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	prepareBicepMocks(mockContext)
+
+	provider := createBicepProviderWithOptions(t, mockContext, Options{
+		Path:   "infra",
+		Module: "main",
+	})
+
+	err := provider.Initialize(*mockContext.Context, "../../../../test/functional/testdata/samples/webapp", Options{
+		DeploymentStacks: &DeploymentStacksOptions{},
+	})
+	require.ErrorIs(t, err, ErrDeploymentStacksNotEnabled)
+}
@@ -0,0 +1,168 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bicep
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	. "github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+)
+
+// savedPlanVersion is bumped whenever the shape of the saved plan changes in a way older versions can't read.
+const savedPlanVersion = 1
+
+var ErrSavedPlanStale = errors.New("the saved plan is out of date")
+
+// savedPlan is the file written by `azd provision --preview --out`. It records the what-if result together with the
+// hashes of the template and parameters it was computed from, so a later `azd provision --from-plan` can verify that
+// it applies exactly what was reviewed.
+type savedPlan struct {
+	Version        int               `json:"version"`
+	Provider       string            `json:"provider"`
+	Environment    string            `json:"environment"`
+	CreatedAt      time.Time         `json:"createdAt"`
+	TemplateHash   string            `json:"templateHash"`
+	ParametersHash string            `json:"parametersHash"`
+	Changes        []savedPlanChange `json:"changes"`
+}
+
+type savedPlanChange struct {
+	ChangeType   ChangeType `json:"changeType"`
+	ResourceId   string     `json:"resourceId"`
+	ResourceType string     `json:"resourceType"`
+	Name         string     `json:"name"`
+}
+
+// templateHash returns a hash of the compiled ARM template.
+func templateHash(rawTemplate []byte) string {
+	return fmt.Sprintf("%x", sha256.Sum256(rawTemplate))
+}
+
+func newSavedPlanChanges(changes []*DeploymentPreviewChange) []savedPlanChange {
+	result := make([]savedPlanChange, 0, len(changes))
+	for _, change := range changes {
+		// resources without changes don't affect what gets applied
+		if change.ChangeType == ChangeTypeNoChange || change.ChangeType == ChangeTypeIgnore {
+			continue
+		}
+
+		result = append(result, savedPlanChange{
+			ChangeType:   change.ChangeType,
+			ResourceId:   change.ResourceId.Id,
+			ResourceType: change.ResourceType,
+			Name:         change.Name,
+		})
+	}
+
+	slices.SortFunc(result, func(a, b savedPlanChange) int {
+		return strings.Compare(strings.ToLower(a.ResourceId), strings.ToLower(b.ResourceId))
+	})
+
+	return result
+}
+
+// savePlan writes the preview of a deployment to the plan output path.
+func (p *BicepProvider) savePlan(
+	deploymentData *deploymentDetails,
+	paramsHash string,
+	changes []*DeploymentPreviewChange,
+) error {
+	plan := savedPlan{
+		Version:        savedPlanVersion,
+		Provider:       string(Bicep),
+		Environment:    p.env.Name(),
+		CreatedAt:      p.clock.Now().UTC(),
+		TemplateHash:   templateHash(deploymentData.CompiledBicep.RawArmTemplate),
+		ParametersHash: paramsHash,
+		Changes:        newSavedPlanChanges(changes),
+	}
+
+	planBytes, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshalling plan: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(p.options.PlanOutputPath), osutil.PermissionDirectory); err != nil {
+		return fmt.Errorf("creating plan directory: %w", err)
+	}
+
+	if err := os.WriteFile(p.options.PlanOutputPath, planBytes, osutil.PermissionFile); err != nil {
+		return fmt.Errorf("writing plan: %w", err)
+	}
+
+	log.Printf("saved provisioning plan to %s", p.options.PlanOutputPath)
+	return nil
+}
+
+// loadPlan reads the plan referenced by the `FromPlan` option.
+func (p *BicepProvider) loadPlan() (*savedPlan, error) {
+	planBytes, err := os.ReadFile(p.options.FromPlan)
+	if err != nil {
+		return nil, fmt.Errorf("reading plan: %w", err)
+	}
+
+	var plan savedPlan
+	if err := json.Unmarshal(planBytes, &plan); err != nil {
+		return nil, fmt.Errorf("'%s' is not a valid plan file: %w", p.options.FromPlan, err)
+	}
+
+	if plan.Provider != string(Bicep) {
+		return nil, fmt.Errorf("'%s' was not created by the bicep provider", p.options.FromPlan)
+	}
+
+	if plan.Version != savedPlanVersion {
+		return nil, fmt.Errorf("unsupported plan version %d", plan.Version)
+	}
+
+	return &plan, nil
+}
+
+// verifySavedPlan ensures the deployment about to be applied matches the saved plan. The template and parameters must
+// hash to the same values and a new what-if must report the same changes, otherwise the state moved since the plan
+// was reviewed.
+func (p *BicepProvider) verifySavedPlan(
+	ctx context.Context,
+	deploymentData *deploymentDetails,
+	paramsHash string,
+) error {
+	plan, err := p.loadPlan()
+	if err != nil {
+		return err
+	}
+
+	if plan.Environment != p.env.Name() {
+		return fmt.Errorf(
+			"%w: the plan was created for environment '%s', not '%s'", ErrSavedPlanStale, plan.Environment, p.env.Name())
+	}
+
+	if plan.TemplateHash != templateHash(deploymentData.CompiledBicep.RawArmTemplate) {
+		return fmt.Errorf("%w: the template changed since the plan was created", ErrSavedPlanStale)
+	}
+
+	if plan.ParametersHash != paramsHash {
+		return fmt.Errorf("%w: the parameters changed since the plan was created", ErrSavedPlanStale)
+	}
+
+	message := "Verifying saved plan"
+	p.console.ShowSpinner(ctx, message, input.Step)
+	preview, err := p.whatIf(ctx, deploymentData)
+	if err == nil && !slices.Equal(plan.Changes, newSavedPlanChanges(preview.Properties.Changes)) {
+		err = fmt.Errorf("%w: the deployed resources changed since the plan was created", ErrSavedPlanStale)
+	}
+	p.console.StopSpinner(ctx, message, input.GetStepResultFormat(err))
+
+	return err
+}
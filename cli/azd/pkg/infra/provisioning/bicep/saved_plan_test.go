@@ -0,0 +1,168 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bicep
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	. "github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockinput"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBicepSavedPlan(t *testing.T) {
+	planPath := filepath.Join(t.TempDir(), "plan.json")
+
+	t.Run("PreviewSavesPlan", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		prepareBicepMocks(mockContext)
+		prepareWhatIfMocks(mockContext, "rg-test-env")
+
+		infraProvider := createBicepProviderWithOptions(t, mockContext, Options{
+			Path:           "infra",
+			Module:         "main",
+			PlanOutputPath: planPath,
+		})
+
+		result, err := infraProvider.Preview(*mockContext.Context)
+		require.NoError(t, err)
+		require.Len(t, result.Preview.Properties.Changes, 2)
+
+		planBytes, err := os.ReadFile(planPath)
+		require.NoError(t, err)
+
+		var plan savedPlan
+		require.NoError(t, json.Unmarshal(planBytes, &plan))
+		require.Equal(t, "test-env", plan.Environment)
+		require.Equal(t, string(Bicep), plan.Provider)
+		require.NotEmpty(t, plan.TemplateHash)
+		require.NotEmpty(t, plan.ParametersHash)
+		require.Equal(t, []savedPlanChange{
+			{
+				ChangeType:   ChangeTypeCreate,
+				ResourceId:   "/subscriptions/SUBSCRIPTION_ID/resourceGroups/rg-test-env",
+				ResourceType: "Microsoft.Resources/resourceGroups",
+				Name:         "rg-test-env",
+			},
+		}, plan.Changes)
+	})
+
+	t.Run("VerifyUnchanged", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		prepareBicepMocks(mockContext)
+		prepareWhatIfMocks(mockContext, "rg-test-env")
+
+		infraProvider := createBicepProviderWithOptions(t, mockContext, Options{
+			Path:     "infra",
+			Module:   "main",
+			FromPlan: planPath,
+		})
+
+		deploymentData, paramsHash := planWithHash(t, mockContext, infraProvider)
+		require.NoError(t, infraProvider.verifySavedPlan(*mockContext.Context, deploymentData, paramsHash))
+
+		spinnerOps := mockContext.Console.SpinnerOps()
+		require.Equal(t, mockinput.SpinnerOp{
+			Op:      mockinput.SpinnerOpStop,
+			Message: "Verifying saved plan",
+			Format:  input.StepDone,
+		}, spinnerOps[len(spinnerOps)-1])
+	})
+
+	t.Run("ParametersChanged", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		prepareBicepMocks(mockContext)
+		prepareWhatIfMocks(mockContext, "rg-test-env")
+
+		infraProvider := createBicepProviderWithOptions(t, mockContext, Options{
+			Path:     "infra",
+			Module:   "main",
+			FromPlan: planPath,
+		})
+
+		deploymentData, _ := planWithHash(t, mockContext, infraProvider)
+		err := infraProvider.verifySavedPlan(*mockContext.Context, deploymentData, "OTHER_HASH")
+		require.ErrorIs(t, err, ErrSavedPlanStale)
+		require.ErrorContains(t, err, "parameters changed")
+	})
+
+	t.Run("StateChanged", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		prepareBicepMocks(mockContext)
+		prepareWhatIfMocks(mockContext, "rg-other")
+
+		infraProvider := createBicepProviderWithOptions(t, mockContext, Options{
+			Path:     "infra",
+			Module:   "main",
+			FromPlan: planPath,
+		})
+
+		deploymentData, paramsHash := planWithHash(t, mockContext, infraProvider)
+		err := infraProvider.verifySavedPlan(*mockContext.Context, deploymentData, paramsHash)
+		require.ErrorIs(t, err, ErrSavedPlanStale)
+		require.ErrorContains(t, err, "deployed resources changed")
+
+		spinnerOps := mockContext.Console.SpinnerOps()
+		require.Equal(t, mockinput.SpinnerOp{
+			Op:      mockinput.SpinnerOpStop,
+			Message: "Verifying saved plan",
+			Format:  input.StepFailed,
+		}, spinnerOps[len(spinnerOps)-1])
+	})
+}
+
+func planWithHash(
+	t *testing.T,
+	mockContext *mocks.MockContext,
+	infraProvider *BicepProvider,
+) (*deploymentDetails, string) {
+	deploymentData, err := infraProvider.plan(*mockContext.Context)
+	require.NoError(t, err)
+
+	paramsHash, err := parametersHash(
+		deploymentData.CompiledBicep.Template.Parameters, deploymentData.CompiledBicep.Parameters)
+	require.NoError(t, err)
+
+	return deploymentData, paramsHash
+}
+
+func prepareWhatIfMocks(mockContext *mocks.MockContext, resourceGroupName string) {
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPost && strings.HasSuffix(request.URL.Path, "/whatIf")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armresources.WhatIfOperationResult{
+			Status: to.Ptr("Succeeded"),
+			Properties: &armresources.WhatIfOperationProperties{
+				Changes: []*armresources.WhatIfChange{
+					{
+						ChangeType: to.Ptr(armresources.ChangeTypeCreate),
+						ResourceID: to.Ptr("/subscriptions/SUBSCRIPTION_ID/resourceGroups/" + resourceGroupName),
+						After: map[string]interface{}{
+							"type": "Microsoft.Resources/resourceGroups",
+							"name": resourceGroupName,
+						},
+					},
+					{
+						ChangeType: to.Ptr(armresources.ChangeTypeNoChange),
+						ResourceID: to.Ptr("/subscriptions/SUBSCRIPTION_ID/resourceGroups/rg-unchanged"),
+						After: map[string]interface{}{
+							"type": "Microsoft.Resources/resourceGroups",
+							"name": "rg-unchanged",
+						},
+					},
+				},
+			},
+		})
+	})
+}
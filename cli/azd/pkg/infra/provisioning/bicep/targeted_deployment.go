@@ -0,0 +1,289 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bicep
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+)
+
+var ErrTargetsWithDeploymentStacks = errors.New(
+	"targeted provisioning is not supported with deployment stacks, as the stack would stop managing the " +
+		"resources which are not targeted",
+)
+
+// targeted returns true when provisioning is limited to a set of modules or resources.
+func (p *BicepProvider) targeted() bool {
+	return len(p.options.Targets) > 0
+}
+
+// targetedDeploymentTag returns the value of the tag which records the targets of a partial deployment. Tag values
+// are limited to 256 characters.
+func targetedDeploymentTag(targets []string) string {
+	value := strings.Join(targets, ",")
+	if len(value) > 256 {
+		return value[:256]
+	}
+
+	return value
+}
+
+// templateResource is a resource, or module, declared in a compiled ARM template.
+type templateResource struct {
+	// symbolicName is the key of the resource in templates using symbolic names (languageVersion 2.0).
+	symbolicName string
+	// name is the value of the name property, which may be an ARM template expression.
+	name string
+	body map[string]any
+}
+
+// matches returns true when the resource is selected by the given target. Targets match either the symbolic name of a
+// resource or its literal name, which for modules is the name of the deployment created for the module.
+func (r *templateResource) matches(target string) bool {
+	if r.symbolicName != "" && strings.EqualFold(r.symbolicName, target) {
+		return true
+	}
+
+	return !isTemplateExpression(r.name) && strings.EqualFold(r.name, target)
+}
+
+// references returns true when a `dependsOn` entry refers to this resource.
+func (r *templateResource) references(dependency string) bool {
+	if r.symbolicName != "" {
+		return dependency == r.symbolicName
+	}
+
+	// dependencies are resource id expressions, e.g. `[resourceId('<type>', <name segments>)]`
+	nameArgs := strings.TrimSuffix(strings.TrimPrefix(r.name, "["), "]")
+	if !isTemplateExpression(r.name) {
+		nameArgs = "'" + strings.Join(strings.Split(r.name, "/"), "', '") + "'"
+	}
+
+	resourceType, _ := r.body["type"].(string)
+	return strings.Contains(dependency, fmt.Sprintf("'%s', %s)", resourceType, nameArgs))
+}
+
+// referencedBy returns true when an ARM template expression reads this resource in a way which only resolves when the
+// resource is declared in the template. With symbolic names, these are the reference(), resourceInfo() and list*()
+// functions of its symbolic name. Otherwise, these are reference() calls without an API version, by name or by
+// resource ID.
+func (r *templateResource) referencedBy(expression string) bool {
+	if r.symbolicName != "" {
+		return regexp.MustCompile(
+			`\b(reference|resourceInfo|list\w*)\('` + regexp.QuoteMeta(r.symbolicName) + `'`).MatchString(expression)
+	}
+
+	nameArgs := strings.TrimSuffix(strings.TrimPrefix(r.name, "["), "]")
+	if !isTemplateExpression(r.name) {
+		if strings.Contains(expression, fmt.Sprintf("reference('%s')", r.name)) {
+			return true
+		}
+		nameArgs = "'" + strings.Join(strings.Split(r.name, "/"), "', '") + "'"
+	}
+
+	resourceType, _ := r.body["type"].(string)
+	return strings.Contains(expression, fmt.Sprintf("reference(resourceId('%s', %s))", resourceType, nameArgs))
+}
+
+// displayName returns the name of the resource used in messages.
+func (r *templateResource) displayName() string {
+	if r.symbolicName != "" {
+		return r.symbolicName
+	}
+
+	return r.name
+}
+
+// existingDeclaration returns the declaration of the resource as an `existing` resource, which makes its properties
+// available to the other resources of the template without deploying it.
+func (r *templateResource) existingDeclaration() map[string]any {
+	declaration := map[string]any{"existing": true}
+	for _, property := range []string{
+		"type", "apiVersion", "name", "scope", "subscriptionId", "resourceGroup", "condition", "copy",
+	} {
+		if value, has := r.body[property]; has {
+			declaration[property] = value
+		}
+	}
+
+	return declaration
+}
+
+// expressions returns the ARM template expressions within a value, e.g. the body of a resource. The top level
+// `dependsOn` property is skipped when skipDependsOn is set.
+func expressions(value any, skipDependsOn bool) []string {
+	switch value := value.(type) {
+	case string:
+		if isTemplateExpression(value) {
+			return []string{value}
+		}
+	case []any:
+		var result []string
+		for _, item := range value {
+			result = append(result, expressions(item, false)...)
+		}
+		return result
+	case map[string]any:
+		var result []string
+		for key, item := range value {
+			if skipDependsOn && key == "dependsOn" {
+				continue
+			}
+			result = append(result, expressions(item, false)...)
+		}
+		return result
+	}
+
+	return nil
+}
+
+func isTemplateExpression(value string) bool {
+	return strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]") && !strings.HasPrefix(value, "[[")
+}
+
+// targetedTemplate generates a wrapper of a compiled template which only deploys the resources and modules selected by
+// targets. Dependencies on resources which are not deployed are dropped, as they already exist from a previous full
+// deployment, and outputs are removed since they may reference resources which are no longer part of the template.
+//
+// With symbolic names, resources which are not targeted but read by the targeted ones, e.g. with reference(), are
+// kept as `existing` resources. Without symbolic names, a targeted resource which reads a resource that isn't targeted
+// is rejected, as the reference can't resolve once that resource is removed from the template.
+func targetedTemplate(rawTemplate azure.RawArmTemplate, targets []string) (azure.RawArmTemplate, error) {
+	var template map[string]json.RawMessage
+	if err := json.Unmarshal(rawTemplate, &template); err != nil {
+		return nil, fmt.Errorf("parsing template: %w", err)
+	}
+
+	resources, symbolic, err := templateResources(template["resources"])
+	if err != nil {
+		return nil, err
+	}
+
+	var kept, removed []*templateResource
+	matchedTargets := map[string]bool{}
+	for _, resource := range resources {
+		selected := false
+		for _, target := range targets {
+			if resource.matches(target) {
+				matchedTargets[target] = true
+				selected = true
+			}
+		}
+
+		if selected {
+			kept = append(kept, resource)
+		} else {
+			removed = append(removed, resource)
+		}
+	}
+
+	for _, target := range targets {
+		if !matchedTargets[target] {
+			return nil, fmt.Errorf("target '%s' does not match any module or resource in the template", target)
+		}
+	}
+
+	// resources are read by the expressions of the kept resources, including the ones kept as existing resources
+	for found := true; found; {
+		found = false
+		for _, resource := range kept {
+			for _, expression := range expressions(resource.body, true) {
+				index := slices.IndexFunc(removed, func(r *templateResource) bool { return r.referencedBy(expression) })
+				if index < 0 {
+					continue
+				}
+
+				referenced := removed[index]
+				if !symbolic {
+					return nil, fmt.Errorf(
+						"'%s' references '%s', which is not targeted. Include '%s' in the targets to provision them "+
+							"together",
+						resource.displayName(), referenced.displayName(), referenced.displayName())
+				}
+
+				referenced.body = referenced.existingDeclaration()
+				kept = append(kept, referenced)
+				removed = slices.Delete(removed, index, index+1)
+				found = true
+			}
+		}
+	}
+
+	for _, resource := range kept {
+		dependsOn, has := resource.body["dependsOn"].([]any)
+		if !has {
+			continue
+		}
+
+		filtered := slices.DeleteFunc(slices.Clone(dependsOn), func(dependency any) bool {
+			value, ok := dependency.(string)
+			if !ok {
+				return false
+			}
+
+			return slices.ContainsFunc(removed, func(r *templateResource) bool { return r.references(value) })
+		})
+		resource.body["dependsOn"] = filtered
+	}
+
+	var resourcesJson []byte
+	if symbolic {
+		byName := make(map[string]map[string]any, len(kept))
+		for _, resource := range kept {
+			byName[resource.symbolicName] = resource.body
+		}
+		resourcesJson, err = json.Marshal(byName)
+	} else {
+		bodies := make([]map[string]any, 0, len(kept))
+		for _, resource := range kept {
+			bodies = append(bodies, resource.body)
+		}
+		resourcesJson, err = json.Marshal(bodies)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	template["resources"] = resourcesJson
+	template["outputs"] = json.RawMessage("{}")
+
+	return json.Marshal(template)
+}
+
+// templateResources reads the resources of a template, which are either an array or, for templates using symbolic
+// names, an object keyed by symbolic name.
+func templateResources(raw json.RawMessage) ([]*templateResource, bool, error) {
+	var resources []*templateResource
+
+	var list []map[string]any
+	if err := json.Unmarshal(raw, &list); err == nil {
+		for _, body := range list {
+			name, _ := body["name"].(string)
+			resources = append(resources, &templateResource{name: name, body: body})
+		}
+		return resources, false, nil
+	}
+
+	var symbolic map[string]map[string]any
+	if err := json.Unmarshal(raw, &symbolic); err != nil {
+		return nil, false, fmt.Errorf("parsing template resources: %w", err)
+	}
+
+	for symbolicName, body := range symbolic {
+		name, _ := body["name"].(string)
+		resources = append(resources, &templateResource{symbolicName: symbolicName, name: name, body: body})
+	}
+
+	slices.SortFunc(resources, func(a, b *templateResource) int {
+		return strings.Compare(a.symbolicName, b.symbolicName)
+	})
+
+	return resources, true, nil
+}
@@ -0,0 +1,206 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bicep
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	. "github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+const targetedArmJson = `{
+	"$schema": "https://schema.management.azure.com/schemas/2018-05-01/subscriptionDeploymentTemplate.json#",
+	"contentVersion": "1.0.0.0",
+	"parameters": {},
+	"resources": [
+		{
+			"type": "Microsoft.Resources/resourceGroups",
+			"name": "[format('rg-{0}', parameters('environmentName'))]"
+		},
+		{
+			"type": "Microsoft.Resources/deployments",
+			"name": "monitoring",
+			"dependsOn": [
+				"[subscriptionResourceId('Microsoft.Resources/resourceGroups', format('rg-{0}', parameters('environmentName')))]"
+			]
+		},
+		{
+			"type": "Microsoft.Resources/deployments",
+			"name": "web",
+			"dependsOn": [
+				"[subscriptionResourceId('Microsoft.Resources/resourceGroups', format('rg-{0}', parameters('environmentName')))]",
+				"[resourceId('Microsoft.Resources/deployments', 'monitoring')]"
+			]
+		}
+	],
+	"outputs": {
+		"WEBSITE_URL": { "type": "string", "value": "[reference('web').outputs.uri.value]" }
+	}
+}`
+
+const targetedSymbolicArmJson = `{
+	"$schema": "https://schema.management.azure.com/schemas/2018-05-01/subscriptionDeploymentTemplate.json#",
+	"languageVersion": "2.0",
+	"contentVersion": "1.0.0.0",
+	"resources": {
+		"rg": { "type": "Microsoft.Resources/resourceGroups", "name": "rg-test" },
+		"monitoring": { "type": "Microsoft.Resources/deployments", "name": "monitoring", "dependsOn": ["rg"] },
+		"web": { "type": "Microsoft.Resources/deployments", "name": "web-app", "dependsOn": ["rg", "monitoring"] }
+	},
+	"outputs": {}
+}`
+
+func TestTargetedTemplate(t *testing.T) {
+	t.Run("Resources", func(t *testing.T) {
+		raw, err := targetedTemplate([]byte(targetedArmJson), []string{"WEB"})
+		require.NoError(t, err)
+
+		var template struct {
+			Resources []map[string]any `json:"resources"`
+			Outputs   map[string]any   `json:"outputs"`
+		}
+		require.NoError(t, json.Unmarshal(raw, &template))
+		require.Len(t, template.Resources, 1)
+		require.Equal(t, "web", template.Resources[0]["name"])
+		require.Empty(t, template.Resources[0]["dependsOn"])
+		require.Empty(t, template.Outputs)
+	})
+
+	t.Run("KeepsDependenciesOnTargets", func(t *testing.T) {
+		raw, err := targetedTemplate([]byte(targetedArmJson), []string{"web", "monitoring"})
+		require.NoError(t, err)
+
+		var template struct {
+			Resources []map[string]any `json:"resources"`
+		}
+		require.NoError(t, json.Unmarshal(raw, &template))
+		require.Len(t, template.Resources, 2)
+		require.Len(t, template.Resources[1]["dependsOn"], 1)
+	})
+
+	t.Run("SymbolicNames", func(t *testing.T) {
+		raw, err := targetedTemplate([]byte(targetedSymbolicArmJson), []string{"web", "monitoring"})
+		require.NoError(t, err)
+
+		var template struct {
+			Resources map[string]map[string]any `json:"resources"`
+		}
+		require.NoError(t, json.Unmarshal(raw, &template))
+		require.Len(t, template.Resources, 2)
+		require.Equal(t, []any{"monitoring"}, template.Resources["web"]["dependsOn"])
+		require.Empty(t, template.Resources["monitoring"]["dependsOn"])
+	})
+
+	t.Run("SymbolicReferencesKeptAsExisting", func(t *testing.T) {
+		armJson := `{
+			"languageVersion": "2.0",
+			"resources": {
+				"storage": {
+					"type": "Microsoft.Storage/storageAccounts",
+					"apiVersion": "2022-05-01",
+					"name": "[parameters('storageName')]",
+					"location": "eastus",
+					"properties": { "minimumTlsVersion": "TLS1_2" }
+				},
+				"plan": { "type": "Microsoft.Web/serverfarms", "apiVersion": "2022-03-01", "name": "plan" },
+				"web": {
+					"type": "Microsoft.Web/sites",
+					"apiVersion": "2022-03-01",
+					"name": "web",
+					"properties": {
+						"siteConfig": {
+							"appSettings": [
+								{ "name": "BLOB_ENDPOINT", "value": "[reference('storage').primaryEndpoints.blob]" }
+							]
+						}
+					},
+					"dependsOn": ["plan", "storage"]
+				}
+			}
+		}`
+
+		raw, err := targetedTemplate([]byte(armJson), []string{"web"})
+		require.NoError(t, err)
+
+		var template struct {
+			Resources map[string]map[string]any `json:"resources"`
+		}
+		require.NoError(t, json.Unmarshal(raw, &template))
+		require.Len(t, template.Resources, 2)
+		require.Equal(t, map[string]any{
+			"existing":   true,
+			"type":       "Microsoft.Storage/storageAccounts",
+			"apiVersion": "2022-05-01",
+			"name":       "[parameters('storageName')]",
+		}, template.Resources["storage"])
+		require.Equal(t, []any{"storage"}, template.Resources["web"]["dependsOn"])
+	})
+
+	t.Run("ReferencesRejected", func(t *testing.T) {
+		armJson := `{
+			"resources": [
+				{ "type": "Microsoft.Storage/storageAccounts", "apiVersion": "2022-05-01", "name": "st" },
+				{
+					"type": "Microsoft.Web/sites",
+					"apiVersion": "2022-03-01",
+					"name": "web",
+					"properties": {
+						"blob": "[reference(resourceId('Microsoft.Storage/storageAccounts', 'st')).primaryEndpoints.blob]"
+					}
+				}
+			]
+		}`
+
+		_, err := targetedTemplate([]byte(armJson), []string{"web"})
+		require.ErrorContains(t, err, "'web' references 'st', which is not targeted")
+
+		_, err = targetedTemplate([]byte(armJson), []string{"web", "st"})
+		require.NoError(t, err)
+	})
+
+	t.Run("ReferencesWithApiVersion", func(t *testing.T) {
+		raw, err := targetedTemplate([]byte(`{
+			"resources": [
+				{ "type": "Microsoft.Storage/storageAccounts", "apiVersion": "2022-05-01", "name": "st" },
+				{
+					"type": "Microsoft.Web/sites",
+					"name": "web",
+					"properties": {
+						"blob": "[reference(resourceId('Microsoft.Storage/storageAccounts', 'st'), '2022-05-01').primaryEndpoints.blob]"
+					}
+				}
+			]
+		}`), []string{"web"})
+		require.NoError(t, err)
+
+		var template struct {
+			Resources []map[string]any `json:"resources"`
+		}
+		require.NoError(t, json.Unmarshal(raw, &template))
+		require.Len(t, template.Resources, 1)
+	})
+
+	t.Run("UnknownTarget", func(t *testing.T) {
+		_, err := targetedTemplate([]byte(targetedArmJson), []string{"database"})
+		require.ErrorContains(t, err, "target 'database' does not match")
+	})
+}
+
+func TestTargetsWithDeploymentStacks(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	err := mockContext.Config.Set("alpha.deployment.stacks", "on")
+	require.NoError(t, err)
+	prepareBicepMocks(mockContext)
+
+	provider := createBicepProvider(t, mockContext)
+	err = provider.Initialize(*mockContext.Context, "../../../../test/functional/testdata/samples/webapp", Options{
+		DeploymentStacks: &DeploymentStacksOptions{},
+		Targets:          []string{"resources"},
+	})
+	require.ErrorIs(t, err, ErrTargetsWithDeploymentStacks)
+}
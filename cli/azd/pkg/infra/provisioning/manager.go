@@ -147,6 +147,24 @@ func (m *Manager) Destroy(ctx context.Context, options DestroyOptions) (*Destroy
 	return destroyResult, nil
 }
 
+// MigrateState moves the local deployment state of the current environment into the remote backend configured for the
+// project. Providers that don't keep local state return an error.
+func (m *Manager) MigrateState(ctx context.Context) error {
+	migrator, ok := m.provider.(StateMigrator)
+	if !ok {
+		return fmt.Errorf("the %s provider does not support state migration", m.provider.Name())
+	}
+
+	if err := migrator.MigrateState(ctx); err != nil {
+		return fmt.Errorf("migrating state: %w", err)
+	}
+
+	// make sure any spinner is stopped
+	m.console.StopSpinner(ctx, "", input.StepDone)
+
+	return nil
+}
+
 func (m *Manager) UpdateEnvironment(
 	ctx context.Context,
 	outputs map[string]OutputParameter,
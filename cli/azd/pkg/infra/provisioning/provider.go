@@ -22,8 +22,62 @@ type Options struct {
 	Provider ProviderKind `yaml:"provider,omitempty"`
 	Path     string       `yaml:"path,omitempty"`
 	Module   string       `yaml:"module,omitempty"`
+	// DeploymentStacks, when set, provisions the infrastructure through an Azure Deployment Stack.
+	DeploymentStacks *DeploymentStacksOptions `yaml:"deploymentStacks,omitempty"`
+	// Terraform holds settings that only apply to the Terraform provider.
+	Terraform *TerraformOptions `yaml:"terraform,omitempty"`
 	// Not expected to be defined at azure.yaml
 	IgnoreDeploymentState bool `yaml:"-"`
+	// PlanOutputPath is the file a preview writes its plan to, so it can be reviewed and applied later.
+	// Not expected to be defined at azure.yaml
+	PlanOutputPath string `yaml:"-"`
+	// FromPlan is the file of a plan saved by a preview. When set, provisioning applies exactly that plan and fails
+	// if the template, parameters or deployed state changed since the plan was created.
+	// Not expected to be defined at azure.yaml
+	FromPlan string `yaml:"-"`
+	// Targets limits provisioning to the given modules or resources.
+	// Not expected to be defined at azure.yaml
+	Targets []string `yaml:"-"`
+}
+
+// DeploymentStacksOptions configures how resources are managed by an Azure Deployment Stack.
+type DeploymentStacksOptions struct {
+	ActionOnUnmanage *ActionOnUnmanageOptions `yaml:"actionOnUnmanage,omitempty"`
+	DenySettings     *DenySettingsOptions     `yaml:"denySettings,omitempty"`
+}
+
+// ActionOnUnmanageOptions defines what happens to resources and resource groups that are removed from the template.
+// Valid values are "delete" and "detach".
+type ActionOnUnmanageOptions struct {
+	Resources      string `yaml:"resources,omitempty"`
+	ResourceGroups string `yaml:"resourceGroups,omitempty"`
+}
+
+// DenySettingsOptions defines the operations that are denied on resources managed by the stack.
+// Valid modes are "none", "denyDelete" and "denyWriteAndDelete".
+type DenySettingsOptions struct {
+	Mode               string   `yaml:"mode,omitempty"`
+	ExcludedActions    []string `yaml:"excludedActions,omitempty"`
+	ExcludedPrincipals []string `yaml:"excludedPrincipals,omitempty"`
+	ApplyToChildScopes bool     `yaml:"applyToChildScopes,omitempty"`
+}
+
+// TerraformOptions holds settings that only apply to the Terraform provider.
+type TerraformOptions struct {
+	RemoteState *TerraformRemoteStateOptions `yaml:"remoteState,omitempty"`
+}
+
+// TerraformRemoteStateOptions configures an azurerm remote backend which is managed by azd.
+// Values support environment variable substitution, e.g. `${AZURE_ENV_NAME}`.
+type TerraformRemoteStateOptions struct {
+	// Bootstrap creates the resource group, storage account and container on provision when they don't exist.
+	Bootstrap      bool   `yaml:"bootstrap,omitempty"`
+	ResourceGroup  string `yaml:"resourceGroup,omitempty"`
+	StorageAccount string `yaml:"storageAccount,omitempty"`
+	Container      string `yaml:"container,omitempty"`
+	// Isolation selects how environments share the backend. "key" (default) stores the state of each environment under
+	// its own state key. "workspace" selects a Terraform workspace named after the environment.
+	Isolation string `yaml:"isolation,omitempty"`
 }
 
 type SkippedReasonType string
@@ -59,3 +113,8 @@ type Provider interface {
 	Destroy(ctx context.Context, options DestroyOptions) (*DestroyResult, error)
 	EnsureEnv(ctx context.Context) error
 }
+
+// StateMigrator is implemented by providers which can move local deployment state into a remote backend.
+type StateMigrator interface {
+	MigrateState(ctx context.Context) error
+}
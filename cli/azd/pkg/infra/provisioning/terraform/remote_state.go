@@ -0,0 +1,287 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package terraform
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/drone/envsubst"
+)
+
+const (
+	// remoteStateIsolationKey stores the state of each environment under its own key in the shared container.
+	remoteStateIsolationKey = "key"
+	// remoteStateIsolationWorkspace stores the state of each environment in a terraform workspace named after it.
+	remoteStateIsolationWorkspace = "workspace"
+
+	defaultRemoteStateContainer = "tfstate"
+	defaultRemoteStateKey       = "terraform.tfstate"
+
+	resourceGroupApiVersion  = "2021-04-01"
+	storageAccountApiVersion = "2023-01-01"
+)
+
+var errMissingBackendBlock = errors.New(
+	"remote state requires an azurerm backend. Add `terraform { backend \"azurerm\" {} }` to your terraform files",
+)
+
+// remoteState is the resolved remote state configuration for the current environment.
+type remoteState struct {
+	bootstrap      bool
+	resourceGroup  string
+	storageAccount string
+	container      string
+	isolation      string
+}
+
+// key returns the name of the blob which holds the state of the current environment.
+func (r *remoteState) key(envName string) string {
+	if r.isolation == remoteStateIsolationWorkspace {
+		// workspaces are stored as `<key>env:<workspace>` by the azurerm backend
+		return defaultRemoteStateKey
+	}
+
+	return fmt.Sprintf("%s.tfstate", envName)
+}
+
+// backendConfigArgs returns the `terraform init` arguments which configure the azurerm backend.
+func (r *remoteState) backendConfigArgs(envName string) []string {
+	return []string{
+		fmt.Sprintf("-backend-config=resource_group_name=%s", r.resourceGroup),
+		fmt.Sprintf("-backend-config=storage_account_name=%s", r.storageAccount),
+		fmt.Sprintf("-backend-config=container_name=%s", r.container),
+		fmt.Sprintf("-backend-config=key=%s", r.key(envName)),
+	}
+}
+
+// remoteState resolves the `infra.terraform.remoteState` configuration for the current environment. A nil value is
+// returned when the project doesn't use azd managed remote state.
+func (t *TerraformProvider) remoteState() (*remoteState, error) {
+	if t.options.Terraform == nil || t.options.Terraform.RemoteState == nil {
+		return nil, nil
+	}
+
+	options := t.options.Terraform.RemoteState
+	resolve := func(value string) (string, error) {
+		return envsubst.Eval(value, t.env.Getenv)
+	}
+
+	state := &remoteState{
+		bootstrap: options.Bootstrap,
+		isolation: options.Isolation,
+	}
+
+	var err error
+	if state.resourceGroup, err = resolve(options.ResourceGroup); err != nil {
+		return nil, fmt.Errorf("resolving remoteState.resourceGroup: %w", err)
+	}
+	if state.storageAccount, err = resolve(options.StorageAccount); err != nil {
+		return nil, fmt.Errorf("resolving remoteState.storageAccount: %w", err)
+	}
+	if state.container, err = resolve(options.Container); err != nil {
+		return nil, fmt.Errorf("resolving remoteState.container: %w", err)
+	}
+
+	if state.resourceGroup == "" {
+		state.resourceGroup = fmt.Sprintf("rg-%s-tfstate", t.env.Name())
+	}
+	if state.storageAccount == "" {
+		state.storageAccount = defaultStorageAccountName(t.env.GetSubscriptionId(), t.env.Name())
+	}
+	if state.container == "" {
+		state.container = defaultRemoteStateContainer
+	}
+
+	switch state.isolation {
+	case "":
+		state.isolation = remoteStateIsolationKey
+	case remoteStateIsolationKey, remoteStateIsolationWorkspace:
+	default:
+		return nil, fmt.Errorf(
+			"invalid value for remoteState.isolation: '%s'. Allowed values are '%s' and '%s'",
+			state.isolation,
+			remoteStateIsolationKey,
+			remoteStateIsolationWorkspace,
+		)
+	}
+
+	return state, nil
+}
+
+// defaultStorageAccountName returns a storage account name which is stable for an environment in a subscription.
+// Storage account names are global, 3 to 24 characters long and only allow lowercase letters and numbers.
+func defaultStorageAccountName(subscriptionId string, envName string) string {
+	var sb strings.Builder
+	for _, c := range strings.ToLower(envName) {
+		if c < unicode.MaxASCII && (unicode.IsLetter(c) || unicode.IsDigit(c)) {
+			sb.WriteRune(c)
+		}
+	}
+
+	hash := fmt.Sprintf("%x", sha256.Sum256([]byte(subscriptionId+envName)))[:8]
+	prefix := "sttf" + sb.String()
+	if len(prefix) > 24-len(hash) {
+		prefix = prefix[:24-len(hash)]
+	}
+
+	return prefix + hash
+}
+
+// bootstrapRemoteState creates the resource group, storage account and container which hold the remote state when
+// they don't exist yet.
+func (t *TerraformProvider) bootstrapRemoteState(ctx context.Context, state *remoteState) error {
+	subscriptionId := t.env.GetSubscriptionId()
+	location := t.env.GetLocation()
+
+	resourceGroupId := azure.ResourceGroupRID(subscriptionId, state.resourceGroup)
+	storageAccountId := fmt.Sprintf(
+		"%s/providers/Microsoft.Storage/storageAccounts/%s", resourceGroupId, state.storageAccount)
+	containerId := fmt.Sprintf("%s/blobServices/default/containers/%s", storageAccountId, state.container)
+
+	steps := []struct {
+		name       string
+		id         string
+		apiVersion string
+		resource   armresources.GenericResource
+	}{
+		{
+			name:       fmt.Sprintf("resource group %s", state.resourceGroup),
+			id:         resourceGroupId,
+			apiVersion: resourceGroupApiVersion,
+			resource: armresources.GenericResource{
+				Location: to.Ptr(location),
+				Tags:     map[string]*string{azure.TagKeyAzdEnvName: to.Ptr(t.env.Name())},
+			},
+		},
+		{
+			name:       fmt.Sprintf("storage account %s", state.storageAccount),
+			id:         storageAccountId,
+			apiVersion: storageAccountApiVersion,
+			resource: armresources.GenericResource{
+				Location: to.Ptr(location),
+				Kind:     to.Ptr("StorageV2"),
+				SKU:      &armresources.SKU{Name: to.Ptr("Standard_LRS")},
+				Tags:     map[string]*string{azure.TagKeyAzdEnvName: to.Ptr(t.env.Name())},
+				Properties: map[string]any{
+					"minimumTlsVersion":        "TLS1_2",
+					"allowBlobPublicAccess":    false,
+					"supportsHttpsTrafficOnly": true,
+				},
+			},
+		},
+		{
+			name:       fmt.Sprintf("container %s", state.container),
+			id:         containerId,
+			apiVersion: storageAccountApiVersion,
+			resource: armresources.GenericResource{
+				Properties: map[string]any{},
+			},
+		},
+	}
+
+	for _, step := range steps {
+		exists, err := t.azCli.ResourceExists(ctx, subscriptionId, step.id, step.apiVersion)
+		if err != nil {
+			return fmt.Errorf("checking %s: %w", step.name, err)
+		}
+		if exists {
+			log.Printf("remote state %s already exists", step.name)
+			continue
+		}
+
+		message := fmt.Sprintf("Creating remote state %s", output.WithHighLightFormat(step.name))
+		t.console.ShowSpinner(ctx, message, input.Step)
+		err = t.azCli.CreateOrUpdateResource(ctx, subscriptionId, step.id, step.apiVersion, step.resource)
+		t.console.StopSpinner(ctx, message, input.GetStepResultFormat(err))
+		if err != nil {
+			return fmt.Errorf("creating %s: %w", step.name, err)
+		}
+	}
+
+	return nil
+}
+
+// initRemoteState prepares the azurerm backend for the current environment: it bootstraps the storage when requested,
+// initializes the module against it and selects the workspace of the environment.
+func (t *TerraformProvider) initRemoteState(ctx context.Context, state *remoteState) (string, error) {
+	hasBackend, err := t.isRemoteBackendConfig()
+	if err != nil {
+		return "", fmt.Errorf("reading backend config: %w", err)
+	}
+	if !hasBackend {
+		return "", errMissingBackendBlock
+	}
+
+	if state.bootstrap {
+		if err := t.bootstrapRemoteState(ctx, state); err != nil {
+			return "", fmt.Errorf("bootstrapping remote state: %w", err)
+		}
+	}
+
+	modulePath := t.modulePath()
+	args := append(state.backendConfigArgs(t.env.Name()), "-reconfigure")
+	runResult, err := t.cli.Init(ctx, modulePath, args...)
+	if err != nil {
+		return runResult, err
+	}
+
+	if state.isolation == remoteStateIsolationWorkspace {
+		if err := t.cli.SelectWorkspace(ctx, modulePath, t.env.Name()); err != nil {
+			return "", fmt.Errorf("selecting workspace '%s': %w", t.env.Name(), err)
+		}
+	}
+
+	return runResult, nil
+}
+
+// MigrateState uploads the local state of the current environment to the configured remote backend. The local state
+// file is kept next to its original location with a `.migrated` suffix.
+func (t *TerraformProvider) MigrateState(ctx context.Context) error {
+	state, err := t.remoteState()
+	if err != nil {
+		return err
+	}
+	if state == nil {
+		return errors.New(
+			"no remote state configured. Set `infra.terraform.remoteState` in azure.yaml before migrating state")
+	}
+
+	localStatePath := t.localStateFilePath()
+	if _, err := os.Stat(localStatePath); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("no local state found at '%s'", localStatePath)
+		}
+		return fmt.Errorf("reading local state: %w", err)
+	}
+
+	if runResult, err := t.initRemoteState(ctx, state); err != nil {
+		return fmt.Errorf("terraform init failed: %s , err: %w", runResult, err)
+	}
+
+	message := fmt.Sprintf("Pushing local state to %s", output.WithHighLightFormat(state.storageAccount))
+	t.console.ShowSpinner(ctx, message, input.Step)
+	err = t.cli.StatePush(ctx, t.modulePath(), localStatePath)
+	t.console.StopSpinner(ctx, message, input.GetStepResultFormat(err))
+	if err != nil {
+		return err
+	}
+
+	if err := os.Rename(localStatePath, localStatePath+".migrated"); err != nil {
+		return fmt.Errorf("renaming migrated local state: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,220 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package terraform
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	. "github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoteStateOptions(t *testing.T) {
+	env := environment.NewWithValues("test-env", map[string]string{
+		"AZURE_SUBSCRIPTION_ID": "00000000-0000-0000-0000-000000000000",
+		"STATE_ACCOUNT":         "sttfstateshared",
+	})
+
+	t.Run("NotConfigured", func(t *testing.T) {
+		provider := &TerraformProvider{env: env}
+		state, err := provider.remoteState()
+		require.NoError(t, err)
+		require.Nil(t, state)
+	})
+
+	t.Run("Defaults", func(t *testing.T) {
+		provider := &TerraformProvider{env: env, options: Options{
+			Terraform: &TerraformOptions{RemoteState: &TerraformRemoteStateOptions{}},
+		}}
+		state, err := provider.remoteState()
+		require.NoError(t, err)
+		require.Equal(t, "rg-test-env-tfstate", state.resourceGroup)
+		require.Equal(t, "tfstate", state.container)
+		require.Equal(t, remoteStateIsolationKey, state.isolation)
+		require.Equal(t, "test-env.tfstate", state.key(env.Name()))
+	})
+
+	t.Run("Configured", func(t *testing.T) {
+		provider := &TerraformProvider{env: env, options: Options{
+			Terraform: &TerraformOptions{RemoteState: &TerraformRemoteStateOptions{
+				ResourceGroup:  "rg-shared",
+				StorageAccount: "${STATE_ACCOUNT}",
+				Isolation:      "workspace",
+			}},
+		}}
+		state, err := provider.remoteState()
+		require.NoError(t, err)
+		require.Equal(t, "sttfstateshared", state.storageAccount)
+		require.Equal(t, []string{
+			"-backend-config=resource_group_name=rg-shared",
+			"-backend-config=storage_account_name=sttfstateshared",
+			"-backend-config=container_name=tfstate",
+			"-backend-config=key=terraform.tfstate",
+		}, state.backendConfigArgs(env.Name()))
+	})
+
+	t.Run("InvalidIsolation", func(t *testing.T) {
+		provider := &TerraformProvider{env: env, options: Options{
+			Terraform: &TerraformOptions{RemoteState: &TerraformRemoteStateOptions{Isolation: "folder"}},
+		}}
+		_, err := provider.remoteState()
+		require.ErrorContains(t, err, "remoteState.isolation")
+	})
+}
+
+func TestDefaultStorageAccountName(t *testing.T) {
+	envName := "My-Very_Long-Environment-Name"
+	name := defaultStorageAccountName("00000000-0000-0000-0000-000000000000", envName)
+	require.LessOrEqual(t, len(name), 24)
+	require.Regexp(t, regexp.MustCompile("^[a-z0-9]+$"), name)
+	require.True(t, strings.HasPrefix(name, "sttfmyvery"))
+
+	require.Equal(t, name, defaultStorageAccountName("00000000-0000-0000-0000-000000000000", envName))
+	require.NotEqual(t, name, defaultStorageAccountName("11111111-1111-1111-1111-111111111111", envName))
+}
+
+func TestTerraformRemoteStateBootstrap(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	prepareGenericMocks(mockContext.CommandRunner)
+	preparePlanningMocks(mockContext.CommandRunner)
+
+	var initArgs []string
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return args.Cmd == "terraform" && strings.Contains(command, " init")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		initArgs = args.Args
+		return exec.NewRunResult(0, "Terraform has been successfully initialized!", ""), nil
+	})
+
+	var workspaceArgs []string
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return args.Cmd == "terraform" && strings.Contains(command, "workspace select")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		workspaceArgs = args.Args
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	var created []string
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodHead
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		// the resource group already exists, everything else has to be created
+		if strings.HasSuffix(request.URL.Path, "/resourceGroups/rg-shared") {
+			return mocks.CreateEmptyHttpResponse(request, http.StatusNoContent)
+		}
+		return mocks.CreateEmptyHttpResponse(request, http.StatusNotFound)
+	})
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPut
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		created = append(created, request.URL.Path)
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{})
+	})
+
+	infraProvider := createTerraformProvider(t, mockContext)
+	infraProvider.projectPath = copyProjectWithBackend(t, infraProvider.projectPath)
+	infraProvider.options.Terraform = &TerraformOptions{
+		RemoteState: &TerraformRemoteStateOptions{
+			Bootstrap:      true,
+			ResourceGroup:  "rg-shared",
+			StorageAccount: "sttfstateshared",
+			Isolation:      "workspace",
+		},
+	}
+
+	_, _, err := infraProvider.plan(*mockContext.Context)
+	require.NoError(t, err)
+
+	require.Len(t, created, 2)
+	require.True(t, strings.HasSuffix(created[0], "/storageAccounts/sttfstateshared"))
+	require.True(t, strings.HasSuffix(created[1], "/blobServices/default/containers/tfstate"))
+
+	require.Contains(t, initArgs, "-backend-config=storage_account_name=sttfstateshared")
+	require.Contains(t, initArgs, "-backend-config=key=terraform.tfstate")
+	require.Contains(t, workspaceArgs, "test-env")
+}
+
+func TestTerraformRemoteStateRequiresBackend(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	prepareGenericMocks(mockContext.CommandRunner)
+	preparePlanningMocks(mockContext.CommandRunner)
+
+	infraProvider := createTerraformProvider(t, mockContext)
+	infraProvider.options.Terraform = &TerraformOptions{
+		RemoteState: &TerraformRemoteStateOptions{},
+	}
+
+	_, _, err := infraProvider.plan(*mockContext.Context)
+	require.ErrorIs(t, err, errMissingBackendBlock)
+}
+
+func TestTerraformMigrateState(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	prepareGenericMocks(mockContext.CommandRunner)
+	preparePlanningMocks(mockContext.CommandRunner)
+
+	var pushArgs []string
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return args.Cmd == "terraform" && strings.Contains(command, "state push")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		pushArgs = args.Args
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	infraProvider := createTerraformProvider(t, mockContext)
+	infraProvider.projectPath = copyProjectWithBackend(t, infraProvider.projectPath)
+
+	err := infraProvider.MigrateState(*mockContext.Context)
+	require.ErrorContains(t, err, "no remote state configured")
+
+	infraProvider.options.Terraform = &TerraformOptions{
+		RemoteState: &TerraformRemoteStateOptions{StorageAccount: "sttfstateshared"},
+	}
+
+	err = infraProvider.MigrateState(*mockContext.Context)
+	require.ErrorContains(t, err, "no local state found")
+
+	localState := infraProvider.localStateFilePath()
+	require.NoError(t, os.MkdirAll(filepath.Dir(localState), 0755))
+	require.NoError(t, os.WriteFile(localState, []byte("{}"), 0600))
+
+	err = infraProvider.MigrateState(*mockContext.Context)
+	require.NoError(t, err)
+	require.Contains(t, pushArgs, localState)
+	require.NoFileExists(t, localState)
+	require.FileExists(t, localState+".migrated")
+}
+
+// copyProjectWithBackend copies the infra folder of a project to a temporary directory and adds an empty azurerm
+// backend block to it.
+func copyProjectWithBackend(t *testing.T, projectPath string) string {
+	target := t.TempDir()
+	infraPath := filepath.Join(target, "infra")
+	require.NoError(t, os.MkdirAll(infraPath, 0755))
+
+	entries, err := os.ReadDir(filepath.Join(projectPath, "infra"))
+	require.NoError(t, err)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		contents, err := os.ReadFile(filepath.Join(projectPath, "infra", entry.Name()))
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(infraPath, entry.Name()), contents, 0600))
+	}
+
+	backend := []byte("terraform {\n  backend \"azurerm\" {}\n}\n")
+	require.NoError(t, os.WriteFile(filepath.Join(infraPath, "backend.tf"), backend, 0600))
+
+	return target
+}
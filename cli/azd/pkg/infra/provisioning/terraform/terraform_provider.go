@@ -17,6 +17,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
 	"github.com/azure/azure-dev/cli/azd/pkg/prompt"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/terraform"
 	"github.com/drone/envsubst"
 	"go.opentelemetry.io/otel/trace"
@@ -35,6 +36,7 @@ type TerraformProvider struct {
 	prompters    prompt.Prompter
 	console      input.Console
 	cli          terraform.TerraformCli
+	azCli        azcli.AzCli
 	curPrincipal CurrentPrincipalIdProvider
 	projectPath  string
 	options      Options
@@ -63,12 +65,14 @@ func NewTerraformProvider(
 	console input.Console,
 	curPrincipal CurrentPrincipalIdProvider,
 	prompters prompt.Prompter,
+	azCli azcli.AzCli,
 ) Provider {
 	provider := &TerraformProvider{
 		envManager:   envManager,
 		env:          env,
 		console:      console,
 		cli:          cli,
+		azCli:        azCli,
 		curPrincipal: curPrincipal,
 		prompters:    prompters,
 	}
@@ -95,6 +99,10 @@ func (t *TerraformProvider) Initialize(ctx context.Context, projectPath string,
 		return err
 	}
 
+	if _, err := t.remoteState(); err != nil {
+		return err
+	}
+
 	envVars := []string{
 		// Sets the terraform data directory env var that will get set on all terraform CLI commands
 		fmt.Sprintf("TF_DATA_DIR=%s", t.dataDirPath()),
@@ -182,12 +190,52 @@ func (t *TerraformProvider) plan(ctx context.Context) (*Deployment, *terraformDe
 	return deployment, &deploymentDetails, nil
 }
 
+// savedPlan prepares applying the plan file referenced by the `FromPlan` option instead of creating a new plan.
+// Terraform rejects applying a saved plan when the state changed since the plan was created.
+func (t *TerraformProvider) savedPlan(ctx context.Context) (*Deployment, *terraformDeploymentDetails, error) {
+	if _, err := os.Stat(t.options.FromPlan); err != nil {
+		return nil, nil, fmt.Errorf("reading plan file: %w", err)
+	}
+
+	isRemoteBackendConfig, err := t.isRemoteBackendConfig()
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading backend config: %w", err)
+	}
+
+	initRes, err := t.init(ctx, isRemoteBackendConfig)
+	if err != nil {
+		return nil, nil, fmt.Errorf("terraform init failed: %s , err: %w", initRes, err)
+	}
+
+	deployment, err := t.createDeployment(ctx, t.modulePath())
+	if err != nil {
+		return nil, nil, fmt.Errorf("create terraform template failed: %w", err)
+	}
+
+	deploymentDetails := terraformDeploymentDetails{
+		ParameterFilePath: t.parametersFilePath(),
+		PlanFilePath:      t.options.FromPlan,
+	}
+	if !isRemoteBackendConfig {
+		deploymentDetails.localStateFilePath = t.localStateFilePath()
+	}
+
+	return deployment, &deploymentDetails, nil
+}
+
 // Deploy the infrastructure within the specified template through terraform apply
 func (t *TerraformProvider) Deploy(ctx context.Context) (*DeployResult, error) {
 	t.console.Message(ctx, "Locating plan file...")
 
 	modulePath := t.modulePath()
-	deployment, terraformDeploymentData, err := t.plan(ctx)
+	var deployment *Deployment
+	var terraformDeploymentData *terraformDeploymentDetails
+	var err error
+	if t.options.FromPlan != "" {
+		deployment, terraformDeploymentData, err = t.savedPlan(ctx)
+	} else {
+		deployment, terraformDeploymentData, err = t.plan(ctx)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -222,11 +270,17 @@ func (t *TerraformProvider) Deploy(ctx context.Context) (*DeployResult, error) {
 func (t *TerraformProvider) Preview(ctx context.Context) (*DeployPreviewResult, error) {
 	// terraform uses plan() to display the what-if output
 	// no changes are added to the properties
-	_, _, err := t.plan(ctx)
+	_, deploymentDetails, err := t.plan(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	if t.options.PlanOutputPath != "" {
+		if err := copyFile(deploymentDetails.PlanFilePath, t.options.PlanOutputPath); err != nil {
+			return nil, fmt.Errorf("saving plan file: %w", err)
+		}
+	}
+
 	return &DeployPreviewResult{
 		Preview: &DeploymentPreview{
 			Status:     "done",
@@ -303,6 +357,10 @@ func (t *TerraformProvider) createPlanArgs(isRemoteBackendConfig bool) []string
 		args = append(args, fmt.Sprintf("-state=%s", t.localStateFilePath()))
 	}
 
+	for _, target := range t.options.Targets {
+		args = append(args, fmt.Sprintf("-target=%s", target))
+	}
+
 	return args
 }
 
@@ -356,6 +414,14 @@ func (t *TerraformProvider) ensureParametersFile(ctx context.Context) error {
 // initialize template terraform provider through terraform init
 func (t *TerraformProvider) init(ctx context.Context, isRemoteBackendConfig bool) (string, error) {
 
+	remoteState, err := t.remoteState()
+	if err != nil {
+		return "", err
+	}
+	if remoteState != nil {
+		return t.initRemoteState(ctx, remoteState)
+	}
+
 	modulePath := t.modulePath()
 	cmd := []string{}
 
@@ -665,6 +731,20 @@ func (t *TerraformProvider) isRemoteBackendConfig() (bool, error) {
 	return false, nil
 }
 
+// copyFile copies the file at source to target, creating the directory of target when needed.
+func copyFile(source string, target string) error {
+	contents, err := os.ReadFile(source)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), osutil.PermissionDirectory); err != nil {
+		return err
+	}
+
+	return os.WriteFile(target, contents, osutil.PermissionFileOwnerOnly)
+}
+
 // Copies the an input parameters file templateFilePath to inputFilePath after replacing environment variable references in
 // the contents.
 func (t *TerraformProvider) createInputParametersFile(
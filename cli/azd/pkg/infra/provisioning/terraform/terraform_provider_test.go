@@ -7,6 +7,8 @@ import (
 	"context"
 	_ "embed"
 	"fmt"
+	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
 	"testing"
@@ -16,6 +18,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	. "github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
 	"github.com/azure/azure-dev/cli/azd/pkg/prompt"
 	terraformTools "github.com/azure/azure-dev/cli/azd/pkg/tools/terraform"
 	"github.com/azure/azure-dev/cli/azd/test/mocks"
@@ -97,6 +100,65 @@ func TestTerraformState(t *testing.T) {
 	)
 }
 
+func TestTerraformPlanWithTargets(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	prepareGenericMocks(mockContext.CommandRunner)
+
+	infraProvider := createTerraformProvider(t, mockContext)
+	infraProvider.options.Targets = []string{"module.web", "azurerm_resource_group.rg"}
+
+	args := infraProvider.createPlanArgs(false)
+	require.Contains(t, args, "-target=module.web")
+	require.Contains(t, args, "-target=azurerm_resource_group.rg")
+}
+
+func TestTerraformPreviewSavesPlan(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	prepareGenericMocks(mockContext.CommandRunner)
+	preparePlanningMocks(mockContext.CommandRunner)
+
+	infraProvider := createTerraformProvider(t, mockContext)
+	infraProvider.options.PlanOutputPath = filepath.Join(t.TempDir(), "review", "main.tfplan")
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(infraProvider.planFilePath()), osutil.PermissionDirectory))
+	require.NoError(t, os.WriteFile(infraProvider.planFilePath(), []byte("PLAN"), osutil.PermissionFile))
+
+	_, err := infraProvider.Preview(*mockContext.Context)
+	require.NoError(t, err)
+
+	saved, err := os.ReadFile(infraProvider.options.PlanOutputPath)
+	require.NoError(t, err)
+	require.Equal(t, "PLAN", string(saved))
+}
+
+func TestTerraformDeployFromPlan(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	prepareGenericMocks(mockContext.CommandRunner)
+	prepareDestroyMocks(mockContext.CommandRunner)
+
+	var applyArgs []string
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return args.Cmd == "terraform" && strings.Contains(command, "apply")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		applyArgs = args.Args
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	infraProvider := createTerraformProvider(t, mockContext)
+
+	infraProvider.options.FromPlan = filepath.Join(t.TempDir(), "missing.tfplan")
+	_, err := infraProvider.Deploy(*mockContext.Context)
+	require.ErrorContains(t, err, "reading plan file")
+
+	infraProvider.options.FromPlan = filepath.Join(t.TempDir(), "main.tfplan")
+	require.NoError(t, os.WriteFile(infraProvider.options.FromPlan, []byte("PLAN"), osutil.PermissionFile))
+
+	deployResult, err := infraProvider.Deploy(*mockContext.Context)
+	require.NoError(t, err)
+	require.Contains(t, deployResult.Deployment.Outputs, "RG_NAME")
+	require.Equal(t, infraProvider.options.FromPlan, applyArgs[len(applyArgs)-1])
+}
+
 func createTerraformProvider(t *testing.T, mockContext *mocks.MockContext) *TerraformProvider {
 	projectDir := "../../../../test/functional/testdata/samples/resourcegroupterraform"
 	options := Options{
@@ -135,6 +197,7 @@ func createTerraformProvider(t *testing.T, mockContext *mocks.MockContext) *Terr
 		mockContext.Console,
 		&mockCurrentPrincipal{},
 		prompt.NewDefaultPrompter(env, mockContext.Console, accountManager, azCli, cloud.AzurePublic().PortalUrlBase),
+		azCli,
 	)
 
 	err := provider.Initialize(*mockContext.Context, projectDir, options)
@@ -0,0 +1,140 @@
+// Package jsonschema validates documents, like the azure.yaml of a project, against the JSON schemas of azd.
+//
+// The validation is implemented by github.com/santhosh-tekuri/jsonschema, which supports the drafts 4, 6, 7, 2019-09
+// and 2020-12 of JSON schema. This package adapts its errors to paths and messages which can be reported to users.
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// schemaUrl is the location the schema is compiled from, local references of the schema are resolved against it.
+const schemaUrl = "schema.json"
+
+// Schema is a compiled JSON schema.
+type Schema struct {
+	schema *jsonschema.Schema
+}
+
+// ValidationError describes a value which doesn't match its schema.
+type ValidationError struct {
+	// Path is the dotted path of the value in the document, or empty for the document itself.
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func (e ValidationError) String() string {
+	if e.Path == "" {
+		return e.Message
+	}
+
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// Compile parses a JSON schema. The draft of the schema is determined by its $schema, draft 2020-12 is used when it
+// isn't set. References to other documents than the schema itself aren't resolved.
+func Compile(schema []byte) (*Schema, error) {
+	compiler := jsonschema.NewCompiler()
+	compiler.LoadURL = func(url string) (io.ReadCloser, error) {
+		return nil, fmt.Errorf("loading '%s': only references within the schema are supported", url)
+	}
+
+	if err := compiler.AddResource(schemaUrl, bytes.NewReader(schema)); err != nil {
+		return nil, fmt.Errorf("parsing JSON schema: %w", err)
+	}
+
+	compiled, err := compiler.Compile(schemaUrl)
+	if err != nil {
+		return nil, fmt.Errorf("compiling JSON schema: %w", err)
+	}
+
+	return &Schema{schema: compiled}, nil
+}
+
+// Validate validates a document, as decoded by encoding/json or gopkg.in/yaml.v3 into an any value, and returns the
+// validation errors sorted by path. The document is valid when no errors are returned.
+func (s *Schema) Validate(document any) []ValidationError {
+	// The document is converted to the values of encoding/json, yaml.v3 decodes values like timestamps to other types
+	contents, err := json.Marshal(document)
+	if err != nil {
+		return []ValidationError{{Message: fmt.Sprintf("converting the document to JSON: %s", err)}}
+	}
+
+	var value any
+	decoder := json.NewDecoder(bytes.NewReader(contents))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return []ValidationError{{Message: fmt.Sprintf("converting the document to JSON: %s", err)}}
+	}
+
+	err = s.schema.Validate(value)
+	if err == nil {
+		return nil
+	}
+
+	var validationErr *jsonschema.ValidationError
+	if !errors.As(err, &validationErr) {
+		return []ValidationError{{Message: err.Error()}}
+	}
+
+	// The causes which have no causes themselves describe what is wrong with the document, the others only describe
+	// which keywords of the schema failed, ex) 'doesn't validate with #/definitions/service'
+	var errs []ValidationError
+	var collect func(validationErr *jsonschema.ValidationError)
+	collect = func(validationErr *jsonschema.ValidationError) {
+		if len(validationErr.Causes) == 0 {
+			errs = append(errs, ValidationError{
+				Path:    dottedPath(value, validationErr.InstanceLocation),
+				Message: validationErr.Message,
+			})
+		}
+
+		for _, cause := range validationErr.Causes {
+			collect(cause)
+		}
+	}
+	collect(validationErr)
+
+	sort.SliceStable(errs, func(i, j int) bool {
+		return errs[i].Path < errs[j].Path
+	})
+
+	return errs
+}
+
+// dottedPath converts the JSON pointer of a value in the document to a dotted path, ex) /services/api/hooks/0 to
+// services.api.hooks[0]. The document is walked to tell the indexes of arrays from the numeric names of properties.
+func dottedPath(document any, pointer string) string {
+	var path strings.Builder
+	current := document
+	for _, segment := range strings.Split(pointer, "/")[1:] {
+		segment = strings.ReplaceAll(strings.ReplaceAll(segment, "~1", "/"), "~0", "~")
+
+		switch typed := current.(type) {
+		case []any:
+			path.WriteString(fmt.Sprintf("[%s]", segment))
+			if index, err := strconv.Atoi(segment); err == nil && index < len(typed) {
+				current = typed[index]
+			}
+		default:
+			if path.Len() > 0 {
+				path.WriteString(".")
+			}
+			path.WriteString(segment)
+
+			object, _ := typed.(map[string]any)
+			current = object[segment]
+		}
+	}
+
+	return path.String()
+}
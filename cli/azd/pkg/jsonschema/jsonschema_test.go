@@ -0,0 +1,187 @@
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/azure/azure-dev/schemas"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+const testSchema = `{
+	"type": "object",
+	"required": ["name"],
+	"additionalProperties": false,
+	"properties": {
+		"name": { "type": "string", "minLength": 2 },
+		"tags": { "type": "array", "minItems": 1, "items": { "type": "string" } },
+		"services": {
+			"type": "object",
+			"additionalProperties": { "$ref": "#/definitions/service" }
+		}
+	},
+	"definitions": {
+		"service": {
+			"type": "object",
+			"required": ["host"],
+			"properties": {
+				"host": { "type": "string", "enum": ["containerapp", "aks"] },
+				"replicas": { "type": "integer" },
+				"k8s": { "type": "object" }
+			},
+			"if": { "properties": { "host": { "const": "containerapp" } } },
+			"then": { "not": { "required": ["k8s"] } }
+		}
+	}
+}`
+
+func Test_Schema_Validate(t *testing.T) {
+	schema, err := Compile([]byte(testSchema))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		document string
+		expected []ValidationError
+	}{
+		{
+			name: "Valid",
+			document: `
+name: app
+tags: [web]
+services:
+  api:
+    host: aks
+    replicas: 2
+    k8s: {}
+`,
+		},
+		{
+			name:     "MissingRequired",
+			document: "tags: [web]\n",
+			expected: []ValidationError{{Message: "missing properties: 'name'"}},
+		},
+		{
+			name:     "AdditionalProperty",
+			document: "name: app\nbogus: true\n",
+			expected: []ValidationError{{Message: "additionalProperties 'bogus' not allowed"}},
+		},
+		{
+			name:     "Type",
+			document: "name: app\ntags: web\n",
+			expected: []ValidationError{{Path: "tags", Message: "expected array, but got string"}},
+		},
+		{
+			name:     "Items",
+			document: "name: a\ntags: []\n",
+			expected: []ValidationError{
+				{Path: "name", Message: "length must be >= 2, but got 1"},
+				{Path: "tags", Message: "minimum 1 items required, but found 0 items"},
+			},
+		},
+		{
+			name: "Definitions",
+			document: `
+name: app
+services:
+  api:
+    host: appservice
+    replicas: 1.5
+  web:
+    replicas: 1
+`,
+			expected: []ValidationError{
+				{Path: "services.api.host", Message: `value must be one of "containerapp", "aks"`},
+				{Path: "services.api.replicas", Message: "expected integer, but got number"},
+				{Path: "services.web", Message: "missing properties: 'host'"},
+			},
+		},
+		{
+			name:     "ArrayIndex",
+			document: "name: app\ntags: [web, 1]\n",
+			expected: []ValidationError{{Path: "tags[1]", Message: "expected string, but got number"}},
+		},
+		{
+			name:     "Timestamp",
+			document: "name: 2024-01-01\n",
+		},
+		{
+			name: "Conditional",
+			document: `
+name: app
+services:
+  api:
+    host: containerapp
+    k8s: {}
+`,
+			expected: []ValidationError{{Path: "services.api", Message: "not failed"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var document any
+			require.NoError(t, yaml.Unmarshal([]byte(tt.document), &document))
+
+			errs := schema.Validate(document)
+			if tt.expected == nil {
+				require.Empty(t, errs)
+			} else {
+				require.Equal(t, tt.expected, errs)
+			}
+		})
+	}
+}
+
+func Test_Compile_AzureYamlSchemas(t *testing.T) {
+	for name, contents := range map[string][]byte{
+		"v1.0":  schemas.AzureYamlV1,
+		"alpha": schemas.AzureYamlAlpha,
+	} {
+		t.Run(name, func(t *testing.T) {
+			schema, err := Compile(contents)
+			require.NoError(t, err)
+
+			var document any
+			require.NoError(t, yaml.Unmarshal([]byte(`
+name: app
+services:
+  api:
+    project: ./src/api
+    language: js
+    host: containerapp
+`), &document))
+			require.Empty(t, schema.Validate(document))
+
+			require.NoError(t, yaml.Unmarshal([]byte(`
+name: app
+services:
+  api:
+    project: ./src/api
+    language: js
+    host: bogus
+`), &document))
+			errs := schema.Validate(document)
+			require.NotEmpty(t, errs)
+			require.Equal(t, "services.api.host", errs[0].Path)
+
+			require.NoError(t, yaml.Unmarshal([]byte(`
+name: app
+infra:
+  provider: terraform
+  terraform:
+    remoteState:
+      bootstrap: true
+      isolation: branch
+`), &document))
+			errs = schema.Validate(document)
+			require.NotEmpty(t, errs)
+			require.Equal(t, "infra.terraform.remoteState.isolation", errs[0].Path)
+		})
+	}
+}
+
+func Test_Compile_ExternalReference(t *testing.T) {
+	_, err := Compile([]byte(`{"$ref": "https://example.com/schema.json"}`))
+	require.ErrorContains(t, err, "only references within the schema are supported")
+}
@@ -4,18 +4,34 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"log"
+	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/async"
 	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/imagescan"
 	"github.com/benbjohnson/clock"
+	"github.com/moby/patternmatcher"
+	"github.com/moby/patternmatcher/ignorefile"
 )
 
+// ErrRegistryNotFound is returned when no container registry is configured for a service which builds an image, which
+// is the case of services with remote builds until the registry is provisioned.
+var ErrRegistryNotFound = errors.New("could not determine container registry endpoint")
+
 type ContainerHelper struct {
 	env                      *environment.Environment
 	envManager               environment.Manager
@@ -23,6 +39,8 @@ type ContainerHelper struct {
 	docker                   docker.Docker
 	clock                    clock.Clock
 	cloud                    *cloud.Cloud
+	commandRunner            exec.CommandRunner
+	console                  input.Console
 }
 
 func NewContainerHelper(
@@ -32,6 +50,8 @@ func NewContainerHelper(
 	containerRegistryService azcli.ContainerRegistryService,
 	docker docker.Docker,
 	cloud *cloud.Cloud,
+	commandRunner exec.CommandRunner,
+	console input.Console,
 ) *ContainerHelper {
 	return &ContainerHelper{
 		env:                      env,
@@ -40,13 +60,30 @@ func NewContainerHelper(
 		docker:                   docker,
 		clock:                    clock,
 		cloud:                    cloud,
+		commandRunner:            commandRunner,
+		console:                  console,
 	}
 }
 
 // RegistryName returns the name of the destination container registry to use for the current environment from the following:
 // 1. AZURE_CONTAINER_REGISTRY_ENDPOINT environment variable
 // 2. docker.registry from the service configuration
+//
+// Services deployed to a local k8s cluster push to docker.registry, or to a registry at localhost:5000 by default.
 func (ch *ContainerHelper) RegistryName(ctx context.Context, serviceConfig *ServiceConfig) (string, error) {
+	if serviceConfig.K8s.IsLocalCluster() {
+		registryName, err := serviceConfig.Docker.Registry.Envsubst(ch.env.Getenv)
+		if err != nil {
+			return "", fmt.Errorf("failed expanding 'docker.registry', %w", err)
+		}
+
+		if registryName == "" {
+			registryName = defaultLocalRegistry
+		}
+
+		return registryName, nil
+	}
+
 	registryName, found := ch.env.LookupEnv(environment.ContainerRegistryEndpointEnvVarName)
 	if !found {
 		log.Printf(
@@ -58,7 +95,7 @@ func (ch *ContainerHelper) RegistryName(ctx context.Context, serviceConfig *Serv
 	if registryName == "" {
 		yamlRegistryName, err := serviceConfig.Docker.Registry.Envsubst(ch.env.Getenv)
 		if err != nil {
-			log.Println("Failed expanding 'docker.registry'")
+			return "", fmt.Errorf("failed expanding 'docker.registry', %w", err)
 		}
 
 		registryName = yamlRegistryName
@@ -71,7 +108,8 @@ func (ch *ContainerHelper) RegistryName(ctx context.Context, serviceConfig *Serv
 	if serviceConfig.RelativePath != "" && registryName == "" {
 		return "", fmt.Errorf(
 			//nolint:lll
-			"could not determine container registry endpoint, ensure 'registry' has been set in the docker options or '%s' environment variable has been set",
+			"%w, ensure 'registry' has been set in the docker options or '%s' environment variable has been set",
+			ErrRegistryNotFound,
 			environment.ContainerRegistryEndpointEnvVarName,
 		)
 	}
@@ -183,14 +221,207 @@ func (ch *ContainerHelper) Login(
 
 	// Only perform automatic login for ACR
 	// Other registries require manual login via external 'docker login' command
-	hostParts := strings.Split(registryName, ".")
-	if len(hostParts) == 1 || strings.HasSuffix(registryName, ch.cloud.ContainerRegistryEndpointSuffix) {
+	if ch.isAzureContainerRegistry(registryName) {
 		return registryName, ch.containerRegistryService.Login(ctx, ch.env.GetSubscriptionId(), registryName)
 	}
 
 	return registryName, nil
 }
 
+// isAzureContainerRegistry returns true when the registry is an ACR login server or the name of an ACR
+func (ch *ContainerHelper) isAzureContainerRegistry(registryName string) bool {
+	// Registries with a port, ex) localhost:5000, are never ACR
+	hostParts := strings.Split(registryName, ".")
+	isAcrName := len(hostParts) == 1 && !strings.Contains(registryName, ":")
+	return isAcrName || strings.HasSuffix(registryName, ch.cloud.ContainerRegistryEndpointSuffix)
+}
+
+// RemoteBuild builds the image of the service with ACR Tasks, which doesn't require docker to be installed, and pushes
+// it to the container registry of the environment. The logs of the build are written to the logs writer. On success,
+// it returns the fully qualified name of the pushed image.
+func (ch *ContainerHelper) RemoteBuild(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	logs io.Writer,
+) (string, error) {
+	dockerOptions := getDockerOptionsWithDefaults(serviceConfig.Docker)
+	if docker.IsMultiPlatform(dockerOptions.Platform) {
+		return "", fmt.Errorf(
+			"building images for multiple platforms ('%s') isn't supported by 'docker.remoteBuild'", dockerOptions.Platform)
+	}
+
+	if len(dockerOptions.CacheFrom) > 0 || len(dockerOptions.CacheTo) > 0 ||
+		len(dockerOptions.Secrets) > 0 || len(dockerOptions.Labels) > 0 {
+		return "", errors.New(
+			"'docker.cacheFrom', 'docker.cacheTo', 'docker.secrets' and 'docker.labels' aren't supported by " +
+				"'docker.remoteBuild'")
+	}
+
+	registryName, err := ch.RegistryName(ctx, serviceConfig)
+	if err != nil {
+		return "", err
+	}
+
+	if !ch.isAzureContainerRegistry(registryName) {
+		return "", fmt.Errorf("'docker.remoteBuild' requires an Azure container registry, got '%s'", registryName)
+	}
+
+	localImage, err := ch.LocalImageTag(ctx, serviceConfig)
+	if err != nil {
+		return "", fmt.Errorf("generating local image tag: %w", err)
+	}
+
+	remoteImage, err := ch.RemoteImageTag(ctx, serviceConfig, localImage)
+	if err != nil {
+		return "", fmt.Errorf("getting remote image tag: %w", err)
+	}
+
+	contextPath := dockerOptions.Context
+	if !filepath.IsAbs(contextPath) {
+		contextPath = filepath.Join(serviceConfig.Path(), contextPath)
+	}
+
+	// ACR reads the Dockerfile from the uploaded build context
+	dockerfile, err := filepath.Rel(contextPath, dockerfilePath(serviceConfig, dockerOptions))
+	if err != nil || strings.HasPrefix(dockerfile, "..") {
+		return "", fmt.Errorf(
+			"the Dockerfile of service '%s' must be within its build context '%s' to be built remotely",
+			serviceConfig.Name,
+			contextPath,
+		)
+	}
+
+	excludePatterns, err := dockerignorePatterns(contextPath, filepath.ToSlash(dockerfile))
+	if err != nil {
+		return "", err
+	}
+
+	archivePath, err := createBuildContextArchive(
+		serviceConfig.Project.Name, serviceConfig.Name, contextPath, excludePatterns)
+	if err != nil {
+		return "", fmt.Errorf("archiving build context: %w", err)
+	}
+	defer os.Remove(archivePath)
+
+	log.Printf("building %s remotely in container registry '%s'\n", remoteImage, registryName)
+	err = ch.containerRegistryService.RemoteBuild(
+		ctx,
+		ch.env.GetSubscriptionId(),
+		registryName,
+		archivePath,
+		&azcli.RemoteBuildOptions{
+			DockerfilePath: filepath.ToSlash(dockerfile),
+			Target:         dockerOptions.Target,
+			Platform:       dockerOptions.Platform,
+			BuildArgs:      dockerOptions.BuildArgs,
+			ImageNames:     []string{localImage},
+		},
+		logs,
+	)
+	if err != nil {
+		return "", fmt.Errorf("building image remotely: %w", err)
+	}
+
+	return remoteImage, nil
+}
+
+// dockerignorePatterns returns the patterns of the .dockerignore file of the build context, which are empty when the
+// file doesn't exist. Like docker, the Dockerfile and the .dockerignore file are never excluded from the build context.
+func dockerignorePatterns(contextPath string, dockerfile string) ([]string, error) {
+	file, err := os.Open(filepath.Join(contextPath, ".dockerignore"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading .dockerignore: %w", err)
+	}
+	defer file.Close()
+
+	patterns, err := ignorefile.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("reading .dockerignore: %w", err)
+	}
+
+	for _, keep := range []string{dockerfile, ".dockerignore"} {
+		excluded, err := patternmatcher.MatchesOrParentMatches(filepath.FromSlash(keep), patterns)
+		if err != nil {
+			return nil, fmt.Errorf("parsing .dockerignore: %w", err)
+		}
+
+		if excluded {
+			patterns = append(patterns, "!"+keep)
+		}
+	}
+
+	return patterns, nil
+}
+
+// deferredRemoteBuild builds the image of the service remotely when it's deployed, and scans it when 'docker.scan' is
+// configured, recording the image and the scan in the package details
+func (ch *ContainerHelper) deferredRemoteBuild(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageDetails *dockerPackageResult,
+	task *async.TaskContextWithProgress[*ServiceDeployResult, ServiceProgress],
+) (string, error) {
+	task.SetProgress(NewServiceProgress("Building container image remotely"))
+	previewerWriter := ch.console.ShowPreviewer(ctx,
+		&input.ShowPreviewerOptions{
+			Prefix:       "  ",
+			MaxLineCount: 8,
+			Title:        "Remote Build Output",
+		})
+	remoteImage, err := ch.RemoteBuild(ctx, serviceConfig, previewerWriter)
+	ch.console.StopPreviewer(ctx, false)
+	if err != nil {
+		return "", fmt.Errorf("building container: %s: %w", serviceConfig.Name, err)
+	}
+
+	log.Printf("built and pushed image %s for %s", remoteImage, serviceConfig.Name)
+	packageDetails.TargetImage = remoteImage
+
+	if serviceConfig.Docker.Scan != nil {
+		task.SetProgress(NewServiceProgress("Scanning container image"))
+		if err := ch.Scan(ctx, serviceConfig, packageDetails); err != nil {
+			return "", err
+		}
+	}
+
+	return remoteImage, nil
+}
+
+// LoginCacheRegistries logs into the Azure container registries of the registry caches of a docker build, ex)
+// type=registry,ref=myregistry.azurecr.io/app:cache
+func (ch *ContainerHelper) LoginCacheRegistries(ctx context.Context, buildOptions *docker.BuildOptions) error {
+	loggedIn := map[string]bool{}
+
+	for _, cache := range append(slices.Clone(buildOptions.CacheFrom), buildOptions.CacheTo...) {
+		attributes := map[string]string{}
+		for _, attribute := range strings.Split(cache, ",") {
+			key, value, _ := strings.Cut(attribute, "=")
+			attributes[key] = value
+		}
+
+		if attributes["type"] != "registry" {
+			continue
+		}
+
+		registryName, _, _ := strings.Cut(attributes["ref"], "/")
+		if loggedIn[registryName] || !strings.HasSuffix(registryName, ch.cloud.ContainerRegistryEndpointSuffix) {
+			continue
+		}
+
+		log.Printf("logging into container registry '%s' of the build cache\n", registryName)
+		if err := ch.containerRegistryService.Login(ctx, ch.env.GetSubscriptionId(), registryName); err != nil {
+			return fmt.Errorf("logging into container registry '%s' of the build cache: %w", registryName, err)
+		}
+
+		loggedIn[registryName] = true
+	}
+
+	return nil
+}
+
 func (ch *ContainerHelper) Credentials(
 	ctx context.Context,
 	serviceConfig *ServiceConfig,
@@ -204,6 +435,20 @@ func (ch *ContainerHelper) Credentials(
 	return ch.containerRegistryService.Credentials(ctx, targetResource.SubscriptionId(), loginServer)
 }
 
+// RegistryCredentials gets the credentials for the specified container registry. Credentials are only available for ACR,
+// nil is returned for other registries which require a manual login.
+func (ch *ContainerHelper) RegistryCredentials(
+	ctx context.Context,
+	subscriptionId string,
+	loginServer string,
+) (*azcli.DockerCredentials, error) {
+	if !strings.HasSuffix(loginServer, ch.cloud.ContainerRegistryEndpointSuffix) {
+		return nil, nil
+	}
+
+	return ch.containerRegistryService.Credentials(ctx, subscriptionId, loginServer)
+}
+
 // Deploy pushes and image to a remote server, and optionally writes the fully qualified remote image name to the
 // environment on success.
 func (ch *ContainerHelper) Deploy(
@@ -238,6 +483,16 @@ func (ch *ContainerHelper) Deploy(
 			// then we are referencing a public/pre-existing image and don't have anything to tag or push
 			if registryName == "" && serviceConfig.RelativePath == "" && sourceImage != "" {
 				remoteImage = sourceImage
+			} else if packageDetails != nil && packageDetails.RemoteBuild && targetImage != "" {
+				// Images built remotely are already pushed to the registry by their build
+				remoteImage = targetImage
+			} else if packageDetails != nil && packageDetails.RemoteBuild {
+				// The remote build was deferred by the package, since the registry didn't exist yet
+				remoteImage, err = ch.deferredRemoteBuild(ctx, serviceConfig, packageDetails, task)
+				if err != nil {
+					task.SetError(err)
+					return
+				}
 			} else {
 				if targetImage == "" {
 					task.SetError(errors.New("failed retrieving package result details"))
@@ -267,11 +522,15 @@ func (ch *ContainerHelper) Deploy(
 					}
 
 					remoteImage = remoteImageWithTag
+					multiPlatform := ch.isMultiPlatformBuild(serviceConfig, packageDetails)
 
-					task.SetProgress(NewServiceProgress("Tagging container image"))
-					if err := ch.docker.Tag(ctx, serviceConfig.Path(), targetImage, remoteImage); err != nil {
-						task.SetError(err)
-						return
+					// A multi-platform image is pushed by its build instead of being tagged and pushed
+					if !multiPlatform {
+						task.SetProgress(NewServiceProgress("Tagging container image"))
+						if err := ch.docker.Tag(ctx, serviceConfig.Path(), targetImage, remoteImage); err != nil {
+							task.SetError(err)
+							return
+						}
 					}
 
 					log.Printf("logging into container registry '%s'\n", registryName)
@@ -285,8 +544,15 @@ func (ch *ContainerHelper) Deploy(
 
 					// Push image.
 					log.Printf("pushing %s to registry", remoteImage)
-					task.SetProgress(NewServiceProgress("Pushing container image"))
-					if err := ch.docker.Push(ctx, serviceConfig.Path(), remoteImage); err != nil {
+					if multiPlatform {
+						task.SetProgress(NewServiceProgress("Building and pushing multi-platform container image"))
+						err = ch.buildAndPush(ctx, serviceConfig, remoteImage)
+					} else {
+						task.SetProgress(NewServiceProgress("Pushing container image"))
+						err = ch.docker.Push(ctx, serviceConfig.Path(), remoteImage)
+					}
+
+					if err != nil {
 						errSuggestion := &azcli.ErrorWithSuggestion{
 							Err: err,
 							//nolint:lll
@@ -319,6 +585,200 @@ func (ch *ContainerHelper) Deploy(
 		})
 }
 
+// isMultiPlatformBuild returns true when the image of the service is built from a Dockerfile for multiple platforms
+func (ch *ContainerHelper) isMultiPlatformBuild(
+	serviceConfig *ServiceConfig,
+	packageDetails *dockerPackageResult,
+) bool {
+	dockerOptions := getDockerOptionsWithDefaults(serviceConfig.Docker)
+	if !docker.IsMultiPlatform(dockerOptions.Platform) || packageDetails == nil || packageDetails.SourceImage != "" {
+		return false
+	}
+
+	// Images built from source without a Dockerfile are only built for a single platform
+	_, err := os.Stat(dockerfilePath(serviceConfig, dockerOptions))
+	return err == nil
+}
+
+// buildAndPush builds the image of the service for every platform and pushes it to the registry, reusing the cache of
+// the local build
+func (ch *ContainerHelper) buildAndPush(ctx context.Context, serviceConfig *ServiceConfig, remoteImage string) error {
+	dockerOptions := getDockerOptionsWithDefaults(serviceConfig.Docker)
+	buildOptions, err := dockerBuildOptions(dockerOptions, ch.env)
+	if err != nil {
+		return err
+	}
+
+	if err := ch.LoginCacheRegistries(ctx, buildOptions); err != nil {
+		return err
+	}
+
+	buildOptions.Push = true
+	_, err = ch.docker.Build(
+		ctx,
+		serviceConfig.Path(),
+		dockerOptions.Path,
+		dockerOptions.Platform,
+		dockerOptions.Target,
+		dockerOptions.Context,
+		remoteImage,
+		dockerOptions.BuildArgs,
+		buildOptions,
+		nil,
+	)
+
+	return err
+}
+
+// scanCredentials gets the credentials of the registry of the image for the scanner, nil when the registry isn't an
+// Azure container registry, in which case the scanner relies on the credentials the user logged in with
+func (ch *ContainerHelper) scanCredentials(ctx context.Context, image string) (*imagescan.RegistryCredentials, error) {
+	containerImage, err := docker.ParseContainerImage(image)
+	if err != nil {
+		return nil, err
+	}
+
+	credentials, err := ch.RegistryCredentials(ctx, ch.env.GetSubscriptionId(), containerImage.Registry)
+	if err != nil {
+		return nil, fmt.Errorf("getting the credentials of container registry '%s' to scan image %s: %w",
+			containerImage.Registry, image, err)
+	}
+
+	if credentials == nil {
+		return nil, nil
+	}
+
+	return &imagescan.RegistryCredentials{
+		LoginServer: credentials.LoginServer,
+		Username:    credentials.Username,
+		Password:    credentials.Password,
+	}, nil
+}
+
 type dockerDeployResult struct {
 	RemoteImageTag string
 }
+
+// The number of vulnerabilities listed in the error or warning of a failed scan
+const maxListedVulnerabilities = 5
+
+// Scan scans the target image for vulnerabilities and generates its SBOM when 'docker.scan' is configured, recording the
+// results in the package details. The scan fails when vulnerabilities are found at or above the configured severity,
+// unless the scan only warns about them.
+// imageScanner returns the scanner configured in `docker.scan`, or nil when the images of the service aren't scanned.
+func imageScanner(serviceConfig *ServiceConfig, commandRunner exec.CommandRunner) (imagescan.Scanner, error) {
+	if serviceConfig.Docker.Scan == nil {
+		return nil, nil
+	}
+
+	scanner, err := imagescan.NewScanner(serviceConfig.Docker.Scan.Scanner, commandRunner)
+	if err != nil {
+		return nil, fmt.Errorf("invalid 'docker.scan.scanner': %w", err)
+	}
+
+	return scanner, nil
+}
+
+func (ch *ContainerHelper) Scan(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageDetails *dockerPackageResult,
+) error {
+	scanOptions := serviceConfig.Docker.Scan
+	if scanOptions == nil {
+		return nil
+	}
+
+	// The scanner is installed as one of the required tools of the service, see serviceManager.GetRequiredTools
+	scanner, err := imageScanner(serviceConfig, ch.commandRunner)
+	if err != nil {
+		return err
+	}
+
+	threshold := imagescan.SeverityHigh
+	if scanOptions.Severity != "" {
+		threshold, err = imagescan.ParseSeverity(scanOptions.Severity)
+		if err != nil {
+			return fmt.Errorf("invalid 'docker.scan.severity': %w", err)
+		}
+	}
+
+	image := packageDetails.TargetImage
+
+	// An image built remotely only exists in the container registry, which the scanner has to log into to pull it
+	if packageDetails.RemoteBuild {
+		credentials, err := ch.scanCredentials(ctx, image)
+		if err != nil {
+			return err
+		}
+
+		scanner.SetRegistryCredentials(credentials)
+	}
+
+	vulnerabilities, err := scanner.Scan(ctx, image)
+	if err != nil {
+		return err
+	}
+
+	// The SBOM is kept in the directory of the environment, ex) .azure/dev/sbom/api.cdx.json, and replaced on every scan
+	sbomDir := filepath.Join(serviceConfig.Project.Path, azdcontext.EnvironmentDirectoryName, ch.env.Name(), "sbom")
+	if err := os.MkdirAll(sbomDir, osutil.PermissionDirectory); err != nil {
+		return fmt.Errorf("creating SBOM directory: %w", err)
+	}
+
+	sbomPath := filepath.Join(sbomDir, fmt.Sprintf("%s.cdx.json", serviceConfig.Name))
+	if err := scanner.GenerateSbom(ctx, image, sbomPath); err != nil {
+		return err
+	}
+	log.Printf("generated SBOM of image %s for %s at %s", image, serviceConfig.Name, sbomPath)
+
+	result := &dockerScanResult{
+		Scanner:         scanner.Name(),
+		Severity:        threshold,
+		Counts:          map[imagescan.Severity]int{},
+		Vulnerabilities: []imagescan.Vulnerability{},
+		SbomPath:        sbomPath,
+	}
+	for _, vulnerability := range vulnerabilities {
+		result.Counts[vulnerability.Severity]++
+		if vulnerability.Severity.AtLeast(threshold) {
+			result.Vulnerabilities = append(result.Vulnerabilities, vulnerability)
+		}
+	}
+
+	result.Passed = len(result.Vulnerabilities) == 0
+	packageDetails.Scan = result
+	log.Printf("scanned image %s for %s, vulnerabilities: %v", image, serviceConfig.Name, result.Counts)
+
+	if result.Passed {
+		return nil
+	}
+
+	ids := []string{}
+	for _, vulnerability := range result.Vulnerabilities[:min(len(result.Vulnerabilities), maxListedVulnerabilities)] {
+		ids = append(ids, fmt.Sprintf("%s (%s)", vulnerability.Id, vulnerability.Package))
+	}
+	if len(result.Vulnerabilities) > maxListedVulnerabilities {
+		ids = append(ids, "...")
+	}
+
+	message := fmt.Sprintf(
+		"%s found %d vulnerabilities with a severity of %s or higher in image %s: %s",
+		scanner.Name(),
+		len(result.Vulnerabilities),
+		threshold,
+		image,
+		strings.Join(ids, ", "),
+	)
+
+	if scanOptions.WarnOnly {
+		ch.console.MessageUxItem(ctx, &ux.WarningMessage{Description: message})
+		return nil
+	}
+
+	return &azcli.ErrorWithSuggestion{
+		Err: errors.New(message),
+		Suggestion: "Suggested action: update the vulnerable packages of the image, or set 'docker.scan.warnOnly' to true in " +
+			"azure.yaml to deploy it with a warning.",
+	}
+}
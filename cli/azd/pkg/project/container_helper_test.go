@@ -1,9 +1,15 @@
 package project
 
 import (
+	"archive/tar"
+	"compress/gzip"
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
 
@@ -58,7 +64,7 @@ func Test_ContainerHelper_LocalImageTag(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			env := environment.NewWithValues("dev", map[string]string{})
-			containerHelper := NewContainerHelper(env, nil, clock.NewMock(), nil, nil, cloud.AzurePublic())
+			containerHelper := NewContainerHelper(env, nil, clock.NewMock(), nil, nil, cloud.AzurePublic(), nil, nil)
 			serviceConfig.Docker = tt.dockerConfig
 
 			tag, err := containerHelper.LocalImageTag(*mockContext.Context, serviceConfig)
@@ -107,7 +113,7 @@ func Test_ContainerHelper_RemoteImageTag(t *testing.T) {
 
 	mockContext := mocks.NewMockContext(context.Background())
 	env := environment.NewWithValues("dev", map[string]string{})
-	containerHelper := NewContainerHelper(env, nil, clock.NewMock(), nil, nil, cloud.AzurePublic())
+	containerHelper := NewContainerHelper(env, nil, clock.NewMock(), nil, nil, cloud.AzurePublic(), nil, nil)
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -134,7 +140,7 @@ func Test_ContainerHelper_Resolve_RegistryName(t *testing.T) {
 			environment.ContainerRegistryEndpointEnvVarName: "contoso.azurecr.io",
 		})
 		envManager := &mockenv.MockEnvManager{}
-		containerHelper := NewContainerHelper(env, envManager, clock.NewMock(), nil, nil, cloud.AzurePublic())
+		containerHelper := NewContainerHelper(env, envManager, clock.NewMock(), nil, nil, cloud.AzurePublic(), nil, nil)
 		serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
 		registryName, err := containerHelper.RegistryName(*mockContext.Context, serviceConfig)
 
@@ -146,7 +152,7 @@ func Test_ContainerHelper_Resolve_RegistryName(t *testing.T) {
 		mockContext := mocks.NewMockContext(context.Background())
 		env := environment.NewWithValues("dev", map[string]string{})
 		envManager := &mockenv.MockEnvManager{}
-		containerHelper := NewContainerHelper(env, envManager, clock.NewMock(), nil, nil, cloud.AzurePublic())
+		containerHelper := NewContainerHelper(env, envManager, clock.NewMock(), nil, nil, cloud.AzurePublic(), nil, nil)
 		serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
 		serviceConfig.Docker.Registry = osutil.NewExpandableString("contoso.azurecr.io")
 		registryName, err := containerHelper.RegistryName(*mockContext.Context, serviceConfig)
@@ -160,7 +166,7 @@ func Test_ContainerHelper_Resolve_RegistryName(t *testing.T) {
 		env := environment.NewWithValues("dev", map[string]string{})
 		env.DotenvSet("MY_CUSTOM_REGISTRY", "custom.azurecr.io")
 		envManager := &mockenv.MockEnvManager{}
-		containerHelper := NewContainerHelper(env, envManager, clock.NewMock(), nil, nil, cloud.AzurePublic())
+		containerHelper := NewContainerHelper(env, envManager, clock.NewMock(), nil, nil, cloud.AzurePublic(), nil, nil)
 		serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
 		serviceConfig.Docker.Registry = osutil.NewExpandableString("${MY_CUSTOM_REGISTRY}")
 		registryName, err := containerHelper.RegistryName(*mockContext.Context, serviceConfig)
@@ -169,11 +175,31 @@ func Test_ContainerHelper_Resolve_RegistryName(t *testing.T) {
 		require.Equal(t, "custom.azurecr.io", registryName)
 	})
 
+	t.Run("Local cluster", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		env := environment.NewWithValues("dev", map[string]string{
+			environment.ContainerRegistryEndpointEnvVarName: "contoso.azurecr.io",
+		})
+		envManager := &mockenv.MockEnvManager{}
+		containerHelper := NewContainerHelper(env, envManager, clock.NewMock(), nil, nil, cloud.AzurePublic(), nil, nil)
+		serviceConfig := createTestServiceConfig("./src/api", AksTarget, ServiceLanguageTypeScript)
+		serviceConfig.K8s.Cluster = AksClusterLocal
+
+		registryName, err := containerHelper.RegistryName(*mockContext.Context, serviceConfig)
+		require.NoError(t, err)
+		require.Equal(t, "localhost:5000", registryName)
+
+		serviceConfig.Docker.Registry = osutil.NewExpandableString("localhost:5001")
+		registryName, err = containerHelper.RegistryName(*mockContext.Context, serviceConfig)
+		require.NoError(t, err)
+		require.Equal(t, "localhost:5001", registryName)
+	})
+
 	t.Run("No registry name", func(t *testing.T) {
 		mockContext := mocks.NewMockContext(context.Background())
 		env := environment.NewWithValues("dev", map[string]string{})
 		envManager := &mockenv.MockEnvManager{}
-		containerHelper := NewContainerHelper(env, envManager, clock.NewMock(), nil, nil, cloud.AzurePublic())
+		containerHelper := NewContainerHelper(env, envManager, clock.NewMock(), nil, nil, cloud.AzurePublic(), nil, nil)
 		serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
 		registryName, err := containerHelper.RegistryName(*mockContext.Context, serviceConfig)
 
@@ -343,6 +369,8 @@ func Test_ContainerHelper_Deploy(t *testing.T) {
 				mockContainerRegistryService,
 				dockerCli,
 				cloud.AzurePublic(),
+				nil,
+				nil,
 			)
 			serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
 
@@ -399,10 +427,384 @@ func Test_ContainerHelper_Deploy(t *testing.T) {
 	}
 }
 
+func Test_ContainerHelper_Deploy_MultiPlatform(t *testing.T) {
+	var buildArgs exec.RunArgs
+
+	mockContext := mocks.NewMockContext(context.Background())
+	mockResults := setupDockerMocks(mockContext)
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "docker buildx build")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		buildArgs = args
+
+		err := os.WriteFile(args.Args[len(args.Args)-1], []byte("IMAGE_ID"), 0600)
+		require.NoError(t, err)
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	env := environment.NewWithValues("dev", map[string]string{})
+	envManager := &mockenv.MockEnvManager{}
+	envManager.On("Save", *mockContext.Context, env).Return(nil)
+
+	mockContainerRegistryService := &mockContainerRegistryService{}
+	setupContainerRegistryMocks(mockContext, &mockContainerRegistryService.Mock)
+
+	containerHelper := NewContainerHelper(
+		env,
+		envManager,
+		clock.NewMock(),
+		mockContainerRegistryService,
+		docker.NewDocker(mockContext.CommandRunner),
+		cloud.AzurePublic(),
+		nil,
+		nil,
+	)
+
+	serviceConfig := createTestServiceConfig(t.TempDir(), ContainerAppTarget, ServiceLanguageTypeScript)
+	serviceConfig.Docker.Registry = osutil.NewExpandableString("contoso.azurecr.io")
+	serviceConfig.Docker.Platform = "linux/amd64,linux/arm64"
+	err := os.WriteFile(filepath.Join(serviceConfig.Path(), "Dockerfile"), []byte("FROM node:20"), 0600)
+	require.NoError(t, err)
+
+	packageOutput := &ServicePackageResult{
+		Details: &dockerPackageResult{
+			ImageHash:   "IMAGE_ID",
+			TargetImage: "my-project/my-service:azd-deploy-0",
+		},
+	}
+
+	deployTask := containerHelper.Deploy(*mockContext.Context, serviceConfig, packageOutput, nil, true)
+	logProgress(deployTask)
+	_, err = deployTask.Await()
+	require.NoError(t, err)
+
+	// The image of every platform is built and pushed instead of tagging and pushing the local image
+	_, dockerTagCalled := mockResults["docker-tag"]
+	_, dockerPushCalled := mockResults["docker-push"]
+	require.False(t, dockerTagCalled)
+	require.False(t, dockerPushCalled)
+	require.Equal(t, []string{
+		"buildx", "build",
+		"--builder", "azd",
+		"-f", "./Dockerfile",
+		"--platform", "linux/amd64,linux/arm64",
+		"-t", "contoso.azurecr.io/my-project/my-service:azd-deploy-0",
+		"--push",
+		".",
+	}, buildArgs.Args[:len(buildArgs.Args)-2])
+	require.Equal(
+		t,
+		"contoso.azurecr.io/my-project/my-service:azd-deploy-0",
+		env.GetServiceProperty("api", "IMAGE_NAME"),
+	)
+}
+
+func Test_ContainerHelper_LoginCacheRegistries(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	env := environment.NewWithValues("dev", map[string]string{})
+
+	mockContainerRegistryService := &mockContainerRegistryService{}
+	setupContainerRegistryMocks(mockContext, &mockContainerRegistryService.Mock)
+
+	containerHelper := NewContainerHelper(
+		env, nil, clock.NewMock(), mockContainerRegistryService, nil, cloud.AzurePublic(), nil, nil)
+
+	err := containerHelper.LoginCacheRegistries(*mockContext.Context, &docker.BuildOptions{
+		CacheFrom: []string{
+			"type=registry,ref=contoso.azurecr.io/api:cache",
+			"type=registry,ref=docker.io/library/api:cache",
+			"type=gha",
+		},
+		CacheTo: []string{"type=registry,ref=contoso.azurecr.io/api:cache,mode=max"},
+	})
+	require.NoError(t, err)
+
+	// Only the Azure container registries are logged into, once each
+	mockContainerRegistryService.AssertNumberOfCalls(t, "Login", 1)
+	mockContainerRegistryService.AssertCalled(
+		t,
+		"Login",
+		*mockContext.Context,
+		env.GetSubscriptionId(),
+		"contoso.azurecr.io",
+	)
+}
+
+func Test_ContainerHelper_RemoteBuild(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	env := environment.NewWithValues("dev", map[string]string{
+		environment.ContainerRegistryEndpointEnvVarName: "contoso.azurecr.io",
+	})
+
+	var sourcePath string
+	var sourceFiles []string
+	mockContainerRegistryService := &mockContainerRegistryService{}
+	mockContainerRegistryService.On(
+		"RemoteBuild",
+		*mockContext.Context,
+		env.GetSubscriptionId(),
+		"contoso.azurecr.io",
+		mock.AnythingOfType("string"),
+		&azcli.RemoteBuildOptions{
+			DockerfilePath: "api/Dockerfile",
+			Platform:       docker.DefaultPlatform,
+			BuildArgs:      []string{"VERSION=1.0"},
+			ImageNames:     []string{"test-app/api-dev:azd-deploy-0"},
+		},
+		io.Discard,
+	).Run(func(args mock.Arguments) {
+		sourcePath = args.String(3)
+		sourceFiles = readTarGzNames(t, sourcePath)
+	}).Return(nil)
+
+	containerHelper := NewContainerHelper(
+		env, nil, clock.NewMock(), mockContainerRegistryService, nil, cloud.AzurePublic(), nil, nil)
+
+	// The build context is the parent directory of the service
+	root := t.TempDir()
+	serviceConfig := createTestServiceConfig(filepath.Join(root, "api"), ContainerAppTarget, ServiceLanguageTypeScript)
+	serviceConfig.Docker.Context = ".."
+	serviceConfig.Docker.BuildArgs = []string{"VERSION=1.0"}
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "api"), osutil.PermissionDirectory))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "api", "Dockerfile"), []byte("FROM node:20"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "package.json"), []byte("{}"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "README.md"), []byte("# api"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "NOTES.md"), []byte("notes"), 0600))
+
+	// The ignored files, and the environments of the project even when they aren't ignored, aren't uploaded
+	require.NoError(t, os.WriteFile(
+		filepath.Join(root, ".dockerignore"), []byte("node_modules\n.git\n*.md\n!README.md\napi/Dockerfile\n"), 0600))
+	for _, ignored := range []string{"node_modules/lib/index.js", ".git/config", ".azure/dev/.env"} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(root, ignored)), osutil.PermissionDirectory))
+		require.NoError(t, os.WriteFile(filepath.Join(root, ignored), []byte("SECRET=1"), 0600))
+	}
+
+	remoteImage, err := containerHelper.RemoteBuild(*mockContext.Context, serviceConfig, io.Discard)
+	require.NoError(t, err)
+	require.Equal(t, "contoso.azurecr.io/test-app/api-dev:azd-deploy-0", remoteImage)
+	// Like docker, the Dockerfile and the .dockerignore file are uploaded even when they're ignored
+	require.ElementsMatch(t, []string{"api/Dockerfile", "package.json", "README.md", ".dockerignore"}, sourceFiles)
+
+	// The archive of the build context is removed after the build
+	_, err = os.Stat(sourcePath)
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	t.Run("UnsupportedOptions", func(t *testing.T) {
+		serviceConfig.Docker.Platform = "linux/amd64,linux/arm64"
+		_, err := containerHelper.RemoteBuild(*mockContext.Context, serviceConfig, io.Discard)
+		require.ErrorContains(t, err, "multiple platforms")
+
+		serviceConfig.Docker.Platform = ""
+		serviceConfig.Docker.Secrets = map[string]string{"npmrc": "NPM_TOKEN"}
+		_, err = containerHelper.RemoteBuild(*mockContext.Context, serviceConfig, io.Discard)
+		require.ErrorContains(t, err, "aren't supported by 'docker.remoteBuild'")
+	})
+}
+
+func Test_ContainerHelper_Deploy_RemoteBuild(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	mockResults := setupDockerMocks(mockContext)
+
+	env := environment.NewWithValues("dev", map[string]string{})
+	envManager := &mockenv.MockEnvManager{}
+	envManager.On("Save", *mockContext.Context, env).Return(nil)
+
+	mockContainerRegistryService := &mockContainerRegistryService{}
+	setupContainerRegistryMocks(mockContext, &mockContainerRegistryService.Mock)
+
+	containerHelper := NewContainerHelper(
+		env,
+		envManager,
+		clock.NewMock(),
+		mockContainerRegistryService,
+		docker.NewDocker(mockContext.CommandRunner),
+		cloud.AzurePublic(),
+		nil,
+		nil,
+	)
+
+	serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+	serviceConfig.Docker.Registry = osutil.NewExpandableString("contoso.azurecr.io")
+	serviceConfig.Docker.RemoteBuild = true
+
+	packageOutput := &ServicePackageResult{
+		Details: &dockerPackageResult{
+			TargetImage: "contoso.azurecr.io/test-app/api-dev:azd-deploy-0",
+			RemoteBuild: true,
+		},
+	}
+
+	deployTask := containerHelper.Deploy(*mockContext.Context, serviceConfig, packageOutput, nil, true)
+	logProgress(deployTask)
+	_, err := deployTask.Await()
+	require.NoError(t, err)
+
+	// The image pushed by the remote build is deployed without docker
+	require.Empty(t, mockResults)
+	mockContainerRegistryService.AssertNotCalled(t, "Login")
+	require.Equal(
+		t,
+		"contoso.azurecr.io/test-app/api-dev:azd-deploy-0",
+		env.GetServiceProperty("api", "IMAGE_NAME"),
+	)
+}
+
+func Test_ContainerHelper_Deploy_DeferredRemoteBuild(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	mockResults := setupDockerMocks(mockContext)
+
+	// The registry is provisioned after the package, ex) azd up of a new environment
+	env := environment.NewWithValues("dev", map[string]string{
+		environment.ContainerRegistryEndpointEnvVarName: "contoso.azurecr.io",
+	})
+	envManager := &mockenv.MockEnvManager{}
+	envManager.On("Save", *mockContext.Context, env).Return(nil)
+
+	mockContainerRegistryService := &mockContainerRegistryService{}
+	mockContainerRegistryService.On(
+		"RemoteBuild",
+		*mockContext.Context,
+		env.GetSubscriptionId(),
+		"contoso.azurecr.io",
+		mock.AnythingOfType("string"),
+		mock.AnythingOfType("*azcli.RemoteBuildOptions"),
+		mock.Anything,
+	).Return(nil)
+
+	containerHelper := NewContainerHelper(
+		env,
+		envManager,
+		clock.NewMock(),
+		mockContainerRegistryService,
+		docker.NewDocker(mockContext.CommandRunner),
+		cloud.AzurePublic(),
+		mockContext.CommandRunner,
+		mockContext.Console,
+	)
+
+	serviceConfig := createTestServiceConfig(t.TempDir(), ContainerAppTarget, ServiceLanguageTypeScript)
+	serviceConfig.Docker.RemoteBuild = true
+	require.NoError(t, os.WriteFile(filepath.Join(serviceConfig.Path(), "Dockerfile"), []byte("FROM node:20"), 0600))
+
+	packageDetails := &dockerPackageResult{RemoteBuild: true}
+	packageOutput := &ServicePackageResult{Details: packageDetails}
+
+	deployTask := containerHelper.Deploy(*mockContext.Context, serviceConfig, packageOutput, nil, true)
+	logProgress(deployTask)
+	_, err := deployTask.Await()
+	require.NoError(t, err)
+
+	// The image is built remotely by the deploy, instead of the package
+	require.Empty(t, mockResults)
+	mockContainerRegistryService.AssertNumberOfCalls(t, "RemoteBuild", 1)
+	require.Equal(t, "contoso.azurecr.io/test-app/api-dev:azd-deploy-0", packageDetails.TargetImage)
+	require.Equal(
+		t,
+		"contoso.azurecr.io/test-app/api-dev:azd-deploy-0",
+		env.GetServiceProperty("api", "IMAGE_NAME"),
+	)
+}
+
+func Test_ContainerHelper_Scan_RemoteBuild(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake scanner on the PATH is a shell script")
+	}
+
+	binDir := t.TempDir()
+	err := os.WriteFile(filepath.Join(binDir, "trivy"), []byte("#!/bin/sh\n"), osutil.PermissionExecutableFile)
+	require.NoError(t, err)
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	scanEnvs := [][]string{}
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "trivy --version")
+	}).Respond(exec.NewRunResult(0, "Version: 0.48.0", ""))
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "trivy image")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		scanEnvs = append(scanEnvs, args.Env)
+		return exec.NewRunResult(0, "{}", ""), nil
+	})
+
+	env := environment.NewWithValues("dev", map[string]string{
+		environment.SubscriptionIdEnvVarName: "SUBSCRIPTION_ID",
+	})
+	mockContainerRegistryService := &mockContainerRegistryService{}
+	mockContainerRegistryService.On(
+		"Credentials",
+		*mockContext.Context,
+		"SUBSCRIPTION_ID",
+		"contoso.azurecr.io",
+	).Return(&azcli.DockerCredentials{
+		Username:    "00000000-0000-0000-0000-000000000000",
+		Password:    "REFRESH_TOKEN",
+		LoginServer: "contoso.azurecr.io",
+	}, nil)
+
+	containerHelper := NewContainerHelper(
+		env, nil, clock.NewMock(), mockContainerRegistryService, nil, cloud.AzurePublic(), mockContext.CommandRunner,
+		mockContext.Console)
+
+	serviceConfig := createTestServiceConfig(t.TempDir(), ContainerAppTarget, ServiceLanguageTypeScript)
+	serviceConfig.Project.Path = t.TempDir()
+	serviceConfig.Docker.Scan = &DockerScanOptions{}
+
+	// The image built remotely is only in the registry, the scanner pulls it with the credentials of the registry
+	err = containerHelper.Scan(*mockContext.Context, serviceConfig, &dockerPackageResult{
+		RemoteBuild: true,
+		TargetImage: "contoso.azurecr.io/test-app/api-dev:azd-deploy-0",
+	})
+	require.NoError(t, err)
+	// The image is scanned and its SBOM generated
+	require.Len(t, scanEnvs, 2)
+	for _, scanEnv := range scanEnvs {
+		require.Equal(t, []string{
+			"TRIVY_USERNAME=00000000-0000-0000-0000-000000000000",
+			"TRIVY_PASSWORD=REFRESH_TOKEN",
+		}, scanEnv)
+	}
+
+	// A local image is scanned from the local image store
+	scanEnvs = [][]string{}
+	err = containerHelper.Scan(*mockContext.Context, serviceConfig, &dockerPackageResult{
+		TargetImage: "test-app/api-dev:azd-deploy-0",
+	})
+	require.NoError(t, err)
+	require.Len(t, scanEnvs, 2)
+	for _, scanEnv := range scanEnvs {
+		require.Empty(t, scanEnv)
+	}
+	mockContainerRegistryService.AssertNumberOfCalls(t, "Credentials", 1)
+}
+
+// readTarGzNames returns the names of the files in a gzipped tarball
+func readTarGzNames(t *testing.T, path string) []string {
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	gr, err := gzip.NewReader(file)
+	require.NoError(t, err)
+
+	names := []string{}
+	tr := tar.NewReader(gr)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		names = append(names, header.Name)
+	}
+
+	return names
+}
+
 func Test_ContainerHelper_ConfiguredImage(t *testing.T) {
 	mockContext := mocks.NewMockContext(context.Background())
 	env := environment.NewWithValues("dev", map[string]string{})
-	containerHelper := NewContainerHelper(env, nil, clock.NewMock(), nil, nil, cloud.AzurePublic())
+	containerHelper := NewContainerHelper(env, nil, clock.NewMock(), nil, nil, cloud.AzurePublic(), nil, nil)
 
 	tests := []struct {
 		name                 string
@@ -550,6 +952,13 @@ func setupContainerRegistryMocks(mockContext *mocks.MockContext, mockContainerRe
 func setupDockerMocks(mockContext *mocks.MockContext) map[string]exec.RunArgs {
 	mockResults := map[string]exec.RunArgs{}
 
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "docker buildx inspect")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		mockResults["docker-buildx-inspect"] = args
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
 	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
 		return strings.Contains(command, "docker tag")
 	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
@@ -621,3 +1030,15 @@ func (m *mockContainerRegistryService) GetContainerRegistries(
 	args := m.Called(ctx, subscriptionId)
 	return args.Get(0).([]*armcontainerregistry.Registry), args.Error(1)
 }
+
+func (m *mockContainerRegistryService) RemoteBuild(
+	ctx context.Context,
+	subscriptionId string,
+	loginServer string,
+	sourcePath string,
+	options *azcli.RemoteBuildOptions,
+	logs io.Writer,
+) error {
+	args := m.Called(ctx, subscriptionId, loginServer, sourcePath, options, logs)
+	return args.Error(0)
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/lazy"
 	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/dotnet"
 	"github.com/psanford/memfs"
 )
@@ -100,11 +101,20 @@ func (ai *DotNetImporter) ProjectInfrastructure(ctx context.Context, svcConfig *
 		return nil, fmt.Errorf("generating app host manifest: %w", err)
 	}
 
-	files, err := apphost.BicepTemplate(manifest)
+	var files fs.FS
+	if svcConfig.Host == AksTarget {
+		files, err = apphost.KubernetesBicepTemplate(manifest)
+	} else {
+		files, err = apphost.BicepTemplate(manifest)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("generating bicep from manifest: %w", err)
 	}
 
+	if err := ai.showWarnings(ctx, manifest); err != nil {
+		return nil, err
+	}
+
 	tmpDir, err := os.MkdirTemp("", "azd-infra")
 	if err != nil {
 		return nil, fmt.Errorf("creating temporary directory: %w", err)
@@ -183,7 +193,8 @@ func (ai *DotNetImporter) Services(
 		svc := &ServiceConfig{
 			RelativePath: relPath,
 			Language:     ServiceLanguageDotNet,
-			Host:         DotNetContainerAppTarget,
+			Host:         appHostServiceTarget(svcConfig),
+			K8s:          appHostK8sOptions(svcConfig),
 		}
 
 		svc.Name = name
@@ -215,7 +226,8 @@ func (ai *DotNetImporter) Services(
 		svc := &ServiceConfig{
 			RelativePath: relPath,
 			Language:     ServiceLanguageDocker,
-			Host:         DotNetContainerAppTarget,
+			Host:         appHostServiceTarget(svcConfig),
+			K8s:          appHostK8sOptions(svcConfig),
 			Docker: DockerProjectOptions{
 				Path:      dockerfile.Path,
 				Context:   dockerfile.Context,
@@ -243,8 +255,36 @@ func (ai *DotNetImporter) Services(
 	return services, nil
 }
 
+// appHostServiceTarget returns the service target of the services imported from the given app host service.
+func appHostServiceTarget(appHostConfig *ServiceConfig) ServiceTargetKind {
+	if appHostConfig.Host == AksTarget {
+		return DotNetAksTarget
+	}
+
+	return DotNetContainerAppTarget
+}
+
+// The k8s service account the pods of the services of an app host run as, which the generated k8s manifests and Helm
+// values of resources/apphost reference
+const appHostServiceAccountName = "azd-workload-identity"
+
+// appHostK8sOptions returns the AKS options of the services imported from the given app host service. The namespace and
+// the format of the generated resources are shared by all the services, while the names of the k8s resources are not.
+// The services share a service account federated with the managed identity of the generated infrastructure, which is
+// done when they're deployed since the namespace, and thus the subject of the federated credential, is only known then.
+func appHostK8sOptions(appHostConfig *ServiceConfig) AksOptions {
+	return AksOptions{
+		Namespace:    appHostConfig.K8s.Namespace,
+		AspireFormat: appHostConfig.K8s.AspireFormat,
+		Identity: &AksIdentityOptions{
+			Name:           osutil.NewExpandableString("${MANAGED_IDENTITY_NAME}"),
+			ServiceAccount: appHostServiceAccountName,
+		},
+	}
+}
+
 func (ai *DotNetImporter) SynthAllInfrastructure(
-	ctx context.Context, p *ProjectConfig, svcConfig *ServiceConfig,
+	ctx context.Context, p *ProjectConfig, svcConfig *ServiceConfig, provider provisioning.ProviderKind,
 ) (fs.FS, error) {
 	manifest, err := ai.ReadManifest(ctx, svcConfig)
 	if err != nil {
@@ -253,11 +293,24 @@ func (ai *DotNetImporter) SynthAllInfrastructure(
 
 	generatedFS := memfs.New()
 
-	infraFS, err := apphost.BicepTemplate(manifest)
+	var infraFS fs.FS
+	if provider == provisioning.Terraform && svcConfig.Host == AksTarget {
+		return nil, fmt.Errorf("generating terraform is not supported for Aspire services deployed to AKS. Use bicep instead")
+	} else if provider == provisioning.Terraform {
+		infraFS, err = apphost.TerraformTemplate(manifest)
+	} else if svcConfig.Host == AksTarget {
+		infraFS, err = apphost.KubernetesBicepTemplate(manifest)
+	} else {
+		infraFS, err = apphost.BicepTemplate(manifest)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("generating infra/ folder: %w", err)
 	}
 
+	if err := ai.showWarnings(ctx, manifest); err != nil {
+		return nil, err
+	}
+
 	err = fs.WalkDir(infraFS, ".", func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
@@ -293,13 +346,9 @@ func (ai *DotNetImporter) SynthAllInfrastructure(
 
 	// writeManifestForResource writes the containerApp.tmpl.yaml for the given resource to the generated filesystem. The
 	// manifest is written to a file name "containerApp.tmpl.yaml" in the same directory as the project that produces the
-	// container we will deploy.
+	// container we will deploy. When the app host is deployed to AKS, the "k8s.tmpl.yaml" manifest or the Helm chart (under
+	// "chart") are written to that directory instead.
 	writeManifestForResource := func(name string, path string) error {
-		containerAppManifest, err := apphost.ContainerAppManifestTemplateForProject(manifest, name)
-		if err != nil {
-			return fmt.Errorf("generating containerApp.tmpl.yaml for resource %s: %w", name, err)
-		}
-
 		normalPath, err := filepath.EvalSymlinks(path)
 		if err != nil {
 			return err
@@ -310,13 +359,52 @@ func (ai *DotNetImporter) SynthAllInfrastructure(
 			return err
 		}
 
-		manifestPath := filepath.Join(filepath.Dir(projectRelPath), "manifests", "containerApp.tmpl.yaml")
+		manifestsPath := filepath.Join(filepath.Dir(projectRelPath), "manifests")
+
+		if svcConfig.Host == AksTarget && svcConfig.K8s.AspireFormat == AksAspireFormatHelm {
+			chart, err := apphost.HelmChartForProject(manifest, name)
+			if err != nil {
+				return fmt.Errorf("generating helm chart for resource %s: %w", name, err)
+			}
+
+			return fs.WalkDir(chart, ".", func(path string, d fs.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+
+				if d.IsDir() {
+					return generatedFS.MkdirAll(
+						filepath.Join(manifestsPath, "chart", path), osutil.PermissionDirectoryOwnerOnly)
+				}
+
+				contents, err := fs.ReadFile(chart, path)
+				if err != nil {
+					return err
+				}
+
+				return generatedFS.WriteFile(
+					filepath.Join(manifestsPath, "chart", path), contents, osutil.PermissionFileOwnerOnly)
+			})
+		}
+
+		manifestName := "containerApp.tmpl.yaml"
+		generate := apphost.ContainerAppManifestTemplateForProject
+		if svcConfig.Host == AksTarget {
+			manifestName = "k8s.tmpl.yaml"
+			generate = apphost.KubernetesManifestTemplateForProject
+		}
 
-		if err := generatedFS.MkdirAll(filepath.Dir(manifestPath), osutil.PermissionDirectoryOwnerOnly); err != nil {
+		contents, err := generate(manifest, name)
+		if err != nil {
+			return fmt.Errorf("generating %s for resource %s: %w", manifestName, name, err)
+		}
+
+		if err := generatedFS.MkdirAll(manifestsPath, osutil.PermissionDirectoryOwnerOnly); err != nil {
 			return err
 		}
 
-		return generatedFS.WriteFile(manifestPath, []byte(containerAppManifest), osutil.PermissionFileOwnerOnly)
+		return generatedFS.WriteFile(
+			filepath.Join(manifestsPath, manifestName), []byte(contents), osutil.PermissionFileOwnerOnly)
 	}
 
 	for name, path := range apphost.ProjectPaths(manifest) {
@@ -334,6 +422,20 @@ func (ai *DotNetImporter) SynthAllInfrastructure(
 	return generatedFS, nil
 }
 
+// showWarnings displays a warning for each resource of the manifest that is not generated as the manifest describes.
+func (ai *DotNetImporter) showWarnings(ctx context.Context, manifest *apphost.Manifest) error {
+	warnings, err := apphost.Warnings(manifest)
+	if err != nil {
+		return err
+	}
+
+	for _, warning := range warnings {
+		ai.console.Message(ctx, output.WithWarningFormat("WARNING: %s", warning))
+	}
+
+	return nil
+}
+
 // ReadManifest reads the manifest for the given app host service, and caches the result.
 func (ai *DotNetImporter) ReadManifest(ctx context.Context, svcConfig *ServiceConfig) (*apphost.Manifest, error) {
 	ai.cacheMu.Lock()
@@ -8,10 +8,12 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"path"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/azure/azure-dev/cli/azd/internal/appdetect"
@@ -28,8 +30,10 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/imagescan"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/pack"
 	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/exp/maps"
 )
 
 type DockerProjectOptions struct {
@@ -41,6 +45,28 @@ type DockerProjectOptions struct {
 	Image     osutil.ExpandableString `yaml:"image,omitempty"     json:"image,omitempty"`
 	Tag       osutil.ExpandableString `yaml:"tag,omitempty"       json:"tag,omitempty"`
 	BuildArgs []string                `yaml:"buildArgs,omitempty" json:"buildArgs,omitempty"`
+	// The external cache sources of the build, ex) type=registry,ref=${AZURE_CONTAINER_REGISTRY_ENDPOINT}/app:cache
+	CacheFrom []osutil.ExpandableString `yaml:"cacheFrom,omitempty" json:"cacheFrom,omitempty"`
+	// The cache export destinations of the build, ex) type=registry,ref=${AZURE_CONTAINER_REGISTRY_ENDPOINT}/app:cache
+	CacheTo []osutil.ExpandableString `yaml:"cacheTo,omitempty" json:"cacheTo,omitempty"`
+	// The BuildKit secrets of the build, mapping the id of each secret to the azd environment variable holding its value
+	Secrets map[string]string `yaml:"secrets,omitempty" json:"secrets,omitempty"`
+	// The labels of the image
+	Labels map[string]osutil.ExpandableString `yaml:"labels,omitempty" json:"labels,omitempty"`
+	// Whether the image is built and pushed by ACR Tasks instead of a local docker build
+	RemoteBuild bool `yaml:"remoteBuild,omitempty" json:"remoteBuild,omitempty"`
+	// The vulnerability scan of the image after it's packaged, the image isn't scanned when not set
+	Scan *DockerScanOptions `yaml:"scan,omitempty" json:"scan,omitempty"`
+}
+
+// DockerScanOptions configures the vulnerability scan and the SBOM generation of the image of a service
+type DockerScanOptions struct {
+	// The scanner, trivy (default) or grype
+	Scanner string `yaml:"scanner,omitempty" json:"scanner,omitempty"`
+	// The lowest severity of the vulnerabilities that fail the scan: low, medium, high (default) or critical
+	Severity string `yaml:"severity,omitempty" json:"severity,omitempty"`
+	// Whether vulnerabilities at or above the severity are reported as a warning instead of failing the package
+	WarnOnly bool `yaml:"warnOnly,omitempty" json:"warnOnly,omitempty"`
 }
 
 type dockerBuildResult struct {
@@ -68,6 +94,26 @@ type dockerPackageResult struct {
 	SourceImage string `json:"sourceImage"`
 	// The target image with tag that is used for publishing and deployment when targeting a container registry
 	TargetImage string `json:"targetImage"`
+	// Whether the target image was built remotely and already pushed to the container registry
+	RemoteBuild bool `json:"remoteBuild,omitempty"`
+	// The vulnerability scan of the target image, when enabled with 'docker.scan'
+	Scan *dockerScanResult `json:"scan,omitempty"`
+}
+
+// The results of the vulnerability scan of an image
+type dockerScanResult struct {
+	// The name of the scanner, ex) Trivy
+	Scanner string `json:"scanner"`
+	// The lowest severity of the vulnerabilities that fail the scan
+	Severity imagescan.Severity `json:"severity"`
+	// Whether no vulnerabilities were found at or above the severity
+	Passed bool `json:"passed"`
+	// The number of vulnerabilities found of each severity
+	Counts map[imagescan.Severity]int `json:"counts"`
+	// The vulnerabilities found at or above the severity
+	Vulnerabilities []imagescan.Vulnerability `json:"vulnerabilities"`
+	// The path of the SBOM of the image, in the CycloneDX JSON format
+	SbomPath string `json:"sbomPath"`
 }
 
 func (dpr *dockerPackageResult) ToString(currentIndentation string) string {
@@ -94,6 +140,20 @@ func (dpr *dockerPackageResult) ToString(currentIndentation string) string {
 		)
 	}
 
+	if dpr.Scan != nil {
+		builder.WriteString(
+			fmt.Sprintf("%s- Vulnerabilities: %d with a severity of %s or higher (%s)\n",
+				currentIndentation,
+				len(dpr.Scan.Vulnerabilities),
+				dpr.Scan.Severity,
+				dpr.Scan.Scanner,
+			),
+		)
+		builder.WriteString(
+			fmt.Sprintf("%s- SBOM: %s\n", currentIndentation, output.WithLinkFormat(dpr.Scan.SbomPath)),
+		)
+	}
+
 	return builder.String()
 }
 
@@ -161,6 +221,21 @@ func (p *dockerProject) RequiredExternalTools(context.Context) []tools.ExternalT
 	return []tools.ExternalTool{p.docker}
 }
 
+// excludeDockerForRemoteBuild removes docker from the required tools of a service whose image is built remotely
+func excludeDockerForRemoteBuild(
+	serviceConfig *ServiceConfig,
+	requiredTools []tools.ExternalTool,
+) []tools.ExternalTool {
+	if !serviceConfig.Docker.RemoteBuild {
+		return requiredTools
+	}
+
+	return slices.DeleteFunc(slices.Clone(requiredTools), func(tool tools.ExternalTool) bool {
+		_, isDocker := tool.(docker.Docker)
+		return isDocker
+	})
+}
+
 // Initializes the docker project
 func (p *dockerProject) Initialize(ctx context.Context, serviceConfig *ServiceConfig) error {
 	return p.framework.Initialize(ctx, serviceConfig)
@@ -200,6 +275,20 @@ func (p *dockerProject) Build(
 				return
 			}
 
+			// Images built remotely are built and pushed when the service is packaged
+			if dockerOptions.RemoteBuild {
+				task.SetResult(&ServiceBuildResult{
+					Restore: restoreOutput,
+				})
+				return
+			}
+
+			buildOptions, err := dockerBuildOptions(dockerOptions, p.env)
+			if err != nil {
+				task.SetError(err)
+				return
+			}
+
 			buildArgs := []string{}
 			for _, arg := range dockerOptions.BuildArgs {
 				buildArgs = append(buildArgs, exec.RedactSensitiveData(arg))
@@ -220,12 +309,7 @@ func (p *dockerProject) Build(
 				strings.ToLower(serviceConfig.Name),
 			)
 
-			path := dockerOptions.Path
-			if !filepath.IsAbs(path) {
-				path = filepath.Join(serviceConfig.Path(), path)
-			}
-
-			_, err := os.Stat(path)
+			_, err = os.Stat(dockerfilePath(serviceConfig, dockerOptions))
 			if err != nil && !errors.Is(err, os.ErrNotExist) {
 				task.SetError(fmt.Errorf("reading dockerfile: %w", err))
 				return
@@ -245,6 +329,19 @@ func (p *dockerProject) Build(
 				return
 			}
 
+			// Registry caches in ACR require logging into the registry before the build
+			if err := p.containerHelper.LoginCacheRegistries(ctx, buildOptions); err != nil {
+				task.SetError(err)
+				return
+			}
+
+			// An image for multiple platforms can't be loaded into the local image store. The local image is built for the
+			// first platform and the image for every platform is built and pushed when the service is deployed.
+			platform := dockerOptions.Platform
+			if docker.IsMultiPlatform(platform) {
+				platform, _, _ = strings.Cut(platform, ",")
+			}
+
 			// Build the container
 			task.SetProgress(NewServiceProgress("Building Docker image"))
 			previewerWriter := p.console.ShowPreviewer(ctx,
@@ -257,11 +354,12 @@ func (p *dockerProject) Build(
 				ctx,
 				serviceConfig.Path(),
 				dockerOptions.Path,
-				dockerOptions.Platform,
+				platform,
 				dockerOptions.Target,
 				dockerOptions.Context,
 				imageName,
 				dockerOptions.BuildArgs,
+				buildOptions,
 				previewerWriter,
 			)
 			p.console.StopPreviewer(ctx, false)
@@ -283,6 +381,40 @@ func (p *dockerProject) Build(
 	)
 }
 
+// Runs the built image, or the source image of the service, in a local container published on the assigned port
+func (p *dockerProject) Run(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	buildOutput *ServiceBuildResult,
+	options *RunOptions,
+	writer io.Writer,
+) error {
+	if serviceConfig.Docker.RemoteBuild {
+		return fmt.Errorf("%w, the image of service '%s' is built remotely", ErrRunNotSupported, serviceConfig.Name)
+	}
+
+	imageName := serviceConfig.Image
+	if buildOutput != nil && buildOutput.BuildOutputPath != "" {
+		imageName = buildOutput.BuildOutputPath
+	}
+
+	if imageName == "" {
+		return fmt.Errorf("no image was built for service '%s'", serviceConfig.Name)
+	}
+
+	targetPort := serviceConfig.Run.TargetPort
+	if targetPort == 0 {
+		targetPort = options.Port
+	}
+
+	return p.docker.Run(ctx, serviceConfig.Path(), imageName, &docker.RunOptions{
+		Name:  fmt.Sprintf("%s-%s", strings.ToLower(serviceConfig.Project.Name), strings.ToLower(serviceConfig.Name)),
+		Ports: []string{fmt.Sprintf("%d:%d", options.Port, targetPort)},
+		// The service listens on the port of the container
+		Env: append(options.Env, fmt.Sprintf("PORT=%d", targetPort)),
+	}, writer)
+}
+
 func (p *dockerProject) Package(
 	ctx context.Context,
 	serviceConfig *ServiceConfig,
@@ -290,6 +422,31 @@ func (p *dockerProject) Package(
 ) *async.TaskWithProgress[*ServicePackageResult, ServiceProgress] {
 	return async.RunTaskWithProgress(
 		func(task *async.TaskContextWithProgress[*ServicePackageResult, ServiceProgress]) {
+			if serviceConfig.Docker.RemoteBuild && serviceConfig.RelativePath != "" {
+				// The registry doesn't exist before the first provision, ex) azd up of a new environment packages the
+				// services before provisioning them, so the image is built remotely when the service is deployed instead
+				if _, err := p.containerHelper.RegistryName(ctx, serviceConfig); errors.Is(err, ErrRegistryNotFound) {
+					log.Printf("deferring the remote build of %s to its deployment: %v", serviceConfig.Name, err)
+					task.SetResult(&ServicePackageResult{
+						Build:   buildOutput,
+						Details: &dockerPackageResult{RemoteBuild: true},
+					})
+					return
+				} else if err != nil {
+					task.SetError(err)
+					return
+				}
+
+				res, err := p.remoteBuild(ctx, serviceConfig, buildOutput, task)
+				if err != nil {
+					task.SetError(err)
+					return
+				}
+
+				task.SetResult(res)
+				return
+			}
+
 			var imageId string
 
 			if buildOutput != nil {
@@ -337,6 +494,11 @@ func (p *dockerProject) Package(
 
 			packageDetails.TargetImage = imageWithTag
 
+			if err := p.scan(ctx, serviceConfig, packageDetails, task); err != nil {
+				task.SetError(err)
+				return
+			}
+
 			task.SetResult(&ServicePackageResult{
 				Build:       buildOutput,
 				PackagePath: packageDetails.SourceImage,
@@ -346,6 +508,58 @@ func (p *dockerProject) Package(
 	)
 }
 
+// remoteBuild builds the image of the service with ACR Tasks, streaming the logs of the build to the previewer. The
+// image is pushed to the registry by the build, so the package result references the pushed image.
+func (p *dockerProject) remoteBuild(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	buildOutput *ServiceBuildResult,
+	task *async.TaskContextWithProgress[*ServicePackageResult, ServiceProgress],
+) (*ServicePackageResult, error) {
+	task.SetProgress(NewServiceProgress("Building container image remotely"))
+	previewerWriter := p.console.ShowPreviewer(ctx,
+		&input.ShowPreviewerOptions{
+			Prefix:       "  ",
+			MaxLineCount: 8,
+			Title:        "Remote Build Output",
+		})
+	remoteImage, err := p.containerHelper.RemoteBuild(ctx, serviceConfig, previewerWriter)
+	p.console.StopPreviewer(ctx, false)
+	if err != nil {
+		return nil, fmt.Errorf("building container: %s: %w", serviceConfig.Name, err)
+	}
+
+	log.Printf("built and pushed image %s for %s", remoteImage, serviceConfig.Name)
+	packageDetails := &dockerPackageResult{
+		TargetImage: remoteImage,
+		RemoteBuild: true,
+	}
+
+	if err := p.scan(ctx, serviceConfig, packageDetails, task); err != nil {
+		return nil, err
+	}
+
+	return &ServicePackageResult{
+		Build:   buildOutput,
+		Details: packageDetails,
+	}, nil
+}
+
+// scan scans the target image when 'docker.scan' is configured, see ContainerHelper.Scan
+func (p *dockerProject) scan(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageDetails *dockerPackageResult,
+	task *async.TaskContextWithProgress[*ServicePackageResult, ServiceProgress],
+) error {
+	if serviceConfig.Docker.Scan == nil {
+		return nil
+	}
+
+	task.SetProgress(NewServiceProgress("Scanning container image"))
+	return p.containerHelper.Scan(ctx, serviceConfig, packageDetails)
+}
+
 // Default builder image to produce container images from source
 const DefaultBuilderImage = "mcr.microsoft.com/oryx/builder:debian-bullseye-20231107.2"
 
@@ -502,6 +716,61 @@ func getEnvironForPython(ctx context.Context, svc *ServiceConfig) ([]string, err
 	return nil, nil
 }
 
+// dockerfilePath returns the absolute path of the Dockerfile of a service
+func dockerfilePath(serviceConfig *ServiceConfig, dockerOptions DockerProjectOptions) string {
+	if filepath.IsAbs(dockerOptions.Path) {
+		return dockerOptions.Path
+	}
+
+	return filepath.Join(serviceConfig.Path(), dockerOptions.Path)
+}
+
+// dockerBuildOptions returns the BuildKit options of the docker build of a service. The cache entries and labels are
+// expanded with the values of the environment, and the values of the secrets are read from the environment.
+func dockerBuildOptions(options DockerProjectOptions, env *environment.Environment) (*docker.BuildOptions, error) {
+	buildOptions := &docker.BuildOptions{}
+
+	for _, cache := range options.CacheFrom {
+		expanded, err := cache.Envsubst(env.Getenv)
+		if err != nil {
+			return nil, fmt.Errorf("failed expanding 'docker.cacheFrom', %w", err)
+		}
+		buildOptions.CacheFrom = append(buildOptions.CacheFrom, expanded)
+	}
+
+	for _, cache := range options.CacheTo {
+		expanded, err := cache.Envsubst(env.Getenv)
+		if err != nil {
+			return nil, fmt.Errorf("failed expanding 'docker.cacheTo', %w", err)
+		}
+		buildOptions.CacheTo = append(buildOptions.CacheTo, expanded)
+	}
+
+	secretIds := maps.Keys(options.Secrets)
+	slices.Sort(secretIds)
+	for _, id := range secretIds {
+		envName := options.Secrets[id]
+		buildOptions.Secrets = append(buildOptions.Secrets, fmt.Sprintf("id=%s,env=%s", id, envName))
+
+		// Values that aren't in the azd environment are read from the environment of azd by the docker CLI
+		if value, has := env.LookupEnv(envName); has {
+			buildOptions.Env = append(buildOptions.Env, fmt.Sprintf("%s=%s", envName, value))
+		}
+	}
+
+	labelKeys := maps.Keys(options.Labels)
+	slices.Sort(labelKeys)
+	for _, key := range labelKeys {
+		value, err := options.Labels[key].Envsubst(env.Getenv)
+		if err != nil {
+			return nil, fmt.Errorf("failed expanding 'docker.labels.%s', %w", key, err)
+		}
+		buildOptions.Labels = append(buildOptions.Labels, fmt.Sprintf("%s=%s", key, value))
+	}
+
+	return buildOptions, nil
+}
+
 func getDockerOptionsWithDefaults(options DockerProjectOptions) DockerProjectOptions {
 	if options.Path == "" {
 		options.Path = "./Dockerfile"
@@ -5,8 +5,11 @@ package project
 
 import (
 	"context"
+	"io"
 	"os"
 	"path/filepath"
+	"runtime"
+	"slices"
 	"strings"
 	"testing"
 
@@ -18,6 +21,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/infra"
 	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/imagescan"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/npm"
 	"github.com/azure/azure-dev/cli/azd/test/mocks"
 	"github.com/azure/azure-dev/cli/azd/test/mocks/mockarmresources"
@@ -108,7 +112,7 @@ services:
 	framework := NewDockerProject(
 		env,
 		docker,
-		NewContainerHelper(env, envManager, clock.NewMock(), nil, docker, cloud.AzurePublic()),
+		NewContainerHelper(env, envManager, clock.NewMock(), nil, docker, cloud.AzurePublic(), nil, nil),
 		mockinput.NewMockConsole(),
 		mockContext.AlphaFeaturesManager,
 		mockContext.CommandRunner)
@@ -216,7 +220,7 @@ services:
 	framework := NewDockerProject(
 		env,
 		docker,
-		NewContainerHelper(env, envManager, clock.NewMock(), nil, docker, cloud.AzurePublic()),
+		NewContainerHelper(env, envManager, clock.NewMock(), nil, docker, cloud.AzurePublic(), nil, nil),
 		mockinput.NewMockConsole(),
 		mockContext.AlphaFeaturesManager,
 		mockContext.CommandRunner)
@@ -327,6 +331,51 @@ func Test_DockerProject_Build(t *testing.T) {
 				".",
 			},
 		},
+		{
+			name:          "With BuildKit options and multiple platforms",
+			project:       "./src/api",
+			language:      ServiceLanguageDocker,
+			hasDockerFile: true,
+			dockerOptions: DockerProjectOptions{
+				Platform:  "linux/amd64,linux/arm64",
+				CacheFrom: []osutil.ExpandableString{osutil.NewExpandableString("type=registry,ref=localhost:5000/api:cache")},
+				CacheTo: []osutil.ExpandableString{
+					osutil.NewExpandableString("type=registry,ref=localhost:5000/api:cache,mode=max"),
+				},
+				Secrets: map[string]string{"npm_token": "NPM_TOKEN"},
+				Labels:  map[string]osutil.ExpandableString{"team": osutil.NewExpandableString("web")},
+			},
+			expectedBuildResult: &ServiceBuildResult{
+				BuildOutputPath: "IMAGE_ID",
+				Details: &dockerBuildResult{
+					ImageName: "test-app-api",
+					ImageId:   "IMAGE_ID",
+				},
+			},
+			// The local image is only built for the first platform
+			expectedDockerBuildArgs: []string{
+				"buildx",
+				"build",
+				"--builder",
+				"azd",
+				"-f",
+				"./Dockerfile",
+				"--platform",
+				"linux/amd64",
+				"-t",
+				"test-app-api",
+				"--cache-from",
+				"type=registry,ref=localhost:5000/api:cache",
+				"--cache-to",
+				"type=registry,ref=localhost:5000/api:cache,mode=max",
+				"--secret",
+				"id=npm_token,env=NPM_TOKEN",
+				"--label",
+				"team=web",
+				"--load",
+				".",
+			},
+		},
 		{
 			name:                    "With no language and docker defaults (external image)",
 			project:                 "",
@@ -360,7 +409,7 @@ func Test_DockerProject_Build(t *testing.T) {
 
 			mockContext.CommandRunner.
 				When(func(args exec.RunArgs, command string) bool {
-					return strings.Contains(command, "docker build")
+					return strings.Contains(command, "docker build") || strings.Contains(command, "docker buildx build")
 				}).
 				RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
 					// extract img id file arg. "--iidfile" and path args are expected always at the end
@@ -373,6 +422,12 @@ func Test_DockerProject_Build(t *testing.T) {
 					return exec.NewRunResult(0, "IMAGE_ID", ""), nil
 				})
 
+			mockContext.CommandRunner.
+				When(func(args exec.RunArgs, command string) bool {
+					return strings.Contains(command, "docker buildx inspect")
+				}).
+				Respond(exec.NewRunResult(0, "", ""))
+
 			mockContext.CommandRunner.
 				When(func(args exec.RunArgs, command string) bool {
 					return strings.Contains(command, "docker image inspect")
@@ -418,7 +473,7 @@ func Test_DockerProject_Build(t *testing.T) {
 			dockerProject := NewDockerProject(
 				env,
 				dockerCli,
-				NewContainerHelper(env, envManager, clock.NewMock(), nil, dockerCli, cloud.AzurePublic()),
+				NewContainerHelper(env, envManager, clock.NewMock(), nil, dockerCli, cloud.AzurePublic(), nil, nil),
 				mockinput.NewMockConsole(),
 				mockContext.AlphaFeaturesManager,
 				mockContext.CommandRunner)
@@ -532,7 +587,7 @@ func Test_DockerProject_Package(t *testing.T) {
 			dockerProject := NewDockerProject(
 				env,
 				dockerCli,
-				NewContainerHelper(env, envManager, clock.NewMock(), nil, dockerCli, cloud.AzurePublic()),
+				NewContainerHelper(env, envManager, clock.NewMock(), nil, dockerCli, cloud.AzurePublic(), nil, nil),
 				mockinput.NewMockConsole(),
 				mockContext.AlphaFeaturesManager,
 				mockContext.CommandRunner)
@@ -575,3 +630,238 @@ func Test_DockerProject_Package(t *testing.T) {
 		})
 	}
 }
+
+func Test_DockerProject_Package_RemoteBuild(t *testing.T) {
+	t.Run("DeferredWithoutRegistry", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		env := environment.NewWithValues("test", map[string]string{})
+		dockerCli := docker.NewDocker(mockContext.CommandRunner)
+		dockerProject := NewDockerProject(
+			env,
+			dockerCli,
+			NewContainerHelper(env, nil, clock.NewMock(), nil, dockerCli, cloud.AzurePublic(), nil, nil),
+			mockinput.NewMockConsole(),
+			mockContext.AlphaFeaturesManager,
+			mockContext.CommandRunner)
+
+		serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+		serviceConfig.Docker.RemoteBuild = true
+
+		packageTask := dockerProject.Package(*mockContext.Context, serviceConfig, &ServiceBuildResult{})
+		logProgress(packageTask)
+		result, err := packageTask.Await()
+		require.NoError(t, err)
+		require.Equal(t, &dockerPackageResult{RemoteBuild: true}, result.Details)
+	})
+
+	t.Run("InvalidRegistry", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		env := environment.NewWithValues("test", map[string]string{})
+		dockerCli := docker.NewDocker(mockContext.CommandRunner)
+		dockerProject := NewDockerProject(
+			env,
+			dockerCli,
+			NewContainerHelper(env, nil, clock.NewMock(), nil, dockerCli, cloud.AzurePublic(), nil, nil),
+			mockinput.NewMockConsole(),
+			mockContext.AlphaFeaturesManager,
+			mockContext.CommandRunner)
+
+		serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+		serviceConfig.Docker.RemoteBuild = true
+		serviceConfig.Docker.Registry = osutil.NewExpandableString("${REGISTRY")
+
+		packageTask := dockerProject.Package(*mockContext.Context, serviceConfig, &ServiceBuildResult{})
+		logProgress(packageTask)
+		_, err := packageTask.Await()
+		require.ErrorContains(t, err, "failed expanding 'docker.registry'")
+	})
+}
+
+func Test_DockerProject_Package_Scan(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake scanner on the PATH is a shell script")
+	}
+
+	// The scanner is discovered on the PATH like other external tools
+	binDir := t.TempDir()
+	err := os.WriteFile(filepath.Join(binDir, "trivy"), []byte("#!/bin/sh\n"), osutil.PermissionExecutableFile)
+	require.NoError(t, err)
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	mediumReport := `{"Results": [{"Vulnerabilities": [
+		{"VulnerabilityID": "CVE-2023-0002", "PkgName": "zlib", "InstalledVersion": "1.2.3", "Severity": "MEDIUM"}
+	]}]}`
+	criticalReport := `{"Results": [{"Vulnerabilities": [
+		{"VulnerabilityID": "CVE-2023-0001", "PkgName": "openssl", "InstalledVersion": "3.0.11", "Severity": "CRITICAL"},
+		{"VulnerabilityID": "CVE-2023-0002", "PkgName": "zlib", "InstalledVersion": "1.2.3", "Severity": "MEDIUM"}
+	]}]}`
+
+	tests := []struct {
+		name              string
+		scan              *DockerScanOptions
+		report            string
+		expectedError     string
+		expectWarning     bool
+		expectedPassed    bool
+		expectedFindings  int
+		expectedCounts    map[imagescan.Severity]int
+		expectedThreshold imagescan.Severity
+	}{
+		{
+			name:              "passes below the default severity",
+			scan:              &DockerScanOptions{},
+			report:            mediumReport,
+			expectedPassed:    true,
+			expectedCounts:    map[imagescan.Severity]int{imagescan.SeverityMedium: 1},
+			expectedThreshold: imagescan.SeverityHigh,
+		},
+		{
+			name:          "fails at the severity",
+			scan:          &DockerScanOptions{Severity: "critical"},
+			report:        criticalReport,
+			expectedError: "1 vulnerabilities with a severity of CRITICAL or higher in image test-app/api-test:azd-deploy-0",
+		},
+		{
+			name:             "warns at the severity",
+			scan:             &DockerScanOptions{Severity: "medium", WarnOnly: true},
+			report:           criticalReport,
+			expectWarning:    true,
+			expectedFindings: 2,
+			expectedCounts: map[imagescan.Severity]int{
+				imagescan.SeverityCritical: 1,
+				imagescan.SeverityMedium:   1,
+			},
+			expectedThreshold: imagescan.SeverityMedium,
+		},
+		{
+			name:          "fails with an invalid severity",
+			scan:          &DockerScanOptions{Severity: "severe"},
+			report:        criticalReport,
+			expectedError: "invalid 'docker.scan.severity'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sbomArgs exec.RunArgs
+
+			mockContext := mocks.NewMockContext(context.Background())
+			setupDockerMocks(mockContext)
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "trivy image")
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				if slices.Contains(args.Args, "cyclonedx") {
+					sbomArgs = args
+					return exec.NewRunResult(0, "", ""), nil
+				}
+
+				return exec.NewRunResult(0, tt.report, ""), nil
+			})
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "trivy --version")
+			}).Respond(exec.NewRunResult(0, "Version: 0.48.0", ""))
+
+			env := environment.NewWithValues("test", map[string]string{})
+			dockerCli := docker.NewDocker(mockContext.CommandRunner)
+			console := mockinput.NewMockConsole()
+			serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+			serviceConfig.Project.Path = t.TempDir()
+			serviceConfig.Docker.Scan = tt.scan
+
+			dockerProject := NewDockerProject(
+				env,
+				dockerCli,
+				NewContainerHelper(
+					env, nil, clock.NewMock(), nil, dockerCli, cloud.AzurePublic(), mockContext.CommandRunner, console),
+				console,
+				mockContext.AlphaFeaturesManager,
+				mockContext.CommandRunner)
+
+			packageTask := dockerProject.Package(
+				*mockContext.Context,
+				serviceConfig,
+				&ServiceBuildResult{BuildOutputPath: "IMAGE_ID"},
+			)
+			logProgress(packageTask)
+			result, err := packageTask.Await()
+
+			if tt.expectedError != "" {
+				require.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			scanResult := result.Details.(*dockerPackageResult).Scan
+			require.NotNil(t, scanResult)
+			require.Equal(t, "Trivy", scanResult.Scanner)
+			require.Equal(t, tt.expectedThreshold, scanResult.Severity)
+			require.Equal(t, tt.expectedPassed, scanResult.Passed)
+			require.Len(t, scanResult.Vulnerabilities, tt.expectedFindings)
+			require.Equal(t, tt.expectedCounts, scanResult.Counts)
+
+			// The SBOM of the tagged image is generated in the directory of the environment
+			require.Equal(t, "test-app/api-test:azd-deploy-0", sbomArgs.Args[len(sbomArgs.Args)-1])
+			require.Equal(t, scanResult.SbomPath, sbomArgs.Args[len(sbomArgs.Args)-2])
+			require.Equal(
+				t,
+				filepath.Join(serviceConfig.Project.Path, ".azure", "test", "sbom", "api.cdx.json"),
+				scanResult.SbomPath,
+			)
+			require.DirExists(t, filepath.Dir(scanResult.SbomPath))
+
+			if tt.expectWarning {
+				require.Contains(t, strings.Join(console.Output(), "\n"), "CVE-2023-0001 (openssl), CVE-2023-0002 (zlib)")
+			}
+		})
+	}
+}
+
+func Test_DockerProject_Run(t *testing.T) {
+	var runArgs exec.RunArgs
+
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.HasPrefix(command, "docker")
+	}).Respond(exec.NewRunResult(0, "", ""))
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "docker run")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		runArgs = args
+		return exec.NewRunResult(0, "CONTAINER_ID", ""), nil
+	})
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "docker wait")
+	}).Respond(exec.NewRunResult(0, "0", ""))
+
+	env := environment.NewWithValues("test", map[string]string{})
+	dockerCli := docker.NewDocker(mockContext.CommandRunner)
+	serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+	serviceConfig.Run.TargetPort = 80
+
+	dockerProject := NewDockerProject(
+		env,
+		dockerCli,
+		NewContainerHelper(env, &mockenv.MockEnvManager{}, clock.NewMock(), nil, dockerCli, cloud.AzurePublic(), nil, nil),
+		mockinput.NewMockConsole(),
+		mockContext.AlphaFeaturesManager,
+		mockContext.CommandRunner)
+
+	runner, ok := dockerProject.(ServiceRunner)
+	require.True(t, ok)
+
+	err := runner.Run(*mockContext.Context, serviceConfig, &ServiceBuildResult{BuildOutputPath: "IMAGE_ID"}, &RunOptions{
+		Port: 3100,
+		Env:  []string{"AZURE_ENV_NAME=test", "PORT=3100"},
+	}, io.Discard)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"run", "--detach",
+		"--name", "test-app-api",
+		"-p", "3100:80",
+		"-e", "AZURE_ENV_NAME",
+		"-e", "PORT",
+		"IMAGE_ID",
+	}, runArgs.Args)
+	// The container listens on the target port
+	require.Equal(t, []string{"AZURE_ENV_NAME=test", "PORT=3100", "PORT=80"}, runArgs.Env)
+}
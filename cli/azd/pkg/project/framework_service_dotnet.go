@@ -6,6 +6,7 @@ package project
 import (
 	"context"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
@@ -61,7 +62,7 @@ func (dp *dotnetProject) Initialize(ctx context.Context, serviceConfig *ServiceC
 	//
 	// We'd like to stop doing this at some point for all .NET projects, but we can make sure that we don't inherit the
 	// bad behavior for containerized projects, without being concerned about it being considered a breaking change.
-	if serviceConfig.Host != DotNetContainerAppTarget {
+	if serviceConfig.Host != DotNetContainerAppTarget && serviceConfig.Host != DotNetAksTarget {
 		projFile, err := findProjectFile(serviceConfig.Name, serviceConfig.Path())
 		if err != nil {
 			return err
@@ -149,6 +150,26 @@ func (dp *dotnetProject) Build(
 	)
 }
 
+// Runs the project locally with `dotnet run`, listening on the assigned port
+func (dp *dotnetProject) Run(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	buildOutput *ServiceBuildResult,
+	options *RunOptions,
+	writer io.Writer,
+) error {
+	projFile, err := findProjectFile(serviceConfig.Name, serviceConfig.Path())
+	if err != nil {
+		return err
+	}
+
+	// The urls are passed on the command line too, since the applicationUrl of the launch profile that dotnet run applies
+	// overrides ASPNETCORE_URLS, while the command line overrides both
+	url := fmt.Sprintf("http://localhost:%d", options.Port)
+	env := append(options.Env, fmt.Sprintf("ASPNETCORE_URLS=%s", url))
+	return dp.dotnetCli.Run(ctx, projFile, []string{"--urls", url}, env, writer)
+}
+
 func (dp *dotnetProject) Package(
 	ctx context.Context,
 	serviceConfig *ServiceConfig,
@@ -156,7 +177,7 @@ func (dp *dotnetProject) Package(
 ) *async.TaskWithProgress[*ServicePackageResult, ServiceProgress] {
 	return async.RunTaskWithProgress(
 		func(task *async.TaskContextWithProgress[*ServicePackageResult, ServiceProgress]) {
-			if serviceConfig.Host == DotNetContainerAppTarget {
+			if serviceConfig.Host == DotNetContainerAppTarget || serviceConfig.Host == DotNetAksTarget {
 				// TODO(weilim): For containerized projects, we publish the produced container image in a single call
 				// via `dotnet publish /p:PublishProfile=DefaultContainer`, thus the default `dotnet publish` command
 				// executed here is not useful.
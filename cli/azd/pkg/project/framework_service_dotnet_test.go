@@ -206,6 +206,54 @@ func Test_DotNetProject_Build(t *testing.T) {
 	)
 }
 
+func Test_DotNetProject_Run(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	var runArgs exec.RunArgs
+	err := os.MkdirAll("./src/api", osutil.PermissionDirectory)
+	require.NoError(t, err)
+	file, err := os.Create("./src/api/test.csproj")
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.CommandRunner.
+		When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "dotnet run")
+		}).
+		RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			runArgs = args
+			return exec.NewRunResult(0, "", ""), nil
+		})
+
+	env := environment.New("test")
+	dotNetCli := dotnet.NewDotNetCli(mockContext.CommandRunner)
+	serviceConfig := createTestServiceConfig("./src/api", AppServiceTarget, ServiceLanguageCsharp)
+
+	dotnetProject := NewDotNetProject(dotNetCli, env)
+	runner, ok := dotnetProject.(ServiceRunner)
+	require.True(t, ok)
+
+	err = runner.Run(*mockContext.Context, serviceConfig, nil, &RunOptions{
+		Port: 3100,
+		Env:  []string{"AZURE_ENV_NAME=test"},
+	}, io.Discard)
+	require.NoError(t, err)
+
+	// The urls of the command line override the applicationUrl of the launch profile
+	require.Equal(t, []string{
+		"run",
+		"--project",
+		filepath.Join(serviceConfig.RelativePath, "test.csproj"),
+		"--",
+		"--urls",
+		"http://localhost:3100",
+	}, runArgs.Args)
+	require.Contains(t, runArgs.Env, "AZURE_ENV_NAME=test")
+	require.Contains(t, runArgs.Env, "ASPNETCORE_URLS=http://localhost:3100")
+}
+
 func Test_DotNetProject_Package(t *testing.T) {
 	var runArgs exec.RunArgs
 
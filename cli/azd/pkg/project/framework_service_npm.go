@@ -6,6 +6,7 @@ package project
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 
@@ -164,6 +165,17 @@ func (np *npmProject) Package(
 	)
 }
 
+// Runs the project locally with the `start` script defined within the project package.json
+func (np *npmProject) Run(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	buildOutput *ServiceBuildResult,
+	options *RunOptions,
+	writer io.Writer,
+) error {
+	return np.cli.Start(ctx, serviceConfig.Path(), options.Env, writer)
+}
+
 const cNodeModulesName = "node_modules"
 
 func excludeNodeModules(path string, file os.FileInfo) bool {
@@ -123,3 +123,38 @@ func Test_NpmProject_Package(t *testing.T) {
 		runArgs.Args,
 	)
 }
+
+func Test_NpmProject_Run(t *testing.T) {
+	var runArgs exec.RunArgs
+
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.CommandRunner.
+		When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "npm start")
+		}).
+		RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			runArgs = args
+			_, err := args.StdOut.Write([]byte("listening on 3100\n"))
+			return exec.NewRunResult(0, "", ""), err
+		})
+
+	env := environment.New("test")
+	npmCli := npm.NewNpmCli(mockContext.CommandRunner)
+	serviceConfig := createTestServiceConfig("./src/api", AppServiceTarget, ServiceLanguageTypeScript)
+
+	npmProject := NewNpmProject(npmCli, env)
+	runner, ok := npmProject.(ServiceRunner)
+	require.True(t, ok)
+
+	output := &strings.Builder{}
+	err := runner.Run(*mockContext.Context, serviceConfig, nil, &RunOptions{
+		Port: 3100,
+		Env:  []string{"AZURE_ENV_NAME=test", "PORT=3100"},
+	}, output)
+	require.NoError(t, err)
+	require.Equal(t, "npm", runArgs.Cmd)
+	require.Equal(t, serviceConfig.Path(), runArgs.Cwd)
+	require.Equal(t, []string{"start"}, runArgs.Args)
+	require.Equal(t, []string{"AZURE_ENV_NAME=test", "PORT=3100"}, runArgs.Env)
+	require.Equal(t, "listening on 3100\n", output.String())
+}
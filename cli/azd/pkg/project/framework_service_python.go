@@ -6,6 +6,7 @@ package project
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"path"
 	"path/filepath"
@@ -168,6 +169,34 @@ func (pp *pythonProject) Package(
 	)
 }
 
+// Runs the project locally with `python -m` in the virtual environment of the project
+func (pp *pythonProject) Run(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	buildOutput *ServiceBuildResult,
+	options *RunOptions,
+	writer io.Writer,
+) error {
+	module := serviceConfig.Run.Module
+	if module == "" {
+		for _, candidate := range []string{"app", "main"} {
+			if _, err := os.Stat(filepath.Join(serviceConfig.Path(), candidate+".py")); err == nil {
+				module = candidate
+				break
+			}
+		}
+	}
+
+	if module == "" {
+		return fmt.Errorf(
+			"no app.py or main.py found for service '%s', set the module to run with 'run.module' in azure.yaml",
+			serviceConfig.Name,
+		)
+	}
+
+	return pp.cli.Run(ctx, serviceConfig.Path(), pp.getVenvName(serviceConfig), []string{"-m", module}, options.Env, writer)
+}
+
 const cVenvConfigFileName = "pyvenv.cfg"
 
 func isPythonVirtualEnv(path string) bool {
@@ -6,6 +6,7 @@ package project
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -101,6 +102,61 @@ func Test_PythonProject_Build(t *testing.T) {
 	require.NotNil(t, result)
 }
 
+func Test_PythonProject_Run(t *testing.T) {
+	t.Run("DefaultModule", func(t *testing.T) {
+		var runArgs exec.RunArgs
+
+		tempDir := t.TempDir()
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.CommandRunner.
+			When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "-m app")
+			}).
+			RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				runArgs = args
+				return exec.NewRunResult(0, "", ""), nil
+			})
+
+		env := environment.New("test")
+		pythonCli := python.NewPythonCli(mockContext.CommandRunner)
+		serviceConfig := createTestServiceConfig(tempDir, AppServiceTarget, ServiceLanguagePython)
+		err := os.WriteFile(filepath.Join(tempDir, "app.py"), nil, osutil.PermissionFile)
+		require.NoError(t, err)
+
+		pythonProject := NewPythonProject(pythonCli, env)
+		runner, ok := pythonProject.(ServiceRunner)
+		require.True(t, ok)
+
+		err = runner.Run(*mockContext.Context, serviceConfig, nil, &RunOptions{
+			Port: 3100,
+			Env:  []string{"PORT=3100"},
+		}, io.Discard)
+		require.NoError(t, err)
+
+		vEnvPath := filepath.Join(tempDir, filepath.Base(tempDir)+"_env")
+		expectedCmd := filepath.Join(vEnvPath, "bin", "python")
+		if runtime.GOOS == "windows" {
+			expectedCmd = filepath.Join(vEnvPath, "Scripts", "python.exe")
+		}
+
+		require.Equal(t, expectedCmd, runArgs.Cmd)
+		require.Equal(t, []string{"-m", "app"}, runArgs.Args)
+		require.Equal(t, []string{"PORT=3100", fmt.Sprintf("VIRTUAL_ENV=%s", vEnvPath)}, runArgs.Env)
+	})
+
+	t.Run("NoModule", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+
+		env := environment.New("test")
+		pythonCli := python.NewPythonCli(mockContext.CommandRunner)
+		serviceConfig := createTestServiceConfig(t.TempDir(), AppServiceTarget, ServiceLanguagePython)
+
+		pythonProject := NewPythonProject(pythonCli, env)
+		err := pythonProject.(ServiceRunner).Run(*mockContext.Context, serviceConfig, nil, &RunOptions{}, io.Discard)
+		require.ErrorContains(t, err, "run.module")
+	})
+}
+
 func Test_PythonProject_Package(t *testing.T) {
 	tempDir := t.TempDir()
 	ostest.Chdir(t, tempDir)
@@ -46,7 +46,7 @@ var (
 		"a project may only contain a single Aspire service and no other services at this time.")
 
 	errAppHostMustTargetContainerApp = fmt.Errorf(
-		"Aspire services must be configured to target the container app host at this time.")
+		"Aspire services must be configured to target the container app or aks host at this time.")
 )
 
 // Retrieves the list of services in the project, in a stable ordering that is deterministic.
@@ -60,7 +60,7 @@ func (im *ImportManager) ServiceStable(ctx context.Context, projectConfig *Proje
 					return nil, errNoMultipleServicesWithAppHost
 				}
 
-				if svcConfig.Host != ContainerAppTarget {
+				if svcConfig.Host != ContainerAppTarget && svcConfig.Host != AksTarget {
 					return nil, errAppHostMustTargetContainerApp
 				}
 
@@ -137,7 +137,7 @@ func (im *ImportManager) ProjectInfrastructure(ctx context.Context, projectConfi
 					return nil, errNoMultipleServicesWithAppHost
 				}
 
-				if svcConfig.Host != ContainerAppTarget {
+				if svcConfig.Host != ContainerAppTarget && svcConfig.Host != AksTarget {
 					return nil, errAppHostMustTargetContainerApp
 				}
 
@@ -166,14 +166,18 @@ func pathHasModule(path, module string) (bool, error) {
 
 }
 
-func (im *ImportManager) SynthAllInfrastructure(ctx context.Context, projectConfig *ProjectConfig) (fs.FS, error) {
+// SynthAllInfrastructure returns the infrastructure of the project, generated for the given provider. When the provider
+// isn't specified, bicep is generated.
+func (im *ImportManager) SynthAllInfrastructure(
+	ctx context.Context, projectConfig *ProjectConfig, provider provisioning.ProviderKind,
+) (fs.FS, error) {
 	for _, svcConfig := range projectConfig.Services {
 		if svcConfig.Language == ServiceLanguageDotNet {
 			if len(projectConfig.Services) != 1 {
 				return nil, errNoMultipleServicesWithAppHost
 			}
 
-			return im.dotNetImporter.SynthAllInfrastructure(ctx, projectConfig, svcConfig)
+			return im.dotNetImporter.SynthAllInfrastructure(ctx, projectConfig, svcConfig, provider)
 		}
 	}
 
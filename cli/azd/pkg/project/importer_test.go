@@ -368,3 +368,83 @@ func TestImportManagerProjectInfrastructureAspire(t *testing.T) {
 	require.NoError(t, e)
 	require.Equal(t, 1, manifestInvokeCount)
 }
+
+func TestImportManagerServiceStableAspireAks(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	mockEnv := &mockenv.MockEnvManager{}
+	mockEnv.On("Save", mock.Anything, mock.Anything).Return(nil)
+
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "dotnet") &&
+			slices.Contains(args.Args, "--getProperty:IsAspireHost")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		return exec.RunResult{
+			Stdout:   "true",
+			ExitCode: 0,
+		}, nil
+	})
+
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "dotnet") &&
+			slices.Contains(args.Args, "--publisher") &&
+			slices.Contains(args.Args, "manifest")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		err := os.WriteFile(args.Args[6], aspireEscapingManifest, osutil.PermissionFile)
+		if err != nil {
+			return exec.RunResult{
+				ExitCode: -1,
+				Stderr:   err.Error(),
+			}, err
+		}
+		return exec.RunResult{}, nil
+	})
+
+	manager := NewImportManager(&DotNetImporter{
+		dotnetCli: dotnet.NewDotNetCli(mockContext.CommandRunner),
+		console:   mockContext.Console,
+		lazyEnv: lazy.NewLazy(func() (*environment.Environment, error) {
+			return environment.NewWithValues("env", map[string]string{}), nil
+		}),
+		lazyEnvManager: lazy.NewLazy(func() (environment.Manager, error) {
+			return mockEnv, nil
+		}),
+		hostCheck: make(map[string]hostCheckResult),
+		cache:     make(map[manifestCacheKey]*apphost.Manifest),
+	})
+
+	projectPath := t.TempDir()
+	services, err := manager.ServiceStable(*mockContext.Context, &ProjectConfig{
+		Path: projectPath,
+		Services: map[string]*ServiceConfig{
+			"app": {
+				Name:         "app",
+				Language:     ServiceLanguageDotNet,
+				Host:         AksTarget,
+				RelativePath: "path",
+				K8s: AksOptions{
+					Namespace:    "aspire",
+					AspireFormat: AksAspireFormatHelm,
+					Service:      AksServiceOptions{Name: "app"},
+				},
+				Project: &ProjectConfig{
+					Path: projectPath,
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, services, 1)
+
+	api := services[0]
+	require.Equal(t, "api", api.Name)
+	require.Equal(t, DotNetAksTarget, api.Host)
+	require.Equal(t, "aspire", api.K8s.Namespace)
+	require.Equal(t, AksAspireFormatHelm, api.K8s.AspireFormat)
+	require.Empty(t, api.K8s.Service.Name)
+
+	// The pods of the services run as a service account federated with the generated managed identity
+	require.NotNil(t, api.K8s.Identity)
+	require.Equal(t, osutil.NewExpandableString("${MANAGED_IDENTITY_NAME}"), api.K8s.Identity.Name)
+	require.Equal(t, "azd-workload-identity", api.K8s.Identity.ServiceAccount)
+	require.NotNil(t, api.DotNetContainerApp)
+}
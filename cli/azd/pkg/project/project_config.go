@@ -29,6 +29,7 @@ type ProjectConfig struct {
 	Platform          *platform.Config           `yaml:"platform,omitempty"`
 	Workflows         workflow.WorkflowMap       `yaml:"workflows,omitempty"`
 	Cloud             *cloud.Config              `yaml:"cloud,omitempty"`
+	Emulators         map[string]*EmulatorConfig `yaml:"emulators,omitempty"`
 
 	*ext.EventDispatcher[ProjectLifecycleEventArgs] `yaml:"-"`
 }
@@ -47,6 +48,16 @@ type PipelineOptions struct {
 	Secrets   []string `yaml:"secrets"`
 }
 
+// EmulatorConfig is a local emulator of an Azure service, started in a container by `azd run`
+type EmulatorConfig struct {
+	// The container image of the emulator
+	Image string `yaml:"image"`
+	// The ports published to the host, as hostPort:containerPort
+	Ports []string `yaml:"ports,omitempty"`
+	// The environment variables of the emulator container
+	Env map[string]string `yaml:"env,omitempty"`
+}
+
 // Project lifecycle event arguments
 type ProjectLifecycleEventArgs struct {
 	Project *ProjectConfig
@@ -61,4 +72,21 @@ type ProjectMetadata struct {
 	// in every template that we ship.
 	// ex: todo-python-mongo@version
 	Template string
+	// TemplateSource records the template repository the project was initialized from. It's written by `azd init`
+	// and used by `azd template upgrade` to merge later changes of the template into the project.
+	TemplateSource *TemplateSource `yaml:"templateSource,omitempty"`
+}
+
+// TemplateSource identifies the commit of a template repository.
+type TemplateSource struct {
+	// Repository is the URL of the template repository.
+	Repository string `yaml:"repository"`
+	// Ref is the branch or tag of the template. The default branch of the repository is used when empty.
+	Ref string `yaml:"ref,omitempty"`
+	// Subdirectory is the slash separated path of the template in the repository, when not at its root.
+	Subdirectory string `yaml:"subdirectory,omitempty"`
+	// Commit is the SHA of the commit the project was initialized or last upgraded from.
+	Commit string `yaml:"commit"`
+	// Values are the values of the parameters declared by the template manifest.
+	Values map[string]string `yaml:"values,omitempty"`
 }
@@ -205,7 +205,7 @@ func (pm *projectManager) EnsureFrameworkTools(
 			return fmt.Errorf("getting service required tools: %w", err)
 		}
 
-		requiredTools = append(requiredTools, frameworkTools...)
+		requiredTools = append(requiredTools, excludeDockerForRemoteBuild(svc, frameworkTools)...)
 	}
 
 	if err := tools.EnsureInstalled(ctx, tools.Unique(requiredTools)...); err != nil {
@@ -242,7 +242,7 @@ func (pm *projectManager) EnsureServiceTargetTools(
 			return fmt.Errorf("getting service required tools: %w", err)
 		}
 
-		requiredTools = append(requiredTools, serviceTargetTools...)
+		requiredTools = append(requiredTools, excludeDockerForRemoteBuild(svc, serviceTargetTools)...)
 	}
 
 	if err := tools.EnsureInstalled(ctx, tools.Unique(requiredTools)...); err != nil {
@@ -7,9 +7,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"time"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/rzip"
+	"github.com/moby/patternmatcher"
 	"github.com/otiai10/copy"
 )
 
@@ -39,6 +41,56 @@ func createDeployableZip(projectName string, appName string, path string) (strin
 	return zipFile.Name(), nil
 }
 
+// alwaysExcludedBuildContextPatterns are excluded from every archive of source code built remotely. The .azure
+// directory holds the environments of the project, ex) the secrets of their .env files.
+var alwaysExcludedBuildContextPatterns = []string{"**/.azure"}
+
+// createBuildContextArchive creates a gzipped tarball of source code built remotely, ex) the build context of an image
+// built with ACR Tasks or the source code built by the Azure Spring Apps build service. The files matching the exclude
+// patterns, which have the syntax of .dockerignore files, aren't archived.
+// Returns the path to the created archive or an error if it fails.
+func createBuildContextArchive(
+	projectName string, appName string, path string, excludePatterns []string) (string, error) {
+	// The patterns which are always excluded are last, so that they can't be overridden by an exception
+	matcher, err := patternmatcher.New(append(slices.Clone(excludePatterns), alwaysExcludedBuildContextPatterns...))
+	if err != nil {
+		return "", fmt.Errorf("parsing exclude patterns: %w", err)
+	}
+
+	filePath := filepath.Join(
+		os.TempDir(), fmt.Sprintf("%s-%s-azdbuild-%d.tar.gz", projectName, appName, time.Now().Unix()))
+	archiveFile, err := os.Create(filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed when creating the build context archive of %s: %w", appName, err)
+	}
+
+	err = rzip.CreateTarGzFromDirectory(path, archiveFile, func(name string, isDir bool) (bool, error) {
+		excluded, err := matcher.MatchesOrParentMatches(filepath.FromSlash(name))
+		if err != nil {
+			return false, err
+		}
+
+		// The files of an excluded directory may be included again by an exception, ex) !bin/app
+		if isDir && excluded && matcher.Exclusions() {
+			return false, nil
+		}
+
+		return excluded, nil
+	})
+	if err != nil {
+		archiveFile.Close()
+		os.Remove(archiveFile.Name())
+		return "", err
+	}
+
+	if err := archiveFile.Close(); err != nil {
+		os.Remove(archiveFile.Name())
+		return "", err
+	}
+
+	return archiveFile.Name(), nil
+}
+
 // excludeDirEntryCondition resolves when a file or directory should be considered or not as part of build, when build is a
 // copy-paste source strategy. Return true to exclude the directory entry.
 type excludeDirEntryCondition func(path string, file os.FileInfo) bool
@@ -0,0 +1,106 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+)
+
+// The options for streaming the logs of a deployed service
+type LogsOptions struct {
+	// Keeps streaming new logs until the context is cancelled
+	Follow bool
+	// Only returns logs newer than the duration. Zero returns all the logs available from the host.
+	Since time.Duration
+	// Streams the system logs of the host instead of the application logs, when supported by the host
+	System bool
+}
+
+// ServiceLogStreamer is implemented by the service targets that can stream the logs of a deployed service
+type ServiceLogStreamer interface {
+	// Writes the logs of the service to the writer, one line per write.
+	// When following, logs are written until the context is cancelled.
+	Logs(
+		ctx context.Context,
+		serviceConfig *ServiceConfig,
+		targetResource *environment.TargetResource,
+		options *LogsOptions,
+		writer io.Writer,
+	) error
+}
+
+// ErrLogsNotSupported is returned when the logs of a service cannot be streamed from its host
+var ErrLogsNotSupported = errors.New("streaming logs is not supported")
+
+// copyLogStreams concurrently writes the lines of the log streams to the writer as they are received, one write per line.
+// The streams are closed once they have all ended or the context is cancelled.
+func copyLogStreams(ctx context.Context, streams []io.ReadCloser, writer io.Writer) error {
+	closeStreams := func() {
+		for _, stream := range streams {
+			stream.Close()
+		}
+	}
+
+	// Closing the streams unblocks any pending reads when the context is cancelled
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			closeStreams()
+		case <-done:
+		}
+	}()
+
+	var writeLock sync.Mutex
+	var wg sync.WaitGroup
+	errs := make([]error, len(streams))
+
+	for i, stream := range streams {
+		wg.Add(1)
+		go func(i int, stream io.ReadCloser) {
+			defer wg.Done()
+
+			reader := bufio.NewReader(stream)
+			for {
+				line, err := reader.ReadString('\n')
+				if line != "" {
+					if !strings.HasSuffix(line, "\n") {
+						line += "\n"
+					}
+
+					writeLock.Lock()
+					_, writeErr := io.WriteString(writer, line)
+					writeLock.Unlock()
+
+					if writeErr != nil {
+						errs[i] = writeErr
+						return
+					}
+				}
+
+				if err != nil {
+					if !errors.Is(err, io.EOF) && ctx.Err() == nil {
+						errs[i] = err
+					}
+
+					return
+				}
+			}
+		}(i, stream)
+	}
+
+	wg.Wait()
+	close(done)
+	closeStreams()
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,28 @@
+package project
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CopyLogStreams(t *testing.T) {
+	streams := []io.ReadCloser{
+		io.NopCloser(strings.NewReader("replica1 line1\nreplica1 line2\n")),
+		io.NopCloser(strings.NewReader("replica2 line1\nreplica2 partial")),
+	}
+
+	output := &bytes.Buffer{}
+	err := copyLogStreams(context.Background(), streams, output)
+	require.NoError(t, err)
+
+	// The streams are interleaved line by line in any order
+	lines := strings.Split(strings.TrimSuffix(output.String(), "\n"), "\n")
+	sort.Strings(lines)
+	require.Equal(t, []string{"replica1 line1", "replica1 line2", "replica2 line1", "replica2 partial"}, lines)
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
 	"github.com/azure/azure-dev/cli/azd/pkg/async"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/ext"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra"
 	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
@@ -88,6 +89,25 @@ type ServiceManager interface {
 		packageOutput *ServicePackageResult,
 	) *async.TaskWithProgress[*ServiceDeployResult, ServiceProgress]
 
+	// Writes the logs of the deployed service to the writer
+	// Returns ErrLogsNotSupported when the service target can't stream the logs of the service
+	Logs(
+		ctx context.Context,
+		serviceConfig *ServiceConfig,
+		options *LogsOptions,
+		writer io.Writer,
+	) error
+
+	// Runs the built service locally with the azd environment, writing its output to the writer
+	// Returns ErrRunNotSupported when the framework service can't run the service locally
+	Run(
+		ctx context.Context,
+		serviceConfig *ServiceConfig,
+		buildOutput *ServiceBuildResult,
+		options *RunOptions,
+		writer io.Writer,
+	) error
+
 	// Gets the framework service for the specified service config
 	// The framework service performs the restoration and building of the service app code
 	GetFrameworkService(ctx context.Context, serviceConfig *ServiceConfig) (FrameworkService, error)
@@ -146,7 +166,21 @@ func (sm *serviceManager) GetRequiredTools(ctx context.Context, serviceConfig *S
 	requiredTools = append(requiredTools, frameworkService.RequiredExternalTools(ctx)...)
 	requiredTools = append(requiredTools, serviceTarget.RequiredExternalTools(ctx)...)
 
-	return tools.Unique(requiredTools), nil
+	// Images of services with `docker.scan` are scanned after they're built, locally or remotely
+	if serviceConfig.Docker.Scan != nil {
+		var commandRunner exec.CommandRunner
+		if err := sm.serviceLocator.Resolve(&commandRunner); err != nil {
+			return nil, fmt.Errorf("resolving command runner: %w", err)
+		}
+
+		scanner, err := imageScanner(serviceConfig, commandRunner)
+		if err != nil {
+			return nil, err
+		}
+		requiredTools = append(requiredTools, scanner)
+	}
+
+	return tools.Unique(excludeDockerForRemoteBuild(serviceConfig, requiredTools)), nil
 }
 
 // Initializes the service configuration and dependent framework & service target
@@ -487,6 +521,10 @@ func (sm *serviceManager) Deploy(
 				containerEnvName,
 				string(infra.AzureResourceTypeContainerAppEnvironment),
 			)
+		} else if (serviceConfig.Host == AksTarget || serviceConfig.Host == DotNetAksTarget) &&
+			serviceConfig.K8s.IsLocalCluster() {
+			// Local clusters are not Azure resources
+			targetResource = environment.NewTargetResource(sm.env.GetSubscriptionId(), "", "", "")
 		} else {
 			targetResource, err = sm.resourceManager.GetTargetResource(ctx, sm.env.GetSubscriptionId(), serviceConfig)
 			if err != nil {
@@ -522,6 +560,62 @@ func (sm *serviceManager) Deploy(
 	})
 }
 
+// Writes the logs of the deployed service to the writer
+func (sm *serviceManager) Logs(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	options *LogsOptions,
+	writer io.Writer,
+) error {
+	serviceTarget, err := sm.GetServiceTarget(ctx, serviceConfig)
+	if err != nil {
+		return fmt.Errorf("getting service target: %w", err)
+	}
+
+	logStreamer, ok := serviceTarget.(ServiceLogStreamer)
+	if !ok {
+		return fmt.Errorf("%w for host '%s'", ErrLogsNotSupported, serviceConfig.Host)
+	}
+
+	var targetResource *environment.TargetResource
+	if serviceConfig.K8s.IsLocalCluster() {
+		// Local clusters are not Azure resources
+		targetResource = environment.NewTargetResource(sm.env.GetSubscriptionId(), "", "", "")
+	} else {
+		targetResource, err = sm.resourceManager.GetTargetResource(ctx, sm.env.GetSubscriptionId(), serviceConfig)
+		if err != nil {
+			return fmt.Errorf("getting target resource: %w", err)
+		}
+	}
+
+	return logStreamer.Logs(ctx, serviceConfig, targetResource, options, writer)
+}
+
+// Runs the built service locally with the azd environment, writing its output to the writer
+func (sm *serviceManager) Run(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	buildOutput *ServiceBuildResult,
+	options *RunOptions,
+	writer io.Writer,
+) error {
+	frameworkService, err := sm.GetFrameworkService(ctx, serviceConfig)
+	if err != nil {
+		return fmt.Errorf("getting framework service: %w", err)
+	}
+
+	runner, ok := frameworkService.(ServiceRunner)
+	if !ok {
+		return fmt.Errorf("%w for language '%s'", ErrRunNotSupported, serviceConfig.Language)
+	}
+
+	// The values of the azd environment are injected along with the port assigned to the service
+	env := append(sm.env.Environ(), options.Env...)
+	env = append(env, fmt.Sprintf("PORT=%d", options.Port))
+
+	return runner.Run(ctx, serviceConfig, buildOutput, &RunOptions{Port: options.Port, Env: env}, writer)
+}
+
 // GetServiceTarget constructs a ServiceTarget from the underlying service configuration
 func (sm *serviceManager) GetServiceTarget(ctx context.Context, serviceConfig *ServiceConfig) (ServiceTarget, error) {
 	var target ServiceTarget
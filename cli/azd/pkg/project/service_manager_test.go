@@ -3,6 +3,7 @@ package project
 import (
 	"context"
 	"errors"
+	"io"
 	"strings"
 	"testing"
 
@@ -67,6 +68,25 @@ func Test_ServiceManager_GetRequiredTools(t *testing.T) {
 	require.Len(t, tools, 1)
 }
 
+func Test_ServiceManager_GetRequiredTools_Scan(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	setupMocksForServiceManager(mockContext)
+	env := environment.New("test")
+	sm := createServiceManager(mockContext, env, ServiceOperationCache{})
+	serviceConfig := createTestServiceConfig("./src/api", ServiceTargetFake, ServiceLanguageFake)
+
+	// The scanner is checked along with the other tools of the service, before its images are built
+	serviceConfig.Docker.Scan = &DockerScanOptions{Scanner: "grype"}
+	tools, err := sm.GetRequiredTools(*mockContext.Context, serviceConfig)
+	require.NoError(t, err)
+	require.Len(t, tools, 2)
+	require.Equal(t, "Grype", tools[1].Name())
+
+	serviceConfig.Docker.Scan = &DockerScanOptions{Scanner: "clair"}
+	_, err = sm.GetRequiredTools(*mockContext.Context, serviceConfig)
+	require.ErrorContains(t, err, "invalid 'docker.scan.scanner'")
+}
+
 func Test_ServiceManager_Initialize(t *testing.T) {
 	mockContext := mocks.NewMockContext(context.Background())
 	setupMocksForServiceManager(mockContext)
@@ -219,6 +239,17 @@ func Test_ServiceManager_Deploy(t *testing.T) {
 	require.True(t, raisedPostDeployEvent)
 }
 
+func Test_ServiceManager_Run(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	setupMocksForServiceManager(mockContext)
+	env := environment.New("test")
+	sm := createServiceManager(mockContext, env, ServiceOperationCache{})
+	serviceConfig := createTestServiceConfig("./src/api", ServiceTargetFake, ServiceLanguageFake)
+
+	err := sm.Run(*mockContext.Context, serviceConfig, nil, &RunOptions{Port: 3100}, io.Discard)
+	require.ErrorIs(t, err, ErrRunNotSupported)
+}
+
 func Test_ServiceManager_GetFrameworkService(t *testing.T) {
 	t.Run("Standard", func(t *testing.T) {
 		mockContext := mocks.NewMockContext(context.Background())
@@ -0,0 +1,44 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// The optional azure.yaml options for running a service locally with `azd run`
+type LocalRunOptions struct {
+	// The local port of the service. A free port is assigned when not set.
+	Port int `yaml:"port,omitempty"`
+	// The port the container of a docker service listens on. Defaults to the local port.
+	TargetPort int `yaml:"targetPort,omitempty"`
+	// The Python module run with `python -m`. Defaults to `app` or `main` when the project has an app.py or main.py file.
+	Module string `yaml:"module,omitempty"`
+}
+
+// The options for running a service locally
+type RunOptions struct {
+	// The local port assigned to the service, which is also set as the PORT environment variable
+	Port int
+	// The environment variables of the service, as KEY=VALUE
+	Env []string
+}
+
+// ServiceRunner is implemented by the framework services that can run a service locally
+type ServiceRunner interface {
+	// Runs the built service locally, writing its output to the writer.
+	// Blocks until the service exits or the context is cancelled.
+	Run(
+		ctx context.Context,
+		serviceConfig *ServiceConfig,
+		buildOutput *ServiceBuildResult,
+		options *RunOptions,
+		writer io.Writer,
+	) error
+}
+
+// ErrRunNotSupported is returned when a service cannot be run locally
+var ErrRunNotSupported = errors.New("running locally is not supported")
@@ -26,6 +26,7 @@ const (
 	SpringAppTarget          ServiceTargetKind = "springapp"
 	AksTarget                ServiceTargetKind = "aks"
 	DotNetContainerAppTarget ServiceTargetKind = "containerapp-dotnet"
+	DotNetAksTarget          ServiceTargetKind = "aks-dotnet"
 )
 
 // RequiresContainer returns true if the service target runs a container image.
@@ -42,9 +43,9 @@ func (stk ServiceTargetKind) RequiresContainer() bool {
 func parseServiceHost(kind ServiceTargetKind) (ServiceTargetKind, error) {
 	switch kind {
 
-	// NOTE: We do not support DotNetContainerAppTarget as a listed service host type in azure.yaml, hence
-	// it not include in this switch statement. We should think about if we should support this in azure.yaml because
-	// presently it's the only service target that is tied to a language.
+	// NOTE: We do not support DotNetContainerAppTarget or DotNetAksTarget as a listed service host type in azure.yaml,
+	// hence they are not included in this switch statement. We should think about if we should support these in azure.yaml
+	// because presently they are the only service targets that are tied to a language.
 	case AppServiceTarget,
 		ContainerAppTarget,
 		AzureFunctionTarget,
@@ -135,7 +136,7 @@ func resourceTypeMismatchError(
 // As an example, ContainerAppTarget is able to provision the container app as part of deployment,
 // and thus returns true.
 func (st ServiceTargetKind) SupportsDelayedProvisioning() bool {
-	return st == AksTarget
+	return st == AksTarget || st == DotNetAksTarget
 }
 
 func checkResourceType(resource *environment.TargetResource, expectedResourceType infra.AzureResourceType) error {
@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"log"
+	"maps"
 	"net/url"
 	"os"
 	"path/filepath"
@@ -26,12 +28,19 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
 	"github.com/sethvargo/go-retry"
 )
 
 const (
 	defaultDeploymentPath = "manifests"
+	// The time to wait for the rollout of each resource applied from the k8s manifests
+	rolloutTimeout = 10 * time.Minute
+	// The cluster of the current kube context, ex) a kind, minikube or k3d cluster
+	AksClusterLocal = "local"
+	// The registry images are pushed to when deploying to a local cluster without a 'docker.registry'
+	defaultLocalRegistry = "localhost:5000"
 )
 
 var (
@@ -45,6 +54,9 @@ var (
 
 // The AKS configuration options
 type AksOptions struct {
+	// The k8s cluster to deploy to. Defaults to the AKS cluster of the service.
+	// Set to 'local' to deploy to the cluster of the current kube context or to the name of a kube context.
+	Cluster string `yaml:"cluster,omitempty"`
 	// The namespace used for deploying k8s resources. Defaults to the project name
 	Namespace string `yaml:"namespace"`
 	// The relative folder path from the service that contains the k8s deployment manifests. Defaults to 'manifests'
@@ -59,8 +71,27 @@ type AksOptions struct {
 	Helm *helm.Config `yaml:"helm"`
 	// The kustomize configuration options
 	Kustomize *kustomize.Config `yaml:"kustomize"`
+	// The format the services of an Aspire app host are deployed as. Defaults to 'manifests'
+	AspireFormat AksAspireFormat `yaml:"aspireFormat,omitempty"`
+	// The workload identity configuration options
+	Identity *AksIdentityOptions `yaml:"identity,omitempty"`
 }
 
+// IsLocalCluster returns true when the service is deployed to a local cluster or kube context instead of an AKS cluster.
+func (o AksOptions) IsLocalCluster() bool {
+	return o.Cluster != ""
+}
+
+// AksAspireFormat is the format azd generates for each service of an Aspire app host deployed to AKS.
+type AksAspireFormat string
+
+const (
+	// Kubernetes manifests, applied with kubectl
+	AksAspireFormatManifests AksAspireFormat = "manifests"
+	// A Helm chart, installed as a release named after the service
+	AksAspireFormatHelm AksAspireFormat = "helm"
+)
+
 // The AKS ingress options
 type AksIngressOptions struct {
 	Name         string `yaml:"name"`
@@ -82,6 +113,7 @@ type aksTarget struct {
 	envManager             environment.Manager
 	console                input.Console
 	managedClustersService azcli.ManagedClustersService
+	managedIdentityService azcli.ManagedIdentityService
 	resourceManager        ResourceManager
 	kubectl                kubectl.KubectlCli
 	kubeLoginCli           *kubelogin.Cli
@@ -97,6 +129,7 @@ func NewAksTarget(
 	envManager environment.Manager,
 	console input.Console,
 	managedClustersService azcli.ManagedClustersService,
+	managedIdentityService azcli.ManagedIdentityService,
 	resourceManager ResourceManager,
 	kubectlCli kubectl.KubectlCli,
 	kubeLoginCli *kubelogin.Cli,
@@ -110,6 +143,7 @@ func NewAksTarget(
 		envManager:             envManager,
 		console:                console,
 		managedClustersService: managedClustersService,
+		managedIdentityService: managedIdentityService,
 		resourceManager:        resourceManager,
 		kubectl:                kubectlCli,
 		kubeLoginCli:           kubeLoginCli,
@@ -206,16 +240,26 @@ func (t *aksTarget) Deploy(
 			// Only deploy the container image if a package output has been defined
 			// Empty package details is a valid scenario for any AKS deployment that does not build any containers
 			// Ex) Helm charts, or other manifests that reference external images
+			var image string
 			if packageOutput.Details != nil || packageOutput.PackagePath != "" {
 				// Login, tag & push container image to ACR
 				containerDeployTask := t.containerHelper.Deploy(ctx, serviceConfig, packageOutput, targetResource, true)
 				syncProgress(task, containerDeployTask.Progress())
 
-				_, err := containerDeployTask.Await()
+				containerDeployResult, err := containerDeployTask.Await()
 				if err != nil {
 					task.SetError(err)
 					return
 				}
+
+				if details, ok := containerDeployResult.Details.(*dockerDeployResult); ok {
+					image = details.RemoteImageTag
+				}
+			}
+
+			if err := t.ensureWorkloadIdentity(ctx, serviceConfig, targetResource, task); err != nil {
+				task.SetError(fmt.Errorf("workload identity configuration failed: %w", err))
+				return
 			}
 
 			// Sync environment
@@ -233,7 +277,7 @@ func (t *aksTarget) Deploy(
 			deployed := false
 
 			// Helm Support
-			helmDeployed, err := t.deployHelmCharts(ctx, serviceConfig, task)
+			helmDeployed, err := t.deployHelmCharts(ctx, serviceConfig, targetResource, image, task)
 			if err != nil {
 				task.SetError(fmt.Errorf("helm deployment failed: %w", err))
 				return
@@ -251,7 +295,7 @@ func (t *aksTarget) Deploy(
 			deployed = deployed || kustomizeDeployed
 
 			// Vanilla k8s manifests with minimal templating support
-			manifestsDeployed, deployment, err := t.deployManifests(ctx, serviceConfig, task)
+			manifestsDeployed, details, err := t.deployManifests(ctx, serviceConfig, task)
 			if err != nil && !os.IsNotExist(err) {
 				task.SetError(err)
 				return
@@ -264,39 +308,62 @@ func (t *aksTarget) Deploy(
 				return
 			}
 
-			task.SetProgress(NewServiceProgress("Fetching endpoints for AKS service"))
-			endpoints, err := t.Endpoints(ctx, serviceConfig, targetResource)
+			result, err := t.deployResult(ctx, serviceConfig, packageOutput, targetResource, details, task)
 			if err != nil {
 				task.SetError(err)
 				return
 			}
 
-			if len(endpoints) > 0 {
-				// The AKS endpoints contain some additional identifying information
-				// Regex is used to pull the URL ignoring the additional metadata
-				// The last endpoint in the array will be the most publicly exposed
-				matches := endpointRegex.FindStringSubmatch(endpoints[len(endpoints)-1])
-				if len(matches) > 1 {
-					t.env.SetServiceProperty(serviceConfig.Name, "ENDPOINT_URL", matches[1])
-					if err := t.envManager.Save(ctx, t.env); err != nil {
-						task.SetError(fmt.Errorf("failed updating environment with endpoint url, %w", err))
-						return
-					}
-				}
+			task.SetResult(result)
+		})
+}
+
+// deployResult fetches the endpoints of a service deployed to the k8s cluster, saves the most publicly exposed one to the
+// environment and returns the result of the deployment.
+func (t *aksTarget) deployResult(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageOutput *ServicePackageResult,
+	targetResource *environment.TargetResource,
+	details *aksDeployResult,
+	task *async.TaskContextWithProgress[*ServiceDeployResult, ServiceProgress],
+) (*ServiceDeployResult, error) {
+	task.SetProgress(NewServiceProgress("Fetching endpoints for AKS service"))
+	endpoints, err := t.Endpoints(ctx, serviceConfig, targetResource)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(endpoints) > 0 {
+		// The AKS endpoints contain some additional identifying information
+		// Regex is used to pull the URL ignoring the additional metadata
+		// The last endpoint in the array will be the most publicly exposed
+		matches := endpointRegex.FindStringSubmatch(endpoints[len(endpoints)-1])
+		if len(matches) > 1 {
+			t.env.SetServiceProperty(serviceConfig.Name, "ENDPOINT_URL", matches[1])
+			if err := t.envManager.Save(ctx, t.env); err != nil {
+				return nil, fmt.Errorf("failed updating environment with endpoint url, %w", err)
 			}
+		}
+	}
 
-			task.SetResult(&ServiceDeployResult{
-				Package: packageOutput,
-				TargetResourceId: azure.KubernetesServiceRID(
-					targetResource.SubscriptionId(),
-					targetResource.ResourceGroupName(),
-					targetResource.ResourceName(),
-				),
-				Kind:      AksTarget,
-				Details:   deployment,
-				Endpoints: endpoints,
-			})
-		})
+	// Local clusters are not Azure resources
+	targetResourceId := ""
+	if !serviceConfig.K8s.IsLocalCluster() {
+		targetResourceId = azure.KubernetesServiceRID(
+			targetResource.SubscriptionId(),
+			targetResource.ResourceGroupName(),
+			targetResource.ResourceName(),
+		)
+	}
+
+	return &ServiceDeployResult{
+		Package:          packageOutput,
+		TargetResourceId: targetResourceId,
+		Kind:             AksTarget,
+		Details:          details,
+		Endpoints:        endpoints,
+	}, nil
 }
 
 // deployManifests deploys raw or templated yaml manifests to the k8s cluster
@@ -304,7 +371,7 @@ func (t *aksTarget) deployManifests(
 	ctx context.Context,
 	serviceConfig *ServiceConfig,
 	task *async.TaskContextWithProgress[*ServiceDeployResult, ServiceProgress],
-) (bool, *kubectl.Deployment, error) {
+) (bool, *aksDeployResult, error) {
 	deploymentPath := serviceConfig.K8s.DeploymentPath
 	if deploymentPath == "" {
 		deploymentPath = defaultDeploymentPath
@@ -317,32 +384,139 @@ func (t *aksTarget) deployManifests(
 		return false, nil, err
 	}
 
+	deploymentName := serviceConfig.K8s.Deployment.Name
+	if deploymentName == "" {
+		deploymentName = serviceConfig.Name
+	}
+
+	result, err := t.applyManifests(ctx, serviceConfig, deploymentPath, deploymentName, task)
+	if err != nil {
+		// We continue to return a true value here when the manifests have been applied
+		// even though the rollout of the resources may have failed
+		return result != nil, result, err
+	}
+
+	return true, result, nil
+}
+
+// applyManifests applies the manifests at the specified path, rendering templates with the environment and the
+// properties of the service, and waits for the rollout of the Deployments, StatefulSets and Jobs that were applied.
+// When the manifests were applied, a result is returned even if a rollout failed.
+func (t *aksTarget) applyManifests(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	path string,
+	deploymentName string,
+	task *async.TaskContextWithProgress[*ServiceDeployResult, ServiceProgress],
+) (*aksDeployResult, error) {
 	task.SetProgress(NewServiceProgress("Applying k8s manifests"))
-	err := t.kubectl.Apply(
-		ctx,
-		deploymentPath,
-		nil,
-	)
+	resources, err := t.kubectl.ApplyWithTemplateData(ctx, path, t.templateData(serviceConfig), nil)
 	if err != nil {
-		return false, nil, fmt.Errorf("failed applying kube manifests: %w", err)
+		return nil, fmt.Errorf("failed applying kube manifests: %w", err)
 	}
 
-	deploymentName := serviceConfig.K8s.Deployment.Name
-	if deploymentName == "" {
-		deploymentName = serviceConfig.Name
+	result := &aksDeployResult{}
+
+	if err := t.applyWorkloadIdentity(ctx, serviceConfig, resources); err != nil {
+		return result, err
+	}
+
+	task.SetProgress(NewServiceProgress("Waiting for rollout"))
+	result.Resources, err = t.waitForRollouts(ctx, resources)
+	if err != nil {
+		return result, err
 	}
 
 	// It is not a requirement for a AZD deploy to contain a deployment object
 	// If we don't find any deployment within the namespace we will continue
 	task.SetProgress(NewServiceProgress("Verifying deployment"))
-	deployment, err := t.waitForDeployment(ctx, deploymentName)
+	result.Deployment, err = t.waitForDeployment(ctx, deploymentName)
 	if err != nil && !errors.Is(err, kubectl.ErrResourceNotFound) {
-		// We continue to return a true value here since at this point we have successfully applied the manifests
-		// even through the deployment may not have been found
-		return true, nil, err
+		return result, err
 	}
 
-	return true, deployment, nil
+	return result, nil
+}
+
+// templateData returns the data available to the templates of the k8s manifests of the service
+func (t *aksTarget) templateData(serviceConfig *ServiceConfig) *kubectl.TemplateData {
+	env := t.env.Dotenv()
+	prefix := fmt.Sprintf("SERVICE_%s_", strings.ReplaceAll(strings.ToUpper(serviceConfig.Name), "-", "_"))
+
+	properties := map[string]string{}
+	for key, value := range env {
+		if name, has := strings.CutPrefix(key, prefix); has {
+			properties[name] = value
+		}
+	}
+
+	return &kubectl.TemplateData{
+		Env: env,
+		Service: &kubectl.TemplateService{
+			Name:       serviceConfig.Name,
+			Namespace:  t.getK8sNamespace(serviceConfig),
+			Image:      t.env.GetServiceProperty(serviceConfig.Name, "IMAGE_NAME"),
+			Properties: properties,
+		},
+	}
+}
+
+// waitForRollouts waits for the rollout of each Deployment and StatefulSet and for the completion of each Job within
+// the resources. Deployments and StatefulSets that fail to roll out are rolled back to their previous revision.
+// The status of every resource waited on is returned, along with an error when any of them failed.
+func (t *aksTarget) waitForRollouts(ctx context.Context, resources []kubectl.Resource) ([]*aksResourceStatus, error) {
+	statuses := []*aksResourceStatus{}
+	failed := []string{}
+
+	for _, resource := range resources {
+		var flags *kubectl.KubeCliFlags
+		if resource.Metadata.Namespace != "" {
+			flags = &kubectl.KubeCliFlags{Namespace: resource.Metadata.Namespace}
+		}
+
+		status := &aksResourceStatus{
+			Kind:      resource.Kind,
+			Name:      resource.Metadata.Name,
+			Namespace: resource.Metadata.Namespace,
+			Status:    aksResourceStatusSucceeded,
+		}
+
+		var err error
+
+		switch resource.Kind {
+		case "Deployment", "StatefulSet":
+			if _, err = t.kubectl.WaitForRollout(ctx, resource.Ref(), rolloutTimeout, flags); err != nil {
+				status.Status = aksResourceStatusFailed
+				status.Message = err.Error()
+
+				log.Printf("rolling back %s after failed rollout", resource.Ref())
+				if _, undoErr := t.kubectl.RolloutUndo(ctx, resource.Ref(), flags); undoErr != nil {
+					log.Printf("failed rolling back %s: %v", resource.Ref(), undoErr)
+				} else {
+					status.Status = aksResourceStatusRolledBack
+				}
+			}
+		case "Job":
+			if _, err = t.kubectl.WaitForCondition(ctx, resource.Ref(), "complete", rolloutTimeout, flags); err != nil {
+				status.Status = aksResourceStatusFailed
+				status.Message = err.Error()
+			}
+		default:
+			continue
+		}
+
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s (%s)", resource.Ref(), status.Status))
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	if len(failed) > 0 {
+		return statuses, fmt.Errorf("rollout failed for %s", strings.Join(failed, ", "))
+	}
+
+	return statuses, nil
 }
 
 // deployKustomize deploys kustomize manifests to the k8s cluster
@@ -424,9 +598,14 @@ func (t *aksTarget) deployKustomize(
 	return true, nil
 }
 
-// deployHelmCharts deploys helm charts to the k8s cluster
+// deployHelmCharts deploys helm charts to the k8s cluster. When the service built a container image, the image is set
+// on the releases of charts stored next to the service and on the releases that configure the paths of their image
+// values.
 func (t *aksTarget) deployHelmCharts(
-	ctx context.Context, serviceConfig *ServiceConfig,
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	image string,
 	task *async.TaskContextWithProgress[*ServiceDeployResult, ServiceProgress],
 ) (bool, error) {
 	if serviceConfig.K8s.Helm == nil {
@@ -448,18 +627,35 @@ func (t *aksTarget) deployHelmCharts(
 		}
 	}
 
-	for _, release := range serviceConfig.K8s.Helm.Releases {
-		if release.Namespace == "" {
-			release.Namespace = t.getK8sNamespace(serviceConfig)
-		}
+	for _, configRelease := range serviceConfig.K8s.Helm.Releases {
+		release := t.resolveHelmRelease(serviceConfig, configRelease, image)
 
 		if err := t.ensureNamespace(ctx, release.Namespace); err != nil {
 			return false, err
 		}
 
+		if release.IsOci() {
+			if err := t.loginHelmRegistry(ctx, release, targetResource, task); err != nil {
+				return false, err
+			}
+		}
+
+		if serviceConfig.K8s.Helm.Diff {
+			previewer := t.console.ShowPreviewer(ctx, &input.ShowPreviewerOptions{
+				Prefix:       "  ",
+				MaxLineCount: 20,
+				Title:        fmt.Sprintf("Helm diff: %s", release.Name),
+			})
+			err := t.helmCli.Diff(ctx, release, previewer)
+			t.console.StopPreviewer(ctx, true)
+			if err != nil {
+				return false, err
+			}
+		}
+
 		task.SetProgress(NewServiceProgress(fmt.Sprintf("Installing helm release: %s", release.Name)))
 		if err := t.helmCli.Upgrade(ctx, release); err != nil {
-			return false, err
+			return false, t.rollbackHelmRelease(ctx, release, task, err)
 		}
 
 		task.SetProgress(NewServiceProgress(fmt.Sprintf("Checking helm release status: %s", release.Name)))
@@ -472,25 +668,133 @@ func (t *aksTarget) deployHelmCharts(
 					return err
 				}
 
-				if status.Info.Status != helm.StatusKindDeployed {
-					fmt.Printf("Status: %s\n", status.Info.Status)
-					return retry.RetryableError(
-						fmt.Errorf("helm release '%s' is not ready, %w", release.Name, err),
-					)
+				switch status.Info.Status {
+				case helm.StatusKindDeployed:
+					return nil
+				case helm.StatusKindFailed:
+					return fmt.Errorf("helm release '%s' failed", release.Name)
+				default:
+					log.Printf("helm release '%s' status: %s", release.Name, status.Info.Status)
+					return retry.RetryableError(fmt.Errorf("helm release '%s' is not ready", release.Name))
 				}
-
-				return nil
 			},
 		)
 
 		if err != nil {
-			return false, err
+			return false, t.rollbackHelmRelease(ctx, release, task, err)
 		}
 	}
 
 	return true, nil
 }
 
+// resolveHelmRelease returns a copy of the release configured for the service with its namespace defaulted, its local
+// chart and values paths resolved from the service directory and the container image of the service set on its values.
+func (t *aksTarget) resolveHelmRelease(serviceConfig *ServiceConfig, release *helm.Release, image string) *helm.Release {
+	resolved := *release
+	resolved.Set = maps.Clone(release.Set)
+	resolved.SetString = maps.Clone(release.SetString)
+
+	if resolved.Namespace == "" {
+		resolved.Namespace = t.getK8sNamespace(serviceConfig)
+	}
+
+	localChart := false
+	if !resolved.IsOci() {
+		chartPath := resolved.Chart
+		if !filepath.IsAbs(chartPath) {
+			chartPath = filepath.Join(serviceConfig.Path(), chartPath)
+		}
+
+		if _, err := os.Stat(filepath.Join(chartPath, "Chart.yaml")); err == nil {
+			resolved.Chart = chartPath
+			localChart = true
+		}
+	}
+
+	if resolved.Values != "" && !filepath.IsAbs(resolved.Values) {
+		valuesPath := filepath.Join(serviceConfig.Path(), resolved.Values)
+		if _, err := os.Stat(valuesPath); err == nil {
+			resolved.Values = valuesPath
+		}
+	}
+
+	// Charts from external repositories only receive the image when they opt in by configuring the image values
+	if image == "" || (!localChart && release.Image == nil) {
+		return &resolved
+	}
+
+	imageValues := helm.ImageValues{
+		Repository: helm.DefaultImageRepositoryValue,
+		Tag:        helm.DefaultImageTagValue,
+	}
+	if release.Image != nil {
+		if release.Image.Repository != "" {
+			imageValues.Repository = release.Image.Repository
+		}
+		if release.Image.Tag != "" {
+			imageValues.Tag = release.Image.Tag
+		}
+	}
+
+	// The image is set as strings, helm would otherwise parse tags like '1.0' as numbers
+	if resolved.SetString == nil {
+		resolved.SetString = map[string]string{}
+	}
+
+	repository, tag := docker.SplitDockerImage(image)
+	resolved.SetString[imageValues.Repository] = repository
+	if tag != "" {
+		resolved.SetString[imageValues.Tag] = tag
+	}
+
+	return &resolved
+}
+
+// loginHelmRegistry logs helm into the ACR the chart of the release is stored in. Other OCI registries require a manual
+// 'helm registry login'.
+func (t *aksTarget) loginHelmRegistry(
+	ctx context.Context,
+	release *helm.Release,
+	targetResource *environment.TargetResource,
+	task *async.TaskContextWithProgress[*ServiceDeployResult, ServiceProgress],
+) error {
+	host := release.RegistryHost()
+	creds, err := t.containerHelper.RegistryCredentials(ctx, targetResource.SubscriptionId(), host)
+	if err != nil {
+		return fmt.Errorf("getting credentials for registry '%s': %w", host, err)
+	}
+
+	if creds == nil {
+		log.Printf("skipping login to non ACR registry '%s'", host)
+		return nil
+	}
+
+	task.SetProgress(NewServiceProgress(fmt.Sprintf("Logging into helm registry: %s", host)))
+	return t.helmCli.RegistryLogin(ctx, creds.LoginServer, creds.Username, creds.Password)
+}
+
+// rollbackHelmRelease rolls back a release whose upgrade failed to its previous revision. Releases that were installed
+// for the first time have no revision to roll back to and are left as is.
+func (t *aksTarget) rollbackHelmRelease(
+	ctx context.Context,
+	release *helm.Release,
+	task *async.TaskContextWithProgress[*ServiceDeployResult, ServiceProgress],
+	deployErr error,
+) error {
+	status, err := t.helmCli.Status(ctx, release)
+	if err != nil || status.Info.Status != helm.StatusKindFailed || status.Version <= 1 {
+		return deployErr
+	}
+
+	task.SetProgress(NewServiceProgress(fmt.Sprintf("Rolling back helm release: %s", release.Name)))
+	if err := t.helmCli.Rollback(ctx, release); err != nil {
+		return fmt.Errorf("%w, %w", deployErr, err)
+	}
+
+	return fmt.Errorf("%w, release '%s' was rolled back to its previous revision", deployErr, release.Name)
+}
+
 // Gets the service endpoints for the AKS service target
 func (t *aksTarget) Endpoints(
 	ctx context.Context,
@@ -526,11 +830,62 @@ func (t *aksTarget) Endpoints(
 	return endpoints, nil
 }
 
+// Streams the logs of all containers of the pods of the deployment of the service with kubectl
+func (t *aksTarget) Logs(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	options *LogsOptions,
+	writer io.Writer,
+) error {
+	if err := t.validateTargetResource(ctx, serviceConfig, targetResource); err != nil {
+		return fmt.Errorf("validating target resource: %w", err)
+	}
+
+	t.kubectl.SetEnv(t.env.Dotenv())
+	if kubeConfigPath := t.env.Getenv(kubectl.KubeConfigEnvVarName); kubeConfigPath != "" {
+		t.kubectl.SetKubeConfig(kubeConfigPath)
+	}
+
+	namespace := t.getK8sNamespace(serviceConfig)
+	if _, err := t.ensureClusterContext(ctx, serviceConfig, targetResource, namespace); err != nil {
+		return err
+	}
+
+	deploymentName := serviceConfig.K8s.Deployment.Name
+	if deploymentName == "" {
+		deploymentName = serviceConfig.Name
+	}
+
+	deployment, err := kubectl.GetResource[*kubectl.Deployment](
+		ctx, t.kubectl, kubectl.ResourceTypeDeployment, deploymentName, &kubectl.KubeCliFlags{Namespace: namespace})
+	if err != nil {
+		return fmt.Errorf("failed retrieving deployment '%s', %w", deploymentName, err)
+	}
+
+	if deployment.Spec.Selector == nil || len(deployment.Spec.Selector.MatchLabels) == 0 {
+		return fmt.Errorf("deployment '%s' does not select its pods by labels", deploymentName)
+	}
+
+	return t.kubectl.Logs(
+		ctx,
+		deployment.Spec.Selector.String(),
+		options.Follow,
+		options.Since,
+		writer,
+		&kubectl.KubeCliFlags{Namespace: namespace},
+	)
+}
+
 func (t *aksTarget) validateTargetResource(
 	ctx context.Context,
 	serviceConfig *ServiceConfig,
 	targetResource *environment.TargetResource,
 ) error {
+	if serviceConfig.K8s.IsLocalCluster() {
+		return nil
+	}
+
 	if targetResource.ResourceGroupName() == "" {
 		return fmt.Errorf("missing resource group name: %s", targetResource.ResourceGroupName())
 	}
@@ -546,9 +901,14 @@ func (t *aksTarget) ensureClusterContext(
 ) (string, error) {
 	kubeConfigPath := t.env.Getenv(kubectl.KubeConfigEnvVarName)
 	if kubeConfigPath != "" {
+		t.helmCli.SetKubeConfig(kubeConfigPath)
 		return kubeConfigPath, nil
 	}
 
+	if serviceConfig.K8s.IsLocalCluster() {
+		return t.ensureLocalClusterContext(ctx, serviceConfig, defaultNamespace)
+	}
+
 	// Login to AKS cluster
 	clusterName, err := t.resolveClusterName(serviceConfig, targetResource)
 	if err != nil {
@@ -642,6 +1002,64 @@ func (t *aksTarget) ensureClusterContext(
 		)
 	}
 
+	t.helmCli.SetKubeConfig(kubeConfigPath)
+	return kubeConfigPath, nil
+}
+
+// ensureLocalClusterContext copies the kube context configured in 'k8s.cluster', or the current kube context when it is
+// 'local', to a kube config managed by azd and sets the default namespace of the copied context. The kube config of the
+// user is left as is, and no AKS credentials are fetched.
+func (t *aksTarget) ensureLocalClusterContext(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	defaultNamespace string,
+) (string, error) {
+	// The context is read from the kube config of the user, which kubectl defaults to when KUBECONFIG is empty
+	t.kubectl.SetKubeConfig(os.Getenv(kubectl.KubeConfigEnvVarName))
+
+	viewArgs := []string{"config", "view", "--minify", "--flatten"}
+	contextName := serviceConfig.K8s.Cluster
+	if contextName != AksClusterLocal {
+		viewArgs = append(viewArgs, "--context", contextName)
+	}
+
+	viewResult, err := t.kubectl.Exec(ctx, nil, viewArgs...)
+	if err != nil {
+		return "", fmt.Errorf(
+			"failed reading kube context '%s'. Ensure the specified context exists. %w", contextName,
+			err,
+		)
+	}
+
+	kubeConfigManager, err := kubectl.NewKubeConfigManager(t.kubectl)
+	if err != nil {
+		return "", err
+	}
+
+	kubeConfigPath, err := kubeConfigManager.SaveRawKubeConfig(
+		ctx, fmt.Sprintf("azd-%s-%s", t.env.Name(), serviceConfig.Name), []byte(viewResult.Stdout))
+	if err != nil {
+		return "", fmt.Errorf("failed writing kube config, %w", err)
+	}
+
+	t.kubectl.SetKubeConfig(kubeConfigPath)
+	t.helmCli.SetKubeConfig(kubeConfigPath)
+
+	if contextName != AksClusterLocal {
+		if _, err := t.kubectl.ConfigUseContext(ctx, contextName, nil); err != nil {
+			return "", fmt.Errorf(
+				"failed setting kube context '%s'. Ensure the specified context exists. %w", contextName,
+				err,
+			)
+		}
+	}
+
+	// Set default namespace for the context
+	// This avoids having to specify the namespace for every kubectl command
+	if _, err := t.kubectl.ConfigSetNamespace(ctx, defaultNamespace, nil); err != nil {
+		return "", fmt.Errorf("failed setting namespace of kube context, %w", err)
+	}
+
 	return kubeConfigPath, nil
 }
 
@@ -849,13 +1267,19 @@ func (t *aksTarget) setK8sContext(ctx context.Context, serviceConfig *ServiceCon
 		hasCustomKubeConfig = true
 	}
 
-	targetResource, err := t.resourceManager.GetTargetResource(ctx, t.env.GetSubscriptionId(), serviceConfig)
-	if err != nil {
-		return err
+	// Local clusters are not Azure resources
+	targetResource := environment.NewTargetResource(t.env.GetSubscriptionId(), "", "", "")
+	if !serviceConfig.K8s.IsLocalCluster() {
+		resource, err := t.resourceManager.GetTargetResource(ctx, t.env.GetSubscriptionId(), serviceConfig)
+		if err != nil {
+			return err
+		}
+
+		targetResource = resource
 	}
 
 	defaultNamespace := t.getK8sNamespace(serviceConfig)
-	_, err = t.ensureClusterContext(ctx, serviceConfig, targetResource, defaultNamespace)
+	_, err := t.ensureClusterContext(ctx, serviceConfig, targetResource, defaultNamespace)
 	if err != nil {
 		return err
 	}
@@ -911,3 +1335,29 @@ func (t *aksTarget) resolveClusterName(
 
 	return clusterName, nil
 }
+
+// aksDeployResult is the details of the result of a deployment to AKS. The deployment of the service is embedded, so
+// the details keep the shape of the deployment they used to be, with the status of the rollouts as an additional field.
+type aksDeployResult struct {
+	// The deployment of the service
+	*kubectl.Deployment
+	// The status of the rollout of each Deployment, StatefulSet and Job that was applied
+	Resources []*aksResourceStatus `json:"resources,omitempty"`
+}
+
+type aksResourceStatusKind string
+
+const (
+	aksResourceStatusSucceeded  aksResourceStatusKind = "Succeeded"
+	aksResourceStatusFailed     aksResourceStatusKind = "Failed"
+	aksResourceStatusRolledBack aksResourceStatusKind = "RolledBack"
+)
+
+// aksResourceStatus is the status of the rollout of a k8s resource
+type aksResourceStatus struct {
+	Kind      string                `json:"kind"`
+	Name      string                `json:"name"`
+	Namespace string                `json:"namespace,omitempty"`
+	Status    aksResourceStatusKind `json:"status"`
+	Message   string                `json:"message,omitempty"`
+}
@@ -0,0 +1,412 @@
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/convert"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
+	"gopkg.in/yaml.v3"
+)
+
+// The AKS workload identity configuration options
+type AksIdentityOptions struct {
+	// The name of the user-assigned managed identity the pods of the service authenticate as.
+	// Supports environment variable substitution.
+	Name osutil.ExpandableString `yaml:"name"`
+	// The resource group of the managed identity. Defaults to the resource group of the AKS cluster.
+	// Supports environment variable substitution.
+	ResourceGroup osutil.ExpandableString `yaml:"resourceGroup,omitempty"`
+	// The k8s service account federated with the managed identity. Defaults to the service name
+	ServiceAccount string `yaml:"serviceAccount,omitempty"`
+	// The Key Vault secrets made available to the pods of the service with the Secrets Store CSI driver
+	KeyVault *AksKeyVaultOptions `yaml:"keyVault,omitempty"`
+}
+
+// The AKS Key Vault secrets configuration options
+type AksKeyVaultOptions struct {
+	// The name of the Key Vault. Supports environment variable substitution.
+	Name osutil.ExpandableString `yaml:"name"`
+	// The names of the secrets to make available
+	Secrets []string `yaml:"secrets"`
+}
+
+// The label that makes the workload identity webhook inject the token of the service account into pods
+const workloadIdentityUseLabel = "azure.workload.identity/use"
+
+// The path the Key Vault secrets are mounted at within the containers of the pods of the service
+const keyVaultMountPath = "/mnt/secrets-store"
+
+// Characters that are not allowed within the name of a federated credential
+var federatedCredentialNameRegex = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+
+// ensureWorkloadIdentity federates the k8s service account of the service with the user-assigned managed identity
+// configured in `k8s.identity`, so that pods running as the service account can authenticate to Azure without secrets.
+// The client ID of the identity and the name of the service account are saved to the environment as the
+// IDENTITY_CLIENT_ID and SERVICE_ACCOUNT_NAME service properties, which are available to templated manifests.
+// When Key Vault secrets are configured, a SecretProviderClass that mounts them as the identity is also applied.
+func (t *aksTarget) ensureWorkloadIdentity(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	task *async.TaskContextWithProgress[*ServiceDeployResult, ServiceProgress],
+) error {
+	identityOptions := serviceConfig.K8s.Identity
+	if identityOptions == nil {
+		return nil
+	}
+
+	if serviceConfig.K8s.IsLocalCluster() {
+		return errors.New("'k8s.identity' requires an AKS cluster and is not supported with 'k8s.cluster'")
+	}
+
+	identityName, err := identityOptions.Name.Envsubst(t.env.Getenv)
+	if err != nil {
+		return fmt.Errorf("failed to envsubst identity name: %w", err)
+	}
+
+	if identityName == "" {
+		return errors.New("'k8s.identity.name' must be set to the name of a user-assigned managed identity")
+	}
+
+	resourceGroupName, err := identityOptions.ResourceGroup.Envsubst(t.env.Getenv)
+	if err != nil {
+		return fmt.Errorf("failed to envsubst identity resource group: %w", err)
+	}
+
+	if resourceGroupName == "" {
+		resourceGroupName = targetResource.ResourceGroupName()
+	}
+
+	clusterName, err := t.resolveClusterName(serviceConfig, targetResource)
+	if err != nil {
+		return err
+	}
+
+	task.SetProgress(NewServiceProgress("Configuring workload identity"))
+	managedCluster, err := t.managedClustersService.Get(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		clusterName,
+	)
+	if err != nil {
+		return fmt.Errorf("failed retrieving managed cluster, %w", err)
+	}
+
+	properties := managedCluster.Properties
+	issuerUrl := ""
+	if properties.OidcIssuerProfile != nil && convert.ToValueWithDefault(properties.OidcIssuerProfile.Enabled, false) {
+		issuerUrl = convert.ToValueWithDefault(properties.OidcIssuerProfile.IssuerURL, "")
+	}
+
+	if issuerUrl == "" {
+		return fmt.Errorf(
+			"the OIDC issuer and workload identity must be enabled on the AKS cluster '%s' to use 'k8s.identity'",
+			clusterName,
+		)
+	}
+
+	if identityOptions.KeyVault != nil {
+		addon, has := properties.AddonProfiles["azureKeyvaultSecretsProvider"]
+		if !has || addon == nil || !convert.ToValueWithDefault(addon.Enabled, false) {
+			return fmt.Errorf(
+				"the Azure Key Vault secrets provider add-on must be enabled on the AKS cluster '%s' "+
+					"to use 'k8s.identity.keyVault'",
+				clusterName,
+			)
+		}
+	}
+
+	identity, err := t.managedIdentityService.Get(
+		ctx,
+		targetResource.SubscriptionId(),
+		resourceGroupName,
+		identityName,
+	)
+	if err != nil {
+		return fmt.Errorf("failed retrieving managed identity '%s', %w", identityName, err)
+	}
+
+	if identity.Properties == nil {
+		return fmt.Errorf("managed identity '%s' is missing its properties", identityName)
+	}
+
+	clientId := convert.ToValueWithDefault(identity.Properties.ClientID, "")
+	tenantId := convert.ToValueWithDefault(identity.Properties.TenantID, "")
+
+	namespace := t.getK8sNamespace(serviceConfig)
+	serviceAccount := identityOptions.ServiceAccount
+	if serviceAccount == "" {
+		serviceAccount = serviceConfig.Name
+	}
+
+	credentialName := federatedCredentialNameRegex.ReplaceAllString(
+		fmt.Sprintf("%s-%s-%s", clusterName, namespace, serviceAccount), "-")
+	subject := fmt.Sprintf("system:serviceaccount:%s:%s", namespace, serviceAccount)
+
+	log.Printf("federating managed identity '%s' with service account '%s'", identityName, subject)
+	err = t.managedIdentityService.CreateOrUpdateFederatedCredential(
+		ctx,
+		targetResource.SubscriptionId(),
+		resourceGroupName,
+		identityName,
+		credentialName,
+		issuerUrl,
+		subject,
+	)
+	if err != nil {
+		return fmt.Errorf("failed creating federated credential for managed identity '%s', %w", identityName, err)
+	}
+
+	manifests := []map[string]any{
+		serviceAccountManifest(serviceAccount, namespace, clientId, tenantId),
+	}
+
+	if identityOptions.KeyVault != nil {
+		keyVaultName, err := identityOptions.KeyVault.Name.Envsubst(t.env.Getenv)
+		if err != nil {
+			return fmt.Errorf("failed to envsubst key vault name: %w", err)
+		}
+
+		if keyVaultName == "" {
+			return errors.New("'k8s.identity.keyVault.name' must be set to the name of a Key Vault")
+		}
+
+		secretProviderClass, err := secretProviderClassManifest(
+			serviceConfig.Name, namespace, clientId, tenantId, keyVaultName, identityOptions.KeyVault.Secrets)
+		if err != nil {
+			return err
+		}
+
+		manifests = append(manifests, secretProviderClass)
+	}
+
+	for _, manifest := range manifests {
+		contents, err := yaml.Marshal(manifest)
+		if err != nil {
+			return fmt.Errorf("failed marshalling %s manifest, %w", manifest["kind"], err)
+		}
+
+		if _, err := t.kubectl.ApplyWithStdIn(ctx, string(contents), nil); err != nil {
+			return fmt.Errorf("failed applying %s, %w", manifest["kind"], err)
+		}
+	}
+
+	t.env.SetServiceProperty(serviceConfig.Name, "IDENTITY_CLIENT_ID", clientId)
+	t.env.SetServiceProperty(serviceConfig.Name, "SERVICE_ACCOUNT_NAME", serviceAccount)
+	if err := t.envManager.Save(ctx, t.env); err != nil {
+		return fmt.Errorf("failed updating environment with workload identity, %w", err)
+	}
+
+	return nil
+}
+
+// serviceAccountManifest returns the manifest of a service account federated with the managed identity.
+// Pods that run as the service account must have the azure.workload.identity/use: "true" label, which
+// applyWorkloadIdentity adds to the pod templates of the workloads of the service.
+func serviceAccountManifest(name string, namespace string, clientId string, tenantId string) map[string]any {
+	return map[string]any{
+		"apiVersion": "v1",
+		"kind":       "ServiceAccount",
+		"metadata": map[string]any{
+			"name":      name,
+			"namespace": namespace,
+			"annotations": map[string]string{
+				"azure.workload.identity/client-id": clientId,
+				"azure.workload.identity/tenant-id": tenantId,
+			},
+		},
+	}
+}
+
+// applyWorkloadIdentity makes the pods of the Deployments and StatefulSets within the applied resources run as the
+// service account federated with the managed identity of the service, and labels them so the workload identity webhook
+// injects the token of the service account. When Key Vault secrets are configured, the SecretProviderClass is also
+// mounted in every container of the pods, since the secrets are only fetched and synced once a pod mounts it.
+// Nothing is patched when `k8s.identity` isn't configured.
+func (t *aksTarget) applyWorkloadIdentity(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	resources []kubectl.Resource,
+) error {
+	if serviceConfig.K8s.Identity == nil {
+		return nil
+	}
+
+	serviceAccount := t.env.GetServiceProperty(serviceConfig.Name, "SERVICE_ACCOUNT_NAME")
+	secretProviderClass := ""
+	if serviceConfig.K8s.Identity.KeyVault != nil {
+		secretProviderClass = fmt.Sprintf("%s-keyvault", serviceConfig.Name)
+	}
+
+	for _, resource := range resources {
+		if resource.Kind != "Deployment" && resource.Kind != "StatefulSet" {
+			continue
+		}
+
+		var flags *kubectl.KubeCliFlags
+		if resource.Metadata.Namespace != "" {
+			flags = &kubectl.KubeCliFlags{Namespace: resource.Metadata.Namespace}
+		}
+
+		// Containers are merged by name, so the secrets volume is mounted in each of the containers of the pod template
+		var containers []string
+		if secretProviderClass != "" {
+			res, err := t.kubectl.Exec(
+				ctx, flags, "get", resource.Ref(), "-o", "jsonpath={.spec.template.spec.containers[*].name}")
+			if err != nil {
+				return fmt.Errorf("failed getting the containers of %s, %w", resource.Ref(), err)
+			}
+
+			containers = strings.Fields(res.Stdout)
+		}
+
+		patch, err := workloadIdentityPodPatch(serviceAccount, secretProviderClass, containers)
+		if err != nil {
+			return err
+		}
+
+		log.Printf("running the pods of %s as service account '%s'", resource.Ref(), serviceAccount)
+		if _, err := t.kubectl.Exec(
+			ctx, flags, "patch", resource.Ref(), "--type", "strategic", "--patch", patch); err != nil {
+			return fmt.Errorf("failed configuring workload identity of %s, %w", resource.Ref(), err)
+		}
+	}
+
+	return nil
+}
+
+// workloadIdentityPodPatch returns the strategic merge patch of a workload that sets the service account of its pod
+// template and adds the azure.workload.identity/use: "true" label to it. When secretProviderClass is set, the patch also
+// adds a Secrets Store CSI volume of the SecretProviderClass to the pod template and mounts it read-only at
+// /mnt/secrets-store in the given containers.
+func workloadIdentityPodPatch(serviceAccount string, secretProviderClass string, containers []string) (string, error) {
+	podSpec := map[string]any{
+		"serviceAccountName": serviceAccount,
+	}
+
+	if secretProviderClass != "" {
+		podSpec["volumes"] = []map[string]any{
+			{
+				"name": secretProviderClass,
+				"csi": map[string]any{
+					"driver":   "secrets-store.csi.k8s.io",
+					"readOnly": true,
+					"volumeAttributes": map[string]string{
+						"secretProviderClass": secretProviderClass,
+					},
+				},
+			},
+		}
+
+		containerPatches := []map[string]any{}
+		for _, container := range containers {
+			containerPatches = append(containerPatches, map[string]any{
+				"name": container,
+				"volumeMounts": []map[string]any{
+					{
+						"name":      secretProviderClass,
+						"mountPath": keyVaultMountPath,
+						"readOnly":  true,
+					},
+				},
+			})
+		}
+		podSpec["containers"] = containerPatches
+	}
+
+	patch, err := json.Marshal(map[string]any{
+		"spec": map[string]any{
+			"template": map[string]any{
+				"metadata": map[string]any{
+					"labels": map[string]string{
+						workloadIdentityUseLabel: "true",
+					},
+				},
+				"spec": podSpec,
+			},
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed marshalling workload identity patch, %w", err)
+	}
+
+	return string(patch), nil
+}
+
+// secretProviderClassManifest returns the manifest of a SecretProviderClass named <service>-keyvault that mounts the Key
+// Vault secrets as the managed identity. applyWorkloadIdentity mounts it in the pods of the service, after
+// which the secrets are also synced to the k8s secret with the same name, keyed by the names of the secrets.
+func secretProviderClassManifest(
+	serviceName string,
+	namespace string,
+	clientId string,
+	tenantId string,
+	keyVaultName string,
+	secrets []string,
+) (map[string]any, error) {
+	name := fmt.Sprintf("%s-keyvault", serviceName)
+
+	objects := []string{}
+	secretData := []map[string]string{}
+	for _, secret := range secrets {
+		object, err := yaml.Marshal(map[string]string{
+			"objectName": secret,
+			"objectType": "secret",
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed marshalling key vault object, %w", err)
+		}
+
+		objects = append(objects, string(object))
+		secretData = append(secretData, map[string]string{
+			"objectName": secret,
+			"key":        secret,
+		})
+	}
+
+	objectsParameter, err := yaml.Marshal(map[string]any{"array": objects})
+	if err != nil {
+		return nil, fmt.Errorf("failed marshalling key vault objects, %w", err)
+	}
+
+	spec := map[string]any{
+		"provider": "azure",
+		"parameters": map[string]string{
+			"usePodIdentity": "false",
+			"clientID":       clientId,
+			"keyvaultName":   keyVaultName,
+			"tenantId":       tenantId,
+			"objects":        string(objectsParameter),
+		},
+	}
+
+	if len(secretData) > 0 {
+		spec["secretObjects"] = []map[string]any{
+			{
+				"secretName": name,
+				"type":       "Opaque",
+				"data":       secretData,
+			},
+		}
+	}
+
+	return map[string]any{
+		"apiVersion": "secrets-store.csi.x-k8s.io/v1",
+		"kind":       "SecretProviderClass",
+		"metadata": map[string]any{
+			"name":      name,
+			"namespace": namespace,
+		},
+		"spec": spec,
+	}, nil
+}
@@ -1,19 +1,25 @@
 package project
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerregistry/armcontainerregistry"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v2"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/msi/armmsi"
 	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
 	"github.com/azure/azure-dev/cli/azd/pkg/async"
 	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
@@ -142,12 +148,494 @@ func Test_Package_Deploy_HappyPath(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, deployResult)
 	require.Equal(t, AksTarget, deployResult.Kind)
-	require.IsType(t, new(kubectl.Deployment), deployResult.Details)
+	require.IsType(t, new(aksDeployResult), deployResult.Details)
+	require.NotNil(t, deployResult.Details.(*aksDeployResult).Deployment)
+
+	// The details have the shape of the deployment of the service, with the status of the rollouts
+	details, err := json.Marshal(deployResult.Details)
+	require.NoError(t, err)
+	var detailsJson map[string]any
+	require.NoError(t, json.Unmarshal(details, &detailsJson))
+	require.Equal(t, "Deployment", detailsJson["kind"])
+	require.Equal(t, "api-deployment", detailsJson["metadata"].(map[string]any)["name"])
+	require.Contains(t, detailsJson, "spec")
+	require.Contains(t, detailsJson, "status")
+	require.NotContains(t, detailsJson, "deployment")
 	require.Greater(t, len(deployResult.Endpoints), 0)
 	// New env variable is created
 	require.Equal(t, "REGISTRY.azurecr.io/test-app/api-test:azd-deploy-0", env.Dotenv()["SERVICE_API_IMAGE_NAME"])
 }
 
+func Test_Deploy_Manifests_Rollout(t *testing.T) {
+	manifests := map[string]string{
+		"deployment.tmpl.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{ .Service.Name }}
+spec:
+  template:
+    spec:
+      containers:
+      - name: {{ .Service.Name }}
+        image: {{ .Service.Image }}
+`,
+		"job.yaml": `apiVersion: batch/v1
+kind: Job
+metadata:
+  name: migrate
+  namespace: jobs
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+`,
+	}
+
+	setup := func(t *testing.T, rolloutErr error) (*mocks.MockContext, *ServiceConfig, ServiceTarget, map[string]exec.RunArgs) {
+		tempDir := t.TempDir()
+		ostest.Chdir(t, tempDir)
+
+		mockContext := mocks.NewMockContext(context.Background())
+		err := setupMocksForAksTarget(mockContext)
+		require.NoError(t, err)
+
+		mockResults := map[string]exec.RunArgs{}
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "kubectl apply -f -")
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			mockResults["kubectl-apply-stdin"] = args
+			return exec.NewRunResult(0, "", ""), nil
+		})
+
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "kubectl rollout status deployment/api ")
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			mockResults["kubectl-rollout-status"] = args
+			if rolloutErr != nil {
+				return exec.NewRunResult(1, "", ""), rolloutErr
+			}
+			return exec.NewRunResult(0, "", ""), nil
+		})
+
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "kubectl rollout undo")
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			mockResults["kubectl-rollout-undo"] = args
+			return exec.NewRunResult(0, "", ""), nil
+		})
+
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "kubectl wait")
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			mockResults["kubectl-wait"] = args
+			return exec.NewRunResult(0, "", ""), nil
+		})
+
+		serviceConfig := createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+		env := createEnv()
+		env.SetServiceProperty("api", "IMAGE_NAME", "REGISTRY.azurecr.io/test-app/api-test:azd-deploy-0")
+
+		serviceTarget := createAksServiceTarget(mockContext, serviceConfig, env, nil)
+		err = simulateInitliaze(*mockContext.Context, serviceTarget, serviceConfig)
+		require.NoError(t, err)
+
+		manifestsDir := filepath.Join(serviceConfig.RelativePath, defaultDeploymentPath)
+		require.NoError(t, os.MkdirAll(manifestsDir, osutil.PermissionDirectory))
+		for name, contents := range manifests {
+			err := os.WriteFile(filepath.Join(manifestsDir, name), []byte(contents), osutil.PermissionFile)
+			require.NoError(t, err)
+		}
+
+		return mockContext, serviceConfig, serviceTarget, mockResults
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockContext, serviceConfig, serviceTarget, mockResults := setup(t, nil)
+
+		scope := environment.NewTargetResource("SUB_ID", "RG_ID", "", string(infra.AzureResourceTypeManagedCluster))
+		deployTask := serviceTarget.Deploy(*mockContext.Context, serviceConfig, &ServicePackageResult{}, scope)
+		logProgress(deployTask)
+		deployResult, err := deployTask.Await()
+		require.NoError(t, err)
+
+		applied, err := io.ReadAll(mockResults["kubectl-apply-stdin"].StdIn)
+		require.NoError(t, err)
+		require.Contains(t, string(applied), "image: REGISTRY.azurecr.io/test-app/api-test:azd-deploy-0")
+
+		require.Equal(t, []string{"wait", "job/migrate", "--for=condition=complete", "--timeout=10m0s", "-n", "jobs"},
+			mockResults["kubectl-wait"].Args)
+
+		details, ok := deployResult.Details.(*aksDeployResult)
+		require.True(t, ok)
+		require.Equal(t, []*aksResourceStatus{
+			{Kind: "Deployment", Name: "api", Status: aksResourceStatusSucceeded},
+			{Kind: "Job", Name: "migrate", Namespace: "jobs", Status: aksResourceStatusSucceeded},
+		}, details.Resources)
+	})
+
+	t.Run("RolloutUndo", func(t *testing.T) {
+		mockContext, serviceConfig, serviceTarget, mockResults := setup(t, errors.New("progress deadline exceeded"))
+
+		scope := environment.NewTargetResource("SUB_ID", "RG_ID", "", string(infra.AzureResourceTypeManagedCluster))
+		deployTask := serviceTarget.Deploy(*mockContext.Context, serviceConfig, &ServicePackageResult{}, scope)
+		logProgress(deployTask)
+		_, err := deployTask.Await()
+		require.Error(t, err)
+		require.ErrorContains(t, err, "rollout failed for deployment/api (RolledBack)")
+
+		undo, undoCalled := mockResults["kubectl-rollout-undo"]
+		require.True(t, undoCalled)
+		require.Equal(t, []string{"rollout", "undo", "deployment/api"}, undo.Args)
+
+		// Jobs are still waited on after a failed rollout
+		_, waitCalled := mockResults["kubectl-wait"]
+		require.True(t, waitCalled)
+	})
+}
+
+func Test_Deploy_WorkloadIdentity(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	err := setupMocksForAksTarget(mockContext)
+	require.NoError(t, err)
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.Contains(
+			request.URL.Path,
+			"Microsoft.ContainerService/managedClusters/AKS_CLUSTER",
+		)
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armcontainerservice.ManagedCluster{
+			ID: convert.RefOf("cluster1"),
+			Properties: &armcontainerservice.ManagedClusterProperties{
+				OidcIssuerProfile: &armcontainerservice.ManagedClusterOIDCIssuerProfile{
+					Enabled:   convert.RefOf(true),
+					IssuerURL: convert.RefOf("https://oidc.example.com/issuer/"),
+				},
+				AddonProfiles: map[string]*armcontainerservice.ManagedClusterAddonProfile{
+					"azureKeyvaultSecretsProvider": {
+						Enabled: convert.RefOf(true),
+					},
+				},
+			},
+		})
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.HasSuffix(
+			request.URL.Path,
+			"resourceGroups/IDENTITY_RG/providers/Microsoft.ManagedIdentity/userAssignedIdentities/api-identity",
+		)
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armmsi.Identity{
+			Properties: &armmsi.UserAssignedIdentityProperties{
+				ClientID: convert.RefOf("CLIENT_ID"),
+				TenantID: convert.RefOf("TENANT_ID"),
+			},
+		})
+	})
+
+	var federatedCredential armmsi.FederatedIdentityCredential
+	var federatedCredentialPath string
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPut && strings.Contains(request.URL.Path, "federatedIdentityCredentials")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		federatedCredentialPath = request.URL.Path
+		body, err := io.ReadAll(request.Body)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(body, &federatedCredential))
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, federatedCredential)
+	})
+
+	applied := []string{}
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl apply -f -")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		contents, err := io.ReadAll(args.StdIn)
+		require.NoError(t, err)
+		applied = append(applied, string(contents))
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	serviceConfig := createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+	serviceConfig.K8s.Identity = &AksIdentityOptions{
+		Name:          osutil.NewExpandableString("${IDENTITY_NAME}"),
+		ResourceGroup: osutil.NewExpandableString("IDENTITY_RG"),
+		KeyVault: &AksKeyVaultOptions{
+			Name:    osutil.NewExpandableString("kv-test"),
+			Secrets: []string{"db-password"},
+		},
+	}
+
+	env := createEnv()
+	env.DotenvSet("IDENTITY_NAME", "api-identity")
+
+	serviceTarget := createAksServiceTarget(mockContext, serviceConfig, env, nil)
+	err = simulateInitliaze(*mockContext.Context, serviceTarget, serviceConfig)
+	require.NoError(t, err)
+
+	err = setupK8sManifests(t, serviceConfig)
+	require.NoError(t, err)
+
+	// The pods of the applied workloads run as the federated service account
+	err = os.WriteFile(
+		filepath.Join(serviceConfig.RelativePath, defaultDeploymentPath, "deployment.yaml"),
+		[]byte("apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: api\n"),
+		osutil.PermissionFile,
+	)
+	require.NoError(t, err)
+
+	var patchArgs exec.RunArgs
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl patch")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		patchArgs = args
+		return exec.NewRunResult(0, "", ""), nil
+	})
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl get deployment/api")
+	}).Respond(exec.NewRunResult(0, "api sidecar", ""))
+
+	scope := environment.NewTargetResource("SUB_ID", "RG_ID", "", string(infra.AzureResourceTypeManagedCluster))
+	deployTask := serviceTarget.Deploy(*mockContext.Context, serviceConfig, &ServicePackageResult{}, scope)
+	logProgress(deployTask)
+	_, err = deployTask.Await()
+	require.NoError(t, err)
+
+	require.True(t, strings.HasSuffix(federatedCredentialPath, "federatedIdentityCredentials/AKS_CLUSTER-Test-App-api"))
+	require.Equal(t, "https://oidc.example.com/issuer/", *federatedCredential.Properties.Issuer)
+	require.Equal(t, "system:serviceaccount:Test-App:api", *federatedCredential.Properties.Subject)
+
+	var serviceAccount, secretProviderClass map[string]any
+	for _, manifest := range applied {
+		var resource map[string]any
+		require.NoError(t, yaml.Unmarshal([]byte(manifest), &resource))
+		switch resource["kind"] {
+		case "ServiceAccount":
+			serviceAccount = resource
+		case "SecretProviderClass":
+			secretProviderClass = resource
+		}
+	}
+
+	require.NotNil(t, serviceAccount)
+	metadata := serviceAccount["metadata"].(map[string]any)
+	require.Equal(t, "api", metadata["name"])
+	require.Equal(t, "CLIENT_ID", metadata["annotations"].(map[string]any)["azure.workload.identity/client-id"])
+
+	// The workload identity webhook reads the label of the pods, not of the service account
+	require.NotContains(t, metadata, "labels")
+	require.Equal(t, []string{"patch", "deployment/api", "--type", "strategic", "--patch"}, patchArgs.Args[:5])
+
+	var patch map[string]any
+	require.NoError(t, json.Unmarshal([]byte(patchArgs.Args[5]), &patch))
+	podTemplate := patch["spec"].(map[string]any)["template"].(map[string]any)
+	require.Equal(t, "api", podTemplate["spec"].(map[string]any)["serviceAccountName"])
+	require.Equal(
+		t,
+		map[string]any{"azure.workload.identity/use": "true"},
+		podTemplate["metadata"].(map[string]any)["labels"],
+	)
+
+	// The Key Vault secrets are mounted in every container of the pods
+	require.Equal(
+		t,
+		[]any{
+			map[string]any{
+				"name": "api-keyvault",
+				"csi": map[string]any{
+					"driver":           "secrets-store.csi.k8s.io",
+					"readOnly":         true,
+					"volumeAttributes": map[string]any{"secretProviderClass": "api-keyvault"},
+				},
+			},
+		},
+		podTemplate["spec"].(map[string]any)["volumes"],
+	)
+	volumeMounts := []any{
+		map[string]any{"name": "api-keyvault", "mountPath": "/mnt/secrets-store", "readOnly": true},
+	}
+	require.Equal(
+		t,
+		[]any{
+			map[string]any{"name": "api", "volumeMounts": volumeMounts},
+			map[string]any{"name": "sidecar", "volumeMounts": volumeMounts},
+		},
+		podTemplate["spec"].(map[string]any)["containers"],
+	)
+
+	require.NotNil(t, secretProviderClass)
+	parameters := secretProviderClass["spec"].(map[string]any)["parameters"].(map[string]any)
+	require.Equal(t, "kv-test", parameters["keyvaultName"])
+	require.Equal(t, "CLIENT_ID", parameters["clientID"])
+	require.Contains(t, parameters["objects"], "objectName: db-password")
+
+	require.Equal(t, "CLIENT_ID", env.GetServiceProperty("api", "IDENTITY_CLIENT_ID"))
+	require.Equal(t, "api", env.GetServiceProperty("api", "SERVICE_ACCOUNT_NAME"))
+}
+
+func Test_Deploy_LocalCluster(t *testing.T) {
+	tests := map[string]struct {
+		cluster        string
+		expectedUseCtx bool
+	}{
+		"CurrentContext": {cluster: AksClusterLocal, expectedUseCtx: false},
+		"KubeContext":    {cluster: "kind-azd", expectedUseCtx: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			ostest.Chdir(t, tempDir)
+			t.Setenv("HOME", tempDir)
+			t.Setenv("USERPROFILE", tempDir)
+			t.Setenv(kubectl.KubeConfigEnvVarName, "")
+
+			mockContext := mocks.NewMockContext(context.Background())
+			err := setupMocksForAksTarget(mockContext)
+			require.NoError(t, err)
+
+			// AKS credentials must never be fetched for local clusters
+			err = setupListClusterUserCredentialsMock(mockContext, http.StatusUnauthorized)
+			require.NoError(t, err)
+
+			commands := []string{}
+			commandsEnv := map[string][]string{}
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "kubectl config") || strings.Contains(command, "docker")
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				command := strings.Join(append([]string{args.Cmd}, args.Args...), " ")
+				commands = append(commands, command)
+				commandsEnv[command] = args.Env
+				if strings.HasPrefix(command, "kubectl config view") {
+					return exec.NewRunResult(0, "current-context: kind-azd\n", ""), nil
+				}
+
+				return exec.NewRunResult(0, "", ""), nil
+			})
+
+			serviceConfig := createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+			serviceConfig.K8s.Cluster = test.cluster
+			env := createEnv()
+
+			serviceTarget := createAksServiceTarget(mockContext, serviceConfig, env, nil)
+			err = simulateInitliaze(*mockContext.Context, serviceTarget, serviceConfig)
+			require.NoError(t, err)
+
+			err = setupK8sManifests(t, serviceConfig)
+			require.NoError(t, err)
+
+			packageResult := &ServicePackageResult{
+				PackagePath: "test-app/api-test:azd-deploy-0",
+				Details: &dockerPackageResult{
+					ImageHash:   "IMAGE_HASH",
+					TargetImage: "test-app/api-test:azd-deploy-0",
+				},
+			}
+
+			// Local clusters don't have a resource group
+			scope := environment.NewTargetResource("SUB_ID", "", "", "")
+			deployTask := serviceTarget.Deploy(*mockContext.Context, serviceConfig, packageResult, scope)
+			logProgress(deployTask)
+			deployResult, err := deployTask.Await()
+
+			require.NoError(t, err)
+			require.NotNil(t, deployResult)
+			require.Empty(t, deployResult.TargetResourceId)
+			require.Equal(t,
+				"localhost:5000/test-app/api-test:azd-deploy-0", env.Dotenv()["SERVICE_API_IMAGE_NAME"])
+
+			require.Equal(t, test.expectedUseCtx, slices.Contains(commands, "kubectl config use-context kind-azd"))
+			require.Contains(t, commands, "kubectl config set-context --current --namespace=Test-App")
+
+			// The context is copied from the kube config of the user to a kube config managed by azd, which is the
+			// only kube config that is modified
+			viewCommand := "kubectl config view --minify --flatten"
+			if test.expectedUseCtx {
+				viewCommand += " --context kind-azd"
+			}
+			require.Contains(t, commands, viewCommand)
+			require.Contains(t, commandsEnv[viewCommand], "KUBECONFIG=")
+
+			kubeConfigPath := filepath.Join(tempDir, ".kube", "azd-test-api")
+			kubeConfig, err := os.ReadFile(kubeConfigPath)
+			require.NoError(t, err)
+			require.Equal(t, "current-context: kind-azd\n", string(kubeConfig))
+			require.Contains(
+				t,
+				commandsEnv["kubectl config set-context --current --namespace=Test-App"],
+				"KUBECONFIG="+kubeConfigPath,
+			)
+			require.Contains(t, commands, "docker push localhost:5000/test-app/api-test:azd-deploy-0")
+			require.NotContains(t, commands, "docker login")
+		})
+	}
+}
+
+func Test_Logs(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	err := setupMocksForAksTarget(mockContext)
+	require.NoError(t, err)
+
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl get deployment api")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		deployment := &kubectl.Deployment{
+			Resource: kubectl.Resource{
+				ApiVersion: "apps/v1",
+				Kind:       "Deployment",
+				Metadata: kubectl.ResourceMetadata{
+					Name:      "api",
+					Namespace: "Test-App",
+				},
+			},
+			Spec: kubectl.DeploymentSpec{
+				Replicas: 2,
+				Selector: &kubectl.LabelSelector{
+					MatchLabels: map[string]string{"tier": "web", "app": "api"},
+				},
+			},
+		}
+		jsonBytes, _ := json.Marshal(deployment)
+
+		return exec.NewRunResult(0, string(jsonBytes), ""), nil
+	})
+
+	var logsArgs exec.RunArgs
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl logs")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		logsArgs = args
+		_, err := args.StdOut.Write([]byte("[pod/api-1/api] listening\n"))
+		return exec.NewRunResult(0, "", ""), err
+	})
+
+	serviceConfig := createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+	env := createEnv()
+
+	serviceTarget := createAksServiceTarget(mockContext, serviceConfig, env, nil)
+	logStreamer, ok := serviceTarget.(ServiceLogStreamer)
+	require.True(t, ok)
+
+	output := &bytes.Buffer{}
+	scope := environment.NewTargetResource("SUB_ID", "RG_ID", "", string(infra.AzureResourceTypeManagedCluster))
+	err = logStreamer.Logs(
+		*mockContext.Context, serviceConfig, scope, &LogsOptions{Follow: true, Since: time.Minute}, output)
+	require.NoError(t, err)
+
+	require.Equal(t, "[pod/api-1/api] listening\n", output.String())
+	require.Equal(t, []string{
+		"logs", "-l", "app=api,tier=web", "--all-containers", "--prefix", "--ignore-errors",
+		"--follow", "--max-log-requests=20", "--since=1m0s", "-n", "Test-App",
+	}, logsArgs.Args)
+}
+
 func Test_Resolve_Cluster_Name(t *testing.T) {
 	tempDir := t.TempDir()
 	ostest.Chdir(t, tempDir)
@@ -312,6 +800,137 @@ func Test_Deploy_Helm(t *testing.T) {
 	require.Contains(t, strings.Join(helmStatus.Args, " "), "status argocd")
 }
 
+func Test_Deploy_Helm_LocalChart(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	err := setupMocksForAksTarget(mockContext)
+	require.NoError(t, err)
+
+	mockResults, err := setupMocksForHelm(mockContext)
+	require.NoError(t, err)
+
+	serviceConfig := *createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+	serviceConfig.K8s.Helm = &helm.Config{
+		Releases: []*helm.Release{
+			{
+				Name:   "api",
+				Chart:  "./chart",
+				Values: "./chart/values-dev.yaml",
+				Set: map[string]string{
+					"replicaCount": "2",
+				},
+			},
+		},
+	}
+
+	chartPath := filepath.Join(serviceConfig.Path(), "chart")
+	require.NoError(t, os.MkdirAll(chartPath, osutil.PermissionDirectory))
+	require.NoError(t, os.WriteFile(filepath.Join(chartPath, "Chart.yaml"), []byte("name: api"), osutil.PermissionFile))
+	require.NoError(t, os.WriteFile(filepath.Join(chartPath, "values-dev.yaml"), []byte(""), osutil.PermissionFile))
+
+	env := createEnv()
+	userConfig := config.NewConfig(nil)
+	_ = userConfig.Set("alpha.aks.helm", "on")
+
+	serviceTarget := createAksServiceTarget(mockContext, &serviceConfig, env, userConfig)
+	err = simulateInitliaze(*mockContext.Context, serviceTarget, &serviceConfig)
+	require.NoError(t, err)
+
+	packageResult := &ServicePackageResult{
+		PackagePath: "test-app/api-test:azd-deploy-0",
+		Details: &dockerPackageResult{
+			ImageHash:   "IMAGE_HASH",
+			TargetImage: "test-app/api-test:azd-deploy-0",
+		},
+	}
+
+	scope := environment.NewTargetResource("SUB_ID", "RG_ID", "", string(infra.AzureResourceTypeManagedCluster))
+	deployTask := serviceTarget.Deploy(*mockContext.Context, &serviceConfig, packageResult, scope)
+	logProgress(deployTask)
+	deployResult, err := deployTask.Await()
+
+	require.NoError(t, err)
+	require.NotNil(t, deployResult)
+
+	helmUpgrade, helmUpgradeCalled := mockResults["helm-upgrade"]
+	require.True(t, helmUpgradeCalled)
+	require.Equal(t, []string{
+		"upgrade", "api", chartPath, "--install", "--wait",
+		"--values", filepath.Join(chartPath, "values-dev.yaml"),
+		"--set", "replicaCount=2",
+		"--set-string", "image.repository=REGISTRY.azurecr.io/test-app/api-test",
+		"--set-string", "image.tag=azd-deploy-0",
+		"--namespace", "Test-App",
+		"--create-namespace",
+	}, helmUpgrade.Args)
+
+	// The configured release is not modified by the deployment
+	require.Equal(t, "./chart", serviceConfig.K8s.Helm.Releases[0].Chart)
+	require.Len(t, serviceConfig.K8s.Helm.Releases[0].Set, 1)
+	require.Empty(t, serviceConfig.K8s.Helm.Releases[0].SetString)
+}
+
+func Test_Deploy_Helm_Rollback(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	err := setupMocksForAksTarget(mockContext)
+	require.NoError(t, err)
+
+	mockResults, err := setupMocksForHelm(mockContext)
+	require.NoError(t, err)
+
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "helm status")
+	}).Respond(exec.NewRunResult(0, `{"version": 2, "info": {"status": "failed"}}`, ""))
+
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "helm rollback")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		mockResults["helm-rollback"] = args
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	serviceConfig := *createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+	serviceConfig.RelativePath = ""
+	serviceConfig.K8s.Helm = &helm.Config{
+		Releases: []*helm.Release{
+			{
+				Name:  "argocd",
+				Chart: "oci://myregistry.example.com/charts/argo-cd",
+			},
+		},
+	}
+
+	env := createEnv()
+	userConfig := config.NewConfig(nil)
+	_ = userConfig.Set("alpha.aks.helm", "on")
+
+	serviceTarget := createAksServiceTarget(mockContext, &serviceConfig, env, userConfig)
+	err = simulateInitliaze(*mockContext.Context, serviceTarget, &serviceConfig)
+	require.NoError(t, err)
+
+	scope := environment.NewTargetResource("SUB_ID", "RG_ID", "", string(infra.AzureResourceTypeManagedCluster))
+	deployTask := serviceTarget.Deploy(*mockContext.Context, &serviceConfig, &ServicePackageResult{}, scope)
+	logProgress(deployTask)
+	_, err = deployTask.Await()
+
+	require.Error(t, err)
+	require.ErrorContains(t, err, "release 'argocd' was rolled back to its previous revision")
+
+	helmUpgrade, helmUpgradeCalled := mockResults["helm-upgrade"]
+	require.True(t, helmUpgradeCalled)
+	require.NotContains(t, helmUpgrade.Args, "--set")
+	require.NotContains(t, helmUpgrade.Args, "--set-string")
+
+	helmRollback, helmRollbackCalled := mockResults["helm-rollback"]
+	require.True(t, helmRollbackCalled)
+	require.Equal(t, []string{"rollback", "argocd", "--wait", "--namespace", "Test-App"}, helmRollback.Args)
+}
+
 func Test_Deploy_Kustomize(t *testing.T) {
 	tempDir := t.TempDir()
 	ostest.Chdir(t, tempDir)
@@ -838,6 +1457,8 @@ func createAksServiceTarget(
 		containerRegistryService,
 		dockerCli,
 		cloud.AzurePublic(),
+		nil,
+		nil,
 	)
 
 	if userConfig == nil {
@@ -852,6 +1473,7 @@ func createAksServiceTarget(
 		envManager,
 		mockContext.Console,
 		managedClustersService,
+		azcli.NewManagedIdentityService(credentialProvider, mockContext.ArmClientOptions),
 		resourceManager,
 		kubeCtl,
 		kubeLoginCli,
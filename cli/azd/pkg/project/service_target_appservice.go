@@ -6,6 +6,7 @@ package project
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
@@ -155,6 +156,47 @@ func (st *appServiceTarget) Endpoints(
 	return endpoints, nil
 }
 
+// Streams the live application logs of the App Service
+func (st *appServiceTarget) Logs(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	options *LogsOptions,
+	writer io.Writer,
+) error {
+	if err := st.validateTargetResource(ctx, serviceConfig, targetResource); err != nil {
+		return fmt.Errorf("validating target resource: %w", err)
+	}
+
+	return appServiceLogs(ctx, st.cli, targetResource, options, writer)
+}
+
+// appServiceLogs streams the live logs of an app service or function app. Previous logs can't be retrieved from the
+// log stream, which is why following is required.
+func appServiceLogs(
+	ctx context.Context,
+	cli azcli.AzCli,
+	targetResource *environment.TargetResource,
+	options *LogsOptions,
+	writer io.Writer,
+) error {
+	if !options.Follow {
+		return fmt.Errorf("%w without following, only live logs are streamed from App Service", ErrLogsNotSupported)
+	}
+
+	stream, err := cli.GetAppServiceLogStream(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+	)
+	if err != nil {
+		return err
+	}
+
+	return copyLogStreams(ctx, []io.ReadCloser{stream}, writer)
+}
+
 func (st *appServiceTarget) validateTargetResource(
 	ctx context.Context,
 	serviceConfig *ServiceConfig,
@@ -6,6 +6,7 @@ package project
 import (
 	"context"
 	"fmt"
+	"io"
 	"strconv"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/async"
@@ -16,6 +17,9 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
 )
 
+// The number of recent lines returned by each log stream of a container app, which is the maximum allowed
+const containerAppLogTailLines = 300
+
 type containerAppTarget struct {
 	env                 *environment.Environment
 	envManager          environment.Manager
@@ -153,6 +157,48 @@ func (at *containerAppTarget) Endpoints(
 	}
 }
 
+// Streams the console logs of the containers, or the system logs, of the Container App
+func (at *containerAppTarget) Logs(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	options *LogsOptions,
+	writer io.Writer,
+) error {
+	if err := at.validateTargetResource(ctx, serviceConfig, targetResource); err != nil {
+		return fmt.Errorf("validating target resource: %w", err)
+	}
+
+	return containerAppLogs(ctx, at.containerAppService, targetResource, options, writer)
+}
+
+// containerAppLogs streams the logs of a container app. Container Apps don't filter logs by time, instead the most recent
+// lines are returned.
+func containerAppLogs(
+	ctx context.Context,
+	containerAppService containerapps.ContainerAppService,
+	targetResource *environment.TargetResource,
+	options *LogsOptions,
+	writer io.Writer,
+) error {
+	streams, err := containerAppService.OpenLogStreams(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+		&containerapps.LogStreamOptions{
+			System:    options.System,
+			Follow:    options.Follow,
+			TailLines: containerAppLogTailLines,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	return copyLogStreams(ctx, streams, writer)
+}
+
 func (at *containerAppTarget) validateTargetResource(
 	ctx context.Context,
 	serviceConfig *ServiceConfig,
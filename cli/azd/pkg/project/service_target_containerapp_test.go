@@ -153,6 +153,8 @@ func createContainerAppServiceTarget(
 		containerRegistryService,
 		dockerCli,
 		cloud.AzurePublic(),
+		nil,
+		nil,
 	)
 	azCli := mockazcli.NewAzCliFromMockContext(mockContext)
 	depOpService := mockazcli.NewDeploymentOperationsServiceFromMockContext(mockContext)
@@ -0,0 +1,327 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
+	"github.com/azure/azure-dev/cli/azd/pkg/apphost"
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/containerapps"
+	"github.com/azure/azure-dev/cli/azd/pkg/cosmosdb"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/helm"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/keyvault"
+	"github.com/azure/azure-dev/cli/azd/pkg/kubelogin"
+	"github.com/azure/azure-dev/cli/azd/pkg/kustomize"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/sqldb"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/dotnet"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
+)
+
+type dotnetAksTarget struct {
+	*aksTarget
+	dotNetCli           dotnet.DotNetCli
+	containerAppService containerapps.ContainerAppService
+	cosmosDbService     cosmosdb.CosmosDbService
+	sqlDbService        sqldb.SqlDbService
+	keyvaultService     keyvault.KeyVaultService
+}
+
+// NewDotNetAksTarget creates the Service Target for a service of an Aspire app host deployed to AKS. Like
+// [DotNetContainerAppTarget], the container image of a .NET project is published with `dotnet publish`. The Kubernetes
+// manifests (or the Helm chart, when the app host sets `k8s.aspireFormat: helm`) of the service are generated from the
+// app host manifest and evaluated with the same template functions as the container app manifest, before they are
+// deployed with the tooling of [AksTarget].
+func NewDotNetAksTarget(
+	env *environment.Environment,
+	envManager environment.Manager,
+	console input.Console,
+	managedClustersService azcli.ManagedClustersService,
+	managedIdentityService azcli.ManagedIdentityService,
+	resourceManager ResourceManager,
+	kubectlCli kubectl.KubectlCli,
+	kubeLoginCli *kubelogin.Cli,
+	helmCli *helm.Cli,
+	kustomizeCli *kustomize.Cli,
+	containerHelper *ContainerHelper,
+	featureManager *alpha.FeatureManager,
+	dotNetCli dotnet.DotNetCli,
+	containerAppService containerapps.ContainerAppService,
+	cosmosDbService cosmosdb.CosmosDbService,
+	sqlDbService sqldb.SqlDbService,
+	keyvaultService keyvault.KeyVaultService,
+) ServiceTarget {
+	return &dotnetAksTarget{
+		aksTarget: NewAksTarget(
+			env,
+			envManager,
+			console,
+			managedClustersService,
+			managedIdentityService,
+			resourceManager,
+			kubectlCli,
+			kubeLoginCli,
+			helmCli,
+			kustomizeCli,
+			containerHelper,
+			featureManager,
+		).(*aksTarget),
+		dotNetCli:           dotNetCli,
+		containerAppService: containerAppService,
+		cosmosDbService:     cosmosDbService,
+		sqlDbService:        sqlDbService,
+		keyvaultService:     keyvaultService,
+	}
+}
+
+// Gets the required external tools
+func (t *dotnetAksTarget) RequiredExternalTools(ctx context.Context) []tools.ExternalTool {
+	return append(t.aksTarget.RequiredExternalTools(ctx), t.dotNetCli)
+}
+
+// Deploys the container image of the service to ACR and its generated k8s resources to the AKS cluster
+func (t *dotnetAksTarget) Deploy(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageOutput *ServicePackageResult,
+	targetResource *environment.TargetResource,
+) *async.TaskWithProgress[*ServiceDeployResult, ServiceProgress] {
+	return async.RunTaskWithProgress(
+		func(task *async.TaskContextWithProgress[*ServiceDeployResult, ServiceProgress]) {
+			if err := t.validateTargetResource(ctx, serviceConfig, targetResource); err != nil {
+				task.SetError(fmt.Errorf("validating target resource: %w", err))
+				return
+			}
+
+			if packageOutput == nil {
+				task.SetError(errors.New("missing package output"))
+				return
+			}
+
+			remoteImageName, portNumber, err := publishAppHostContainer(
+				ctx, task, t.containerHelper, t.dotNetCli, serviceConfig, packageOutput, targetResource)
+			if err != nil {
+				task.SetError(err)
+				return
+			}
+
+			if err := t.ensureWorkloadIdentity(ctx, serviceConfig, targetResource, task); err != nil {
+				task.SetError(fmt.Errorf("workload identity configuration failed: %w", err))
+				return
+			}
+
+			// Sync environment
+			t.kubectl.SetEnv(t.env.Dotenv())
+
+			fns := &containerAppTemplateManifestFuncs{
+				ctx:                 ctx,
+				manifest:            serviceConfig.DotNetContainerApp.Manifest,
+				targetResource:      targetResource,
+				containerAppService: t.containerAppService,
+				cosmosDbService:     t.cosmosDbService,
+				sqlDbService:        t.sqlDbService,
+				env:                 t.env,
+				keyvaultService:     t.keyvaultService,
+			}
+
+			projectRoot := serviceConfig.Path()
+			if f, err := os.Stat(projectRoot); err == nil && !f.IsDir() {
+				projectRoot = filepath.Dir(projectRoot)
+			}
+
+			tempDir, err := os.MkdirTemp("", "azd-k8s")
+			if err != nil {
+				task.SetError(fmt.Errorf("creating temporary directory: %w", err))
+				return
+			}
+			defer os.RemoveAll(tempDir)
+
+			var details *aksDeployResult
+			switch serviceConfig.K8s.AspireFormat {
+			case "", AksAspireFormatManifests:
+				details, err = t.deployManifest(
+					ctx, serviceConfig, task, fns, projectRoot, tempDir, remoteImageName, portNumber)
+			case AksAspireFormatHelm:
+				details, err = t.deployChart(
+					ctx, serviceConfig, targetResource, task, fns, projectRoot, tempDir, remoteImageName, portNumber)
+			default:
+				err = fmt.Errorf("unsupported value '%s' for k8s.aspireFormat", serviceConfig.K8s.AspireFormat)
+			}
+			if err != nil {
+				task.SetError(err)
+				return
+			}
+
+			result, err := t.deployResult(ctx, serviceConfig, packageOutput, targetResource, details, task)
+			if err != nil {
+				task.SetError(err)
+				return
+			}
+
+			task.SetResult(result)
+		},
+	)
+}
+
+// deployManifest evaluates the k8s manifest of the service, either from manifests/k8s.tmpl.yaml next to the project or
+// generated from the app host manifest, applies it to the k8s cluster and waits for the rollout of its resources.
+func (t *dotnetAksTarget) deployManifest(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	task *async.TaskContextWithProgress[*ServiceDeployResult, ServiceProgress],
+	fns *containerAppTemplateManifestFuncs,
+	projectRoot string,
+	tempDir string,
+	image string,
+	portNumber int,
+) (*aksDeployResult, error) {
+	var manifest string
+
+	manifestPath := filepath.Join(projectRoot, "manifests", "k8s.tmpl.yaml")
+	if _, err := os.Stat(manifestPath); err == nil {
+		log.Printf("using k8s manifest from %s", manifestPath)
+
+		contents, err := os.ReadFile(manifestPath)
+		if err != nil {
+			return nil, fmt.Errorf("reading k8s manifest: %w", err)
+		}
+		manifest = string(contents)
+	} else {
+		log.Printf(
+			"generating k8s manifest from %s for project %s",
+			serviceConfig.DotNetContainerApp.ProjectPath,
+			serviceConfig.DotNetContainerApp.ProjectName)
+
+		generatedManifest, err := apphost.KubernetesManifestTemplateForProject(
+			serviceConfig.DotNetContainerApp.Manifest,
+			serviceConfig.DotNetContainerApp.ProjectName,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("generating k8s manifest: %w", err)
+		}
+		manifest = generatedManifest
+	}
+
+	k8sYaml, err := fns.execute("k8s.tmpl.yaml", manifest, image, portNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	// The manifest is written without the .tmpl suffix since it has already been evaluated.
+	if err := os.WriteFile(filepath.Join(tempDir, "k8s.yaml"), []byte(k8sYaml), osutil.PermissionFileOwnerOnly); err != nil {
+		return nil, fmt.Errorf("writing k8s manifest: %w", err)
+	}
+
+	return t.applyManifests(ctx, serviceConfig, tempDir, serviceConfig.Name, task)
+}
+
+// deployChart evaluates the values of the Helm chart of the service, either from manifests/chart next to the project or
+// generated from the app host manifest, installs the chart as a release named after the service and waits for the
+// deployment of the service.
+func (t *dotnetAksTarget) deployChart(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	task *async.TaskContextWithProgress[*ServiceDeployResult, ServiceProgress],
+	fns *containerAppTemplateManifestFuncs,
+	projectRoot string,
+	tempDir string,
+	image string,
+	portNumber int,
+) (*aksDeployResult, error) {
+	chartPath := filepath.Join(projectRoot, "manifests", "chart")
+	if _, err := os.Stat(filepath.Join(chartPath, "Chart.yaml")); err == nil {
+		log.Printf("using helm chart from %s", chartPath)
+	} else {
+		log.Printf(
+			"generating helm chart from %s for project %s",
+			serviceConfig.DotNetContainerApp.ProjectPath,
+			serviceConfig.DotNetContainerApp.ProjectName)
+
+		chart, err := apphost.HelmChartForProject(
+			serviceConfig.DotNetContainerApp.Manifest,
+			serviceConfig.DotNetContainerApp.ProjectName,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("generating helm chart: %w", err)
+		}
+
+		chartPath = filepath.Join(tempDir, "chart")
+		if err := writeFS(chart, chartPath); err != nil {
+			return nil, fmt.Errorf("writing helm chart: %w", err)
+		}
+	}
+
+	valuesTemplate, err := os.ReadFile(filepath.Join(chartPath, "values.tmpl.yaml"))
+	if err != nil {
+		return nil, fmt.Errorf("reading helm values: %w", err)
+	}
+
+	values, err := fns.execute("values.tmpl.yaml", string(valuesTemplate), image, portNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	valuesPath := filepath.Join(tempDir, "values.yaml")
+	if err := os.WriteFile(valuesPath, []byte(values), osutil.PermissionFileOwnerOnly); err != nil {
+		return nil, fmt.Errorf("writing helm values: %w", err)
+	}
+
+	chartConfig := *serviceConfig
+	chartConfig.K8s.Helm = &helm.Config{
+		Releases: []*helm.Release{
+			{
+				Name:   serviceConfig.Name,
+				Chart:  chartPath,
+				Values: valuesPath,
+			},
+		},
+	}
+
+	// The image is already part of the evaluated values of the chart
+	if _, err := t.deployHelmCharts(ctx, &chartConfig, targetResource, "", task); err != nil {
+		return nil, fmt.Errorf("helm deployment failed: %w", err)
+	}
+
+	// It is not a requirement for a AZD deploy to contain a deployment object
+	task.SetProgress(NewServiceProgress("Verifying deployment"))
+	deployment, err := t.waitForDeployment(ctx, serviceConfig.Name)
+	if err != nil && !errors.Is(err, kubectl.ErrResourceNotFound) {
+		return nil, err
+	}
+
+	return &aksDeployResult{Deployment: deployment}, nil
+}
+
+// writeFS writes all the files of the given filesystem under the target directory.
+func writeFS(files fs.FS, target string) error {
+	return fs.WalkDir(files, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			return os.MkdirAll(filepath.Join(target, path), osutil.PermissionDirectoryOwnerOnly)
+		}
+
+		contents, err := fs.ReadFile(files, path)
+		if err != nil {
+			return err
+		}
+
+		return os.WriteFile(filepath.Join(target, path), contents, osutil.PermissionFileOwnerOnly)
+	})
+}
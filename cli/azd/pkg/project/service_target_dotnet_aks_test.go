@@ -0,0 +1,115 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/apphost"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/dotnet"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/ostest"
+	"github.com/stretchr/testify/require"
+)
+
+const k8sTemplate = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: api
+data:
+  image: {{ .Image }}
+  port: "{{ targetPortOrDefault 80 }}"
+`
+
+func Test_DotNetAks_Deploy_LocalCluster(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+	t.Setenv("HOME", tempDir)
+	t.Setenv("USERPROFILE", tempDir)
+	t.Setenv(kubectl.KubeConfigEnvVarName, "")
+
+	mockContext := mocks.NewMockContext(context.Background())
+	err := setupMocksForAksTarget(mockContext)
+	require.NoError(t, err)
+
+	// Neither AKS nor ACR credentials are fetched for local clusters and their registries
+	err = setupListClusterUserCredentialsMock(mockContext, http.StatusUnauthorized)
+	require.NoError(t, err)
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return strings.Contains(request.URL.Path, "/oauth2/exchange")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateEmptyHttpResponse(request, http.StatusUnauthorized)
+	})
+
+	var publish exec.RunArgs
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "dotnet publish")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		publish = args
+		return exec.NewRunResult(0, `{"config": {"ExposedPorts": {"8080/tcp": {}}}}`, ""), nil
+	})
+
+	applied := ""
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl config") || strings.Contains(command, "kubectl apply")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		// The evaluated manifest is applied from a file, ex) kubectl apply -f <file>
+		if args.Args[0] == "apply" && args.Args[2] != "-" {
+			contents, err := os.ReadFile(args.Args[2])
+			if err != nil {
+				return exec.RunResult{}, err
+			}
+			applied = string(contents)
+		}
+
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	serviceConfig := createTestServiceConfig(tempDir, DotNetAksTarget, ServiceLanguageDotNet)
+	serviceConfig.K8s.Cluster = AksClusterLocal
+	serviceConfig.DotNetContainerApp = &DotNetContainerAppOptions{
+		Manifest:    &apphost.Manifest{},
+		ProjectName: "api",
+	}
+
+	manifestsDir := filepath.Join(serviceConfig.Path(), "manifests")
+	require.NoError(t, os.MkdirAll(manifestsDir, osutil.PermissionDirectory))
+	err = os.WriteFile(
+		filepath.Join(manifestsDir, "k8s.tmpl.yaml"),
+		[]byte(k8sTemplate),
+		osutil.PermissionFile)
+	require.NoError(t, err)
+
+	env := createEnv()
+	serviceTarget := &dotnetAksTarget{
+		aksTarget: createAksServiceTarget(mockContext, serviceConfig, env, nil).(*aksTarget),
+		dotNetCli: dotnet.NewDotNetCli(mockContext.CommandRunner),
+	}
+	err = simulateInitliaze(*mockContext.Context, serviceTarget, serviceConfig)
+	require.NoError(t, err)
+
+	scope := environment.NewTargetResource("SUB_ID", "", "", "")
+	deployTask := serviceTarget.Deploy(*mockContext.Context, serviceConfig, &ServicePackageResult{}, scope)
+	logProgress(deployTask)
+	deployResult, err := deployTask.Await()
+
+	require.NoError(t, err)
+	require.Empty(t, deployResult.TargetResourceId)
+
+	require.Contains(t, publish.Args, "-p:ContainerRegistry=localhost:5000")
+	for _, envVar := range publish.Env {
+		require.False(t, strings.HasPrefix(envVar, "SDK_CONTAINER_REGISTRY_"), envVar)
+	}
+
+	require.Regexp(t, `image: localhost:5000/azd-deploy-api-\d+\n  port: "8080"\n$`, applied)
+}
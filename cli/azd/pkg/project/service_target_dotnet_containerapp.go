@@ -6,6 +6,7 @@ package project
 import (
 	"context"
 	"fmt"
+	"io"
 	"log"
 	"net/url"
 	"os"
@@ -106,50 +107,13 @@ func (at *dotnetContainerAppTarget) Deploy(
 				return
 			}
 
-			task.SetProgress(NewServiceProgress("Logging in to registry"))
-
-			// Login, tag & push container image to ACR
-			dockerCreds, err := at.containerHelper.Credentials(ctx, serviceConfig, targetResource)
+			remoteImageName, portNumber, err := publishAppHostContainer(
+				ctx, task, at.containerHelper, at.dotNetCli, serviceConfig, packageOutput, targetResource)
 			if err != nil {
-				task.SetError(fmt.Errorf("logging in to registry: %w", err))
+				task.SetError(err)
 				return
 			}
 
-			task.SetProgress(NewServiceProgress("Pushing container image"))
-
-			var remoteImageName string
-			var portNumber int
-
-			if serviceConfig.Language == ServiceLanguageDocker {
-				containerDeployTask := at.containerHelper.Deploy(ctx, serviceConfig, packageOutput, targetResource, false)
-				syncProgress(task, containerDeployTask.Progress())
-
-				res, err := containerDeployTask.Await()
-				if err != nil {
-					task.SetError(err)
-					return
-				}
-
-				remoteImageName = res.Details.(*dockerDeployResult).RemoteImageTag
-			} else {
-				imageName := fmt.Sprintf("azd-deploy-%s-%d", serviceConfig.Name, time.Now().Unix())
-
-				portNumber, err = at.dotNetCli.PublishContainer(
-					ctx,
-					serviceConfig.Path(),
-					"Release",
-					imageName,
-					dockerCreds.LoginServer,
-					dockerCreds.Username,
-					dockerCreds.Password)
-				if err != nil {
-					task.SetError(fmt.Errorf("publishing container: %w", err))
-					return
-				}
-
-				remoteImageName = fmt.Sprintf("%s/%s", dockerCreds.LoginServer, imageName)
-			}
-
 			task.SetProgress(NewServiceProgress("Updating container app"))
 
 			var manifest string
@@ -197,52 +161,9 @@ func (at *dotnetContainerAppTarget) Deploy(
 				keyvaultService:     at.keyvaultService,
 			}
 
-			tmpl, err := template.New("containerApp.tmpl.yaml").
-				Option("missingkey=error").
-				Funcs(template.FuncMap{
-					"urlHost":          fns.UrlHost,
-					"connectionString": fns.ConnectionString,
-					"parameter":        fns.Parameter,
-					// securedParameter gets a parameter the same way as parameter, but supporting the securedParameter
-					// allows to update the logic of pulling secret parameters in the future, if azd changes the way it
-					// stores the parameter value.
-					"securedParameter": fns.Parameter,
-					"secretOutput":     fns.kvSecret,
-					"targetPortOrDefault": func(targetPortFromManifest int) int {
-						// portNumber is 0 for dockerfile.v0, so we use the targetPort from the manifest
-						if portNumber == 0 {
-							return targetPortFromManifest
-						}
-						return portNumber
-					},
-				}).
-				Parse(manifest)
-			if err != nil {
-				task.SetError(fmt.Errorf("failing parsing containerApp.tmpl.yaml: %w", err))
-				return
-			}
-
-			var inputs map[string]any
-			// inputs are auto-gen during provision and saved to env-config
-			if has, err := at.env.Config.GetSection("inputs", &inputs); err != nil {
-				task.SetError(fmt.Errorf("failed to get inputs section: %w", err))
-				return
-			} else if !has {
-				inputs = make(map[string]any)
-			}
-
-			builder := strings.Builder{}
-			err = tmpl.Execute(&builder, struct {
-				Env    map[string]string
-				Image  string
-				Inputs map[string]any
-			}{
-				Env:    at.env.Dotenv(),
-				Image:  remoteImageName,
-				Inputs: inputs,
-			})
+			containerAppYaml, err := fns.execute("containerApp.tmpl.yaml", manifest, remoteImageName, portNumber)
 			if err != nil {
-				task.SetError(fmt.Errorf("failed executing template file: %w", err))
+				task.SetError(err)
 				return
 			}
 
@@ -251,7 +172,7 @@ func (at *dotnetContainerAppTarget) Deploy(
 				targetResource.SubscriptionId(),
 				targetResource.ResourceGroupName(),
 				serviceConfig.Name,
-				[]byte(builder.String()),
+				[]byte(containerAppYaml),
 			)
 			if err != nil {
 				task.SetError(fmt.Errorf("updating container app service: %w", err))
@@ -309,6 +230,24 @@ func (at *dotnetContainerAppTarget) Endpoints(
 	}
 }
 
+// Streams the console logs of the containers, or the system logs, of the Container App
+func (at *dotnetContainerAppTarget) Logs(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	options *LogsOptions,
+	writer io.Writer,
+) error {
+	// Unlike deployments, logs target the container app of the service rather than its container app environment
+	if targetResource.ResourceType() != "" {
+		if err := checkResourceType(targetResource, infra.AzureResourceTypeContainerApp); err != nil {
+			return err
+		}
+	}
+
+	return containerAppLogs(ctx, at.containerAppService, targetResource, options, writer)
+}
+
 func (at *dotnetContainerAppTarget) validateTargetResource(
 	ctx context.Context,
 	serviceConfig *ServiceConfig,
@@ -327,6 +266,68 @@ func (at *dotnetContainerAppTarget) validateTargetResource(
 	return nil
 }
 
+// publishAppHostContainer logs in to the container registry and pushes the container image of a service imported from an
+// app host. Services with a Dockerfile are built by the container helper, while .NET projects are published as a container
+// by `dotnet publish`, in which case the port the container listens on is returned as well.
+func publishAppHostContainer(
+	ctx context.Context,
+	task *async.TaskContextWithProgress[*ServiceDeployResult, ServiceProgress],
+	containerHelper *ContainerHelper,
+	dotNetCli dotnet.DotNetCli,
+	serviceConfig *ServiceConfig,
+	packageOutput *ServicePackageResult,
+	targetResource *environment.TargetResource,
+) (string, int, error) {
+	task.SetProgress(NewServiceProgress("Logging in to registry"))
+
+	loginServer, err := containerHelper.RegistryName(ctx, serviceConfig)
+	if err != nil {
+		return "", 0, err
+	}
+
+	// Credentials are only exchanged for ACR, ex) local registries of local clusters don't require a login
+	dockerCreds, err := containerHelper.RegistryCredentials(ctx, targetResource.SubscriptionId(), loginServer)
+	if err != nil {
+		return "", 0, fmt.Errorf("logging in to registry: %w", err)
+	}
+
+	var username, password string
+	if dockerCreds != nil {
+		username = dockerCreds.Username
+		password = dockerCreds.Password
+	}
+
+	task.SetProgress(NewServiceProgress("Pushing container image"))
+
+	if serviceConfig.Language == ServiceLanguageDocker {
+		containerDeployTask := containerHelper.Deploy(ctx, serviceConfig, packageOutput, targetResource, false)
+		syncProgress(task, containerDeployTask.Progress())
+
+		res, err := containerDeployTask.Await()
+		if err != nil {
+			return "", 0, err
+		}
+
+		return res.Details.(*dockerDeployResult).RemoteImageTag, 0, nil
+	}
+
+	imageName := fmt.Sprintf("azd-deploy-%s-%d", serviceConfig.Name, time.Now().Unix())
+
+	portNumber, err := dotNetCli.PublishContainer(
+		ctx,
+		serviceConfig.Path(),
+		"Release",
+		imageName,
+		loginServer,
+		username,
+		password)
+	if err != nil {
+		return "", 0, fmt.Errorf("publishing container: %w", err)
+	}
+
+	return fmt.Sprintf("%s/%s", loginServer, imageName), portNumber, nil
+}
+
 // containerAppTemplateManifestFuncs contains all the functions that are callable while evaluating the manifest template.
 type containerAppTemplateManifestFuncs struct {
 	ctx                 context.Context
@@ -620,3 +621,58 @@ func (fns *containerAppTemplateManifestFuncs) kvSecret(kvHost, secretName string
 	}
 	return secret.Value, nil
 }
+
+// execute evaluates the given manifest template, which was generated from an app host manifest, for a service that runs
+// the given container image. portNumber is the port the container of a .NET project listens on, and 0 for any other
+// service.
+func (fns *containerAppTemplateManifestFuncs) execute(
+	name string, manifest string, image string, portNumber int,
+) (string, error) {
+	tmpl, err := template.New(name).
+		Option("missingkey=error").
+		Funcs(template.FuncMap{
+			"urlHost":          fns.UrlHost,
+			"connectionString": fns.ConnectionString,
+			"parameter":        fns.Parameter,
+			// securedParameter gets a parameter the same way as parameter, but supporting the securedParameter
+			// allows to update the logic of pulling secret parameters in the future, if azd changes the way it
+			// stores the parameter value.
+			"securedParameter": fns.Parameter,
+			"secretOutput":     fns.kvSecret,
+			"targetPortOrDefault": func(targetPortFromManifest int) int {
+				// portNumber is 0 for dockerfile.v0, so we use the targetPort from the manifest
+				if portNumber == 0 {
+					return targetPortFromManifest
+				}
+				return portNumber
+			},
+		}).
+		Parse(manifest)
+	if err != nil {
+		return "", fmt.Errorf("failing parsing %s: %w", name, err)
+	}
+
+	var inputs map[string]any
+	// inputs are auto-gen during provision and saved to env-config
+	if has, err := fns.env.Config.GetSection("inputs", &inputs); err != nil {
+		return "", fmt.Errorf("failed to get inputs section: %w", err)
+	} else if !has {
+		inputs = make(map[string]any)
+	}
+
+	builder := strings.Builder{}
+	err = tmpl.Execute(&builder, struct {
+		Env    map[string]string
+		Image  string
+		Inputs map[string]any
+	}{
+		Env:    fns.env.Dotenv(),
+		Image:  image,
+		Inputs: inputs,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed executing template file: %w", err)
+	}
+
+	return builder.String(), nil
+}
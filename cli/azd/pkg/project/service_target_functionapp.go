@@ -6,6 +6,7 @@ package project
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
@@ -157,6 +158,21 @@ func (f *functionAppTarget) Endpoints(
 	}
 }
 
+// Streams the live logs of the Function App
+func (f *functionAppTarget) Logs(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	options *LogsOptions,
+	writer io.Writer,
+) error {
+	if err := f.validateTargetResource(ctx, serviceConfig, targetResource); err != nil {
+		return fmt.Errorf("validating target resource: %w", err)
+	}
+
+	return appServiceLogs(ctx, f.cli, targetResource, options, writer)
+}
+
 func (f *functionAppTarget) validateTargetResource(
 	ctx context.Context,
 	serviceConfig *ServiceConfig,
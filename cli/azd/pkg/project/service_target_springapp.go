@@ -7,6 +7,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 
@@ -26,6 +27,30 @@ const (
 type SpringOptions struct {
 	// The deployment name of ASA app
 	DeploymentName string `yaml:"deploymentName"`
+	// The name of the staging deployment of ASA app. When set, the app is deployed to whichever of the deployment and
+	// the staging deployment isn't active, which is then set active, swapping the production and staging deployments.
+	StagingDeploymentName string `yaml:"stagingDeploymentName"`
+	// The options of the JVM, ex) -Xms1024m -Xmx2048m
+	JvmOptions string `yaml:"jvmOptions"`
+	// The Java runtime version, ex) Java_17
+	RuntimeVersion string `yaml:"runtimeVersion"`
+	// The number of instances of the deployment
+	InstanceCount int `yaml:"instanceCount"`
+	// Builds the source code with the build service of the Enterprise tier instead of deploying the jar built by azd
+	BuildService bool `yaml:"buildService"`
+	// The builder of the build service, ex) default
+	Builder string `yaml:"builder"`
+}
+
+// springBuildServiceExcludePatterns are excluded from the source code built by the build service, the outputs of local
+// Maven and Gradle builds of the project and of its modules, which the build service rebuilds anyway.
+var springBuildServiceExcludePatterns = []string{
+	".git",
+	".gradle",
+	"target",
+	"build",
+	"*/target",
+	"*/build",
 }
 
 type springAppTarget struct {
@@ -58,7 +83,8 @@ func (st *springAppTarget) Initialize(ctx context.Context, serviceConfig *Servic
 	return nil
 }
 
-// Do nothing for Spring Apps
+// Archives the source code of the service when it's built by the build service, otherwise the jar built by the
+// framework service is deployed as is
 func (st *springAppTarget) Package(
 	ctx context.Context,
 	serviceConfig *ServiceConfig,
@@ -66,7 +92,27 @@ func (st *springAppTarget) Package(
 ) *async.TaskWithProgress[*ServicePackageResult, ServiceProgress] {
 	return async.RunTaskWithProgress(
 		func(task *async.TaskContextWithProgress[*ServicePackageResult, ServiceProgress]) {
-			task.SetResult(packageOutput)
+			if !serviceConfig.Spring.BuildService {
+				task.SetResult(packageOutput)
+				return
+			}
+
+			task.SetProgress(NewServiceProgress("Compressing source code"))
+			archivePath, err := createBuildContextArchive(
+				serviceConfig.Project.Name,
+				serviceConfig.Name,
+				serviceConfig.Path(),
+				springBuildServiceExcludePatterns,
+			)
+			if err != nil {
+				task.SetError(err)
+				return
+			}
+
+			task.SetResult(&ServicePackageResult{
+				Build:       packageOutput.Build,
+				PackagePath: archivePath,
+			})
 		},
 	)
 }
@@ -85,78 +131,41 @@ func (st *springAppTarget) Deploy(
 				return
 			}
 
-			deploymentName := serviceConfig.Spring.DeploymentName
-			if deploymentName == "" {
-				deploymentName = defaultDeploymentName
-			}
-
-			_, err := st.springService.GetSpringAppDeployment(
-				ctx,
-				targetResource.SubscriptionId(),
-				targetResource.ResourceGroupName(),
-				targetResource.ResourceName(),
-				serviceConfig.Name,
-				deploymentName,
-			)
-
+			deploymentName, err := st.targetDeploymentName(ctx, serviceConfig, targetResource)
 			if err != nil {
-				task.SetError(fmt.Errorf("get deployment '%s' of Spring App '%s' failed: %w",
-					serviceConfig.Name, deploymentName, err))
+				task.SetError(err)
 				return
 			}
 
-			// TODO: Consider support container image and buildpacks deployment in the future
-			// For now, Azure Spring Apps only support jar deployment
-			ext := ".jar"
-			artifactPath := filepath.Join(packageOutput.PackagePath, AppServiceJavaPackageName+ext)
-
-			_, err = os.Stat(artifactPath)
-			if errors.Is(err, os.ErrNotExist) {
-				task.SetError(fmt.Errorf("artifact %s does not exist: %w", artifactPath, err))
-				return
+			deploymentOptions := &azcli.SpringDeploymentOptions{
+				JvmOptions:     serviceConfig.Spring.JvmOptions,
+				RuntimeVersion: serviceConfig.Spring.RuntimeVersion,
+				InstanceCount:  serviceConfig.Spring.InstanceCount,
 			}
-			if err != nil {
-				task.SetError(fmt.Errorf("reading artifact file %s: %w", artifactPath, err))
-				return
-			}
-
-			task.SetProgress(NewServiceProgress("Uploading spring artifact"))
-
-			relativePath, err := st.springService.UploadSpringArtifact(
-				ctx,
-				targetResource.SubscriptionId(),
-				targetResource.ResourceGroupName(),
-				targetResource.ResourceName(),
-				serviceConfig.Name,
-				artifactPath,
-			)
 
+			if serviceConfig.Spring.BuildService {
+				err = st.deployBuildResult(
+					ctx, task, serviceConfig, packageOutput, targetResource, deploymentName, deploymentOptions)
+			} else {
+				err = st.deployArtifact(
+					ctx, task, serviceConfig, packageOutput, targetResource, deploymentName, deploymentOptions)
+			}
 			if err != nil {
-				task.SetError(fmt.Errorf("failed to upload spring artifact: %w", err))
+				task.SetError(err)
 				return
 			}
 
-			task.SetProgress(NewServiceProgress("Deploying spring artifact"))
-
-			res, err := st.springService.DeploySpringAppArtifact(
+			task.SetProgress(NewServiceProgress(fmt.Sprintf("Setting deployment '%s' active", deploymentName)))
+			res, err := st.springService.SetSpringAppActiveDeployment(
 				ctx,
 				targetResource.SubscriptionId(),
 				targetResource.ResourceGroupName(),
 				targetResource.ResourceName(),
 				serviceConfig.Name,
-				*relativePath,
 				deploymentName,
 			)
 			if err != nil {
-				task.SetError(fmt.Errorf("deploying service %s: %w", serviceConfig.Name, err))
-				return
-			}
-
-			// save the storage relative, otherwise the relative path will be overwritten
-			// in the deployment from Bicep/Terraform
-			st.env.SetServiceProperty(serviceConfig.Name, "RELATIVE_PATH", *relativePath)
-			if err := st.envManager.Save(ctx, st.env); err != nil {
-				task.SetError(fmt.Errorf("failed updating environment with relative path, %w", err))
+				task.SetError(fmt.Errorf("setting deployment '%s' active: %w", deploymentName, err))
 				return
 			}
 
@@ -185,6 +194,192 @@ func (st *springAppTarget) Deploy(
 	)
 }
 
+// targetDeploymentName returns the name of the deployment that the app is deployed to. With a staging deployment,
+// it's whichever of the deployment and the staging deployment isn't active, so that the production and the staging
+// deployments are swapped by setting it active once it's deployed.
+func (st *springAppTarget) targetDeploymentName(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+) (string, error) {
+	deploymentName := serviceConfig.Spring.DeploymentName
+	if deploymentName == "" {
+		deploymentName = defaultDeploymentName
+	}
+
+	if serviceConfig.Spring.StagingDeploymentName == "" {
+		_, err := st.springService.GetSpringAppDeployment(
+			ctx,
+			targetResource.SubscriptionId(),
+			targetResource.ResourceGroupName(),
+			targetResource.ResourceName(),
+			serviceConfig.Name,
+			deploymentName,
+		)
+		if err != nil {
+			return "", fmt.Errorf("get deployment '%s' of Spring App '%s' failed: %w",
+				serviceConfig.Name, deploymentName, err)
+		}
+
+		return deploymentName, nil
+	}
+
+	if serviceConfig.Spring.StagingDeploymentName == deploymentName {
+		return "", fmt.Errorf(
+			"the staging deployment of Spring App '%s' must be different from its deployment '%s'",
+			serviceConfig.Name, deploymentName)
+	}
+
+	activeDeployment, err := st.springService.GetSpringAppActiveDeployment(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+		serviceConfig.Name,
+	)
+	if err != nil {
+		return "", fmt.Errorf("get active deployment of Spring App '%s' failed: %w", serviceConfig.Name, err)
+	}
+
+	return stagingDeploymentName(deploymentName, serviceConfig.Spring.StagingDeploymentName, activeDeployment), nil
+}
+
+// stagingDeploymentName returns the deployment of a blue/green deployment that isn't active, which is the deployment
+// when neither of them is active, ex) the first deployment
+func stagingDeploymentName(deploymentName string, stagingDeploymentName string, activeDeployment *string) string {
+	if activeDeployment != nil && *activeDeployment == deploymentName {
+		return stagingDeploymentName
+	}
+
+	return deploymentName
+}
+
+// deployArtifact uploads the jar built by the framework service and deploys it to the deployment
+func (st *springAppTarget) deployArtifact(
+	ctx context.Context,
+	task *async.TaskContextWithProgress[*ServiceDeployResult, ServiceProgress],
+	serviceConfig *ServiceConfig,
+	packageOutput *ServicePackageResult,
+	targetResource *environment.TargetResource,
+	deploymentName string,
+	options *azcli.SpringDeploymentOptions,
+) error {
+	// TODO: Consider support container image deployment in the future
+	ext := ".jar"
+	artifactPath := filepath.Join(packageOutput.PackagePath, AppServiceJavaPackageName+ext)
+
+	_, err := os.Stat(artifactPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("artifact %s does not exist: %w", artifactPath, err)
+	}
+	if err != nil {
+		return fmt.Errorf("reading artifact file %s: %w", artifactPath, err)
+	}
+
+	task.SetProgress(NewServiceProgress("Uploading spring artifact"))
+
+	relativePath, err := st.springService.UploadSpringArtifact(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+		serviceConfig.Name,
+		artifactPath,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to upload spring artifact: %w", err)
+	}
+
+	task.SetProgress(NewServiceProgress(fmt.Sprintf("Deploying spring artifact to deployment '%s'", deploymentName)))
+
+	_, err = st.springService.DeploySpringAppArtifact(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+		serviceConfig.Name,
+		*relativePath,
+		deploymentName,
+		options,
+	)
+	if err != nil {
+		return fmt.Errorf("deploying service %s: %w", serviceConfig.Name, err)
+	}
+
+	// save the storage relative, otherwise the relative path will be overwritten
+	// in the deployment from Bicep/Terraform
+	st.env.SetServiceProperty(serviceConfig.Name, "RELATIVE_PATH", *relativePath)
+	if err := st.envManager.Save(ctx, st.env); err != nil {
+		return fmt.Errorf("failed updating environment with relative path, %w", err)
+	}
+
+	return nil
+}
+
+// deployBuildResult uploads the source code archive to the build service, builds it and deploys the result of the
+// build to the deployment
+func (st *springAppTarget) deployBuildResult(
+	ctx context.Context,
+	task *async.TaskContextWithProgress[*ServiceDeployResult, ServiceProgress],
+	serviceConfig *ServiceConfig,
+	packageOutput *ServicePackageResult,
+	targetResource *environment.TargetResource,
+	deploymentName string,
+	options *azcli.SpringDeploymentOptions,
+) error {
+	defer os.Remove(packageOutput.PackagePath)
+
+	task.SetProgress(NewServiceProgress("Uploading source code"))
+
+	relativePath, err := st.springService.UploadSpringBuildSource(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+		packageOutput.PackagePath,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to upload source code: %w", err)
+	}
+
+	task.SetProgress(NewServiceProgress("Building source code with the build service"))
+
+	buildResultId, err := st.springService.BuildSpringAppSource(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+		serviceConfig.Name,
+		deploymentName,
+		*relativePath,
+		&azcli.SpringBuildOptions{
+			Builder:        serviceConfig.Spring.Builder,
+			RuntimeVersion: serviceConfig.Spring.RuntimeVersion,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("building service %s: %w", serviceConfig.Name, err)
+	}
+
+	task.SetProgress(NewServiceProgress(fmt.Sprintf("Deploying build result to deployment '%s'", deploymentName)))
+
+	_, err = st.springService.DeploySpringAppBuildResult(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+		serviceConfig.Name,
+		*buildResultId,
+		deploymentName,
+		options,
+	)
+	if err != nil {
+		return fmt.Errorf("deploying service %s: %w", serviceConfig.Name, err)
+	}
+
+	return nil
+}
+
 // Gets the exposed endpoints for the Spring Apps Service
 func (st *springAppTarget) Endpoints(
 	ctx context.Context,
@@ -205,6 +400,60 @@ func (st *springAppTarget) Endpoints(
 	return springAppProperties.Url, nil
 }
 
+// Streams the logs of the instances of the Spring app deployment
+func (st *springAppTarget) Logs(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	options *LogsOptions,
+	writer io.Writer,
+) error {
+	if err := st.validateTargetResource(ctx, serviceConfig, targetResource); err != nil {
+		return fmt.Errorf("validating target resource: %w", err)
+	}
+
+	deploymentName := serviceConfig.Spring.DeploymentName
+	if deploymentName == "" {
+		deploymentName = defaultDeploymentName
+	}
+
+	// With a staging deployment, the logs of the production deployment are streamed, which is the active one
+	if serviceConfig.Spring.StagingDeploymentName != "" {
+		activeDeployment, err := st.springService.GetSpringAppActiveDeployment(
+			ctx,
+			targetResource.SubscriptionId(),
+			targetResource.ResourceGroupName(),
+			targetResource.ResourceName(),
+			serviceConfig.Name,
+		)
+		if err != nil {
+			return fmt.Errorf("get active deployment of Spring App '%s' failed: %w", serviceConfig.Name, err)
+		}
+
+		if activeDeployment != nil {
+			deploymentName = *activeDeployment
+		}
+	}
+
+	streams, err := st.springService.OpenSpringAppLogStreams(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+		serviceConfig.Name,
+		deploymentName,
+		&azcli.SpringLogStreamOptions{
+			Follow: options.Follow,
+			Since:  options.Since,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	return copyLogStreams(ctx, streams, writer)
+}
+
 func (st *springAppTarget) validateTargetResource(
 	ctx context.Context,
 	serviceConfig *ServiceConfig,
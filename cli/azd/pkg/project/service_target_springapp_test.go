@@ -5,11 +5,15 @@ package project
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
 	"github.com/azure/azure-dev/cli/azd/test/mocks"
 	"github.com/stretchr/testify/require"
 )
@@ -57,3 +61,59 @@ func TestNewSpringAppTargetTypeValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestSpringAppPackageBuildService(t *testing.T) {
+	root := t.TempDir()
+	for _, file := range []string{
+		"pom.xml",
+		"src/main/java/com/contoso/build/App.java",
+		"api/pom.xml",
+		"api/target/api.jar",
+		"target/app.jar",
+		"build/libs/app.jar",
+		".gradle/8.5/checksums.lock",
+		".git/HEAD",
+		".azure/dev/.env",
+	} {
+		path := filepath.Join(root, filepath.FromSlash(file))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), osutil.PermissionDirectory))
+		require.NoError(t, os.WriteFile(path, nil, osutil.PermissionFile))
+	}
+
+	mockContext := mocks.NewMockContext(context.Background())
+	serviceConfig := createTestServiceConfig(root, SpringAppTarget, ServiceLanguageJava)
+	serviceConfig.Spring.BuildService = true
+
+	packageTask := (&springAppTarget{}).Package(*mockContext.Context, serviceConfig, &ServicePackageResult{})
+	logProgress(packageTask)
+	result, err := packageTask.Await()
+	require.NoError(t, err)
+	defer os.Remove(result.PackagePath)
+
+	// The build outputs, the git repository and the environments aren't uploaded to the build service
+	require.ElementsMatch(t, []string{
+		"pom.xml",
+		"src/main/java/com/contoso/build/App.java",
+		"api/pom.xml",
+	}, readTarGzNames(t, result.PackagePath))
+}
+
+func TestSpringAppStagingDeploymentName(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		activeDeployment *string
+		expected         string
+	}{
+		"NoActiveDeployment":      {activeDeployment: nil, expected: "blue"},
+		"DeploymentActive":        {activeDeployment: to.Ptr("blue"), expected: "green"},
+		"StagingDeploymentActive": {activeDeployment: to.Ptr("green"), expected: "blue"},
+		"OtherDeploymentActive":   {activeDeployment: to.Ptr("default"), expected: "blue"},
+	}
+
+	for test, data := range tests {
+		t.Run(test, func(t *testing.T) {
+			require.Equal(t, data.expected, stagingDeploymentName("blue", "green", data.activeDeployment))
+		})
+	}
+}
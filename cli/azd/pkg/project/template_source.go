@@ -0,0 +1,205 @@
+package project
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"gopkg.in/yaml.v3"
+)
+
+// SaveTemplateSource records the template source in the metadata of the azure.yaml file at projectFilePath.
+//
+// Unlike Save, the file is edited in place which keeps its comments and the order of its properties. This keeps the
+// project file close to the one of the template, which matters when later changes of the template are merged into it.
+func SaveTemplateSource(projectFilePath string, source *TemplateSource) error {
+	contents, err := os.ReadFile(projectFilePath)
+	if err != nil {
+		return fmt.Errorf("reading project file: %w", err)
+	}
+
+	updated, err := SetTemplateSource(contents, source)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(projectFilePath, updated, osutil.PermissionFile); err != nil {
+		return fmt.Errorf("saving project file: %w", err)
+	}
+
+	return nil
+}
+
+// SetTemplateSource returns the contents of an azure.yaml file with the template source set in its metadata.
+//
+// Only the lines of the metadata.templateSource property are rewritten, or new lines added when it doesn't exist yet.
+// The rest of the file, including its comments, blank lines, indentation and quoting, is kept as is.
+func SetTemplateSource(contents []byte, source *TemplateSource) ([]byte, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(contents, &document); err != nil {
+		return nil, fmt.Errorf("parsing project file: %w", err)
+	}
+
+	if document.Kind != yaml.DocumentNode ||
+		len(document.Content) == 0 ||
+		document.Content[0].Kind != yaml.MappingNode {
+		return nil, errors.New("parsing project file: expected a mapping at the root of the document")
+	}
+
+	root := document.Content[0]
+	lines := strings.SplitAfter(string(contents), "\n")
+	eol := "\n"
+	if bytes.Contains(contents, []byte("\r\n")) {
+		eol = "\r\n"
+	}
+
+	metadataKey, metadata := mappingEntry(root, "metadata")
+	switch {
+	case metadataKey == nil:
+		// The metadata is appended at the end of the file
+		block, err := yamlBlock(
+			map[string]any{"metadata": map[string]any{"templateSource": source}}, documentIndent(root), 0, eol)
+		if err != nil {
+			return nil, err
+		}
+
+		return spliceLines(lines, len(lines), len(lines), block, eol), nil
+	case metadata.Kind == yaml.MappingNode && metadata.Style&yaml.FlowStyle == 0 && len(metadata.Content) > 0:
+		column := metadata.Content[0].Column - 1
+		block, err := yamlBlock(
+			map[string]any{"templateSource": source}, column-(metadataKey.Column-1), column, eol)
+		if err != nil {
+			return nil, err
+		}
+
+		sourceKey, _ := mappingEntry(metadata, "templateSource")
+		if sourceKey == nil {
+			end := blockEnd(lines, metadataKey)
+			return spliceLines(lines, end, end, block, eol), nil
+		}
+
+		return spliceLines(lines, sourceKey.Line-1, blockEnd(lines, sourceKey), block, eol), nil
+	default:
+		// Metadata that is empty or in flow style, ex) metadata: {}, is rewritten as a block mapping
+		updated := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		if metadata.Kind == yaml.MappingNode {
+			updated.Content = metadata.Content
+		}
+
+		var sourceNode yaml.Node
+		if err := sourceNode.Encode(source); err != nil {
+			return nil, fmt.Errorf("marshalling template source: %w", err)
+		}
+		setMappingValue(updated, "templateSource", &sourceNode)
+
+		block, err := yamlBlock(
+			map[string]any{"metadata": updated}, documentIndent(root), metadataKey.Column-1, eol)
+		if err != nil {
+			return nil, err
+		}
+
+		return spliceLines(lines, metadataKey.Line-1, blockEnd(lines, metadataKey), block, eol), nil
+	}
+}
+
+// yamlBlock returns the lines of value encoded as yaml with the given indentation, each prefixed by column spaces.
+func yamlBlock(value any, indent int, column int, eol string) ([]string, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(indent)
+	if err := encoder.Encode(value); err != nil {
+		return nil, fmt.Errorf("marshalling template source: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("marshalling template source: %w", err)
+	}
+
+	prefix := strings.Repeat(" ", column)
+	block := []string{}
+	for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+		block = append(block, prefix+line+eol)
+	}
+
+	return block, nil
+}
+
+// spliceLines returns lines with the lines in [start, end) replaced by block.
+func spliceLines(lines []string, start int, end int, block []string, eol string) []byte {
+	var buf bytes.Buffer
+	for _, line := range lines[:start] {
+		buf.WriteString(line)
+	}
+
+	// The last line of a file doesn't always end with a line break
+	if start > 0 && lines[start-1] != "" && !strings.HasSuffix(lines[start-1], "\n") {
+		buf.WriteString(eol)
+	}
+
+	for _, line := range block {
+		buf.WriteString(line)
+	}
+	for _, line := range lines[end:] {
+		buf.WriteString(line)
+	}
+
+	return buf.Bytes()
+}
+
+// blockEnd returns the index of the line following the property of key, which spans the line of the key and the
+// following lines indented further than it. Trailing blank lines and comments that are not indented further are
+// left out.
+func blockEnd(lines []string, key *yaml.Node) int {
+	end := key.Line
+	for i := key.Line; i < len(lines); i++ {
+		trimmed := strings.TrimLeft(lines[i], " ")
+		if strings.TrimSpace(trimmed) == "" {
+			continue
+		}
+
+		if len(lines[i])-len(trimmed) <= key.Column-1 {
+			break
+		}
+
+		end = i + 1
+	}
+
+	return end
+}
+
+// documentIndent returns the indentation of the nested block mappings of the document, defaulting to two spaces.
+func documentIndent(root *yaml.Node) int {
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		value := root.Content[i+1]
+		if value.Kind == yaml.MappingNode && value.Style&yaml.FlowStyle == 0 && len(value.Content) > 0 {
+			if indent := value.Content[0].Column - root.Content[i].Column; indent > 0 {
+				return indent
+			}
+		}
+	}
+
+	return 2
+}
+
+func mappingEntry(mapping *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i], mapping.Content[i+1]
+		}
+	}
+
+	return nil, nil
+}
+
+func setMappingValue(mapping *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			mapping.Content[i+1] = value
+			return
+		}
+	}
+
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+}
@@ -0,0 +1,145 @@
+package project
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SetTemplateSource(t *testing.T) {
+	source := &TemplateSource{
+		Repository: "https://github.com/Azure-Samples/todo-python-mongo",
+		Ref:        "main",
+		Commit:     "0123456789abcdef0123456789abcdef01234567",
+		Values:     map[string]string{"serviceName": "orders"},
+	}
+
+	t.Run("AddsMetadata", func(t *testing.T) {
+		contents := heredoc.Doc(`
+			# yaml-language-server: $schema=https://raw.githubusercontent.com/Azure/azure-dev/main/schemas/v1.0/azure.yaml.json
+
+			name: todo-python-mongo
+			services:
+			  # The API of the application
+			  api:
+			    project: ./src/api
+			    language: py
+			    host: appservice
+			hooks:
+			  postprovision:
+			    run: ./scripts/setup.sh
+			    shell: sh
+		`)
+
+		updated, err := SetTemplateSource([]byte(contents), source)
+		require.NoError(t, err)
+		require.Equal(t, contents+heredoc.Doc(`
+			metadata:
+			  templateSource:
+			    repository: https://github.com/Azure-Samples/todo-python-mongo
+			    ref: main
+			    commit: 0123456789abcdef0123456789abcdef01234567
+			    values:
+			      serviceName: orders
+		`), string(updated))
+	})
+
+	t.Run("ReplacesTemplateSource", func(t *testing.T) {
+		contents := heredoc.Doc(`
+			name: todo-python-mongo
+			metadata:
+			  template: todo-python-mongo@0.0.1-beta
+			  templateSource:
+			    repository: https://github.com/Azure-Samples/todo-python-mongo
+			    commit: fedcba9876543210fedcba9876543210fedcba98
+		`)
+
+		updated, err := SetTemplateSource([]byte(contents), source)
+		require.NoError(t, err)
+
+		project, err := Parse(context.Background(), string(updated))
+		require.NoError(t, err)
+		require.Equal(t, "todo-python-mongo@0.0.1-beta", project.Metadata.Template)
+		require.Equal(t, source, project.Metadata.TemplateSource)
+	})
+
+	t.Run("KeepsFormatting", func(t *testing.T) {
+		contents := heredoc.Doc(`
+			name: 'todo-python-mongo'
+			metadata:
+			    template: "todo-python-mongo@0.0.1-beta" # the template of the project
+			    templateSource:
+			        repository: https://github.com/Azure-Samples/todo-python-mongo
+			        commit: fedcba9876543210fedcba9876543210fedcba98
+
+			services:
+			    api:
+			        project: ./src/api
+			        language: py
+			        host: appservice
+			        hooks: { prepackage: { run: ./build.sh } }
+		`)
+
+		updated, err := SetTemplateSource([]byte(contents), source)
+		require.NoError(t, err)
+		require.Equal(t, heredoc.Doc(`
+			name: 'todo-python-mongo'
+			metadata:
+			    template: "todo-python-mongo@0.0.1-beta" # the template of the project
+			    templateSource:
+			        repository: https://github.com/Azure-Samples/todo-python-mongo
+			        ref: main
+			        commit: 0123456789abcdef0123456789abcdef01234567
+			        values:
+			            serviceName: orders
+
+			services:
+			    api:
+			        project: ./src/api
+			        language: py
+			        host: appservice
+			        hooks: { prepackage: { run: ./build.sh } }
+		`), string(updated))
+	})
+
+	t.Run("AddsToMetadata", func(t *testing.T) {
+		contents := "name: todo-python-mongo\nmetadata:\n  template: todo-python-mongo@0.0.1-beta\n\nservices: {}"
+
+		updated, err := SetTemplateSource([]byte(contents), source)
+		require.NoError(t, err)
+		require.Equal(t, heredoc.Doc(`
+			name: todo-python-mongo
+			metadata:
+			  template: todo-python-mongo@0.0.1-beta
+			  templateSource:
+			    repository: https://github.com/Azure-Samples/todo-python-mongo
+			    ref: main
+			    commit: 0123456789abcdef0123456789abcdef01234567
+			    values:
+			      serviceName: orders
+
+			services: {}`), string(updated))
+	})
+
+	t.Run("FlowMetadata", func(t *testing.T) {
+		contents := "name: todo-python-mongo\nmetadata: {template: todo-python-mongo@0.0.1-beta}\nservices: {}\n"
+
+		updated, err := SetTemplateSource([]byte(contents), source)
+		require.NoError(t, err)
+
+		project, err := Parse(context.Background(), string(updated))
+		require.NoError(t, err)
+		require.Equal(t, "todo-python-mongo@0.0.1-beta", project.Metadata.Template)
+		require.Equal(t, source, project.Metadata.TemplateSource)
+		require.True(t, strings.HasPrefix(string(updated), "name: todo-python-mongo\nmetadata:\n"))
+		require.True(t, strings.HasSuffix(string(updated), "\nservices: {}\n"))
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := SetTemplateSource([]byte("- name: todo"), source)
+		require.Error(t, err)
+	})
+}
@@ -4,7 +4,9 @@
 package rzip
 
 import (
+	"archive/tar"
 	"archive/zip"
+	"compress/gzip"
 	"io"
 	"io/fs"
 	"os"
@@ -14,45 +16,122 @@ import (
 
 func CreateFromDirectory(source string, buf *os.File) error {
 	w := zip.NewWriter(buf)
-	err := filepath.WalkDir(source, func(path string, info fs.DirEntry, err error) error {
-		if err != nil {
-			return err
+	err := walkFiles(source, nil, func(path string, name string, fileInfo fs.FileInfo) error {
+		header := &zip.FileHeader{
+			Name:     name,
+			Modified: fileInfo.ModTime(),
+			Method:   zip.Deflate,
 		}
 
-		if info.IsDir() {
-			return nil
-		}
-		fileInfo, err := info.Info()
+		f, err := w.CreateHeader(header)
 		if err != nil {
 			return err
 		}
+		return copyFile(f, path)
+	})
+	if err != nil {
+		return err
+	}
 
-		header := &zip.FileHeader{
-			Name: strings.Replace(
-				strings.TrimPrefix(
-					strings.TrimPrefix(path, source),
-					string(filepath.Separator)), "\\", "/", -1),
-			Modified: fileInfo.ModTime(),
-			Method:   zip.Deflate,
+	return w.Close()
+}
+
+// ExcludeFunc returns true when the file or directory with the slash separated path name, relative to the source
+// directory, is excluded from an archive. The files of an excluded directory are excluded without being walked.
+type ExcludeFunc func(name string, isDir bool) (bool, error)
+
+// CreateTarGzFromDirectory writes the files of the source directory which aren't excluded to a gzipped tarball, ex)
+// the build context of a remote image build. A nil exclude includes every file.
+func CreateTarGzFromDirectory(source string, buf *os.File, exclude ExcludeFunc) error {
+	gw := gzip.NewWriter(buf)
+	w := tar.NewWriter(gw)
+	err := walkFiles(source, exclude, func(path string, name string, fileInfo fs.FileInfo) error {
+		// Symbolic links are archived as the files they point to, like in zip archives
+		if fileInfo.Mode()&fs.ModeSymlink != 0 {
+			target, err := os.Stat(path)
+			if err != nil {
+				return err
+			}
+			if target.IsDir() {
+				return nil
+			}
+			fileInfo = target
 		}
 
-		f, err := w.CreateHeader(header)
-		if err != nil {
+		header := &tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     name,
+			Mode:     int64(fileInfo.Mode().Perm()),
+			Size:     fileInfo.Size(),
+			ModTime:  fileInfo.ModTime(),
+		}
+
+		if err := w.WriteHeader(header); err != nil {
 			return err
 		}
-		in, err := os.Open(path)
+		return copyFile(w, path)
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return gw.Close()
+}
+
+// walkFiles calls fn for each file of the source directory that isn't excluded, with the slash separated path of the
+// file relative to source
+func walkFiles(
+	source string,
+	exclude ExcludeFunc,
+	fn func(path string, name string, fileInfo fs.FileInfo) error,
+) error {
+	return filepath.WalkDir(source, func(path string, info fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
-		_, err = io.Copy(f, in)
+
+		name := strings.Replace(
+			strings.TrimPrefix(
+				strings.TrimPrefix(path, source),
+				string(filepath.Separator)), "\\", "/", -1)
+
+		if exclude != nil && name != "" {
+			excluded, err := exclude(name, info.IsDir())
+			if err != nil {
+				return err
+			}
+
+			if excluded && info.IsDir() {
+				return filepath.SkipDir
+			}
+			if excluded {
+				return nil
+			}
+		}
+
+		if info.IsDir() {
+			return nil
+		}
+		fileInfo, err := info.Info()
 		if err != nil {
 			return err
 		}
-		return nil
+
+		return fn(path, name, fileInfo)
 	})
+}
+
+func copyFile(w io.Writer, path string) error {
+	in, err := os.Open(path)
 	if err != nil {
 		return err
 	}
+	defer in.Close()
 
-	return w.Close()
+	_, err = io.Copy(w, in)
+	return err
 }
@@ -0,0 +1,130 @@
+package templates
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/lazy"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/github"
+)
+
+const (
+	// GitTokenConfigKey is the user config key of the personal access token used to clone private template
+	// repositories.
+	GitTokenConfigKey = "template.git.token"
+	// GitHostConfigKey is the user config key of the host the personal access token is sent to, ex) github.com.
+	GitHostConfigKey = "template.git.host"
+)
+
+// GitCloner clones template repositories.
+//
+// Private repositories are cloned with the personal access token set in the user config at GitTokenConfigKey, which is
+// only sent to the host set at GitHostConfigKey. Otherwise, repositories hosted on GitHub which can't be cloned with the
+// credential helpers of the user are cloned with the credentials of the GitHub CLI, when it's logged in.
+type GitCloner struct {
+	gitCli        git.GitCli
+	configManager config.UserConfigManager
+	lazyGhCli     *lazy.Lazy[github.GitHubCli]
+}
+
+func NewGitCloner(
+	gitCli git.GitCli,
+	configManager config.UserConfigManager,
+	lazyGhCli *lazy.Lazy[github.GitHubCli],
+) *GitCloner {
+	return &GitCloner{
+		gitCli:        gitCli,
+		configManager: configManager,
+		lazyGhCli:     lazyGhCli,
+	}
+}
+
+// ShallowClone clones the latest commit of ref, or of the default branch when empty, into target.
+func (c *GitCloner) ShallowClone(ctx context.Context, repositoryUrl string, ref string, target string) error {
+	return c.clone(ctx, c.gitCli.ShallowClone, repositoryUrl, ref, target)
+}
+
+// Clone clones the full history of the repository into target, with ref, or the default branch, checked out.
+func (c *GitCloner) Clone(ctx context.Context, repositoryUrl string, ref string, target string) error {
+	return c.clone(ctx, c.gitCli.Clone, repositoryUrl, ref, target)
+}
+
+type cloneFunc func(
+	ctx context.Context, repositoryPath string, branch string, target string, credential *git.Credential) error
+
+func (c *GitCloner) clone(
+	ctx context.Context,
+	clone cloneFunc,
+	repositoryUrl string,
+	ref string,
+	target string) error {
+	userConfig, err := c.configManager.Load()
+	if err != nil {
+		return fmt.Errorf("loading user config: %w", err)
+	}
+
+	if token, has := userConfig.GetString(GitTokenConfigKey); has && token != "" {
+		host, _ := userConfig.GetString(GitHostConfigKey)
+		if host != "" && strings.EqualFold(host, repositoryHost(repositoryUrl)) {
+			return clone(ctx, repositoryUrl, ref, target, &git.Credential{Token: token})
+		}
+
+		log.Printf("not sending the template git token to '%s', it's only sent to the host set at '%s'",
+			repositoryUrl, GitHostConfigKey)
+	}
+
+	err = clone(ctx, repositoryUrl, ref, target, nil)
+	if err == nil || !isGitHubRepository(repositoryUrl) {
+		return err
+	}
+
+	ghCli, ghErr := c.lazyGhCli.GetValue()
+	if ghErr != nil {
+		log.Printf("GitHub CLI not available to authenticate '%s': %v", repositoryUrl, ghErr)
+		return err
+	}
+
+	authStatus, ghErr := ghCli.GetAuthStatus(ctx, "github.com")
+	if ghErr != nil || !authStatus.LoggedIn {
+		return &azcli.ErrorWithSuggestion{
+			Err: err,
+			Suggestion: fmt.Sprintf(
+				"Suggestion: if the template repository is private, log in to GitHub with `%s auth login` "+
+					"or set a personal access token with `azd config set %s <token>` and `azd config set %s %s`",
+				ghCli.BinaryPath(),
+				GitTokenConfigKey,
+				GitHostConfigKey,
+				repositoryHost(repositoryUrl),
+			),
+		}
+	}
+
+	// a failed clone may leave a partial repository behind
+	if err := os.RemoveAll(target); err != nil {
+		return err
+	}
+
+	return clone(ctx, repositoryUrl, ref, target, &git.Credential{GhPath: ghCli.BinaryPath()})
+}
+
+func isGitHubRepository(repositoryUrl string) bool {
+	return strings.EqualFold(repositoryHost(repositoryUrl), "github.com")
+}
+
+// repositoryHost returns the host of an http(s) repository url, or empty for other urls, like SSH urls, which git
+// doesn't authenticate with a token.
+func repositoryHost(repositoryUrl string) string {
+	parsed, err := url.Parse(repositoryUrl)
+	if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") {
+		return ""
+	}
+
+	return parsed.Hostname()
+}
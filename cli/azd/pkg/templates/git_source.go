@@ -0,0 +1,62 @@
+package templates
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// DefaultGitSourcePath is the path of the list of templates in the repository of a git source, when not configured.
+const DefaultGitSourcePath = "templates.json"
+
+// NewGitTemplateSource creates a new template source from a JSON list of templates in a git repository.
+//
+// Templates of the list without a repositoryPath are hosted in the repository of the source, at the ref of the source
+// unless they set their own. This allows a single repository to host both the list and the templates, each in its own
+// subdirectory.
+func NewGitTemplateSource(ctx context.Context, config *SourceConfig, gitCloner *GitCloner) (Source, error) {
+	repositoryUrl, err := Absolute(config.Location)
+	if err != nil {
+		return nil, err
+	}
+
+	staging, err := os.MkdirTemp("", "az-dev-template-source")
+	if err != nil {
+		return nil, fmt.Errorf("creating temp folder: %w", err)
+	}
+	defer func() {
+		_ = os.RemoveAll(staging)
+	}()
+
+	if err := gitCloner.ShallowClone(ctx, repositoryUrl, config.Ref, staging); err != nil {
+		return nil, fmt.Errorf("fetching template source '%s': %w", repositoryUrl, err)
+	}
+
+	path := config.Path
+	if path == "" {
+		path = DefaultGitSourcePath
+	}
+
+	templatesJson, err := os.ReadFile(filepath.Join(staging, filepath.FromSlash(path)))
+	if err != nil {
+		return nil, fmt.Errorf("reading '%s' from template source '%s': %w", path, repositoryUrl, err)
+	}
+
+	var templates []*Template
+	if err := json.Unmarshal(templatesJson, &templates); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal templates JSON %w", err)
+	}
+
+	for _, template := range templates {
+		if template.RepositoryPath == "" {
+			template.RepositoryPath = repositoryUrl
+			if template.Ref == "" {
+				template.Ref = config.Ref
+			}
+		}
+	}
+
+	return NewTemplateSource(config.Name, templates)
+}
@@ -0,0 +1,235 @@
+package templates
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/lazy"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/github"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+const gitSourceTemplates = `[
+	{
+		"name": "API",
+		"description": "An API hosted in the source repository",
+		"subdirectory": "templates/api"
+	},
+	{
+		"name": "Worker",
+		"description": "A worker hosted in its own repository",
+		"repositoryPath": "contoso/worker",
+		"ref": "v2"
+	}
+]`
+
+func Test_GitTemplateSource(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	mockGitSourceClone(mockContext, "https://github.com/contoso/templates", map[string]string{
+		"catalog/templates.json": gitSourceTemplates,
+	})
+
+	configManager := &mockUserConfigManager{}
+	configManager.On("Load").Return(config.NewEmptyConfig(), nil)
+
+	cloner := NewGitCloner(git.NewGitCli(mockContext.CommandRunner), configManager, noGhCli())
+
+	source, err := NewGitTemplateSource(*mockContext.Context, &SourceConfig{
+		Key:      "contoso",
+		Name:     "Contoso",
+		Type:     SourceKindGit,
+		Location: "contoso/templates",
+		Ref:      "main",
+		Path:     "catalog/templates.json",
+	}, cloner)
+	require.NoError(t, err)
+
+	templates, err := source.ListTemplates(*mockContext.Context)
+	require.NoError(t, err)
+	require.Len(t, templates, 2)
+
+	require.Equal(t, "https://github.com/contoso/templates", templates[0].RepositoryPath)
+	require.Equal(t, "main", templates[0].Ref)
+	require.Equal(t, "templates/api", templates[0].Subdirectory)
+	require.Equal(t, "Contoso", templates[0].Source)
+
+	require.Equal(t, "contoso/worker", templates[1].RepositoryPath)
+	require.Equal(t, "v2", templates[1].Ref)
+
+	_, err = NewGitTemplateSource(*mockContext.Context, &SourceConfig{
+		Name:     "Contoso",
+		Type:     SourceKindGit,
+		Location: "contoso/templates",
+	}, cloner)
+	require.ErrorContains(t, err, "templates.json")
+}
+
+func Test_GitCloner_Credentials(t *testing.T) {
+	const repositoryUrl = "https://github.com/contoso/private"
+
+	t.Run("Token", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		clones := mockGitSourceClone(mockContext, repositoryUrl, map[string]string{"templates.json": "[]"})
+
+		configManager := &mockUserConfigManager{}
+		configManager.On("Load").Return(config.NewConfig(map[string]any{
+			"template": map[string]any{"git": map[string]any{"token": "s3cret", "host": "GitHub.com"}},
+		}), nil)
+
+		cloner := NewGitCloner(git.NewGitCli(mockContext.CommandRunner), configManager, noGhCli())
+		err := cloner.ShallowClone(*mockContext.Context, repositoryUrl, "", t.TempDir())
+		require.NoError(t, err)
+
+		require.Len(t, *clones, 1)
+		require.Contains(t, (*clones)[0].Env, "AZD_GIT_TOKEN=s3cret")
+		require.NotContains(t, strings.Join((*clones)[0].Args, " "), "s3cret")
+
+		// the token is only sent to the host of the repository, ex) not to the hosts of submodules
+		require.Contains(t, (*clones)[0].Args, "credential.https://github.com.helper=")
+		require.NotContains(t, (*clones)[0].Args, "credential.helper=")
+	})
+
+	t.Run("TokenNotSentToOtherHosts", func(t *testing.T) {
+		const otherUrl = "https://example.com/contoso/private.git"
+		for _, gitConfig := range []map[string]any{
+			{"token": "s3cret"},
+			{"token": "s3cret", "host": "github.com"},
+		} {
+			mockContext := mocks.NewMockContext(context.Background())
+			clones := mockGitSourceClone(mockContext, otherUrl, map[string]string{"templates.json": "[]"})
+
+			configManager := &mockUserConfigManager{}
+			configManager.On("Load").Return(config.NewConfig(map[string]any{
+				"template": map[string]any{"git": gitConfig},
+			}), nil)
+
+			cloner := NewGitCloner(git.NewGitCli(mockContext.CommandRunner), configManager, noGhCli())
+			err := cloner.ShallowClone(*mockContext.Context, otherUrl, "", t.TempDir())
+			require.NoError(t, err)
+
+			require.Len(t, *clones, 1)
+			require.NotContains(t, strings.Join((*clones)[0].Args, " "), "credential.")
+			require.NotContains(t, (*clones)[0].Env, "AZD_GIT_TOKEN=s3cret")
+		}
+	})
+
+	t.Run("TokenNotSentOverSsh", func(t *testing.T) {
+		const sshUrl = "git@github.com:contoso/private.git"
+		mockContext := mocks.NewMockContext(context.Background())
+		clones := mockGitSourceClone(mockContext, sshUrl, map[string]string{"templates.json": "[]"})
+
+		configManager := &mockUserConfigManager{}
+		configManager.On("Load").Return(config.NewConfig(map[string]any{
+			"template": map[string]any{"git": map[string]any{"token": "s3cret"}},
+		}), nil)
+
+		cloner := NewGitCloner(git.NewGitCli(mockContext.CommandRunner), configManager, noGhCli())
+		err := cloner.ShallowClone(*mockContext.Context, sshUrl, "", t.TempDir())
+		require.NoError(t, err)
+
+		require.Len(t, *clones, 1)
+		require.NotContains(t, strings.Join((*clones)[0].Args, " "), "credential.")
+		require.NotContains(t, (*clones)[0].Env, "AZD_GIT_TOKEN=s3cret")
+	})
+
+	t.Run("GitHubCli", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		clones := mockGitSourceClone(mockContext, repositoryUrl, map[string]string{"templates.json": "[]"})
+		failUnauthenticatedClones(mockContext)
+
+		configManager := &mockUserConfigManager{}
+		configManager.On("Load").Return(config.NewEmptyConfig(), nil)
+
+		ghCli := &mockGhCli{loggedIn: true}
+		cloner := NewGitCloner(git.NewGitCli(mockContext.CommandRunner), configManager, lazy.From[github.GitHubCli](ghCli))
+		err := cloner.Clone(*mockContext.Context, repositoryUrl, "main", t.TempDir())
+		require.NoError(t, err)
+
+		// only the authenticated clone is recorded, the first one fails
+		require.Len(t, *clones, 1)
+		require.Contains(t, (*clones)[0].Args, "credential.https://github.com.helper=!/bin/gh auth git-credential")
+	})
+
+	t.Run("GitHubCliNotLoggedIn", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		mockGitSourceClone(mockContext, repositoryUrl, map[string]string{"templates.json": "[]"})
+		failUnauthenticatedClones(mockContext)
+
+		configManager := &mockUserConfigManager{}
+		configManager.On("Load").Return(config.NewEmptyConfig(), nil)
+
+		ghCli := &mockGhCli{loggedIn: false}
+		cloner := NewGitCloner(git.NewGitCli(mockContext.CommandRunner), configManager, lazy.From[github.GitHubCli](ghCli))
+		err := cloner.Clone(*mockContext.Context, repositoryUrl, "", t.TempDir())
+
+		var suggestionErr *azcli.ErrorWithSuggestion
+		require.ErrorAs(t, err, &suggestionErr)
+		require.Contains(t, suggestionErr.Suggestion, GitTokenConfigKey)
+	})
+}
+
+// mockGitSourceClone mocks the clone of repositoryUrl by writing files to the target of the clone. The arguments of
+// each clone are recorded.
+func mockGitSourceClone(
+	mockContext *mocks.MockContext, repositoryUrl string, files map[string]string) *[]exec.RunArgs {
+	clones := &[]exec.RunArgs{}
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return args.Cmd == "git" && slices.Contains(args.Args, "clone") && slices.Contains(args.Args, repositoryUrl)
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		*clones = append(*clones, args)
+		target := args.Args[len(args.Args)-1]
+		for name, contents := range files {
+			path := filepath.Join(target, filepath.FromSlash(name))
+			if err := os.MkdirAll(filepath.Dir(path), osutil.PermissionDirectory); err != nil {
+				return exec.RunResult{}, err
+			}
+			if err := os.WriteFile(path, []byte(contents), osutil.PermissionFile); err != nil {
+				return exec.RunResult{}, err
+			}
+		}
+
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	return clones
+}
+
+// failUnauthenticatedClones fails the clones which don't set a credential helper, like for a private repository.
+func failUnauthenticatedClones(mockContext *mocks.MockContext) {
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return args.Cmd == "git" && slices.Contains(args.Args, "clone") &&
+			!slices.Contains(args.Args, "credential.https://github.com.helper=")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		return exec.NewRunResult(128, "", "Authentication failed"), errors.New("exit code: 128")
+	})
+}
+
+func noGhCli() *lazy.Lazy[github.GitHubCli] {
+	return lazy.NewLazy(func() (github.GitHubCli, error) {
+		return nil, errors.New("GitHub CLI is not available")
+	})
+}
+
+type mockGhCli struct {
+	github.GitHubCli
+	loggedIn bool
+}
+
+func (m *mockGhCli) GetAuthStatus(ctx context.Context, hostname string) (github.AuthStatus, error) {
+	return github.AuthStatus{LoggedIn: m.loggedIn}, nil
+}
+
+func (m *mockGhCli) BinaryPath() string {
+	return "/bin/gh"
+}
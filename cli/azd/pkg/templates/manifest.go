@@ -0,0 +1,342 @@
+package templates
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"gopkg.in/yaml.v3"
+)
+
+// ManifestFileName is the name of the optional file at the root of a template repository which declares the inputs of
+// the template and how they are applied when the template is initialized.
+const ManifestFileName = "azd-template.yaml"
+
+type ManifestParameterType string
+
+const (
+	ManifestParameterTypeString ManifestParameterType = "string"
+	ManifestParameterTypeInt    ManifestParameterType = "int"
+	ManifestParameterTypeBool   ManifestParameterType = "bool"
+)
+
+// Manifest describes the inputs of a template.
+//
+// Values of the parameters are available to Go templates as `{{ .<name> }}`. Files matching the `render` globs are
+// rendered with these values and paths matching an `exclude` rule are removed from the initialized project.
+type Manifest struct {
+	Parameters []ManifestParameter `yaml:"parameters,omitempty"`
+	// Render is a list of globs, relative to the template root, of the files rendered as Go templates.
+	Render []string `yaml:"render,omitempty"`
+	// Exclude is a list of paths removed from the template depending on the parameter values.
+	Exclude []ManifestExclusion `yaml:"exclude,omitempty"`
+	// Delimiters overrides the default `{{` and `}}` Go template action delimiters of rendered files, which is useful
+	// when they use them for another purpose, e.g. GitHub workflows.
+	Delimiters []string `yaml:"delimiters,omitempty"`
+}
+
+// ManifestParameter is an input of a template.
+type ManifestParameter struct {
+	Name string                `yaml:"name"`
+	Type ManifestParameterType `yaml:"type,omitempty"`
+	// Prompt is the message displayed when prompting for the value.
+	Prompt  string `yaml:"prompt,omitempty"`
+	Help    string `yaml:"help,omitempty"`
+	Default any    `yaml:"default,omitempty"`
+	// Allowed restricts a string parameter to a set of values, which are offered as a selection when prompting.
+	Allowed []string `yaml:"allowed,omitempty"`
+	// Pattern is a regular expression string values must match.
+	Pattern string `yaml:"pattern,omitempty"`
+}
+
+// ManifestExclusion removes paths from the template when its condition is met.
+type ManifestExclusion struct {
+	// Paths is a list of globs, relative to the template root, of the files and directories to remove.
+	Paths []string `yaml:"paths"`
+	// When is a Go template condition, e.g. `{{ not .includeWorker }}`. The paths are removed when it renders to
+	// `true`. Paths are always removed when no condition is set.
+	When string `yaml:"when,omitempty"`
+}
+
+// LoadManifest reads the template manifest at the root of a template directory. A nil manifest is returned when the
+// template doesn't have one.
+func LoadManifest(templateDir string) (*Manifest, error) {
+	manifestBytes, err := os.ReadFile(filepath.Join(templateDir, ManifestFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading template manifest: %w", err)
+	}
+
+	var manifest Manifest
+	if err := yaml.Unmarshal(manifestBytes, &manifest); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", ManifestFileName, err)
+	}
+
+	if err := manifest.validate(); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", ManifestFileName, err)
+	}
+
+	return &manifest, nil
+}
+
+func (m *Manifest) validate() error {
+	names := map[string]bool{}
+	for i := range m.Parameters {
+		param := &m.Parameters[i]
+		if param.Name == "" {
+			return fmt.Errorf("parameter %d is missing a name", i)
+		}
+		if names[param.Name] {
+			return fmt.Errorf("parameter '%s' is declared more than once", param.Name)
+		}
+		names[param.Name] = true
+
+		switch param.Type {
+		case "":
+			param.Type = ManifestParameterTypeString
+		case ManifestParameterTypeString, ManifestParameterTypeInt, ManifestParameterTypeBool:
+		default:
+			return fmt.Errorf("parameter '%s' has unsupported type '%s'", param.Name, param.Type)
+		}
+
+		if param.Pattern != "" {
+			if _, err := regexp.Compile(param.Pattern); err != nil {
+				return fmt.Errorf("parameter '%s' has an invalid pattern: %w", param.Name, err)
+			}
+		}
+
+		if param.Default != nil {
+			if _, err := param.Parse(fmt.Sprint(param.Default)); err != nil {
+				return fmt.Errorf("invalid default: %w", err)
+			}
+		}
+	}
+
+	if len(m.Delimiters) != 0 && len(m.Delimiters) != 2 {
+		return errors.New("delimiters must be a list of a left and a right delimiter")
+	}
+
+	for _, glob := range m.globs() {
+		if !doublestar.ValidatePattern(glob) {
+			return fmt.Errorf("invalid glob '%s'", glob)
+		}
+	}
+
+	return nil
+}
+
+func (m *Manifest) globs() []string {
+	globs := slices.Clone(m.Render)
+	for _, exclusion := range m.Exclude {
+		globs = append(globs, exclusion.Paths...)
+	}
+
+	return globs
+}
+
+// Parameter returns the parameter with the given name.
+func (m *Manifest) Parameter(name string) (*ManifestParameter, bool) {
+	index := slices.IndexFunc(m.Parameters, func(p ManifestParameter) bool {
+		return p.Name == name
+	})
+	if index < 0 {
+		return nil, false
+	}
+
+	return &m.Parameters[index], true
+}
+
+// ParseValues converts `key=value` assignments, e.g. from `--set` flags, to typed parameter values.
+func (m *Manifest) ParseValues(assignments map[string]string) (map[string]any, error) {
+	values := map[string]any{}
+	for name, value := range assignments {
+		param, has := m.Parameter(name)
+		if !has {
+			return nil, fmt.Errorf("the template doesn't declare a parameter named '%s'", name)
+		}
+
+		typedValue, err := param.Parse(value)
+		if err != nil {
+			return nil, err
+		}
+
+		values[name] = typedValue
+	}
+
+	return values, nil
+}
+
+// Parse converts a string to a value of the parameter type and validates it.
+func (p *ManifestParameter) Parse(value string) (any, error) {
+	switch p.Type {
+	case ManifestParameterTypeBool:
+		boolValue, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("'%s' is not a valid value for boolean parameter '%s'", value, p.Name)
+		}
+		return boolValue, nil
+	case ManifestParameterTypeInt:
+		intValue, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("'%s' is not a valid value for integer parameter '%s'", value, p.Name)
+		}
+		return intValue, nil
+	}
+
+	if len(p.Allowed) > 0 && !slices.Contains(p.Allowed, value) {
+		return nil, fmt.Errorf(
+			"'%s' is not a valid value for parameter '%s'. Allowed values are: %s",
+			value, p.Name, strings.Join(p.Allowed, ", "))
+	}
+
+	if p.Pattern != "" && !regexp.MustCompile(p.Pattern).MatchString(value) {
+		return nil, fmt.Errorf("'%s' is not a valid value for parameter '%s', it must match '%s'", value, p.Name, p.Pattern)
+	}
+
+	return value, nil
+}
+
+// Apply removes the excluded paths and renders the selected files of the template in templateDir with the given
+// parameter values. The manifest file itself is removed, since it's not part of the initialized project.
+func (m *Manifest) Apply(templateDir string, values map[string]any) error {
+	for _, param := range m.Parameters {
+		if _, has := values[param.Name]; !has {
+			return fmt.Errorf("missing value for template parameter '%s'", param.Name)
+		}
+	}
+
+	for _, exclusion := range m.Exclude {
+		excluded := true
+		if exclusion.When != "" {
+			condition, err := renderTemplate(exclusion.When, values, nil)
+			if err != nil {
+				return fmt.Errorf("evaluating exclusion condition '%s': %w", exclusion.When, err)
+			}
+			excluded = strings.TrimSpace(condition) == "true"
+		}
+
+		if excluded {
+			if err := removeMatches(templateDir, exclusion.Paths); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(m.Render) > 0 {
+		err := walkMatches(templateDir, m.Render, func(path string, d fs.DirEntry) error {
+			if d.IsDir() {
+				return nil
+			}
+
+			return m.renderFile(path, values)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := os.Remove(filepath.Join(templateDir, ManifestFileName)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("removing template manifest: %w", err)
+	}
+
+	return nil
+}
+
+// renderTemplate renders text as a Go template. Delimiters only apply to rendered files, conditions in the manifest
+// itself always use the default delimiters.
+func renderTemplate(text string, values map[string]any, delimiters []string) (string, error) {
+	tmpl := template.New("").Funcs(manifestFuncs).Option("missingkey=error")
+	if len(delimiters) == 2 {
+		tmpl = tmpl.Delims(delimiters[0], delimiters[1])
+	}
+
+	tmpl, err := tmpl.Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, values); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+func (m *Manifest) renderFile(path string, values map[string]any) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	rendered, err := renderTemplate(string(contents), values, m.Delimiters)
+	if err != nil {
+		return fmt.Errorf("rendering %s: %w", filepath.Base(path), err)
+	}
+
+	return os.WriteFile(path, []byte(rendered), info.Mode().Perm())
+}
+
+var manifestFuncs = template.FuncMap{
+	"lower":   strings.ToLower,
+	"upper":   strings.ToUpper,
+	"trim":    strings.TrimSpace,
+	"replace": strings.ReplaceAll,
+}
+
+// removeMatches removes the files and directories under root matching any of the globs.
+func removeMatches(root string, globs []string) error {
+	return walkMatches(root, globs, func(path string, d fs.DirEntry) error {
+		if err := os.RemoveAll(path); err != nil {
+			return fmt.Errorf("removing excluded path: %w", err)
+		}
+
+		if d.IsDir() {
+			return filepath.SkipDir
+		}
+
+		return nil
+	})
+}
+
+// walkMatches calls fn for each file or directory under root whose slash separated relative path matches a glob.
+func walkMatches(root string, globs []string, fn func(path string, d fs.DirEntry) error) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+
+		rel = filepath.ToSlash(rel)
+		for _, glob := range globs {
+			if match, _ := doublestar.Match(glob, rel); match {
+				return fn(path, d)
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,174 @@
+package templates
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_LoadManifest(t *testing.T) {
+	t.Run("NoManifest", func(t *testing.T) {
+		manifest, err := LoadManifest(t.TempDir())
+		require.NoError(t, err)
+		require.Nil(t, manifest)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		dir := writeManifest(t, `
+parameters:
+  - name: serviceName
+    default: api
+  - name: replicas
+    type: int
+    default: 2
+render: ["**/*.yaml"]
+`)
+		manifest, err := LoadManifest(dir)
+		require.NoError(t, err)
+		require.Len(t, manifest.Parameters, 2)
+		require.Equal(t, ManifestParameterTypeString, manifest.Parameters[0].Type)
+		require.Equal(t, ManifestParameterTypeInt, manifest.Parameters[1].Type)
+	})
+
+	invalid := []struct {
+		name      string
+		manifest  string
+		errorText string
+	}{
+		{"MissingName", "parameters:\n  - type: string\n", "missing a name"},
+		{"DuplicateName", "parameters:\n  - name: a\n  - name: a\n", "more than once"},
+		{"UnsupportedType", "parameters:\n  - name: a\n    type: list\n", "unsupported type"},
+		{"InvalidPattern", "parameters:\n  - name: a\n    pattern: '['\n", "invalid pattern"},
+		{"InvalidDefault", "parameters:\n  - name: a\n    type: bool\n    default: maybe\n", "invalid default"},
+		{"InvalidDelimiters", "delimiters: ['[[']\n", "delimiters"},
+		{"InvalidGlob", "render: ['src/[']\n", "invalid glob"},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadManifest(writeManifest(t, tt.manifest))
+			require.ErrorContains(t, err, tt.errorText)
+		})
+	}
+}
+
+func Test_Manifest_ParseValues(t *testing.T) {
+	manifest := &Manifest{
+		Parameters: []ManifestParameter{
+			{Name: "name", Type: ManifestParameterTypeString, Pattern: "^[a-z]+$"},
+			{Name: "sku", Type: ManifestParameterTypeString, Allowed: []string{"B1", "S1"}},
+			{Name: "replicas", Type: ManifestParameterTypeInt},
+			{Name: "worker", Type: ManifestParameterTypeBool},
+		},
+	}
+
+	values, err := manifest.ParseValues(map[string]string{
+		"name":     "orders",
+		"sku":      "S1",
+		"replicas": "3",
+		"worker":   "true",
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"name": "orders", "sku": "S1", "replicas": 3, "worker": true}, values)
+
+	invalid := []struct {
+		name      string
+		values    map[string]string
+		errorText string
+	}{
+		{"Undeclared", map[string]string{"location": "eastus"}, "doesn't declare a parameter"},
+		{"PatternMismatch", map[string]string{"name": "Orders"}, "must match"},
+		{"NotAllowed", map[string]string{"sku": "P1"}, "Allowed values are: B1, S1"},
+		{"NotInt", map[string]string{"replicas": "three"}, "integer parameter"},
+		{"NotBool", map[string]string{"worker": "maybe"}, "boolean parameter"},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := manifest.ParseValues(tt.values)
+			require.ErrorContains(t, err, tt.errorText)
+		})
+	}
+}
+
+func Test_Manifest_Apply(t *testing.T) {
+	manifest := &Manifest{
+		Parameters: []ManifestParameter{
+			{Name: "name", Type: ManifestParameterTypeString},
+			{Name: "worker", Type: ManifestParameterTypeBool},
+		},
+		Render: []string{"*.yaml", ".github/**/*.yml"},
+		Exclude: []ManifestExclusion{
+			{Paths: []string{"worker"}, When: "{{ not .worker }}"},
+			{Paths: []string{"**/*.tmp"}},
+		},
+		Delimiters: []string{"<%", "%>"},
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		dir := writeManifest(t, "")
+		writeFiles(t, dir, map[string]string{
+			"azure.yaml":                   "name: <% .name | lower %>\n",
+			".github/workflows/deploy.yml": "run: ${{ secrets.TOKEN }} <% .name %>\n",
+			"README.md":                    "<% .name %>\n",
+			"worker/main.py":               "print('worker')\n",
+			"src/scratch.tmp":              "",
+		})
+
+		err := manifest.Apply(dir, map[string]any{"name": "Orders", "worker": false})
+		require.NoError(t, err)
+
+		requireFileContent(t, filepath.Join(dir, "azure.yaml"), "name: orders\n")
+		requireFileContent(t, filepath.Join(dir, ".github", "workflows", "deploy.yml"),
+			"run: ${{ secrets.TOKEN }} Orders\n")
+		requireFileContent(t, filepath.Join(dir, "README.md"), "<% .name %>\n")
+		require.NoDirExists(t, filepath.Join(dir, "worker"))
+		require.NoFileExists(t, filepath.Join(dir, "src", "scratch.tmp"))
+		require.NoFileExists(t, filepath.Join(dir, ManifestFileName))
+	})
+
+	t.Run("ConditionNotMet", func(t *testing.T) {
+		dir := writeManifest(t, "")
+		writeFiles(t, dir, map[string]string{"worker/main.py": "print('worker')\n"})
+
+		err := manifest.Apply(dir, map[string]any{"name": "orders", "worker": true})
+		require.NoError(t, err)
+		require.FileExists(t, filepath.Join(dir, "worker", "main.py"))
+	})
+
+	t.Run("MissingValue", func(t *testing.T) {
+		err := manifest.Apply(t.TempDir(), map[string]any{"name": "orders"})
+		require.ErrorContains(t, err, "missing value for template parameter 'worker'")
+	})
+
+	t.Run("UnknownKey", func(t *testing.T) {
+		dir := writeManifest(t, "")
+		writeFiles(t, dir, map[string]string{"azure.yaml": "name: <% .unknown %>\n"})
+
+		err := manifest.Apply(dir, map[string]any{"name": "orders", "worker": true})
+		require.ErrorContains(t, err, "rendering azure.yaml")
+	})
+}
+
+func writeManifest(t *testing.T, contents string) string {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, ManifestFileName), []byte(contents), osutil.PermissionFile)
+	require.NoError(t, err)
+
+	return dir
+}
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	for name, contents := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), osutil.PermissionDirectory))
+		require.NoError(t, os.WriteFile(path, []byte(contents), osutil.PermissionFile))
+	}
+}
+
+func requireFileContent(t *testing.T, path string, expected string) {
+	contents, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, expected, string(contents))
+}
@@ -25,6 +25,7 @@ const (
 	SourceKindUrl        SourceKind = "url"
 	SourceKindResource   SourceKind = "resource"
 	SourceKindAwesomeAzd SourceKind = "awesome-azd"
+	SourceKindGit        SourceKind = "git"
 )
 
 type SourceConfig struct {
@@ -32,6 +33,11 @@ type SourceConfig struct {
 	Name     string     `json:"name,omitempty"`
 	Type     SourceKind `json:"type,omitempty"`
 	Location string     `json:"location,omitempty"`
+	// Ref is the branch or tag of a git source. The default branch of the repository is used when empty.
+	Ref string `json:"ref,omitempty"`
+	// Path is the path of the JSON list of templates in the repository of a git source, which defaults to
+	// templates.json.
+	Path string `json:"path,omitempty"`
 }
 
 type templateSource struct {
@@ -228,6 +228,11 @@ func (sm *sourceManager) CreateSource(ctx context.Context, config *SourceConfig)
 		source, err = NewAwesomeAzdTemplateSource(ctx, SourceAwesomeAzd.Name, SourceAwesomeAzd.Location, sm.httpClient)
 	case SourceKindResource:
 		source, err = NewJsonTemplateSource(SourceDefault.Name, string(resources.TemplatesJson))
+	case SourceKindGit:
+		var gitCloner *GitCloner
+		if err = sm.serviceLocator.Resolve(&gitCloner); err == nil {
+			source, err = NewGitTemplateSource(ctx, config, gitCloner)
+		}
 	default:
 		err = sm.serviceLocator.ResolveNamed(string(config.Type), &source)
 		if err != nil {
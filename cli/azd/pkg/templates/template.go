@@ -25,6 +25,14 @@ type Template struct {
 	// or "{repo}" for GitHub repositories under Azure-Samples (default organization).
 	RepositoryPath string `json:"repositoryPath"`
 
+	// Ref is the branch or tag of the repository the template is initialized from. The default branch of the
+	// repository is used when empty.
+	Ref string `json:"ref,omitempty"`
+
+	// Subdirectory is the slash separated path of the template in the repository, which allows a single repository
+	// to host many templates. The root of the repository is used when empty.
+	Subdirectory string `json:"subdirectory,omitempty"`
+
 	// Additional metadata about the template
 	Metadata Metadata `json:"metadata,omitempty"`
 }
@@ -48,10 +56,18 @@ func (t *Template) Display(writer io.Writer) error {
 		output.TableFlags)
 	text := [][]string{
 		{"RepositoryPath", ":", Hyperlink(t.RepositoryPath)},
+	}
+	if t.Ref != "" {
+		text = append(text, []string{"Ref", ":", t.Ref})
+	}
+	if t.Subdirectory != "" {
+		text = append(text, []string{"Subdirectory", ":", t.Subdirectory})
+	}
+	text = append(text, [][]string{
 		{"Name", ":", t.Name},
 		{"Source", ":", t.Source},
 		{"Description", ":", t.Description},
-	}
+	}...)
 
 	for _, line := range text {
 		_, err := tabs.Write([]byte(strings.Join(line, "\t") + "\n"))
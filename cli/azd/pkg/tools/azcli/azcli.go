@@ -5,11 +5,13 @@ package azcli
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"io"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/cognitiveservices/armcognitiveservices"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
 	"github.com/azure/azure-dev/cli/azd/pkg/account"
 	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
 )
@@ -28,6 +30,16 @@ type AzCli interface {
 		resourceId string,
 		apiVersion string,
 	) (AzCliResourceExtended, error)
+	// ResourceExists checks whether the resource with the given id exists.
+	ResourceExists(ctx context.Context, subscriptionId string, resourceId string, apiVersion string) (bool, error)
+	// CreateOrUpdateResource creates or updates the resource with the given id and waits for the operation to complete.
+	CreateOrUpdateResource(
+		ctx context.Context,
+		subscriptionId string,
+		resourceId string,
+		apiVersion string,
+		resource armresources.GenericResource,
+	) error
 	GetManagedHSM(
 		ctx context.Context,
 		subscriptionId string,
@@ -68,6 +80,13 @@ type AzCli interface {
 		resourceGroup string,
 		funcName string,
 	) (*AzCliFunctionAppProperties, error)
+	// Opens the live log stream of an app service or function app
+	GetAppServiceLogStream(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroup string,
+		appName string,
+	) (io.ReadCloser, error)
 
 	DeleteResourceGroup(ctx context.Context, subscriptionId string, resourceGroupName string) error
 	CreateOrUpdateResourceGroup(
@@ -88,6 +107,14 @@ type AzCli interface {
 		resourceGroupName string,
 		listOptions *ListResourceGroupResourcesOptions,
 	) ([]AzCliResource, error)
+	// GetResourceGroup returns the resource group with the given name.
+	GetResourceGroup(ctx context.Context, subscriptionId string, resourceGroupName string) (AzCliResource, error)
+	// ExportResourceGroupTemplate exports the ARM template of all the resources of a resource group.
+	ExportResourceGroupTemplate(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+	) (json.RawMessage, error)
 	// CreateOrUpdateServicePrincipal creates a service principal using a given name and returns a JSON object which
 	// may be used by tools which understand the `AZURE_CREDENTIALS` format (i.e. the `sdk-auth` format). The service
 	// principal is assigned a given role. If an existing principal exists with the given name,
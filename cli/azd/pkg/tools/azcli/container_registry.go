@@ -46,6 +46,29 @@ type ContainerRegistryService interface {
 	Credentials(ctx context.Context, subscriptionId string, loginServer string) (*DockerCredentials, error)
 	// Gets a list of container registries for the specified subscription
 	GetContainerRegistries(ctx context.Context, subscriptionId string) ([]*armcontainerregistry.Registry, error)
+	// Uploads the build context archive and builds and pushes an image with ACR Tasks, writing the build logs to logs
+	RemoteBuild(
+		ctx context.Context,
+		subscriptionId string,
+		loginServer string,
+		sourcePath string,
+		options *RemoteBuildOptions,
+		logs io.Writer,
+	) error
+}
+
+// RemoteBuildOptions are the options of an image built by ACR Tasks
+type RemoteBuildOptions struct {
+	// The path of the Dockerfile, relative to the root of the build context
+	DockerfilePath string
+	// The target build stage
+	Target string
+	// The platform of the image, ex) linux/amd64
+	Platform string
+	// The build arguments, ex) NAME=VALUE
+	BuildArgs []string
+	// The repositories and tags of the image pushed to the registry, ex) app/api:azd-deploy-1700000000
+	ImageNames []string
 }
 
 type containerRegistryService struct {
@@ -0,0 +1,247 @@
+package azcli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerregistry/armcontainerregistry"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/convert"
+)
+
+// The interval between the polls of the status and the logs of a remote build
+const defaultRemoteBuildPollInterval = 3 * time.Second
+
+var remoteBuildPollInterval = defaultRemoteBuildPollInterval
+
+// The statuses of a finished ACR run
+var finishedRunStatuses = []armcontainerregistry.RunStatus{
+	armcontainerregistry.RunStatusSucceeded,
+	armcontainerregistry.RunStatusFailed,
+	armcontainerregistry.RunStatusCanceled,
+	armcontainerregistry.RunStatusError,
+	armcontainerregistry.RunStatusTimeout,
+}
+
+// RemoteBuild uploads the build context archive at sourcePath to the registry and schedules an ACR run that builds the
+// image and pushes it to the registry. The logs of the run are streamed to the logs writer until the run finishes.
+func (crs *containerRegistryService) RemoteBuild(
+	ctx context.Context,
+	subscriptionId string,
+	loginServer string,
+	sourcePath string,
+	options *RemoteBuildOptions,
+	logs io.Writer,
+) error {
+	registryName, _, _ := strings.Cut(loginServer, ".")
+	_, resourceGroup, err := crs.findContainerRegistryByName(ctx, subscriptionId, registryName)
+	if err != nil {
+		return err
+	}
+
+	credential, err := crs.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return err
+	}
+
+	registriesClient, err := armcontainerregistry.NewRegistriesClient(subscriptionId, credential, crs.armClientOptions)
+	if err != nil {
+		return fmt.Errorf("creating registries client: %w", err)
+	}
+
+	runsClient, err := armcontainerregistry.NewRunsClient(subscriptionId, credential, crs.armClientOptions)
+	if err != nil {
+		return fmt.Errorf("creating runs client: %w", err)
+	}
+
+	upload, err := registriesClient.GetBuildSourceUploadURL(ctx, resourceGroup, registryName, nil)
+	if err != nil {
+		return fmt.Errorf("getting build source upload url: %w", err)
+	}
+
+	pipeline := runtime.NewPipeline("azd-acr", internal.Version, runtime.PipelineOptions{}, crs.coreClientOptions)
+
+	log.Printf("uploading build context to container registry '%s'\n", registryName)
+	if err := uploadBuildSource(ctx, pipeline, *upload.UploadURL, sourcePath); err != nil {
+		return fmt.Errorf("uploading build context: %w", err)
+	}
+
+	request, err := newDockerBuildRequest(*upload.RelativePath, options)
+	if err != nil {
+		return err
+	}
+
+	poller, err := registriesClient.BeginScheduleRun(ctx, resourceGroup, registryName, request, nil)
+	if err != nil {
+		return fmt.Errorf("scheduling build: %w", err)
+	}
+
+	scheduled, err := poller.PollUntilDone(ctx, &runtime.PollUntilDoneOptions{Frequency: remoteBuildPollInterval})
+	if err != nil {
+		return fmt.Errorf("scheduling build: %w", err)
+	}
+
+	runId := *scheduled.Properties.RunID
+	log.Printf("scheduled build run '%s' in container registry '%s'\n", runId, registryName)
+
+	logLink, err := runsClient.GetLogSasURL(ctx, resourceGroup, registryName, runId, nil)
+	if err != nil {
+		return fmt.Errorf("getting logs of build run '%s': %w", runId, err)
+	}
+
+	var logOffset int64
+	for {
+		run, err := runsClient.Get(ctx, resourceGroup, registryName, runId, nil)
+		if err != nil {
+			return fmt.Errorf("getting status of build run '%s': %w", runId, err)
+		}
+
+		// The logs are copied after getting the status so that the logs of a finished run are complete
+		logOffset, err = copyRunLogs(ctx, pipeline, *logLink.LogLink, logOffset, logs)
+		if err != nil {
+			return fmt.Errorf("getting logs of build run '%s': %w", runId, err)
+		}
+
+		status := *run.Properties.Status
+		if slices.Contains(finishedRunStatuses, status) {
+			if status != armcontainerregistry.RunStatusSucceeded {
+				return fmt.Errorf("build run '%s' finished with status '%s'", runId, status)
+			}
+
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(remoteBuildPollInterval):
+		}
+	}
+}
+
+// newDockerBuildRequest returns the request of an ACR run that builds an image from the uploaded build context
+func newDockerBuildRequest(
+	sourceLocation string,
+	options *RemoteBuildOptions,
+) (*armcontainerregistry.DockerBuildRequest, error) {
+	osName, architecture, _ := strings.Cut(options.Platform, "/")
+	architecture, variant, _ := strings.Cut(architecture, "/")
+	if osName == "" || architecture == "" {
+		return nil, fmt.Errorf("invalid platform '%s', expected a platform like 'linux/amd64'", options.Platform)
+	}
+
+	platform := &armcontainerregistry.PlatformProperties{
+		// ACR expects the name of the operating system to be capitalized, ex) Linux
+		OS:           convert.RefOf(armcontainerregistry.OS(strings.ToUpper(osName[:1]) + osName[1:])),
+		Architecture: convert.RefOf(armcontainerregistry.Architecture(architecture)),
+	}
+	if variant != "" {
+		platform.Variant = convert.RefOf(armcontainerregistry.Variant(variant))
+	}
+
+	arguments := []*armcontainerregistry.Argument{}
+	for _, arg := range options.BuildArgs {
+		name, value, has := strings.Cut(arg, "=")
+		// Like docker, build arguments without a value are read from the environment. These are typically secrets
+		// which are kept out of azure.yaml, so ACR is asked to hide their values from the logs of the run.
+		if !has {
+			value = os.Getenv(name)
+		}
+
+		arguments = append(arguments, &armcontainerregistry.Argument{
+			Name:     convert.RefOf(name),
+			Value:    convert.RefOf(value),
+			IsSecret: convert.RefOf(!has),
+		})
+	}
+
+	imageNames := []*string{}
+	for _, imageName := range options.ImageNames {
+		imageNames = append(imageNames, convert.RefOf(imageName))
+	}
+
+	request := &armcontainerregistry.DockerBuildRequest{
+		SourceLocation: convert.RefOf(sourceLocation),
+		DockerFilePath: convert.RefOf(options.DockerfilePath),
+		Platform:       platform,
+		Arguments:      arguments,
+		ImageNames:     imageNames,
+		IsPushEnabled:  convert.RefOf(true),
+	}
+	if options.Target != "" {
+		request.Target = convert.RefOf(options.Target)
+	}
+
+	return request, nil
+}
+
+// uploadBuildSource uploads the build context archive to the blob at the SAS url returned by ACR
+func uploadBuildSource(ctx context.Context, pipeline runtime.Pipeline, uploadUrl string, sourcePath string) error {
+	file, err := os.Open(sourcePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	req, err := runtime.NewRequest(ctx, http.MethodPut, uploadUrl)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	req.Raw().Header.Set("x-ms-blob-type", "BlockBlob")
+	if err := req.SetBody(file, "application/octet-stream"); err != nil {
+		return err
+	}
+
+	response, err := pipeline.Do(req)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if !runtime.HasStatusCode(response, http.StatusCreated) {
+		return runtime.NewResponseError(response)
+	}
+
+	return nil
+}
+
+// copyRunLogs writes the logs of a run that were written after offset and returns the offset of the end of the logs
+func copyRunLogs(
+	ctx context.Context,
+	pipeline runtime.Pipeline,
+	logUrl string,
+	offset int64,
+	logs io.Writer,
+) (int64, error) {
+	req, err := runtime.NewRequest(ctx, http.MethodGet, logUrl)
+	if err != nil {
+		return offset, fmt.Errorf("creating request: %w", err)
+	}
+
+	req.Raw().Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	response, err := pipeline.Do(req)
+	if err != nil {
+		return offset, err
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusOK, http.StatusPartialContent:
+		written, err := io.Copy(logs, response.Body)
+		return offset + written, err
+	// The logs aren't created yet, or there are no new logs
+	case http.StatusNotFound, http.StatusRequestedRangeNotSatisfiable:
+		return offset, nil
+	default:
+		return offset, runtime.NewResponseError(response)
+	}
+}
@@ -0,0 +1,246 @@
+package azcli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerregistry/armcontainerregistry"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockaccount"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ContainerRegistryService_RemoteBuild(t *testing.T) {
+	remoteBuildPollInterval = 0
+	t.Cleanup(func() {
+		remoteBuildPollInterval = defaultRemoteBuildPollInterval
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		buildMocks := setupRemoteBuildMocks(mockContext, armcontainerregistry.RunStatusSucceeded)
+
+		sourcePath := filepath.Join(t.TempDir(), "source.tar.gz")
+		require.NoError(t, os.WriteFile(sourcePath, []byte("SOURCE"), 0600))
+
+		logs := &bytes.Buffer{}
+		err := newContainerRegistryServiceFromMockContext(mockContext).RemoteBuild(
+			*mockContext.Context,
+			"SUBSCRIPTION_ID",
+			"contoso.azurecr.io",
+			sourcePath,
+			&RemoteBuildOptions{
+				DockerfilePath: "src/Dockerfile",
+				Platform:       "linux/arm64/v8",
+				BuildArgs:      []string{"VERSION=1.0"},
+				ImageNames:     []string{"app/api:azd-deploy-0"},
+			},
+			logs,
+		)
+		require.NoError(t, err)
+
+		require.Equal(t, "SOURCE", buildMocks.uploaded)
+		require.Equal(t, "BlockBlob", buildMocks.uploadBlobType)
+
+		request := buildMocks.request
+		require.Equal(t, "source/context.tar.gz", *request.SourceLocation)
+		require.Equal(t, "src/Dockerfile", *request.DockerFilePath)
+		require.Equal(t, armcontainerregistry.OSLinux, *request.Platform.OS)
+		require.Equal(t, armcontainerregistry.ArchitectureArm64, *request.Platform.Architecture)
+		require.Equal(t, armcontainerregistry.VariantV8, *request.Platform.Variant)
+		require.Equal(t, "VERSION", *request.Arguments[0].Name)
+		require.Equal(t, "1.0", *request.Arguments[0].Value)
+		require.Equal(t, []*string{to.Ptr("app/api:azd-deploy-0")}, request.ImageNames)
+		require.True(t, *request.IsPushEnabled)
+		require.Nil(t, request.Target)
+
+		// The logs are streamed from the offset of the previous poll
+		require.Equal(t, "Step 1/2\nStep 2/2\n", logs.String())
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		setupRemoteBuildMocks(mockContext, armcontainerregistry.RunStatusFailed)
+
+		sourcePath := filepath.Join(t.TempDir(), "source.tar.gz")
+		require.NoError(t, os.WriteFile(sourcePath, []byte("SOURCE"), 0600))
+
+		err := newContainerRegistryServiceFromMockContext(mockContext).RemoteBuild(
+			*mockContext.Context,
+			"SUBSCRIPTION_ID",
+			"contoso.azurecr.io",
+			sourcePath,
+			&RemoteBuildOptions{
+				DockerfilePath: "Dockerfile",
+				Platform:       "linux/amd64",
+				ImageNames:     []string{"app/api:azd-deploy-0"},
+			},
+			io.Discard,
+		)
+		require.ErrorContains(t, err, "build run 'ca1' finished with status 'Failed'")
+	})
+}
+
+func Test_NewDockerBuildRequest_InvalidPlatform(t *testing.T) {
+	_, err := newDockerBuildRequest("source/context.tar.gz", &RemoteBuildOptions{Platform: "linux"})
+	require.ErrorContains(t, err, "invalid platform 'linux'")
+}
+
+func Test_NewDockerBuildRequest_BuildArgs(t *testing.T) {
+	t.Setenv("NPM_TOKEN", "SECRET")
+
+	request, err := newDockerBuildRequest("source/context.tar.gz", &RemoteBuildOptions{
+		Platform:  "linux/amd64",
+		BuildArgs: []string{"VERSION=1.0", "NPM_TOKEN"},
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, []*armcontainerregistry.Argument{
+		{Name: to.Ptr("VERSION"), Value: to.Ptr("1.0"), IsSecret: to.Ptr(false)},
+		// build arguments read from the environment are hidden from the logs of the run
+		{Name: to.Ptr("NPM_TOKEN"), Value: to.Ptr("SECRET"), IsSecret: to.Ptr(true)},
+	}, request.Arguments)
+}
+
+type remoteBuildMocks struct {
+	uploaded       string
+	uploadBlobType string
+	request        *armcontainerregistry.DockerBuildRequest
+}
+
+// setupRemoteBuildMocks mocks the registry APIs of a run that is running on the first poll and finishes with the final
+// status on the second poll, writing a line of logs on each poll
+func setupRemoteBuildMocks(
+	mockContext *mocks.MockContext,
+	finalStatus armcontainerregistry.RunStatus,
+) *remoteBuildMocks {
+	buildMocks := &remoteBuildMocks{}
+	registryPath := "/resourceGroups/RESOURCE_GROUP/providers/Microsoft.ContainerRegistry/registries/contoso"
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet &&
+			strings.HasSuffix(request.URL.Path, "/providers/Microsoft.ContainerRegistry/registries")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armcontainerregistry.RegistryListResult{
+			Value: []*armcontainerregistry.Registry{
+				{
+					Name: to.Ptr("contoso"),
+					ID:   to.Ptr("/subscriptions/SUBSCRIPTION_ID" + registryPath),
+				},
+			},
+		})
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPost && strings.HasSuffix(request.URL.Path, "/listBuildSourceUploadUrl")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armcontainerregistry.SourceUploadDefinition{
+			UploadURL:    to.Ptr("https://upload.blob.core.windows.net/source/context.tar.gz?sig=SAS"),
+			RelativePath: to.Ptr("source/context.tar.gz"),
+		})
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPut && request.URL.Host == "upload.blob.core.windows.net"
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		body, err := io.ReadAll(request.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		buildMocks.uploaded = string(body)
+		buildMocks.uploadBlobType = request.Header.Get("x-ms-blob-type")
+		return mocks.CreateEmptyHttpResponse(request, http.StatusCreated)
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPost && strings.HasSuffix(request.URL.Path, registryPath+"/scheduleRun")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		buildMocks.request = &armcontainerregistry.DockerBuildRequest{}
+		body, err := io.ReadAll(request.Body)
+		if err != nil {
+			return nil, err
+		}
+		if err := buildMocks.request.UnmarshalJSON(body); err != nil {
+			return nil, err
+		}
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armcontainerregistry.Run{
+			Properties: &armcontainerregistry.RunProperties{
+				RunID:  to.Ptr("ca1"),
+				Status: to.Ptr(armcontainerregistry.RunStatusQueued),
+			},
+		})
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPost && strings.HasSuffix(request.URL.Path, registryPath+"/runs/ca1/listLogSasUrl")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armcontainerregistry.RunGetLogResult{
+			LogLink: to.Ptr("https://logs.blob.core.windows.net/logs/ca1/rawtext.log?sig=SAS"),
+		})
+	})
+
+	polls := 0
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, registryPath+"/runs/ca1")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		polls++
+
+		status := armcontainerregistry.RunStatusRunning
+		if polls > 1 {
+			status = finalStatus
+		}
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armcontainerregistry.Run{
+			Properties: &armcontainerregistry.RunProperties{
+				RunID:  to.Ptr("ca1"),
+				Status: to.Ptr(status),
+			},
+		})
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && request.URL.Host == "logs.blob.core.windows.net"
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		logs := strings.Repeat("Step 1/2\n", min(polls, 1)) + strings.Repeat("Step 2/2\n", max(polls-1, 0))
+
+		var offset int
+		if _, err := fmt.Sscanf(request.Header.Get("Range"), "bytes=%d-", &offset); err != nil {
+			return nil, err
+		}
+
+		if offset >= len(logs) {
+			return mocks.CreateEmptyHttpResponse(request, http.StatusRequestedRangeNotSatisfiable)
+		}
+
+		return &http.Response{
+			Request:    request,
+			StatusCode: http.StatusPartialContent,
+			Header:     http.Header{},
+			Body:       io.NopCloser(strings.NewReader(logs[offset:])),
+		}, nil
+	})
+
+	return buildMocks
+}
+
+func newContainerRegistryServiceFromMockContext(mockContext *mocks.MockContext) ContainerRegistryService {
+	return NewContainerRegistryService(
+		mockaccount.SubscriptionCredentialProviderFunc(func(_ context.Context, _ string) (azcore.TokenCredential, error) {
+			return mockContext.Credentials, nil
+		}),
+		nil,
+		mockContext.ArmClientOptions,
+		mockContext.CoreClientOptions,
+	)
+}
@@ -0,0 +1,118 @@
+package azcli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/msi/armmsi"
+	"github.com/azure/azure-dev/cli/azd/pkg/account"
+)
+
+// The audience of the tokens exchanged for the federated credentials of workload identities
+const federatedCredentialAudience = "api://AzureADTokenExchange"
+
+// ManagedIdentityService provides actions on top of user-assigned managed identities
+type ManagedIdentityService interface {
+	// Gets the user-assigned managed identity by name
+	Get(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		identityName string,
+	) (*armmsi.Identity, error)
+	// Creates or updates a federated credential that lets the specified subject of the issuer authenticate as the
+	// user-assigned managed identity
+	CreateOrUpdateFederatedCredential(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		identityName string,
+		credentialName string,
+		issuer string,
+		subject string,
+	) error
+}
+
+type managedIdentityService struct {
+	credentialProvider account.SubscriptionCredentialProvider
+	armClientOptions   *arm.ClientOptions
+}
+
+// Creates a new instance of the ManagedIdentityService
+func NewManagedIdentityService(
+	credentialProvider account.SubscriptionCredentialProvider,
+	armClientOptions *arm.ClientOptions,
+) ManagedIdentityService {
+	return &managedIdentityService{
+		credentialProvider: credentialProvider,
+		armClientOptions:   armClientOptions,
+	}
+}
+
+// Gets the user-assigned managed identity by name
+func (mis *managedIdentityService) Get(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	identityName string,
+) (*armmsi.Identity, error) {
+	credential, err := mis.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := armmsi.NewUserAssignedIdentitiesClient(subscriptionId, credential, mis.armClientOptions)
+	if err != nil {
+		return nil, fmt.Errorf("creating user assigned identities client, %w", err)
+	}
+
+	identity, err := client.Get(ctx, resourceGroupName, identityName, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &identity.Identity, nil
+}
+
+// Creates or updates a federated credential of the user-assigned managed identity
+func (mis *managedIdentityService) CreateOrUpdateFederatedCredential(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	identityName string,
+	credentialName string,
+	issuer string,
+	subject string,
+) error {
+	credential, err := mis.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return err
+	}
+
+	client, err := armmsi.NewFederatedIdentityCredentialsClient(subscriptionId, credential, mis.armClientOptions)
+	if err != nil {
+		return fmt.Errorf("creating federated identity credentials client, %w", err)
+	}
+
+	_, err = client.CreateOrUpdate(
+		ctx,
+		resourceGroupName,
+		identityName,
+		credentialName,
+		armmsi.FederatedIdentityCredential{
+			Properties: &armmsi.FederatedIdentityCredentialProperties{
+				Audiences: []*string{to.Ptr(federatedCredentialAudience)},
+				Issuer:    to.Ptr(issuer),
+				Subject:   to.Ptr(subject),
+			},
+		},
+		nil,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
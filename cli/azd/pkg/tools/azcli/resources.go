@@ -2,8 +2,11 @@ package azcli
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"log"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
 )
 
@@ -30,6 +33,46 @@ func (cli *azCli) GetResource(
 	}, nil
 }
 
+func (cli *azCli) ResourceExists(
+	ctx context.Context, subscriptionId string, resourceId string, apiVersion string) (bool, error) {
+	client, err := cli.createResourcesClient(ctx, subscriptionId)
+	if err != nil {
+		return false, err
+	}
+
+	res, err := client.CheckExistenceByID(ctx, resourceId, apiVersion, nil)
+	if err != nil {
+		return false, fmt.Errorf("checking resource existence: %w", err)
+	}
+
+	return res.Success, nil
+}
+
+func (cli *azCli) CreateOrUpdateResource(
+	ctx context.Context,
+	subscriptionId string,
+	resourceId string,
+	apiVersion string,
+	resource armresources.GenericResource,
+) error {
+	client, err := cli.createResourcesClient(ctx, subscriptionId)
+	if err != nil {
+		return err
+	}
+
+	poller, err := client.BeginCreateOrUpdateByID(ctx, resourceId, apiVersion, resource, nil)
+	if err != nil {
+		return fmt.Errorf("beginning resource creation: %w", err)
+	}
+
+	_, err = poller.PollUntilDone(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("creating resource: %w", err)
+	}
+
+	return nil
+}
+
 func (cli *azCli) ListResourceGroupResources(
 	ctx context.Context,
 	subscriptionId string,
@@ -117,6 +160,65 @@ func (cli *azCli) ListResourceGroup(
 	return groups, nil
 }
 
+func (cli *azCli) GetResourceGroup(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+) (AzCliResource, error) {
+	client, err := cli.createResourceGroupClient(ctx, subscriptionId)
+	if err != nil {
+		return AzCliResource{}, err
+	}
+
+	res, err := client.Get(ctx, resourceGroupName, nil)
+	if err != nil {
+		return AzCliResource{}, fmt.Errorf("getting resource group: %w", err)
+	}
+
+	return AzCliResource{
+		Id:       *res.ID,
+		Name:     *res.Name,
+		Type:     *res.Type,
+		Location: *res.Location,
+	}, nil
+}
+
+func (cli *azCli) ExportResourceGroupTemplate(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+) (json.RawMessage, error) {
+	client, err := cli.createResourceGroupClient(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	poller, err := client.BeginExportTemplate(ctx, resourceGroupName, armresources.ExportTemplateRequest{
+		Resources: []*string{to.Ptr("*")},
+		Options:   to.Ptr("IncludeParameterDefaultValue"),
+	}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("beginning template export: %w", err)
+	}
+
+	res, err := poller.PollUntilDone(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("exporting template: %w", err)
+	}
+
+	if res.Error != nil && res.Error.Message != nil {
+		// the template is still exported when some of the resources can't be, the error lists those resources
+		log.Printf("exporting template of resource group %s: %s", resourceGroupName, *res.Error.Message)
+	}
+
+	template, err := json.Marshal(res.Template)
+	if err != nil {
+		return nil, fmt.Errorf("marshalling template: %w", err)
+	}
+
+	return template, nil
+}
+
 func (cli *azCli) CreateOrUpdateResourceGroup(
 	ctx context.Context,
 	subscriptionId string,
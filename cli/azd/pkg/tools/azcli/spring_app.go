@@ -4,14 +4,20 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"net/http"
 	"net/url"
 	"os"
+	"strconv"
+	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appplatform/armappplatform/v2"
 	"github.com/Azure/azure-storage-file-go/azfile"
 	"github.com/azure/azure-dev/cli/azd/pkg/account"
+	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
 )
 
 // SpringService provides artifacts upload/deploy and query to Azure Spring Apps (ASA)
@@ -24,7 +30,7 @@ type SpringService interface {
 		instanceName string,
 		appName string,
 	) (*SpringAppProperties, error)
-	// Deploy jar artifact to ASA app deployment
+	// Deploy jar artifact to ASA app deployment, creating the deployment when it doesn't exist
 	DeploySpringAppArtifact(
 		ctx context.Context,
 		subscriptionId string,
@@ -33,6 +39,28 @@ type SpringService interface {
 		appName string,
 		relativePath string,
 		deploymentName string,
+		options *SpringDeploymentOptions,
+	) (*string, error)
+	// Deploy the result of a build of the Enterprise tier build service to ASA app deployment, creating the deployment
+	// when it doesn't exist
+	DeploySpringAppBuildResult(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroup string,
+		instanceName string,
+		appName string,
+		buildResultId string,
+		deploymentName string,
+		options *SpringDeploymentOptions,
+	) (*string, error)
+	// Set the active deployment of ASA app, which receives the production traffic
+	SetSpringAppActiveDeployment(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroup string,
+		instanceName string,
+		appName string,
+		deploymentName string,
 	) (*string, error)
 	// Upload jar artifact to ASA app Storage File
 	UploadSpringArtifact(
@@ -43,6 +71,25 @@ type SpringService interface {
 		appName string,
 		artifactPath string,
 	) (*string, error)
+	// Upload source code archive to the build service of an Enterprise tier ASA instance
+	UploadSpringBuildSource(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroup string,
+		instanceName string,
+		sourcePath string,
+	) (*string, error)
+	// Build the uploaded source code with the build service and return the id of the build result
+	BuildSpringAppSource(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroup string,
+		instanceName string,
+		appName string,
+		deploymentName string,
+		relativePath string,
+		options *SpringBuildOptions,
+	) (*string, error)
 	// Get Spring app deployment
 	GetSpringAppDeployment(
 		ctx context.Context,
@@ -52,20 +99,59 @@ type SpringService interface {
 		appName string,
 		deploymentName string,
 	) (*string, error)
+	// Get the name of the active deployment of Spring app, nil when no deployment is active
+	GetSpringAppActiveDeployment(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		instanceName string,
+		appName string,
+	) (*string, error)
+	// Open the log streams of the instances of a Spring app deployment
+	OpenSpringAppLogStreams(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		instanceName string,
+		appName string,
+		deploymentName string,
+		options *SpringLogStreamOptions,
+	) ([]io.ReadCloser, error)
+}
+
+// The options for streaming the logs of a Spring app
+type SpringLogStreamOptions struct {
+	// Keeps the streams open to receive new logs
+	Follow bool
+	// Only returns logs newer than the duration
+	Since time.Duration
+}
+
+// The options of a Spring app deployment
+type SpringDeploymentOptions struct {
+	// The options of the JVM, ex) -Xms1024m -Xmx2048m
+	JvmOptions string
+	// The Java runtime version of a jar deployment, ex) Java_17
+	RuntimeVersion string
+	// The number of instances of the deployment, the current instance count is kept when 0
+	InstanceCount int
 }
 
 type springService struct {
 	credentialProvider account.SubscriptionCredentialProvider
+	httpClient         httputil.HttpClient
 	armClientOptions   *arm.ClientOptions
 }
 
 // Creates a new instance of the NewSpringService
 func NewSpringService(
 	credentialProvider account.SubscriptionCredentialProvider,
+	httpClient httputil.HttpClient,
 	armClientOptions *arm.ClientOptions,
 ) SpringService {
 	return &springService{
 		credentialProvider: credentialProvider,
+		httpClient:         httpClient,
 		armClientOptions:   armClientOptions,
 	}
 }
@@ -125,79 +211,270 @@ func (ss *springService) UploadSpringArtifact(
 		return nil, fmt.Errorf("failed to get resource upload URL: %w", err)
 	}
 
-	url, err := url.Parse(*storageInfo.UploadURL)
+	if err := uploadToFileShare(ctx, storageInfo.UploadURL, file); err != nil {
+		return nil, fmt.Errorf("failed to upload artifact %s : %w", artifactPath, err)
+	}
+
+	return storageInfo.RelativePath, nil
+}
+
+// uploadToFileShare uploads the file to the Storage File at the upload URL returned by Azure Spring Apps
+func uploadToFileShare(ctx context.Context, uploadUrl *string, file *os.File) error {
+	url, err := url.Parse(*uploadUrl)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse storage upload url %s : %w", *storageInfo.UploadURL, err)
+		return fmt.Errorf("failed to parse storage upload url %s : %w", *uploadUrl, err)
 	}
 
 	// Pass NewAnonymousCredential here, since the URL returned by Azure Spring Apps already contains a SAS token
 	fileURL := azfile.NewFileURL(*url, azfile.NewPipeline(azfile.NewAnonymousCredential(), azfile.PipelineOptions{}))
-	err = azfile.UploadFileToAzureFile(ctx, file, fileURL,
+	return azfile.UploadFileToAzureFile(ctx, file, fileURL,
 		azfile.UploadToAzureFileOptions{
 			Metadata: azfile.Metadata{
 				"createdby": "AZD",
 			},
 		})
+}
 
-	if err != nil {
-		return nil, fmt.Errorf("failed to upload artifact %s : %w", artifactPath, err)
+func (ss *springService) DeploySpringAppArtifact(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	instanceName string,
+	appName string,
+	relativePath string,
+	deploymentName string,
+	options *SpringDeploymentOptions,
+) (*string, error) {
+	if options == nil {
+		options = &SpringDeploymentOptions{}
 	}
 
-	return storageInfo.RelativePath, nil
+	source := &armappplatform.JarUploadedUserSourceInfo{
+		Type:         to.Ptr("Jar"),
+		RelativePath: to.Ptr(relativePath),
+	}
+	if options.JvmOptions != "" {
+		source.JvmOptions = to.Ptr(options.JvmOptions)
+	}
+	if options.RuntimeVersion != "" {
+		source.RuntimeVersion = to.Ptr(options.RuntimeVersion)
+	}
+
+	return ss.createOrUpdateDeployment(
+		ctx, subscriptionId, resourceGroup, instanceName, appName, deploymentName, source, nil, options)
 }
 
-func (ss *springService) DeploySpringAppArtifact(
+func (ss *springService) DeploySpringAppBuildResult(
 	ctx context.Context,
 	subscriptionId string,
 	resourceGroup string,
 	instanceName string,
 	appName string,
-	relativePath string,
+	buildResultId string,
 	deploymentName string,
+	options *SpringDeploymentOptions,
 ) (*string, error) {
-	deploymentClient, err := ss.createSpringAppDeploymentClient(ctx, subscriptionId)
-	if err != nil {
-		return nil, err
+	if options == nil {
+		options = &SpringDeploymentOptions{}
+	}
+
+	source := &armappplatform.BuildResultUserSourceInfo{
+		Type:          to.Ptr("BuildResult"),
+		BuildResultID: to.Ptr(buildResultId),
 	}
 
+	// The Enterprise tier doesn't support the JVM options of the source, they're passed to the JVM of the
+	// buildpacks by the JAVA_OPTS environment variable
+	environmentVariables := map[string]*string{}
+	if options.JvmOptions != "" {
+		environmentVariables["JAVA_OPTS"] = to.Ptr(options.JvmOptions)
+	}
+
+	return ss.createOrUpdateDeployment(
+		ctx, subscriptionId, resourceGroup, instanceName, appName, deploymentName, source, environmentVariables, options)
+}
+
+func (ss *springService) SetSpringAppActiveDeployment(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	instanceName string,
+	appName string,
+	deploymentName string,
+) (*string, error) {
 	springClient, err := ss.createSpringAppClient(ctx, subscriptionId)
 	if err != nil {
 		return nil, err
 	}
 
-	_, err = ss.createOrUpdateDeployment(deploymentClient, ctx, resourceGroup, instanceName, appName,
-		deploymentName, relativePath)
+	return ss.activeDeployment(springClient, ctx, resourceGroup, instanceName, appName, deploymentName)
+}
+
+func (ss *springService) GetSpringAppDeployment(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	instanceName string,
+	appName string,
+	deploymentName string,
+) (*string, error) {
+	client, err := ss.createSpringAppDeploymentClient(ctx, subscriptionId)
 	if err != nil {
 		return nil, err
 	}
-	resName, err := ss.activeDeployment(springClient, ctx, resourceGroup, instanceName, appName, deploymentName)
+
+	resp, err := client.Get(ctx, resourceGroupName, instanceName, appName, deploymentName, nil)
+
 	if err != nil {
 		return nil, err
 	}
 
-	return resName, nil
+	return resp.Name, nil
 }
 
-func (ss *springService) GetSpringAppDeployment(
+func (ss *springService) GetSpringAppActiveDeployment(
 	ctx context.Context,
 	subscriptionId string,
 	resourceGroupName string,
 	instanceName string,
 	appName string,
-	deploymentName string,
 ) (*string, error) {
 	client, err := ss.createSpringAppDeploymentClient(ctx, subscriptionId)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := client.Get(ctx, resourceGroupName, instanceName, appName, deploymentName, nil)
+	pager := client.NewListPager(resourceGroupName, instanceName, appName, nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed listing spring app deployments: %w", err)
+		}
+
+		for _, deployment := range page.Value {
+			if deployment.Properties != nil && deployment.Properties.Active != nil && *deployment.Properties.Active {
+				return deployment.Name, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// Opens the log streams of every instance of the Spring app deployment. The logs are streamed from the log stream
+// endpoint of the Azure Spring Apps instance, authenticated with its primary test key.
+// The caller is responsible for closing the streams.
+func (ss *springService) OpenSpringAppLogStreams(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	instanceName string,
+	appName string,
+	deploymentName string,
+	options *SpringLogStreamOptions,
+) ([]io.ReadCloser, error) {
+	if options == nil {
+		options = &SpringLogStreamOptions{}
+	}
 
+	credential, err := ss.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
 	if err != nil {
 		return nil, err
 	}
 
-	return resp.Name, nil
+	servicesClient, err := armappplatform.NewServicesClient(subscriptionId, credential, ss.armClientOptions)
+	if err != nil {
+		return nil, fmt.Errorf("creating SpringService client: %w", err)
+	}
+
+	service, err := servicesClient.Get(ctx, resourceGroupName, instanceName, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed retrieving spring service properties: %w", err)
+	}
+
+	if service.Properties == nil || service.Properties.Fqdn == nil {
+		return nil, fmt.Errorf("spring service '%s' does not have a fully qualified domain name", instanceName)
+	}
+
+	testKeys, err := servicesClient.ListTestKeys(ctx, resourceGroupName, instanceName, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed retrieving spring service test keys: %w", err)
+	}
+
+	if testKeys.PrimaryKey == nil || *testKeys.PrimaryKey == "" {
+		return nil, fmt.Errorf(
+			"the test endpoint of spring service '%s' must be enabled to stream logs", instanceName)
+	}
+
+	deploymentClient, err := ss.createSpringAppDeploymentClient(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	deployment, err := deploymentClient.Get(ctx, resourceGroupName, instanceName, appName, deploymentName, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed retrieving spring app deployment: %w", err)
+	}
+
+	if deployment.Properties == nil {
+		return nil, fmt.Errorf("spring app deployment '%s' is missing its properties", deploymentName)
+	}
+
+	streams := []io.ReadCloser{}
+	for _, instance := range deployment.Properties.Instances {
+		endpoint := fmt.Sprintf(
+			"https://%s/api/logstream/apps/%s/instances/%s",
+			*service.Properties.Fqdn,
+			appName,
+			*instance.Name,
+		)
+
+		stream, err := ss.openLogStream(ctx, endpoint, *testKeys.PrimaryKey, options)
+		if err != nil {
+			for _, opened := range streams {
+				opened.Close()
+			}
+
+			return nil, err
+		}
+
+		streams = append(streams, stream)
+	}
+
+	return streams, nil
+}
+
+func (ss *springService) openLogStream(
+	ctx context.Context,
+	endpoint string,
+	key string,
+	options *SpringLogStreamOptions,
+) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating log stream request: %w", err)
+	}
+
+	query := req.URL.Query()
+	query.Set("follow", strconv.FormatBool(options.Follow))
+	if options.Since > 0 {
+		query.Set("sinceSeconds", strconv.Itoa(int(options.Since.Seconds())))
+	}
+
+	req.URL.RawQuery = query.Encode()
+	req.SetBasicAuth("primary", key)
+
+	response, err := ss.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("opening log stream: %w", err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		defer response.Body.Close()
+		return nil, fmt.Errorf("opening log stream: unexpected status code %d", response.StatusCode)
+	}
+
+	return response.Body, nil
 }
 
 func (ss *springService) createSpringAppClient(
@@ -234,24 +511,74 @@ func (ss *springService) createSpringAppDeploymentClient(
 	return client, nil
 }
 
+// createOrUpdateDeployment deploys the source to the deployment. The settings and the SKU of an existing deployment
+// are kept, so that a deployment only replaces the deployed source.
 func (ss *springService) createOrUpdateDeployment(
-	deploymentClient *armappplatform.DeploymentsClient,
 	ctx context.Context,
+	subscriptionId string,
 	resourceGroup string,
 	instanceName string,
 	appName string,
 	deploymentName string,
-	relativePath string,
+	source armappplatform.UserSourceInfoClassification,
+	environmentVariables map[string]*string,
+	options *SpringDeploymentOptions,
 ) (*string, error) {
-	poller, err := deploymentClient.BeginCreateOrUpdate(ctx, resourceGroup, instanceName, appName, deploymentName,
-		armappplatform.DeploymentResource{
-			Properties: &armappplatform.DeploymentResourceProperties{
-				Source: &armappplatform.JarUploadedUserSourceInfo{
-					Type:         to.Ptr("Jar"),
-					RelativePath: to.Ptr(relativePath),
-				},
-			},
-		}, nil)
+	deploymentClient, err := ss.createSpringAppDeploymentClient(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	deployment := armappplatform.DeploymentResource{
+		Properties: &armappplatform.DeploymentResourceProperties{
+			DeploymentSettings: &armappplatform.DeploymentSettings{},
+		},
+	}
+
+	existing, err := deploymentClient.Get(ctx, resourceGroup, instanceName, appName, deploymentName, nil)
+	if err != nil {
+		var responseError *azcore.ResponseError
+		// If the response is a 404 then the deployment is created, ex) a new staging deployment
+		if !errors.As(err, &responseError) || responseError.StatusCode != http.StatusNotFound {
+			return nil, fmt.Errorf("failed retrieving spring app deployment: %w", err)
+		}
+	} else {
+		deployment.SKU = existing.SKU
+		if existing.Properties != nil && existing.Properties.DeploymentSettings != nil {
+			deployment.Properties.DeploymentSettings = existing.Properties.DeploymentSettings
+		}
+	}
+
+	deployment.Properties.Source = source
+
+	if len(environmentVariables) > 0 {
+		if deployment.Properties.DeploymentSettings.EnvironmentVariables == nil {
+			deployment.Properties.DeploymentSettings.EnvironmentVariables = map[string]*string{}
+		}
+		for name, value := range environmentVariables {
+			deployment.Properties.DeploymentSettings.EnvironmentVariables[name] = value
+		}
+	}
+
+	if options.InstanceCount > 0 {
+		// A new deployment has the SKU of the Spring service
+		if deployment.SKU == nil {
+			sku, err := ss.getSpringServiceSku(ctx, subscriptionId, resourceGroup, instanceName)
+			if err != nil {
+				return nil, err
+			}
+
+			deployment.SKU = &armappplatform.SKU{
+				Name: sku.Name,
+				Tier: sku.Tier,
+			}
+		}
+
+		deployment.SKU.Capacity = to.Ptr(int32(options.InstanceCount))
+	}
+
+	poller, err := deploymentClient.BeginCreateOrUpdate(
+		ctx, resourceGroup, instanceName, appName, deploymentName, deployment, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -264,6 +591,35 @@ func (ss *springService) createOrUpdateDeployment(
 	return res.Name, nil
 }
 
+// getSpringServiceSku returns the SKU of the Spring service, ex) the Enterprise tier
+func (ss *springService) getSpringServiceSku(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	instanceName string,
+) (*armappplatform.SKU, error) {
+	credential, err := ss.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	servicesClient, err := armappplatform.NewServicesClient(subscriptionId, credential, ss.armClientOptions)
+	if err != nil {
+		return nil, fmt.Errorf("creating SpringService client: %w", err)
+	}
+
+	service, err := servicesClient.Get(ctx, resourceGroup, instanceName, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed retrieving spring service properties: %w", err)
+	}
+
+	if service.SKU == nil {
+		return nil, fmt.Errorf("spring service '%s' is missing its SKU", instanceName)
+	}
+
+	return service.SKU, nil
+}
+
 func (ss *springService) activeDeployment(
 	springClient *armappplatform.AppsClient,
 	ctx context.Context,
@@ -0,0 +1,222 @@
+package azcli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appplatform/armappplatform/v2"
+)
+
+// The interval between the polls of the status of a build of the build service
+const defaultSpringBuildPollInterval = 5 * time.Second
+
+var springBuildPollInterval = defaultSpringBuildPollInterval
+
+const (
+	// The build service and the agent pool of an Enterprise tier instance, which only has a single one of each
+	springBuildServiceName = "default"
+	springAgentPoolName    = "default"
+	// The builder used when the builder isn't set in the build options
+	defaultSpringBuilderName = "default"
+)
+
+// The options of a build of the Enterprise tier build service
+type SpringBuildOptions struct {
+	// The name of the builder, ex) default
+	Builder string
+	// The Java runtime version, ex) Java_17, which is passed to the buildpacks as BP_JVM_VERSION
+	RuntimeVersion string
+}
+
+func (ss *springService) UploadSpringBuildSource(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	instanceName string,
+	sourcePath string,
+) (*string, error) {
+	file, err := os.Open(sourcePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("source archive %s does not exist: %w", sourcePath, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading source archive %s: %w", sourcePath, err)
+	}
+	defer file.Close()
+
+	sku, err := ss.getSpringServiceSku(ctx, subscriptionId, resourceGroup, instanceName)
+	if err != nil {
+		return nil, err
+	}
+
+	if sku.Tier == nil || !strings.EqualFold(*sku.Tier, "Enterprise") {
+		return nil, &ErrorWithSuggestion{
+			Err: fmt.Errorf("the build service isn't available in spring service '%s'", instanceName),
+			Suggestion: "Suggested action: The build service is only available in the Enterprise tier of " +
+				"Azure Spring Apps. Remove 'spring.buildService' from azure.yaml to deploy the jar built by azd.",
+		}
+	}
+
+	client, err := ss.createBuildServiceClient(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	storageInfo, err := client.GetResourceUploadURL(ctx, resourceGroup, instanceName, springBuildServiceName, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resource upload URL: %w", err)
+	}
+
+	if err := uploadToFileShare(ctx, storageInfo.UploadURL, file); err != nil {
+		return nil, fmt.Errorf("failed to upload source archive %s : %w", sourcePath, err)
+	}
+
+	return storageInfo.RelativePath, nil
+}
+
+// BuildSpringAppSource builds the uploaded source code with the build service, polling the result of the build until
+// it finishes. The build is named after the app and the deployment, so that a build is kept for each deployment.
+func (ss *springService) BuildSpringAppSource(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	instanceName string,
+	appName string,
+	deploymentName string,
+	relativePath string,
+	options *SpringBuildOptions,
+) (*string, error) {
+	if options == nil {
+		options = &SpringBuildOptions{}
+	}
+
+	builder := options.Builder
+	if builder == "" {
+		builder = defaultSpringBuilderName
+	}
+
+	buildServiceId := fmt.Sprintf(
+		"/subscriptions/%s/resourceGroups/%s/providers/Microsoft.AppPlatform/Spring/%s/buildServices/%s",
+		subscriptionId,
+		resourceGroup,
+		instanceName,
+		springBuildServiceName,
+	)
+
+	env := map[string]*string{}
+	if options.RuntimeVersion != "" {
+		// The runtime version of a jar deployment, ex) Java_17, is the JVM version of the buildpacks, ex) 17
+		env["BP_JVM_VERSION"] = to.Ptr(strings.TrimPrefix(options.RuntimeVersion, "Java_"))
+	}
+
+	client, err := ss.createBuildServiceClient(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	buildName := fmt.Sprintf("%s-%s", appName, deploymentName)
+	build, err := client.CreateOrUpdateBuild(ctx, resourceGroup, instanceName, springBuildServiceName, buildName,
+		armappplatform.Build{
+			Properties: &armappplatform.BuildProperties{
+				Builder:      to.Ptr(fmt.Sprintf("%s/builders/%s", buildServiceId, builder)),
+				AgentPool:    to.Ptr(fmt.Sprintf("%s/agentPools/%s", buildServiceId, springAgentPoolName)),
+				RelativePath: to.Ptr(relativePath),
+				Env:          env,
+			},
+		}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed creating build '%s': %w", buildName, err)
+	}
+
+	if build.Properties == nil ||
+		build.Properties.TriggeredBuildResult == nil ||
+		build.Properties.TriggeredBuildResult.ID == nil {
+		return nil, fmt.Errorf("build '%s' didn't trigger a build result", buildName)
+	}
+
+	buildResultId := *build.Properties.TriggeredBuildResult.ID
+	resourceId, err := arm.ParseResourceID(buildResultId)
+	if err != nil {
+		return nil, fmt.Errorf("parsing build result id '%s': %w", buildResultId, err)
+	}
+
+	log.Printf("waiting for build result '%s' of build '%s'\n", resourceId.Name, buildName)
+	for {
+		result, err := client.GetBuildResult(
+			ctx, resourceGroup, instanceName, springBuildServiceName, buildName, resourceId.Name, nil)
+		if err != nil {
+			return nil, fmt.Errorf("getting status of build '%s': %w", buildName, err)
+		}
+
+		var state armappplatform.BuildResultProvisioningState
+		if result.Properties != nil && result.Properties.ProvisioningState != nil {
+			state = *result.Properties.ProvisioningState
+		}
+
+		switch state {
+		case armappplatform.BuildResultProvisioningStateSucceeded:
+			return &buildResultId, nil
+		case armappplatform.BuildResultProvisioningStateFailed, armappplatform.BuildResultProvisioningStateDeleting:
+			return nil, ss.buildFailedError(
+				ctx, client, resourceGroup, instanceName, buildName, resourceId.Name, result.Properties)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(springBuildPollInterval):
+		}
+	}
+}
+
+// buildFailedError returns the error of a failed build, which links the logs of the build when they're available
+func (ss *springService) buildFailedError(
+	ctx context.Context,
+	client *armappplatform.BuildServiceClient,
+	resourceGroup string,
+	instanceName string,
+	buildName string,
+	buildResultName string,
+	properties *armappplatform.BuildResultProperties,
+) error {
+	message := fmt.Sprintf("build '%s' finished with status '%s'", buildName, *properties.ProvisioningState)
+	if properties.Error != nil && properties.Error.Message != nil {
+		message = fmt.Sprintf("%s: %s", message, *properties.Error.Message)
+	}
+
+	buildLog, err := client.GetBuildResultLog(
+		ctx, resourceGroup, instanceName, springBuildServiceName, buildName, buildResultName, nil)
+	if err != nil || buildLog.BlobURL == nil {
+		log.Printf("failed getting the logs of build '%s': %v", buildName, err)
+		return errors.New(message)
+	}
+
+	return &ErrorWithSuggestion{
+		Err:        errors.New(message),
+		Suggestion: fmt.Sprintf("Suggested action: Review the logs of the build at %s", *buildLog.BlobURL),
+	}
+}
+
+func (ss *springService) createBuildServiceClient(
+	ctx context.Context,
+	subscriptionId string,
+) (*armappplatform.BuildServiceClient, error) {
+	credential, err := ss.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := armappplatform.NewBuildServiceClient(subscriptionId, credential, ss.armClientOptions)
+	if err != nil {
+		return nil, fmt.Errorf("creating SpringBuildService client: %w", err)
+	}
+
+	return client, nil
+}
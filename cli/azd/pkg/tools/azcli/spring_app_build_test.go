@@ -0,0 +1,137 @@
+package azcli
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appplatform/armappplatform/v2"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SpringService_BuildSpringAppSource(t *testing.T) {
+	springBuildPollInterval = 0
+	t.Cleanup(func() {
+		springBuildPollInterval = defaultSpringBuildPollInterval
+	})
+
+	buildServicePath := "/subscriptions/SUBSCRIPTION_ID/resourceGroups/RESOURCE_GROUP" +
+		"/providers/Microsoft.AppPlatform/Spring/contoso/buildServices/default"
+
+	t.Run("Success", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		buildMocks := setupSpringBuildMocks(mockContext, armappplatform.BuildResultProvisioningStateSucceeded)
+
+		buildResultId, err := newSpringServiceFromMockContext(mockContext).BuildSpringAppSource(
+			*mockContext.Context,
+			"SUBSCRIPTION_ID",
+			"RESOURCE_GROUP",
+			"contoso",
+			"api",
+			"green",
+			"resources/source.tar.gz",
+			&SpringBuildOptions{RuntimeVersion: "Java_17"},
+		)
+		require.NoError(t, err)
+		require.Equal(t, buildServicePath+"/builds/api-green/results/1", *buildResultId)
+
+		properties := buildMocks.build.Properties
+		require.Equal(t, buildServicePath+"/builders/default", *properties.Builder)
+		require.Equal(t, buildServicePath+"/agentPools/default", *properties.AgentPool)
+		require.Equal(t, "resources/source.tar.gz", *properties.RelativePath)
+		require.Equal(t, "17", *properties.Env["BP_JVM_VERSION"])
+
+		// The result is polled until the build finishes
+		require.Equal(t, 2, buildMocks.polls)
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		setupSpringBuildMocks(mockContext, armappplatform.BuildResultProvisioningStateFailed)
+
+		_, err := newSpringServiceFromMockContext(mockContext).BuildSpringAppSource(
+			*mockContext.Context,
+			"SUBSCRIPTION_ID",
+			"RESOURCE_GROUP",
+			"contoso",
+			"api",
+			"green",
+			"resources/source.tar.gz",
+			nil,
+		)
+		require.ErrorContains(t, err, "build 'api-green' finished with status 'Failed': build failed")
+
+		var errWithSuggestion *ErrorWithSuggestion
+		require.ErrorAs(t, err, &errWithSuggestion)
+		require.Contains(t, errWithSuggestion.Suggestion, "https://logs.file.core.windows.net/build.log")
+	})
+}
+
+type springBuildMocks struct {
+	build *armappplatform.Build
+	polls int
+}
+
+// setupSpringBuildMocks mocks the build service APIs of a build that is building on the first poll and finishes with
+// the final state on the second poll
+func setupSpringBuildMocks(
+	mockContext *mocks.MockContext,
+	finalState armappplatform.BuildResultProvisioningState,
+) *springBuildMocks {
+	buildMocks := &springBuildMocks{}
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPut && strings.HasSuffix(request.URL.Path, "/builds/api-green")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		body, err := io.ReadAll(request.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		buildMocks.build = &armappplatform.Build{}
+		if err := json.Unmarshal(body, buildMocks.build); err != nil {
+			return nil, err
+		}
+
+		buildMocks.build.Properties.TriggeredBuildResult = &armappplatform.TriggeredBuildResult{
+			ID: to.Ptr(strings.TrimSuffix(request.URL.Path, "/") + "/results/1"),
+		}
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, buildMocks.build)
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, "/builds/api-green/results/1")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		buildMocks.polls++
+		state := armappplatform.BuildResultProvisioningStateBuilding
+		properties := &armappplatform.BuildResultProperties{}
+		if buildMocks.polls > 1 {
+			state = finalState
+			if finalState == armappplatform.BuildResultProvisioningStateFailed {
+				properties.Error = &armappplatform.Error{Message: to.Ptr("build failed")}
+			}
+		}
+
+		properties.ProvisioningState = &state
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armappplatform.BuildResult{
+			Name:       to.Ptr("1"),
+			Properties: properties,
+		})
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPost &&
+			strings.HasSuffix(request.URL.Path, "/builds/api-green/results/1/getLogFileUrl")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armappplatform.BuildResultLog{
+			BlobURL: to.Ptr("https://logs.file.core.windows.net/build.log"),
+		})
+	})
+
+	return buildMocks
+}
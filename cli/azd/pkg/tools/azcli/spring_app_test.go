@@ -0,0 +1,202 @@
+package azcli
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appplatform/armappplatform/v2"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockaccount"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SpringService_DeploySpringAppArtifact(t *testing.T) {
+	t.Run("ExistingDeployment", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		existing := &armappplatform.DeploymentResource{
+			Name: to.Ptr("green"),
+			SKU:  &armappplatform.SKU{Name: to.Ptr("S0"), Tier: to.Ptr("Standard"), Capacity: to.Ptr[int32](1)},
+			Properties: &armappplatform.DeploymentResourceProperties{
+				DeploymentSettings: &armappplatform.DeploymentSettings{
+					EnvironmentVariables: map[string]*string{"PROFILE": to.Ptr("prod")},
+				},
+			},
+		}
+		deployed := setupSpringDeploymentMocks(mockContext, existing)
+
+		_, err := newSpringServiceFromMockContext(mockContext).DeploySpringAppArtifact(
+			*mockContext.Context,
+			"SUBSCRIPTION_ID",
+			"RESOURCE_GROUP",
+			"contoso",
+			"api",
+			"resources/app.jar",
+			"green",
+			&SpringDeploymentOptions{
+				JvmOptions:     "-Xmx2048m",
+				RuntimeVersion: "Java_17",
+				InstanceCount:  3,
+			},
+		)
+		require.NoError(t, err)
+
+		source, ok := deployed.resource.Properties.Source.(*armappplatform.JarUploadedUserSourceInfo)
+		require.True(t, ok)
+		require.Equal(t, "resources/app.jar", *source.RelativePath)
+		require.Equal(t, "-Xmx2048m", *source.JvmOptions)
+		require.Equal(t, "Java_17", *source.RuntimeVersion)
+
+		// The settings and the SKU of the deployment are kept
+		require.Equal(t, "prod", *deployed.resource.Properties.DeploymentSettings.EnvironmentVariables["PROFILE"])
+		require.Equal(t, "S0", *deployed.resource.SKU.Name)
+		require.Equal(t, int32(3), *deployed.resource.SKU.Capacity)
+	})
+
+	t.Run("NewDeployment", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		deployed := setupSpringDeploymentMocks(mockContext, nil)
+
+		_, err := newSpringServiceFromMockContext(mockContext).DeploySpringAppArtifact(
+			*mockContext.Context,
+			"SUBSCRIPTION_ID",
+			"RESOURCE_GROUP",
+			"contoso",
+			"api",
+			"resources/app.jar",
+			"green",
+			&SpringDeploymentOptions{InstanceCount: 2},
+		)
+		require.NoError(t, err)
+
+		source, ok := deployed.resource.Properties.Source.(*armappplatform.JarUploadedUserSourceInfo)
+		require.True(t, ok)
+		require.Nil(t, source.JvmOptions)
+		require.Nil(t, source.RuntimeVersion)
+
+		// A new deployment has the SKU of the Spring service
+		require.Equal(t, "E0", *deployed.resource.SKU.Name)
+		require.Equal(t, "Enterprise", *deployed.resource.SKU.Tier)
+		require.Equal(t, int32(2), *deployed.resource.SKU.Capacity)
+	})
+}
+
+func Test_SpringService_DeploySpringAppBuildResult(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	deployed := setupSpringDeploymentMocks(mockContext, nil)
+
+	_, err := newSpringServiceFromMockContext(mockContext).DeploySpringAppBuildResult(
+		*mockContext.Context,
+		"SUBSCRIPTION_ID",
+		"RESOURCE_GROUP",
+		"contoso",
+		"api",
+		"BUILD_RESULT_ID",
+		"green",
+		&SpringDeploymentOptions{JvmOptions: "-Xmx2048m"},
+	)
+	require.NoError(t, err)
+
+	source, ok := deployed.resource.Properties.Source.(*armappplatform.BuildResultUserSourceInfo)
+	require.True(t, ok)
+	require.Equal(t, "BUILD_RESULT_ID", *source.BuildResultID)
+	require.Equal(t, "-Xmx2048m", *deployed.resource.Properties.DeploymentSettings.EnvironmentVariables["JAVA_OPTS"])
+	require.Nil(t, deployed.resource.SKU)
+}
+
+func Test_SpringService_GetSpringAppActiveDeployment(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, "/apps/api/deployments")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armappplatform.DeploymentResourceCollection{
+			Value: []*armappplatform.DeploymentResource{
+				{
+					Name:       to.Ptr("blue"),
+					Properties: &armappplatform.DeploymentResourceProperties{Active: to.Ptr(false)},
+				},
+				{
+					Name:       to.Ptr("green"),
+					Properties: &armappplatform.DeploymentResourceProperties{Active: to.Ptr(true)},
+				},
+			},
+		})
+	})
+
+	activeDeployment, err := newSpringServiceFromMockContext(mockContext).GetSpringAppActiveDeployment(
+		*mockContext.Context,
+		"SUBSCRIPTION_ID",
+		"RESOURCE_GROUP",
+		"contoso",
+		"api",
+	)
+	require.NoError(t, err)
+	require.Equal(t, "green", *activeDeployment)
+}
+
+type springDeploymentMocks struct {
+	resource *armappplatform.DeploymentResource
+}
+
+// setupSpringDeploymentMocks mocks the APIs of the 'green' deployment of the 'api' app, which doesn't exist when
+// existing is nil, recording the deployed resource
+func setupSpringDeploymentMocks(
+	mockContext *mocks.MockContext,
+	existing *armappplatform.DeploymentResource,
+) *springDeploymentMocks {
+	deploymentMocks := &springDeploymentMocks{}
+	deploymentPath := "/Spring/contoso/apps/api/deployments/green"
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, "/Spring/contoso")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armappplatform.ServiceResource{
+			Name: to.Ptr("contoso"),
+			SKU:  &armappplatform.SKU{Name: to.Ptr("E0"), Tier: to.Ptr("Enterprise")},
+		})
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, deploymentPath)
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		if existing == nil {
+			return mocks.CreateEmptyHttpResponse(request, http.StatusNotFound)
+		}
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, existing)
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPut && strings.HasSuffix(request.URL.Path, deploymentPath)
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		body, err := io.ReadAll(request.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		deploymentMocks.resource = &armappplatform.DeploymentResource{}
+		if err := json.Unmarshal(body, deploymentMocks.resource); err != nil {
+			return nil, err
+		}
+
+		deploymentMocks.resource.Name = to.Ptr("green")
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, deploymentMocks.resource)
+	})
+
+	return deploymentMocks
+}
+
+func newSpringServiceFromMockContext(mockContext *mocks.MockContext) SpringService {
+	return NewSpringService(
+		mockaccount.SubscriptionCredentialProviderFunc(func(_ context.Context, _ string) (azcore.TokenCredential, error) {
+			return mockContext.Credentials, nil
+		}),
+		mockContext.HttpClient,
+		mockContext.ArmClientOptions,
+	)
+}
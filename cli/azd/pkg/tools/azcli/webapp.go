@@ -100,6 +100,37 @@ func (cli *azCli) DeployAppServiceZip(
 	return convert.RefOf(response.StatusText), nil
 }
 
+// Opens the live log stream of an app service or function app from its Kudu (SCM) site.
+// The caller is responsible for closing the stream.
+func (cli *azCli) GetAppServiceLogStream(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	appName string,
+) (io.ReadCloser, error) {
+	hostName, err := cli.appServiceRepositoryHost(ctx, subscriptionId, resourceGroup, appName)
+	if err != nil {
+		return nil, err
+	}
+
+	credential, err := cli.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := azsdk.NewLogStreamClient(hostName, credential, cli.armClientOptions)
+	if err != nil {
+		return nil, fmt.Errorf("creating log stream client: %w", err)
+	}
+
+	stream, err := client.Stream(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("opening log stream for '%s': %w", appName, err)
+	}
+
+	return stream, nil
+}
+
 func (cli *azCli) createWebAppsClient(ctx context.Context, subscriptionId string) (*armappservice.WebAppsClient, error) {
 	credential, err := cli.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
 	if err != nil {
@@ -32,6 +32,9 @@ var BicepVersion semver.Version = semver.MustParse("0.25.3")
 type BicepCli interface {
 	Build(ctx context.Context, file string) (BuildResult, error)
 	BuildBicepParam(ctx context.Context, file string, env []string) (BuildResult, error)
+	// Decompile converts the ARM template file to Bicep, written to outFile. Decompilation is best effort; the returned
+	// warnings describe the parts of the template which may need to be fixed manually.
+	Decompile(ctx context.Context, file string, outFile string) (string, error)
 }
 
 // NewBicepCli creates a new BicepCli. Azd manages its own copy of the bicep CLI, stored in `$AZD_CONFIG_DIR/bin`. If
@@ -302,6 +305,19 @@ func (cli *bicepCli) BuildBicepParam(ctx context.Context, file string, env []str
 	}, nil
 }
 
+func (cli *bicepCli) Decompile(ctx context.Context, file string, outFile string) (string, error) {
+	args := []string{"decompile", file, "--outfile", outFile, "--force"}
+	res, err := cli.runCommand(ctx, nil, args...)
+	if err != nil {
+		return "", fmt.Errorf(
+			"failed running bicep decompile: %w",
+			err,
+		)
+	}
+
+	return res.Stderr, nil
+}
+
 func (cli *bicepCli) runCommand(ctx context.Context, env []string, args ...string) (exec.RunResult, error) {
 	runArgs := exec.NewRunArgs(cli.path, args...)
 	if env != nil {
@@ -8,6 +8,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -30,12 +31,47 @@ type Docker interface {
 		buildContext string,
 		name string,
 		buildArgs []string,
+		options *BuildOptions,
 		buildProgress io.Writer,
 	) (string, error)
 	Tag(ctx context.Context, cwd string, imageName string, tag string) error
 	Push(ctx context.Context, cwd string, tag string) error
 	Pull(ctx context.Context, imageName string) error
 	Inspect(ctx context.Context, imageName string, format string) (string, error)
+	Run(ctx context.Context, cwd string, imageName string, options *RunOptions, writer io.Writer) error
+}
+
+// BuildOptions are the optional BuildKit options of a docker build
+type BuildOptions struct {
+	// The external cache sources of the build, ex) type=registry,ref=myregistry.azurecr.io/app:cache
+	CacheFrom []string
+	// The cache export destinations of the build, which require buildx
+	CacheTo []string
+	// The BuildKit secrets of the build, as id=ID,env=NAME. Their values are read from Env so they are neither part of
+	// the image nor of the command line.
+	Secrets []string
+	// The environment variables of the build, as NAME=VALUE
+	Env []string
+	// The labels of the image, as KEY=VALUE
+	Labels []string
+	// Pushes the image to its registry instead of loading it into the local image store, which is required to build
+	// an image for multiple platforms
+	Push bool
+}
+
+// IsMultiPlatform returns true when the platform lists multiple platforms, ex) linux/amd64,linux/arm64
+func IsMultiPlatform(platform string) bool {
+	return strings.Contains(platform, ",")
+}
+
+// RunOptions are the options of a container started with `docker run`
+type RunOptions struct {
+	// The name of the container. The container is removed once it stops.
+	Name string
+	// The ports published to the host, as hostPort:containerPort
+	Ports []string
+	// The environment variables of the container, as KEY=VALUE
+	Env []string
 }
 
 func NewDocker(commandRunner exec.CommandRunner) Docker {
@@ -67,6 +103,8 @@ func (d *docker) Login(ctx context.Context, loginServer string, username string,
 // Runs a Docker build for a given Dockerfile, writing the output of docker build to [stdOut] when it is
 // not nil. If the platform is not specified (empty) it defaults to amd64. If the build is successful,
 // the function returns the image id of the built image.
+//
+// Builds that export their cache, target multiple platforms or push the image run with buildx.
 func (d *docker) Build(
 	ctx context.Context,
 	cwd string,
@@ -76,8 +114,13 @@ func (d *docker) Build(
 	buildContext string,
 	tagName string,
 	buildArgs []string,
+	options *BuildOptions,
 	buildProgress io.Writer,
 ) (string, error) {
+	if options == nil {
+		options = &BuildOptions{}
+	}
+
 	if strings.TrimSpace(platform) == "" {
 		platform = DefaultPlatform
 	}
@@ -94,11 +137,26 @@ func (d *docker) Build(
 	}
 	imgIdFile := filepath.Join(tmpFolder, "imgId")
 
-	args := []string{
-		"build",
+	useBuildx := len(options.CacheTo) > 0 || options.Push || IsMultiPlatform(platform)
+
+	args := []string{"build"}
+	if useBuildx {
+		args = []string{"buildx", "build"}
+	}
+
+	// The default builder of docker, which uses the docker driver, can't export caches or build multi-platform images
+	if requiresContainerBuilder(platform, options.CacheTo) {
+		if err := d.ensureContainerBuilder(ctx, cwd); err != nil {
+			return "", err
+		}
+
+		args = append(args, "--builder", ContainerBuilderName)
+	}
+
+	args = append(args,
 		"-f", dockerFilePath,
 		"--platform", platform,
-	}
+	)
 
 	if target != "" {
 		args = append(args, "--target", target)
@@ -111,13 +169,37 @@ func (d *docker) Build(
 	for _, arg := range buildArgs {
 		args = append(args, "--build-arg", arg)
 	}
+
+	for _, cache := range options.CacheFrom {
+		args = append(args, "--cache-from", cache)
+	}
+
+	for _, cache := range options.CacheTo {
+		args = append(args, "--cache-to", cache)
+	}
+
+	for _, secret := range options.Secrets {
+		args = append(args, "--secret", secret)
+	}
+
+	for _, label := range options.Labels {
+		args = append(args, "--label", label)
+	}
+
+	if options.Push {
+		args = append(args, "--push")
+	} else if useBuildx {
+		// Unlike docker build, buildx keeps the image in the build cache unless it is loaded
+		args = append(args, "--load")
+	}
+
 	args = append(args, buildContext)
 
 	// create a file with the docker img id
 	args = append(args, "--iidfile", imgIdFile)
 
 	// Build and produce output
-	runArgs := exec.NewRunArgs("docker", args...).WithCwd(cwd)
+	runArgs := exec.NewRunArgs("docker", args...).WithCwd(cwd).WithEnv(options.Env)
 
 	if buildProgress != nil {
 		// setting stderr and stdout both, as it's been noticed
@@ -137,6 +219,44 @@ func (d *docker) Build(
 	return strings.TrimSpace(string(imgId)), nil
 }
 
+// ContainerBuilderName is the name of the buildx builder azd creates with the docker-container driver for the builds the
+// default builder doesn't support.
+const ContainerBuilderName = "azd"
+
+// requiresContainerBuilder returns true when a build exports caches, other than inline caches, or builds images for more
+// than one platform, which the docker driver of the default builder rejects.
+func requiresContainerBuilder(platform string, cacheTo []string) bool {
+	if IsMultiPlatform(platform) {
+		return true
+	}
+
+	return slices.ContainsFunc(cacheTo, func(cache string) bool {
+		return !slices.Contains(strings.Split(cache, ","), "type=inline")
+	})
+}
+
+// ensureContainerBuilder creates the buildx builder named ContainerBuilderName when it doesn't exist yet. The builder
+// is only selected by the builds which require it, the default builder of the user is kept.
+func (d *docker) ensureContainerBuilder(ctx context.Context, cwd string) error {
+	if _, err := d.executeCommand(ctx, cwd, "buildx", "inspect", ContainerBuilderName); err == nil {
+		return nil
+	}
+
+	_, err := d.executeCommand(
+		ctx, cwd, "buildx", "create", "--name", ContainerBuilderName, "--driver", "docker-container")
+	if err != nil {
+		return fmt.Errorf(
+			"creating the '%s' buildx builder with the docker-container driver, which is required to export build "+
+				"caches and build multi-platform images. Ensure docker buildx is installed or remove 'cacheTo' and "+
+				"the additional platforms from the docker options of the service: %w",
+			ContainerBuilderName,
+			err,
+		)
+	}
+
+	return nil
+}
+
 func (d *docker) Tag(ctx context.Context, cwd string, imageName string, tag string) error {
 	_, err := d.executeCommand(ctx, cwd, "tag", imageName, tag)
 	if err != nil {
@@ -173,6 +293,74 @@ func (d *docker) Inspect(ctx context.Context, imageName string, format string) (
 	return out.Stdout, nil
 }
 
+// Runs a container from the image, writing its output to the writer until it stops. The values of the environment
+// variables are passed through the environment of the docker CLI so they don't show up in the command line.
+//
+// The container runs detached and is removed when Run returns, including when the context is canceled, since stopping
+// the docker CLI of a container running in the foreground doesn't stop the container. A stale container with the same
+// name, ex) left by a previous run that was killed, is removed before the container starts.
+func (d *docker) Run(ctx context.Context, cwd string, imageName string, options *RunOptions, writer io.Writer) error {
+	args := []string{"run", "--detach"}
+
+	if options.Name != "" {
+		d.removeContainer(ctx, options.Name)
+		args = append(args, "--name", options.Name)
+	}
+
+	for _, port := range options.Ports {
+		args = append(args, "-p", port)
+	}
+
+	envNames := map[string]bool{}
+	for _, envVar := range options.Env {
+		name, _, _ := strings.Cut(envVar, "=")
+		if !envNames[name] {
+			envNames[name] = true
+			args = append(args, "-e", name)
+		}
+	}
+
+	args = append(args, imageName)
+
+	runArgs := exec.NewRunArgs("docker", args...).
+		WithCwd(cwd).
+		WithEnv(options.Env)
+
+	res, err := d.commandRunner.Run(ctx, runArgs)
+	if err != nil {
+		return fmt.Errorf("running container: %w", err)
+	}
+
+	containerId := strings.TrimSpace(res.Stdout)
+	defer d.removeContainer(context.WithoutCancel(ctx), containerId)
+
+	logsArgs := exec.NewRunArgs("docker", "logs", "--follow", containerId).
+		WithStdOut(writer).
+		WithStdErr(writer)
+	if _, err := d.commandRunner.Run(ctx, logsArgs); err != nil {
+		return fmt.Errorf("reading container logs: %w", err)
+	}
+
+	// The logs end when the container stops
+	waitResult, err := d.executeCommand(ctx, "", "wait", containerId)
+	if err != nil {
+		return fmt.Errorf("waiting for container: %w", err)
+	}
+
+	if exitCode := strings.TrimSpace(waitResult.Stdout); exitCode != "0" {
+		return fmt.Errorf("container exited with code %s", exitCode)
+	}
+
+	return nil
+}
+
+// removeContainer force removes the container, stopping it when it's running. Containers that don't exist are ignored.
+func (d *docker) removeContainer(ctx context.Context, container string) {
+	if _, err := d.executeCommand(ctx, "", "rm", "--force", container); err != nil {
+		log.Printf("removing container %s: %v", container, err)
+	}
+}
+
 func (d *docker) versionInfo() tools.VersionInfo {
 	return tools.VersionInfo{
 		MinimumVersion: semver.Version{
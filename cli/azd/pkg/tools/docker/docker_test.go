@@ -68,6 +68,7 @@ func Test_DockerBuild(t *testing.T) {
 			imageName,
 			buildArgs,
 			nil,
+			nil,
 		)
 
 		require.Equal(t, true, ran)
@@ -125,6 +126,7 @@ func Test_DockerBuild(t *testing.T) {
 			imageName,
 			buildArgs,
 			nil,
+			nil,
 		)
 
 		require.Equal(t, true, ran)
@@ -180,7 +182,7 @@ func Test_DockerBuildEmptyPlatform(t *testing.T) {
 		}, nil
 	})
 
-	result, err := docker.Build(context.Background(), cwd, dockerFile, "", "", dockerContext, imageName, buildArgs, nil)
+	result, err := docker.Build(context.Background(), cwd, dockerFile, "", "", dockerContext, imageName, buildArgs, nil, nil)
 
 	require.Equal(t, true, ran)
 	require.Nil(t, err)
@@ -228,7 +230,7 @@ func Test_DockerBuildArgsEmpty(t *testing.T) {
 		}, nil
 	})
 
-	result, err := docker.Build(context.Background(), cwd, dockerFile, "", "", dockerContext, imageName, buildArgs, nil)
+	result, err := docker.Build(context.Background(), cwd, dockerFile, "", "", dockerContext, imageName, buildArgs, nil, nil)
 
 	require.Equal(t, true, ran)
 	require.Nil(t, err)
@@ -278,13 +280,193 @@ func Test_DockerBuildArgsMultiple(t *testing.T) {
 		}, nil
 	})
 
-	result, err := docker.Build(context.Background(), cwd, dockerFile, "", "", dockerContext, imageName, buildArgs, nil)
+	result, err := docker.Build(context.Background(), cwd, dockerFile, "", "", dockerContext, imageName, buildArgs, nil, nil)
 
 	require.Equal(t, true, ran)
 	require.Nil(t, err)
 	require.Equal(t, mockedDockerImgId, result)
 }
 
+func Test_DockerBuildWithBuildKitOptions(t *testing.T) {
+	tests := []struct {
+		name         string
+		platform     string
+		push         bool
+		expectedArgs []string
+	}{
+		{
+			name:     "Load",
+			platform: DefaultPlatform,
+			expectedArgs: []string{
+				"buildx", "build",
+				"--builder", "azd",
+				"-f", "./Dockerfile",
+				"--platform", DefaultPlatform,
+				"-t", "IMAGE_NAME",
+				"--cache-from", "type=registry,ref=myregistry.azurecr.io/app:cache",
+				"--cache-to", "type=registry,ref=myregistry.azurecr.io/app:cache,mode=max",
+				"--secret", "id=npm_token,env=NPM_TOKEN",
+				"--label", "env=dev",
+				"--load",
+				".",
+			},
+		},
+		{
+			name:     "MultiPlatformPush",
+			platform: "linux/amd64,linux/arm64",
+			push:     true,
+			expectedArgs: []string{
+				"buildx", "build",
+				"--builder", "azd",
+				"-f", "./Dockerfile",
+				"--platform", "linux/amd64,linux/arm64",
+				"-t", "IMAGE_NAME",
+				"--cache-from", "type=registry,ref=myregistry.azurecr.io/app:cache",
+				"--cache-to", "type=registry,ref=myregistry.azurecr.io/app:cache,mode=max",
+				"--secret", "id=npm_token,env=NPM_TOKEN",
+				"--label", "env=dev",
+				"--push",
+				".",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ran := false
+			mockContext := mocks.NewMockContext(context.Background())
+			docker := NewDocker(mockContext.CommandRunner)
+
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "docker buildx inspect azd")
+			}).Respond(exec.NewRunResult(0, "", ""))
+
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "docker buildx build")
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				ran = true
+
+				// extract img id file arg. "--iidfile" and path args are expected always at the end
+				argsNoFile, value := args.Args[:len(args.Args)-2], args.Args[len(args.Args)-1]
+
+				require.Equal(t, tt.expectedArgs, argsNoFile)
+				// Secret values are only passed through the environment of the build
+				require.Equal(t, []string{"NPM_TOKEN=secret"}, args.Env)
+
+				err := os.WriteFile(value, []byte(mockedDockerImgId), 0600)
+				require.NoError(t, err)
+
+				return exec.NewRunResult(0, "", ""), nil
+			})
+
+			result, err := docker.Build(
+				context.Background(),
+				".",
+				"./Dockerfile",
+				tt.platform,
+				"",
+				".",
+				"IMAGE_NAME",
+				nil,
+				&BuildOptions{
+					CacheFrom: []string{"type=registry,ref=myregistry.azurecr.io/app:cache"},
+					CacheTo:   []string{"type=registry,ref=myregistry.azurecr.io/app:cache,mode=max"},
+					Secrets:   []string{"id=npm_token,env=NPM_TOKEN"},
+					Env:       []string{"NPM_TOKEN=secret"},
+					Labels:    []string{"env=dev"},
+					Push:      tt.push,
+				},
+				nil,
+			)
+
+			require.NoError(t, err)
+			require.True(t, ran)
+			require.Equal(t, mockedDockerImgId, result)
+		})
+	}
+}
+
+func Test_DockerBuildContainerBuilder(t *testing.T) {
+	tests := []struct {
+		name             string
+		platform         string
+		cacheTo          []string
+		builderExists    bool
+		expectedCommands []string
+	}{
+		{
+			name:     "InlineCache",
+			platform: DefaultPlatform,
+			cacheTo:  []string{"type=inline"},
+			expectedCommands: []string{
+				"docker buildx build -f ./Dockerfile --platform linux/amd64 --cache-to type=inline --load .",
+			},
+		},
+		{
+			name:     "CreateBuilder",
+			platform: DefaultPlatform,
+			cacheTo:  []string{"type=registry,ref=myregistry.azurecr.io/app:cache"},
+			expectedCommands: []string{
+				"docker buildx inspect azd",
+				"docker buildx create --name azd --driver docker-container",
+				"docker buildx build --builder azd -f ./Dockerfile --platform linux/amd64 " +
+					"--cache-to type=registry,ref=myregistry.azurecr.io/app:cache --load .",
+			},
+		},
+		{
+			name:          "ExistingBuilder",
+			platform:      "linux/amd64,linux/arm64",
+			builderExists: true,
+			expectedCommands: []string{
+				"docker buildx inspect azd",
+				"docker buildx build --builder azd -f ./Dockerfile --platform linux/amd64,linux/arm64 --load .",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContext := mocks.NewMockContext(context.Background())
+			docker := NewDocker(mockContext.CommandRunner)
+
+			var commands []string
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.HasPrefix(command, "docker buildx")
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				if args.Args[1] == "inspect" && !tt.builderExists {
+					commands = append(commands, strings.Join(append([]string{args.Cmd}, args.Args...), " "))
+					return exec.NewRunResult(1, "", "no builder \"azd\" found"), errors.New("exit code: 1")
+				}
+
+				if args.Args[1] == "build" {
+					// the iidfile is the last argument
+					err := os.WriteFile(args.Args[len(args.Args)-1], []byte(mockedDockerImgId), 0600)
+					require.NoError(t, err)
+					args.Args = args.Args[:len(args.Args)-2]
+				}
+
+				commands = append(commands, strings.Join(append([]string{args.Cmd}, args.Args...), " "))
+				return exec.NewRunResult(0, "", ""), nil
+			})
+
+			_, err := docker.Build(
+				context.Background(),
+				".",
+				"./Dockerfile",
+				tt.platform,
+				"",
+				".",
+				"",
+				nil,
+				&BuildOptions{CacheTo: tt.cacheTo},
+				nil,
+			)
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedCommands, commands)
+		})
+	}
+}
+
 func Test_DockerTag(t *testing.T) {
 	cwd := "."
 	imageName := "image-name"
@@ -505,6 +687,75 @@ func Test_DockerLogin(t *testing.T) {
 	})
 }
 
+func Test_DockerRun(t *testing.T) {
+	tests := map[string]struct {
+		exitCode      string
+		expectedError string
+	}{
+		"Success":  {exitCode: "0"},
+		"ExitCode": {exitCode: "1", expectedError: "container exited with code 1"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			commands := []string{}
+
+			mockContext := mocks.NewMockContext(context.Background())
+			docker := NewDocker(mockContext.CommandRunner)
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.HasPrefix(command, "docker")
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				commands = append(commands, strings.Join(args.Args, " "))
+
+				switch args.Args[0] {
+				case "run":
+					require.Equal(t, []string{
+						"run", "--detach",
+						"--name", "todo-api",
+						"-p", "3100:80",
+						"-e", "PORT",
+						"-e", "AZURE_SECRET",
+						"IMAGE_ID",
+					}, args.Args)
+					require.Equal(t, []string{"PORT=3100", "AZURE_SECRET=secret", "PORT=80"}, args.Env)
+					return exec.NewRunResult(0, "CONTAINER_ID\n", ""), nil
+				case "logs":
+					_, err := args.StdOut.Write([]byte("listening\n"))
+					return exec.NewRunResult(0, "", ""), err
+				case "wait":
+					return exec.NewRunResult(0, test.exitCode+"\n", ""), nil
+				}
+
+				return exec.NewRunResult(0, "", ""), nil
+			})
+
+			output := &strings.Builder{}
+			err := docker.Run(*mockContext.Context, ".", "IMAGE_ID", &RunOptions{
+				Name:  "todo-api",
+				Ports: []string{"3100:80"},
+				Env:   []string{"PORT=3100", "AZURE_SECRET=secret", "PORT=80"},
+			}, output)
+
+			if test.expectedError != "" {
+				require.EqualError(t, err, test.expectedError)
+			} else {
+				require.NoError(t, err)
+			}
+
+			require.Equal(t, "listening\n", output.String())
+
+			// A stale container is removed before the run, and the container is removed once it stops
+			require.Equal(t, []string{
+				"rm --force todo-api",
+				"run --detach --name todo-api -p 3100:80 -e PORT -e AZURE_SECRET IMAGE_ID",
+				"logs --follow CONTAINER_ID",
+				"wait CONTAINER_ID",
+				"rm --force CONTAINER_ID",
+			}, commands)
+		})
+	}
+}
+
 func Test_IsSupportedDockerVersion(t *testing.T) {
 	cases := []struct {
 		name        string
@@ -7,6 +7,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
@@ -34,6 +35,9 @@ type DotNetCli interface {
 	PublishAppHostManifest(ctx context.Context, hostProject string, manifestPath string, dotnetEnv string) error
 	SetSecrets(ctx context.Context, secrets map[string]string, project string) error
 	GetMsBuildProperty(ctx context.Context, project string, propertyName string) (string, error)
+	// Run runs the project with the arguments of the program and the additional environment variables, writing its output
+	// to the writer. It blocks until the program exits or the context is cancelled.
+	Run(ctx context.Context, project string, args []string, env []string, writer io.Writer) error
 }
 
 type dotNetCli struct {
@@ -195,10 +199,13 @@ func (cli *dotNetCli) PublishContainer(
 		"--getProperty:GeneratedContainerConfiguration",
 	)
 
-	runArgs = runArgs.WithEnv([]string{
-		fmt.Sprintf("SDK_CONTAINER_REGISTRY_UNAME=%s", username),
-		fmt.Sprintf("SDK_CONTAINER_REGISTRY_PWORD=%s", password),
-	})
+	// Registries which don't require a login, like local registries, are pushed to without credentials
+	if username != "" {
+		runArgs = runArgs.WithEnv([]string{
+			fmt.Sprintf("SDK_CONTAINER_REGISTRY_UNAME=%s", username),
+			fmt.Sprintf("SDK_CONTAINER_REGISTRY_PWORD=%s", password),
+		})
+	}
 
 	result, err := cli.commandRunner.Run(ctx, runArgs)
 	if err != nil {
@@ -290,6 +297,25 @@ func (cli *dotNetCli) GetMsBuildProperty(ctx context.Context, project string, pr
 	return res.Stdout, nil
 }
 
+func (cli *dotNetCli) Run(ctx context.Context, project string, args []string, env []string, writer io.Writer) error {
+	runArgs := newDotNetRunArgs("run", "--project", project)
+	if len(args) > 0 {
+		runArgs = runArgs.AppendParams("--")
+		runArgs = runArgs.AppendParams(args...)
+	}
+
+	runArgs = runArgs.
+		WithEnv(append(runArgs.Env, env...)).
+		WithStdOut(writer).
+		WithStdErr(writer)
+
+	_, err := cli.commandRunner.Run(ctx, runArgs)
+	if err != nil {
+		return fmt.Errorf("dotnet run on project '%s' failed: %w", project, err)
+	}
+	return nil
+}
+
 func NewDotNetCli(commandRunner exec.CommandRunner) DotNetCli {
 	return &dotNetCli{
 		commandRunner: commandRunner,
@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"net/url"
 	"regexp"
 	"runtime"
 	"strings"
@@ -21,7 +22,21 @@ import (
 type GitCli interface {
 	tools.ExternalTool
 	GetRemoteUrl(ctx context.Context, string, remoteName string) (string, error)
-	ShallowClone(ctx context.Context, repositoryPath string, branch string, target string) error
+	// ShallowClone clones the latest commit of a branch, or of the default branch when empty. A nil credential uses
+	// the credential helpers configured for the user.
+	ShallowClone(
+		ctx context.Context, repositoryPath string, branch string, target string, credential *Credential) error
+	// Clone clones the full history of a repository, which is needed to check out commits other than the tip of branch.
+	Clone(ctx context.Context, repositoryPath string, branch string, target string, credential *Credential) error
+	// GetCommitHash resolves a revision, e.g. HEAD or a tag, of the repository to the SHA of its commit.
+	GetCommitHash(ctx context.Context, repositoryPath string, revision string) (string, error)
+	// AddWorktree checks out a commit of the repository in a detached working tree at target.
+	AddWorktree(ctx context.Context, repositoryPath string, target string, commit string) error
+	// MergeFile runs a three-way merge of the changes from base to other into current and returns the merged
+	// contents. Conflicting changes are delimited by conflict markers using the given labels of current, base and
+	// other, and reported by returning true.
+	MergeFile(
+		ctx context.Context, current string, base string, other string, labels [3]string) (string, bool, error)
 	InitRepo(ctx context.Context, repositoryPath string) error
 	AddRemote(ctx context.Context, repositoryPath string, remoteName string, remoteUrl string) error
 	UpdateRemote(ctx context.Context, repositoryPath string, remoteName string, remoteUrl string) error
@@ -37,6 +52,19 @@ type GitCli interface {
 	SetGitHubAuthForRepo(ctx context.Context, repositoryPath, credential, ghPath string) error
 }
 
+// Credential authenticates git with the host of the cloned repository, replacing the credential helpers configured for
+// the user for that host. Other hosts, like the hosts of submodules, keep the credential helpers of the user.
+type Credential struct {
+	// Token is a personal access token, e.g. a GitHub or Azure DevOps PAT.
+	Token string
+	// GhPath is the path of the GitHub CLI, used as the credential helper when no token is set.
+	GhPath string
+}
+
+// tokenEnvVarName is the environment variable the credential helper reads the token from, which keeps the token out
+// of the command line of git.
+const tokenEnvVarName = "AZD_GIT_TOKEN"
+
 type gitCli struct {
 	commandRunner exec.CommandRunner
 }
@@ -89,8 +117,27 @@ func (cli *gitCli) Name() string {
 	return "git CLI"
 }
 
-func (cli *gitCli) ShallowClone(ctx context.Context, repositoryPath string, branch string, target string) error {
-	args := []string{"clone", "--depth", "1", repositoryPath}
+func (cli *gitCli) ShallowClone(
+	ctx context.Context, repositoryPath string, branch string, target string, credential *Credential) error {
+	return cli.clone(ctx, []string{"--depth", "1"}, repositoryPath, branch, target, credential)
+}
+
+func (cli *gitCli) Clone(
+	ctx context.Context, repositoryPath string, branch string, target string, credential *Credential) error {
+	return cli.clone(ctx, nil, repositoryPath, branch, target, credential)
+}
+
+func (cli *gitCli) clone(
+	ctx context.Context,
+	options []string,
+	repositoryPath string,
+	branch string,
+	target string,
+	credential *Credential) error {
+	args, env := credentialConfig(repositoryPath, credential)
+	args = append(args, "clone")
+	args = append(args, options...)
+	args = append(args, repositoryPath)
 	if branch != "" {
 		args = append(args, "--branch", branch)
 	}
@@ -99,7 +146,7 @@ func (cli *gitCli) ShallowClone(ctx context.Context, repositoryPath string, bran
 	// Do not call `newRunArgs()` here because we don't want to apply the codespaces special patch that removes
 	// default authentication. `git clone` should work for private repos within a codespace with default auth.
 	// See: https://github.com/Azure/azure-dev/issues/2582
-	runArgs := exec.NewRunArgs("git", args...)
+	runArgs := exec.NewRunArgs("git", args...).WithEnv(env)
 	_, err := cli.commandRunner.Run(ctx, runArgs)
 	if err != nil {
 		return fmt.Errorf("failed to clone repository %s: %w", repositoryPath, err)
@@ -108,6 +155,80 @@ func (cli *gitCli) ShallowClone(ctx context.Context, repositoryPath string, bran
 	return nil
 }
 
+// credentialConfig returns the git config arguments and environment variables which authenticate with the credential.
+// The credential helper is scoped to the scheme and host of the repository, so that the credential is never sent to
+// another host. git doesn't prompt for credentials when a credential is set.
+func credentialConfig(repositoryPath string, credential *Credential) ([]string, []string) {
+	if credential == nil {
+		return nil, nil
+	}
+
+	// Credential helpers are only used by the http transports of git, ex) not by ssh
+	repositoryUrl, err := url.Parse(repositoryPath)
+	if err != nil || (repositoryUrl.Scheme != "https" && repositoryUrl.Scheme != "http") || repositoryUrl.Host == "" {
+		log.Printf("not setting a credential helper for repository '%s', which isn't an http url", repositoryPath)
+		return nil, nil
+	}
+	helperKey := fmt.Sprintf("credential.%s://%s.helper", repositoryUrl.Scheme, repositoryUrl.Host)
+
+	env := []string{"GIT_TERMINAL_PROMPT=0"}
+	var helper string
+	if credential.Token != "" {
+		helper = fmt.Sprintf(`!f() { echo username=azd; echo "password=$%s"; }; f`, tokenEnvVarName)
+		env = append(env, fmt.Sprintf("%s=%s", tokenEnvVarName, credential.Token))
+	} else {
+		ghPath := credential.GhPath
+		// path needs to be quoted on windows
+		if runtime.GOOS == "windows" {
+			ghPath = fmt.Sprintf("'%s'", ghPath)
+		}
+		helper = fmt.Sprintf("!%s auth git-credential", ghPath)
+	}
+
+	// The empty helper resets the helpers configured for the user for the host.
+	return []string{"-c", helperKey + "=", "-c", helperKey + "=" + helper}, env
+}
+
+func (cli *gitCli) GetCommitHash(ctx context.Context, repositoryPath string, revision string) (string, error) {
+	runArgs := newRunArgs("-C", repositoryPath, "rev-parse", "--verify", revision+"^{commit}")
+	res, err := cli.commandRunner.Run(ctx, runArgs)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve commit of '%s': %w", revision, err)
+	}
+
+	return strings.TrimSpace(res.Stdout), nil
+}
+
+func (cli *gitCli) AddWorktree(ctx context.Context, repositoryPath string, target string, commit string) error {
+	runArgs := newRunArgs("-C", repositoryPath, "worktree", "add", "--detach", target, commit)
+	_, err := cli.commandRunner.Run(ctx, runArgs)
+	if err != nil {
+		return fmt.Errorf("failed to check out commit %s: %w", commit, err)
+	}
+
+	return nil
+}
+
+func (cli *gitCli) MergeFile(
+	ctx context.Context, current string, base string, other string, labels [3]string) (string, bool, error) {
+	runArgs := newRunArgs(
+		"merge-file", "-p",
+		"-L", labels[0], "-L", labels[1], "-L", labels[2],
+		current, base, other,
+	)
+	res, err := cli.commandRunner.Run(ctx, runArgs)
+
+	// merge-file exits with the number of conflicts, or a negative value when the merge couldn't be done.
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode > 0 && exitErr.ExitCode < 128 {
+		return res.Stdout, true, nil
+	} else if err != nil {
+		return "", false, fmt.Errorf("failed to merge file: %w", err)
+	}
+
+	return res.Stdout, false, nil
+}
+
 var noSuchRemoteRegex = regexp.MustCompile("(fatal|error): No such remote")
 var notGitRepositoryRegex = regexp.MustCompile("(fatal|error): not a git repository")
 var ErrNoSuchRemote = errors.New("no such remote")
@@ -0,0 +1,108 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package imagescan
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+)
+
+type grype struct {
+	commandRunner exec.CommandRunner
+	credentials   *RegistryCredentials
+}
+
+// NewGrype creates a scanner that uses the Grype CLI
+func NewGrype(commandRunner exec.CommandRunner) Scanner {
+	return &grype{
+		commandRunner: commandRunner,
+	}
+}
+
+func (g *grype) CheckInstalled(ctx context.Context) error {
+	return checkInstalled(ctx, g.commandRunner, "grype")
+}
+
+func (g *grype) InstallUrl() string {
+	return "https://github.com/anchore/grype#installation"
+}
+
+func (g *grype) Name() string {
+	return "Grype"
+}
+
+func (g *grype) SetRegistryCredentials(credentials *RegistryCredentials) {
+	g.credentials = credentials
+}
+
+// run runs grype, passing the registry credentials in the environment variables grype reads them from
+func (g *grype) run(ctx context.Context, args ...string) (exec.RunResult, error) {
+	runArgs := exec.NewRunArgs("grype", args...)
+	if g.credentials != nil {
+		runArgs = runArgs.WithEnv([]string{
+			fmt.Sprintf("GRYPE_REGISTRY_AUTH_AUTHORITY=%s", g.credentials.LoginServer),
+			fmt.Sprintf("GRYPE_REGISTRY_AUTH_USERNAME=%s", g.credentials.Username),
+			fmt.Sprintf("GRYPE_REGISTRY_AUTH_PASSWORD=%s", g.credentials.Password),
+		})
+	}
+
+	return g.commandRunner.Run(ctx, runArgs)
+}
+
+// The report of `grype --output json`
+type grypeReport struct {
+	Matches []struct {
+		Vulnerability struct {
+			Id       string `json:"id"`
+			Severity string `json:"severity"`
+			Fix      struct {
+				Versions []string `json:"versions"`
+			} `json:"fix"`
+		} `json:"vulnerability"`
+		Artifact struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+		} `json:"artifact"`
+	} `json:"matches"`
+}
+
+func (g *grype) Scan(ctx context.Context, image string) ([]Vulnerability, error) {
+	res, err := g.run(ctx, image, "--quiet", "--output", "json")
+	if err != nil {
+		return nil, fmt.Errorf("scanning image %s with grype: %w", image, err)
+	}
+
+	var report grypeReport
+	if err := json.Unmarshal([]byte(res.Stdout), &report); err != nil {
+		return nil, fmt.Errorf("parsing grype report: %w", err)
+	}
+
+	vulnerabilities := []Vulnerability{}
+	for _, match := range report.Matches {
+		vulnerabilities = append(vulnerabilities, Vulnerability{
+			Id:               match.Vulnerability.Id,
+			Severity:         normalizeSeverity(match.Vulnerability.Severity),
+			Package:          match.Artifact.Name,
+			InstalledVersion: match.Artifact.Version,
+			FixedVersion:     strings.Join(match.Vulnerability.Fix.Versions, ", "),
+		})
+	}
+
+	return vulnerabilities, nil
+}
+
+// GenerateSbom writes the CycloneDX report of grype, which lists the components of the image along with their
+// vulnerabilities
+func (g *grype) GenerateSbom(ctx context.Context, image string, sbomPath string) error {
+	_, err := g.run(ctx, image, "--quiet", "--output", "cyclonedx-json", "--file", sbomPath)
+	if err != nil {
+		return fmt.Errorf("generating SBOM of image %s with grype: %w", image, err)
+	}
+
+	return nil
+}
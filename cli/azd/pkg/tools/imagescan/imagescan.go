@@ -0,0 +1,121 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package imagescan
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+)
+
+// Scans container images for vulnerabilities and generates their software bill of materials (SBOM)
+type Scanner interface {
+	tools.ExternalTool
+	// Scans the image, from the local image store or a registry, and returns the vulnerabilities found
+	Scan(ctx context.Context, image string) ([]Vulnerability, error)
+	// Generates the SBOM of the image in the CycloneDX JSON format at sbomPath
+	GenerateSbom(ctx context.Context, image string, sbomPath string) error
+	// Sets the credentials used to pull images from a private registry, nil pulls images anonymously
+	SetRegistryCredentials(credentials *RegistryCredentials)
+}
+
+// The credentials of a private container registry, ex) an Azure container registry the image was built in remotely
+type RegistryCredentials struct {
+	LoginServer string
+	Username    string
+	Password    string
+}
+
+const (
+	ScannerTrivy = "trivy"
+	ScannerGrype = "grype"
+)
+
+// NewScanner creates the scanner with the specified name, trivy or grype. Trivy is used when name is empty.
+func NewScanner(name string, commandRunner exec.CommandRunner) (Scanner, error) {
+	switch strings.ToLower(name) {
+	case "", ScannerTrivy:
+		return NewTrivy(commandRunner), nil
+	case ScannerGrype:
+		return NewGrype(commandRunner), nil
+	default:
+		return nil, fmt.Errorf("unsupported image scanner '%s', supported scanners are 'trivy' and 'grype'", name)
+	}
+}
+
+// A vulnerability found in a package of an image
+type Vulnerability struct {
+	Id               string   `json:"id"`
+	Severity         Severity `json:"severity"`
+	Package          string   `json:"package"`
+	InstalledVersion string   `json:"installedVersion"`
+	FixedVersion     string   `json:"fixedVersion,omitempty"`
+}
+
+type Severity string
+
+const (
+	SeverityUnknown  Severity = "UNKNOWN"
+	SeverityLow      Severity = "LOW"
+	SeverityMedium   Severity = "MEDIUM"
+	SeverityHigh     Severity = "HIGH"
+	SeverityCritical Severity = "CRITICAL"
+)
+
+var severityRanks = map[Severity]int{
+	SeverityUnknown:  0,
+	SeverityLow:      1,
+	SeverityMedium:   2,
+	SeverityHigh:     3,
+	SeverityCritical: 4,
+}
+
+// ParseSeverity parses a severity regardless of its case, ex) High
+func ParseSeverity(value string) (Severity, error) {
+	severity := Severity(strings.ToUpper(value))
+	if _, has := severityRanks[severity]; !has {
+		return "", fmt.Errorf(
+			"invalid severity '%s', expected one of 'low', 'medium', 'high' or 'critical'", value)
+	}
+
+	return severity, nil
+}
+
+// AtLeast returns true when the severity is the same as or higher than the threshold
+func (s Severity) AtLeast(threshold Severity) bool {
+	return severityRanks[s] >= severityRanks[threshold]
+}
+
+// normalizeSeverity converts the severity reported by a scanner, which isn't known to azd when it's a severity like
+// Negligible, to a Severity
+func normalizeSeverity(value string) Severity {
+	severity, err := ParseSeverity(value)
+	if err != nil {
+		log.Printf("unknown vulnerability severity '%s'", value)
+		return SeverityUnknown
+	}
+
+	return severity
+}
+
+// checkInstalled checks whether the scanner is installed and available within the PATH, logging its version
+func checkInstalled(ctx context.Context, commandRunner exec.CommandRunner, name string) error {
+	if err := tools.ToolInPath(name); err != nil {
+		return err
+	}
+
+	// There is no minimum required version of the scanners, the version is only logged for diagnostics
+	version, err := tools.ExecuteCommand(ctx, commandRunner, name, "--version")
+	if err != nil {
+		log.Printf("error fetching %s version: %s", name, err)
+	} else {
+		log.Printf("%s version: %s", name, strings.TrimSpace(version))
+	}
+
+	return nil
+}
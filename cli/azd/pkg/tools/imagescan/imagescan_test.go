@@ -0,0 +1,200 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package imagescan
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Trivy_Scan(t *testing.T) {
+	var scanArgs exec.RunArgs
+
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "trivy image")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		scanArgs = args
+		return exec.NewRunResult(0, `{
+			"Results": [
+				{
+					"Target": "app (debian 12.4)",
+					"Vulnerabilities": [
+						{
+							"VulnerabilityID": "CVE-2023-0001",
+							"PkgName": "openssl",
+							"InstalledVersion": "3.0.11",
+							"FixedVersion": "3.0.13",
+							"Severity": "CRITICAL"
+						}
+					]
+				},
+				{
+					"Target": "package-lock.json"
+				}
+			]
+		}`, ""), nil
+	})
+
+	vulnerabilities, err := NewTrivy(mockContext.CommandRunner).Scan(*mockContext.Context, "app:latest")
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"image", "--quiet", "--scanners", "vuln", "--format", "json", "app:latest",
+	}, scanArgs.Args)
+	require.Equal(t, []Vulnerability{
+		{
+			Id:               "CVE-2023-0001",
+			Severity:         SeverityCritical,
+			Package:          "openssl",
+			InstalledVersion: "3.0.11",
+			FixedVersion:     "3.0.13",
+		},
+	}, vulnerabilities)
+}
+
+func Test_Grype_Scan(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "grype app:latest")
+	}).Respond(exec.NewRunResult(0, `{
+		"matches": [
+			{
+				"vulnerability": {"id": "CVE-2023-0002", "severity": "Medium", "fix": {"versions": ["1.2.4"]}},
+				"artifact": {"name": "zlib", "version": "1.2.3"}
+			},
+			{
+				"vulnerability": {"id": "CVE-2023-0003", "severity": "Negligible", "fix": {"versions": []}},
+				"artifact": {"name": "bash", "version": "5.2"}
+			}
+		]
+	}`, ""))
+
+	vulnerabilities, err := NewGrype(mockContext.CommandRunner).Scan(*mockContext.Context, "app:latest")
+	require.NoError(t, err)
+	require.Equal(t, []Vulnerability{
+		{
+			Id:               "CVE-2023-0002",
+			Severity:         SeverityMedium,
+			Package:          "zlib",
+			InstalledVersion: "1.2.3",
+			FixedVersion:     "1.2.4",
+		},
+		{
+			Id:               "CVE-2023-0003",
+			Severity:         SeverityUnknown,
+			Package:          "bash",
+			InstalledVersion: "5.2",
+		},
+	}, vulnerabilities)
+}
+
+func Test_GenerateSbom(t *testing.T) {
+	var trivyArgs, grypeArgs exec.RunArgs
+
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return args.Cmd == "trivy"
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		trivyArgs = args
+		return exec.NewRunResult(0, "", ""), nil
+	})
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return args.Cmd == "grype"
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		grypeArgs = args
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	err := NewTrivy(mockContext.CommandRunner).GenerateSbom(*mockContext.Context, "app:latest", "sbom.cdx.json")
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"image", "--quiet", "--format", "cyclonedx", "--output", "sbom.cdx.json", "app:latest",
+	}, trivyArgs.Args)
+
+	err = NewGrype(mockContext.CommandRunner).GenerateSbom(*mockContext.Context, "app:latest", "sbom.cdx.json")
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"app:latest", "--quiet", "--output", "cyclonedx-json", "--file", "sbom.cdx.json",
+	}, grypeArgs.Args)
+}
+
+func Test_RegistryCredentials(t *testing.T) {
+	var trivyArgs, grypeArgs exec.RunArgs
+
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return args.Cmd == "trivy"
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		trivyArgs = args
+		return exec.NewRunResult(0, "{}", ""), nil
+	})
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return args.Cmd == "grype"
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		grypeArgs = args
+		return exec.NewRunResult(0, "{}", ""), nil
+	})
+
+	credentials := &RegistryCredentials{
+		LoginServer: "contoso.azurecr.io",
+		Username:    "00000000-0000-0000-0000-000000000000",
+		Password:    "REFRESH_TOKEN",
+	}
+	image := "contoso.azurecr.io/app:latest"
+
+	trivy := NewTrivy(mockContext.CommandRunner)
+	_, err := trivy.Scan(*mockContext.Context, image)
+	require.NoError(t, err)
+	require.Empty(t, trivyArgs.Env)
+
+	trivy.SetRegistryCredentials(credentials)
+	_, err = trivy.Scan(*mockContext.Context, image)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"TRIVY_USERNAME=00000000-0000-0000-0000-000000000000",
+		"TRIVY_PASSWORD=REFRESH_TOKEN",
+	}, trivyArgs.Env)
+
+	grype := NewGrype(mockContext.CommandRunner)
+	grype.SetRegistryCredentials(credentials)
+	err = grype.GenerateSbom(*mockContext.Context, image, "sbom.cdx.json")
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"GRYPE_REGISTRY_AUTH_AUTHORITY=contoso.azurecr.io",
+		"GRYPE_REGISTRY_AUTH_USERNAME=00000000-0000-0000-0000-000000000000",
+		"GRYPE_REGISTRY_AUTH_PASSWORD=REFRESH_TOKEN",
+	}, grypeArgs.Env)
+}
+
+func Test_NewScanner(t *testing.T) {
+	scanner, err := NewScanner("", nil)
+	require.NoError(t, err)
+	require.Equal(t, "Trivy", scanner.Name())
+
+	scanner, err = NewScanner("Grype", nil)
+	require.NoError(t, err)
+	require.Equal(t, "Grype", scanner.Name())
+
+	_, err = NewScanner("clair", nil)
+	require.ErrorContains(t, err, "unsupported image scanner 'clair'")
+}
+
+func Test_Severity(t *testing.T) {
+	severity, err := ParseSeverity("high")
+	require.NoError(t, err)
+	require.Equal(t, SeverityHigh, severity)
+
+	_, err = ParseSeverity("severe")
+	require.Error(t, err)
+
+	require.True(t, SeverityCritical.AtLeast(SeverityHigh))
+	require.True(t, SeverityHigh.AtLeast(SeverityHigh))
+	require.False(t, SeverityMedium.AtLeast(SeverityHigh))
+	require.False(t, SeverityUnknown.AtLeast(SeverityLow))
+}
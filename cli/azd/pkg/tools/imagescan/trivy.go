@@ -0,0 +1,102 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package imagescan
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+)
+
+type trivy struct {
+	commandRunner exec.CommandRunner
+	credentials   *RegistryCredentials
+}
+
+// NewTrivy creates a scanner that uses the Trivy CLI
+func NewTrivy(commandRunner exec.CommandRunner) Scanner {
+	return &trivy{
+		commandRunner: commandRunner,
+	}
+}
+
+func (t *trivy) CheckInstalled(ctx context.Context) error {
+	return checkInstalled(ctx, t.commandRunner, "trivy")
+}
+
+func (t *trivy) InstallUrl() string {
+	return "https://aquasecurity.github.io/trivy/latest/getting-started/installation/"
+}
+
+func (t *trivy) Name() string {
+	return "Trivy"
+}
+
+func (t *trivy) SetRegistryCredentials(credentials *RegistryCredentials) {
+	t.credentials = credentials
+}
+
+// run runs trivy, passing the registry credentials in the environment variables trivy reads them from
+func (t *trivy) run(ctx context.Context, args ...string) (exec.RunResult, error) {
+	runArgs := exec.NewRunArgs("trivy", args...)
+	if t.credentials != nil {
+		runArgs = runArgs.WithEnv([]string{
+			fmt.Sprintf("TRIVY_USERNAME=%s", t.credentials.Username),
+			fmt.Sprintf("TRIVY_PASSWORD=%s", t.credentials.Password),
+		})
+	}
+
+	return t.commandRunner.Run(ctx, runArgs)
+}
+
+// The report of `trivy image --format json`
+type trivyReport struct {
+	Results []struct {
+		Vulnerabilities []struct {
+			VulnerabilityID  string
+			PkgName          string
+			InstalledVersion string
+			FixedVersion     string
+			Severity         string
+		}
+	}
+}
+
+func (t *trivy) Scan(ctx context.Context, image string) ([]Vulnerability, error) {
+	res, err := t.run(ctx, "image", "--quiet", "--scanners", "vuln", "--format", "json", image)
+	if err != nil {
+		return nil, fmt.Errorf("scanning image %s with trivy: %w", image, err)
+	}
+
+	var report trivyReport
+	if err := json.Unmarshal([]byte(res.Stdout), &report); err != nil {
+		return nil, fmt.Errorf("parsing trivy report: %w", err)
+	}
+
+	vulnerabilities := []Vulnerability{}
+	for _, result := range report.Results {
+		for _, vulnerability := range result.Vulnerabilities {
+			vulnerabilities = append(vulnerabilities, Vulnerability{
+				Id:               vulnerability.VulnerabilityID,
+				Severity:         normalizeSeverity(vulnerability.Severity),
+				Package:          vulnerability.PkgName,
+				InstalledVersion: vulnerability.InstalledVersion,
+				FixedVersion:     vulnerability.FixedVersion,
+			})
+		}
+	}
+
+	return vulnerabilities, nil
+}
+
+func (t *trivy) GenerateSbom(ctx context.Context, image string, sbomPath string) error {
+	_, err := t.run(ctx, "image", "--quiet", "--format", "cyclonedx", "--output", sbomPath, image)
+	if err != nil {
+		return fmt.Errorf("generating SBOM of image %s with trivy: %w", image, err)
+	}
+
+	return nil
+}
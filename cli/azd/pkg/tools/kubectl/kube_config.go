@@ -47,13 +47,23 @@ func (kcm *KubeConfigManager) SaveKubeConfig(ctx context.Context, configName str
 		return "", fmt.Errorf("failed marshalling KubeConfig to yaml: %w", err)
 	}
 
+	return kcm.SaveRawKubeConfig(ctx, configName, kubeConfigRaw)
+}
+
+// Saves the raw kube config, ex) the output of kubectl config view, to the kube configuration folder with the specified
+// name. Unlike SaveKubeConfig, the fields that aren't modeled by KubeConfig are kept.
+func (kcm *KubeConfigManager) SaveRawKubeConfig(
+	ctx context.Context,
+	configName string,
+	kubeConfigRaw []byte,
+) (string, error) {
 	// Create .kube config folder if it doesn't already exist
 	if err := os.MkdirAll(kcm.configPath, osutil.PermissionDirectory); err != nil {
 		return "", fmt.Errorf("failed creating .kube config directory, %w", err)
 	}
 
 	outFilePath := filepath.Join(kcm.configPath, configName)
-	err = os.WriteFile(outFilePath, kubeConfigRaw, osutil.PermissionFile)
+	err := os.WriteFile(outFilePath, kubeConfigRaw, osutil.PermissionFile)
 	if err != nil {
 		return "", fmt.Errorf("failed writing kube config file: %w", err)
 	}
@@ -2,16 +2,22 @@ package kubectl
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"text/template"
+	"time"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"gopkg.in/yaml.v3"
 )
 
 // Executes commands against the Kubernetes CLI
@@ -25,6 +31,9 @@ type KubectlCli interface {
 	SetKubeConfig(kubeConfig string)
 	// Applies one or more files from the specified path
 	Apply(ctx context.Context, path string, flags *KubeCliFlags) error
+	// Applies one or more files from the specified path, rendering templates with the specified data, and returns the
+	// resources that were applied
+	ApplyWithTemplateData(ctx context.Context, path string, data *TemplateData, flags *KubeCliFlags) ([]Resource, error)
 	// Applies manifests from the specified input
 	ApplyWithStdIn(ctx context.Context, input string, flags *KubeCliFlags) (*exec.RunResult, error)
 	// Applies manifests from the specified file path
@@ -33,14 +42,39 @@ type KubectlCli interface {
 	ConfigView(ctx context.Context, merge bool, flatten bool, flags *KubeCliFlags) (*exec.RunResult, error)
 	// Sets the k8s context to use for future CLI commands
 	ConfigUseContext(ctx context.Context, name string, flags *KubeCliFlags) (*exec.RunResult, error)
+	// Sets the default namespace of the current k8s context
+	ConfigSetNamespace(ctx context.Context, namespace string, flags *KubeCliFlags) (*exec.RunResult, error)
 	// Creates a new k8s namespace with the specified name
 	CreateNamespace(ctx context.Context, name string, flags *KubeCliFlags) (*exec.RunResult, error)
 	// Executes a k8s CLI command from the specified arguments and flags
 	Exec(ctx context.Context, flags *KubeCliFlags, args ...string) (exec.RunResult, error)
 	// Gets the deployment rollout status
 	RolloutStatus(ctx context.Context, deploymentName string, flags *KubeCliFlags) (*exec.RunResult, error)
+	// Waits for the rollout of the specified resource, ex) statefulset/name, to complete
+	WaitForRollout(
+		ctx context.Context, resource string, timeout time.Duration, flags *KubeCliFlags) (*exec.RunResult, error)
+	// Rolls back the specified resource, ex) deployment/name, to its previous revision
+	RolloutUndo(ctx context.Context, resource string, flags *KubeCliFlags) (*exec.RunResult, error)
+	// Waits for the specified resource, ex) job/name, to reach the specified condition
+	WaitForCondition(
+		ctx context.Context,
+		resource string,
+		condition string,
+		timeout time.Duration,
+		flags *KubeCliFlags,
+	) (*exec.RunResult, error)
 	// Applies the manifests at the specified path using kustomize
 	ApplyWithKustomize(ctx context.Context, path string, flags *KubeCliFlags) error
+	// Writes the logs of all containers of the pods matching the label selector, ex) app=api, to the writer.
+	// When following, new logs are written until the context is cancelled.
+	Logs(
+		ctx context.Context,
+		selector string,
+		follow bool,
+		since time.Duration,
+		writer io.Writer,
+		flags *KubeCliFlags,
+	) error
 }
 
 type OutputType string
@@ -70,13 +104,44 @@ type KubeCliFlags struct {
 	Output OutputType
 }
 
-// templateRoot is the structure of the template available within the test templates that can be used within k8s manifests.
-// We have the option to include additional nodes within the template in the future for things like config, etc
-type templateRoot struct {
-	// The Azd environment variables
+// TemplateData is the structure of the data available within the templates that can be used within k8s manifests.
+type TemplateData struct {
+	// The Azd environment variables, including the outputs of the provisioned infrastructure
 	Env map[string]string
+	// The service the manifests are deployed for
+	Service *TemplateService
 }
 
+// TemplateService is the service the templates of k8s manifests are rendered for
+type TemplateService struct {
+	// The name of the service
+	Name string
+	// The namespace the service is deployed to
+	Namespace string
+	// The container image deployed for the service
+	Image string
+	// The properties saved to the environment for the service, ex) IMAGE_NAME for SERVICE_API_IMAGE_NAME
+	Properties map[string]string
+}
+
+// templateFuncs are the functions available within the templates of k8s manifests
+var templateFuncs = template.FuncMap{
+	"b64enc": func(value string) string {
+		return base64.StdEncoding.EncodeToString([]byte(value))
+	},
+	"quote": strconv.Quote,
+	"default": func(defaultValue any, value any) any {
+		if value == nil || value == "" {
+			return defaultValue
+		}
+
+		return value
+	},
+}
+
+// The maximum number of concurrent log streams when following the logs of multiple pods
+const maxLogRequests = 20
+
 type kubectlCli struct {
 	tools.ExternalTool
 	commandRunner exec.CommandRunner
@@ -166,6 +231,49 @@ func (cli *kubectlCli) ConfigUseContext(ctx context.Context, name string, flags
 	return &res, nil
 }
 
+// Sets the default namespace of the current k8s context
+func (cli *kubectlCli) ConfigSetNamespace(
+	ctx context.Context,
+	namespace string,
+	flags *KubeCliFlags,
+) (*exec.RunResult, error) {
+	res, err := cli.Exec(ctx, flags, "config", "set-context", "--current", fmt.Sprintf("--namespace=%s", namespace))
+	if err != nil {
+		return nil, fmt.Errorf("failed setting kubectl context namespace: %w", err)
+	}
+
+	return &res, nil
+}
+
+// Writes the logs of all containers of the pods matching the label selector to the writer.
+// Each line is prefixed with the pod and container it was written by.
+func (cli *kubectlCli) Logs(
+	ctx context.Context,
+	selector string,
+	follow bool,
+	since time.Duration,
+	writer io.Writer,
+	flags *KubeCliFlags,
+) error {
+	runArgs := exec.
+		NewRunArgs("kubectl", "logs", "-l", selector, "--all-containers", "--prefix", "--ignore-errors").
+		WithStdOut(writer)
+
+	if follow {
+		runArgs = runArgs.AppendParams("--follow", fmt.Sprintf("--max-log-requests=%d", maxLogRequests))
+	}
+
+	if since > 0 {
+		runArgs = runArgs.AppendParams(fmt.Sprintf("--since=%s", since))
+	}
+
+	if _, err := cli.executeCommandWithArgs(ctx, runArgs, flags); err != nil {
+		return fmt.Errorf("kubectl logs: %w", err)
+	}
+
+	return nil
+}
+
 // Views the current k8s configuration including available clusters, contexts & users
 func (cli *kubectlCli) ConfigView(
 	ctx context.Context,
@@ -223,11 +331,33 @@ func (cli *kubectlCli) ApplyWithFile(ctx context.Context, filePath string, flags
 
 // Applies manifests from the specified input
 func (cli *kubectlCli) Apply(ctx context.Context, path string, flags *KubeCliFlags) error {
-	if err := cli.applyTemplates(ctx, path, flags); err != nil {
-		return fmt.Errorf("failed process templates, %w", err)
+	_, err := cli.ApplyWithTemplateData(ctx, path, nil, flags)
+	return err
+}
+
+// Applies manifests from the specified input, rendering templates with the specified data.
+// When the data does not include any environment variables, the env vars of the CLI are used.
+func (cli *kubectlCli) ApplyWithTemplateData(
+	ctx context.Context,
+	path string,
+	data *TemplateData,
+	flags *KubeCliFlags,
+) ([]Resource, error) {
+	templateData := TemplateData{}
+	if data != nil {
+		templateData = *data
 	}
 
-	return nil
+	if templateData.Env == nil {
+		templateData.Env = cli.env
+	}
+
+	resources, err := cli.applyTemplates(ctx, path, &templateData, flags)
+	if err != nil {
+		return nil, fmt.Errorf("failed process templates, %w", err)
+	}
+
+	return resources, nil
 }
 
 // Applies the manifests at the specified path using kustomize
@@ -268,6 +398,54 @@ func (cli *kubectlCli) RolloutStatus(
 	return &res, nil
 }
 
+// Waits for the rollout of the specified resource to complete
+func (cli *kubectlCli) WaitForRollout(
+	ctx context.Context,
+	resource string,
+	timeout time.Duration,
+	flags *KubeCliFlags,
+) (*exec.RunResult, error) {
+	res, err := cli.Exec(ctx, flags, "rollout", "status", resource, fmt.Sprintf("--timeout=%s", timeout))
+	if err != nil {
+		return nil, fmt.Errorf("%s rollout failed, %w", resource, err)
+	}
+
+	return &res, nil
+}
+
+// Rolls back the specified resource to its previous revision
+func (cli *kubectlCli) RolloutUndo(ctx context.Context, resource string, flags *KubeCliFlags) (*exec.RunResult, error) {
+	res, err := cli.Exec(ctx, flags, "rollout", "undo", resource)
+	if err != nil {
+		return nil, fmt.Errorf("%s rollout undo failed, %w", resource, err)
+	}
+
+	return &res, nil
+}
+
+// Waits for the specified resource to reach the specified condition
+func (cli *kubectlCli) WaitForCondition(
+	ctx context.Context,
+	resource string,
+	condition string,
+	timeout time.Duration,
+	flags *KubeCliFlags,
+) (*exec.RunResult, error) {
+	res, err := cli.Exec(
+		ctx,
+		flags,
+		"wait",
+		resource,
+		fmt.Sprintf("--for=condition=%s", condition),
+		fmt.Sprintf("--timeout=%s", timeout),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("waiting for %s condition %s failed, %w", resource, condition, err)
+	}
+
+	return &res, nil
+}
+
 // Executes a k8s CLI command from the specified arguments and flags
 func (cli *kubectlCli) Exec(ctx context.Context, flags *KubeCliFlags, args ...string) (exec.RunResult, error) {
 	runArgs := exec.
@@ -277,47 +455,63 @@ func (cli *kubectlCli) Exec(ctx context.Context, flags *KubeCliFlags, args ...st
 	return cli.executeCommandWithArgs(ctx, runArgs, flags)
 }
 
-func (cli *kubectlCli) applyTemplate(ctx context.Context, filePath string, flags *KubeCliFlags) (*exec.RunResult, error) {
-	k8sTemplate, err := template.ParseFiles(filePath)
+func (cli *kubectlCli) applyTemplate(
+	ctx context.Context,
+	filePath string,
+	data *TemplateData,
+	flags *KubeCliFlags,
+) (string, error) {
+	k8sTemplate, err := template.New(filepath.Base(filePath)).Funcs(templateFuncs).ParseFiles(filePath)
 	if err != nil {
-		return nil, fmt.Errorf("failed parsing template file '%s', %w", filePath, err)
+		return "", fmt.Errorf("failed parsing template file '%s', %w", filePath, err)
 	}
 
 	builder := strings.Builder{}
-	err = k8sTemplate.Execute(&builder, templateRoot{Env: cli.env})
+	err = k8sTemplate.Execute(&builder, data)
 	if err != nil {
-		return nil, fmt.Errorf("failed executing template file '%s', %w", filePath, err)
+		return "", fmt.Errorf("failed executing template file '%s', %w", filePath, err)
 	}
 
-	result, err := cli.ApplyWithStdIn(ctx, builder.String(), flags)
+	_, err = cli.ApplyWithStdIn(ctx, builder.String(), flags)
 	if err != nil {
-		return nil, fmt.Errorf("failed applying file '%s', %w", filePath, err)
+		return "", fmt.Errorf("failed applying file '%s', %w", filePath, err)
 	}
 
-	return result, nil
+	return builder.String(), nil
 }
 
 // Recursively loops through the specified directory and applies all k8s manifests
 // If the file is a *.tmpl file, it will be parsed as a template to support environment injection.
 // Otherwise the actual file contents will be applied.
-func (cli *kubectlCli) applyTemplates(ctx context.Context, directoryPath string, flags *KubeCliFlags) error {
+// Returns the resources declared within the applied manifests.
+func (cli *kubectlCli) applyTemplates(
+	ctx context.Context,
+	directoryPath string,
+	data *TemplateData,
+	flags *KubeCliFlags,
+) ([]Resource, error) {
 	entries, err := os.ReadDir(directoryPath)
 	if err != nil {
-		return fmt.Errorf("failed reading files in path, '%s', %w", directoryPath, err)
+		return nil, fmt.Errorf("failed reading files in path, '%s', %w", directoryPath, err)
 	}
 
+	resources := []Resource{}
+
 	for _, entry := range entries {
 		entryPath := filepath.Join(directoryPath, entry.Name())
 
 		if entry.IsDir() {
-			if err := cli.applyTemplates(ctx, entryPath, flags); err != nil {
-				return fmt.Errorf("failed applying templates at '%s', %w", entryPath, err)
+			dirResources, err := cli.applyTemplates(ctx, entryPath, data, flags)
+			if err != nil {
+				return nil, fmt.Errorf("failed applying templates at '%s', %w", entryPath, err)
 			}
 
+			resources = append(resources, dirResources...)
 			continue
 		}
 
 		ext := filepath.Ext(entry.Name())
+		var manifest string
 		var err error
 
 		switch ext {
@@ -326,20 +520,70 @@ func (cli *kubectlCli) applyTemplates(ctx context.Context, directoryPath string,
 			isTemplateFile := strings.HasSuffix(fileNameWithoutExtension, ".tmpl")
 
 			if isTemplateFile {
-				_, err = cli.applyTemplate(ctx, entryPath, flags)
+				manifest, err = cli.applyTemplate(ctx, entryPath, data, flags)
 			} else {
 				_, err = cli.ApplyWithFile(ctx, entryPath, flags)
+				if err == nil {
+					var contents []byte
+					contents, err = os.ReadFile(entryPath)
+					manifest = string(contents)
+				}
 			}
 		default: // Ignore all other files
 			continue
 		}
 
 		if err != nil {
-			return fmt.Errorf("failed applying file '%s', %w", entryPath, err)
+			return nil, fmt.Errorf("failed applying file '%s', %w", entryPath, err)
 		}
+
+		fileResources, err := parseResources(manifest)
+		if err != nil {
+			return nil, fmt.Errorf("failed parsing k8s resources of file '%s', %w", entryPath, err)
+		}
+
+		resources = append(resources, fileResources...)
 	}
 
-	return nil
+	return resources, nil
+}
+
+// parseResources returns the resources declared within the documents of a k8s manifest.
+// Documents that are not k8s resources are ignored, while documents that can't be parsed fail the whole manifest so
+// that none of its resources are silently left out.
+func parseResources(manifest string) ([]Resource, error) {
+	resources := []Resource{}
+	decoder := yaml.NewDecoder(strings.NewReader(manifest))
+
+	for document := 1; ; document++ {
+		var node yaml.Node
+		err := decoder.Decode(&node)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("parsing document %d of manifest: %w", document, err)
+		}
+
+		// k8s resources are mappings
+		if len(node.Content) == 0 || node.Content[0].Kind != yaml.MappingNode {
+			continue
+		}
+
+		var resource Resource
+		if err := node.Decode(&resource); err != nil {
+			return nil, fmt.Errorf("parsing document %d of manifest: %w", document, err)
+		}
+
+		if resource.Kind == "" || resource.Metadata.Name == "" {
+			continue
+		}
+
+		resources = append(resources, resource)
+	}
+
+	return resources, nil
 }
 
 func (cli *kubectlCli) executeCommandWithArgs(
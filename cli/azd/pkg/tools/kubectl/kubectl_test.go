@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
@@ -100,6 +101,29 @@ func Test_Command_Args(t *testing.T) {
 				return err
 			},
 		},
+		"config-set-namespace": {
+			mockCommandPredicate: "kubectl config set-context",
+			expectedCmd:          "kubectl",
+			expectedArgs:         []string{"config", "set-context", "--current", "--namespace=namespace-name"},
+			testFn: func() error {
+				_, err := cli.ConfigSetNamespace(*mockContext.Context, "namespace-name", nil)
+
+				return err
+			},
+		},
+		"logs": {
+			mockCommandPredicate: "kubectl logs",
+			expectedCmd:          "kubectl",
+			expectedArgs: []string{
+				"logs", "-l", "app=api", "--all-containers", "--prefix", "--ignore-errors",
+				"--follow", "--max-log-requests=20", "--since=5m0s", "-n", "namespace-name",
+			},
+			testFn: func() error {
+				return cli.Logs(*mockContext.Context, "app=api", true, 5*time.Minute, io.Discard, &KubeCliFlags{
+					Namespace: "namespace-name",
+				})
+			},
+		},
 		"create-namespace": {
 			mockCommandPredicate: "kubectl create namespace",
 			expectedCmd:          "kubectl",
@@ -125,6 +149,44 @@ func Test_Command_Args(t *testing.T) {
 				return err
 			},
 		},
+		"wait-for-rollout": {
+			mockCommandPredicate: "kubectl rollout status",
+			expectedCmd:          "kubectl",
+			expectedArgs:         []string{"rollout", "status", "statefulset/db", "--timeout=5m0s", "-n", "test-namespace"},
+			testFn: func() error {
+				_, err := cli.WaitForRollout(*mockContext.Context, "statefulset/db", 5*time.Minute, &KubeCliFlags{
+					Namespace: "test-namespace",
+				})
+
+				return err
+			},
+		},
+		"rollout-undo": {
+			mockCommandPredicate: "kubectl rollout undo",
+			expectedCmd:          "kubectl",
+			expectedArgs:         []string{"rollout", "undo", "deployment/api", "-n", "test-namespace"},
+			testFn: func() error {
+				_, err := cli.RolloutUndo(*mockContext.Context, "deployment/api", &KubeCliFlags{
+					Namespace: "test-namespace",
+				})
+
+				return err
+			},
+		},
+		"wait-for-condition": {
+			mockCommandPredicate: "kubectl wait",
+			expectedCmd:          "kubectl",
+			expectedArgs: []string{
+				"wait", "job/migrate", "--for=condition=complete", "--timeout=1m0s", "-n", "test-namespace",
+			},
+			testFn: func() error {
+				_, err := cli.WaitForCondition(*mockContext.Context, "job/migrate", "complete", time.Minute, &KubeCliFlags{
+					Namespace: "test-namespace",
+				})
+
+				return err
+			},
+		},
 		"exec": {
 			mockCommandPredicate: "kubectl get deployment",
 			expectedCmd:          "kubectl",
@@ -253,3 +315,119 @@ func Test_Apply_Template(t *testing.T) {
 		require.Contains(t, yaml, "EXAMPLE_CLIENT_ID")
 	})
 }
+
+func Test_ApplyWithTemplateData(t *testing.T) {
+	var stdIn string
+
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl apply")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		if args.StdIn != nil {
+			contents, err := io.ReadAll(args.StdIn)
+			require.NoError(t, err)
+			stdIn = string(contents)
+		}
+
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	tempDir := t.TempDir()
+	manifest := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{ .Service.Name }}
+  namespace: {{ .Service.Namespace }}
+spec:
+  template:
+    spec:
+      containers:
+      - name: {{ .Service.Name }}
+        image: {{ .Service.Image }}
+        env:
+        - name: ENDPOINT
+          value: {{ .Service.Properties.ENDPOINT_URL | default "http://localhost" | quote }}
+        - name: STORAGE
+          value: {{ .Env.AZURE_STORAGE_ENDPOINT | quote }}
+---
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: {{ .Service.Name }}-migrate
+`
+	err := os.WriteFile(filepath.Join(tempDir, "api.tmpl.yaml"), []byte(manifest), osutil.PermissionFile)
+	require.NoError(t, err)
+
+	raw := `apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: db
+`
+	err = os.WriteFile(filepath.Join(tempDir, "db.yaml"), []byte(raw), osutil.PermissionFile)
+	require.NoError(t, err)
+
+	cli := NewKubectl(mockContext.CommandRunner)
+	cli.SetEnv(map[string]string{
+		"AZURE_STORAGE_ENDPOINT": "https://storage.example.com",
+	})
+
+	resources, err := cli.ApplyWithTemplateData(*mockContext.Context, tempDir, &TemplateData{
+		Service: &TemplateService{
+			Name:       "api",
+			Namespace:  "test",
+			Image:      "test.azurecr.io/api:latest",
+			Properties: map[string]string{},
+		},
+	}, nil)
+	require.NoError(t, err)
+
+	require.Contains(t, stdIn, "image: test.azurecr.io/api:latest")
+	require.Contains(t, stdIn, `value: "http://localhost"`)
+	require.Contains(t, stdIn, `value: "https://storage.example.com"`)
+
+	refs := []string{}
+	for _, resource := range resources {
+		refs = append(refs, resource.Ref())
+	}
+	require.Equal(t, []string{"deployment/api", "job/api-migrate", "statefulset/db"}, refs)
+	require.Equal(t, "test", resources[0].Metadata.Namespace)
+}
+
+func Test_ParseResources(t *testing.T) {
+	t.Run("Documents", func(t *testing.T) {
+		resources, err := parseResources(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+---
+# Documents that aren't k8s resources are ignored
+---
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: migrate
+`)
+		require.NoError(t, err)
+		require.Len(t, resources, 2)
+		require.Equal(t, "deployment/api", resources[0].Ref())
+		require.Equal(t, "job/migrate", resources[1].Ref())
+	})
+
+	t.Run("MalformedDocument", func(t *testing.T) {
+		// The resources after a malformed document aren't silently dropped
+		_, err := parseResources(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+---
+kind: Job
+metadata: [name: migrate
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: db
+`)
+		require.ErrorContains(t, err, "parsing document 2 of manifest")
+	})
+}
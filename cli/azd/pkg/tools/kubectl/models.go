@@ -3,6 +3,8 @@ package kubectl
 import (
 	"encoding/json"
 	"fmt"
+	"slices"
+	"strings"
 )
 
 type ResourceType string
@@ -20,6 +22,11 @@ type Resource struct {
 	Metadata   ResourceMetadata `json:"metadata"   yaml:"metadata"`
 }
 
+// Ref returns the reference of the resource used by kubectl commands, ex) deployment/name
+func (r Resource) Ref() string {
+	return fmt.Sprintf("%s/%s", strings.ToLower(r.Kind), r.Metadata.Name)
+}
+
 type List[T any] struct {
 	Resource
 	Items []T `json:"items" yaml:"items"`
@@ -40,7 +47,24 @@ type ResourceMetadata struct {
 type Deployment ResourceWithSpec[DeploymentSpec, DeploymentStatus]
 
 type DeploymentSpec struct {
-	Replicas int `json:"replicas" yaml:"replicas"`
+	Replicas int            `json:"replicas"           yaml:"replicas"`
+	Selector *LabelSelector `json:"selector,omitempty" yaml:"selector,omitempty"`
+}
+
+type LabelSelector struct {
+	MatchLabels map[string]string `json:"matchLabels,omitempty" yaml:"matchLabels,omitempty"`
+}
+
+// String returns the selector in the format expected by the -l flag of kubectl commands, ex) app=api,tier=web
+func (s *LabelSelector) String() string {
+	labels := make([]string, 0, len(s.MatchLabels))
+	for key, value := range s.MatchLabels {
+		labels = append(labels, fmt.Sprintf("%s=%s", key, value))
+	}
+
+	slices.Sort(labels)
+
+	return strings.Join(labels, ",")
 }
 
 type DeploymentStatus struct {
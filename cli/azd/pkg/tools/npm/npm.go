@@ -6,6 +6,7 @@ package npm
 import (
 	"context"
 	"fmt"
+	"io"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
@@ -21,6 +22,10 @@ type NpmCli interface {
 	// Returns an error only if the script execution fails. If the script doesn't exist, no error is returned.
 	RunScript(ctx context.Context, projectPath string, scriptName string) error
 	Prune(ctx context.Context, projectPath string, production bool) error
+
+	// Start runs the `start` script of the project with the additional environment variables, writing its output to
+	// the writer. It blocks until the script exits or the context is cancelled.
+	Start(ctx context.Context, projectPath string, env []string, writer io.Writer) error
 }
 
 type npmCli struct {
@@ -117,3 +122,19 @@ func (cli *npmCli) Prune(ctx context.Context, projectPath string, production boo
 
 	return nil
 }
+
+func (cli *npmCli) Start(ctx context.Context, projectPath string, env []string, writer io.Writer) error {
+	runArgs := exec.
+		NewRunArgs("npm", "start").
+		WithCwd(projectPath).
+		WithEnv(env).
+		WithStdOut(writer).
+		WithStdErr(writer)
+
+	_, err := cli.commandRunner.Run(ctx, runArgs)
+	if err != nil {
+		return fmt.Errorf("failed to run NPM start script, %w", err)
+	}
+
+	return nil
+}
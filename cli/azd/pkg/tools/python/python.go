@@ -6,6 +6,7 @@ package python
 import (
 	"context"
 	"fmt"
+	"io"
 	"log"
 	"path"
 	"path/filepath"
@@ -129,6 +130,42 @@ func (cli *PythonCli) CreateVirtualEnv(ctx context.Context, workingDir, name str
 	return nil
 }
 
+// Run runs the Python interpreter of the virtual environment with the arguments and the additional environment variables,
+// writing its output to the writer. It blocks until the interpreter exits or the context is cancelled.
+func (cli *PythonCli) Run(
+	ctx context.Context,
+	workingDir string,
+	environment string,
+	args []string,
+	env []string,
+	writer io.Writer,
+) error {
+	absWorkingDir, err := filepath.Abs(workingDir)
+	if err != nil {
+		return err
+	}
+
+	vEnvPath := filepath.Join(absWorkingDir, environment)
+	pyPath := filepath.Join(vEnvPath, "bin", "python")
+	if runtime.GOOS == "windows" {
+		pyPath = filepath.Join(vEnvPath, "Scripts", "python.exe")
+	}
+
+	// Running the interpreter of the virtual environment is equivalent to activating it, except for VIRTUAL_ENV
+	runArgs := exec.
+		NewRunArgs(pyPath, args...).
+		WithCwd(workingDir).
+		WithEnv(append(env, fmt.Sprintf("VIRTUAL_ENV=%s", vEnvPath))).
+		WithStdOut(writer).
+		WithStdErr(writer)
+
+	_, err = cli.commandRunner.Run(ctx, runArgs)
+	if err != nil {
+		return fmt.Errorf("failed to run Python for project '%s': %w", workingDir, err)
+	}
+	return nil
+}
+
 func checkPath() (pyString string, err error) {
 	if runtime.GOOS == "windows" {
 		// py for https://peps.python.org/pep-0397
@@ -32,6 +32,10 @@ type TerraformCli interface {
 	Show(ctx context.Context, modulePath string, additionalArgs ...string) (string, error)
 	// Destroys all resources referenced in the terraform module
 	Destroy(ctx context.Context, modulePath string, additionalArgs ...string) (string, error)
+	// Selects the workspace with the given name, creating it when it doesn't exist
+	SelectWorkspace(ctx context.Context, modulePath string, workspace string) error
+	// Uploads a local state file to the configured backend
+	StatePush(ctx context.Context, modulePath string, stateFilePath string) error
 }
 
 type terraformCli struct {
@@ -252,3 +256,41 @@ func (cli *terraformCli) Destroy(ctx context.Context, modulePath string, additio
 	}
 	return cmdRes.Stdout, nil
 }
+
+func (cli *terraformCli) SelectWorkspace(ctx context.Context, modulePath string, workspace string) error {
+	chdir := fmt.Sprintf("-chdir=%s", modulePath)
+
+	// `workspace select -or-create` is only available from terraform 1.4, fall back to `workspace new` instead.
+	if _, err := cli.runCommand(ctx, chdir, "workspace", "select", workspace); err == nil {
+		return nil
+	}
+
+	cmdRes, err := cli.runCommand(ctx, chdir, "workspace", "new", workspace)
+	if err != nil {
+		return fmt.Errorf(
+			"failed running terraform workspace new: %s (%w)",
+			cmdRes.Stderr,
+			err,
+		)
+	}
+	return nil
+}
+
+func (cli *terraformCli) StatePush(ctx context.Context, modulePath string, stateFilePath string) error {
+	args := []string{
+		fmt.Sprintf("-chdir=%s", modulePath),
+		"state",
+		"push",
+		stateFilePath,
+	}
+
+	cmdRes, err := cli.runCommand(ctx, args...)
+	if err != nil {
+		return fmt.Errorf(
+			"failed running terraform state push: %s (%w)",
+			cmdRes.Stderr,
+			err,
+		)
+	}
+	return nil
+}
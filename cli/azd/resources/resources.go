@@ -22,5 +22,14 @@ var ScaffoldBase embed.FS
 //go:embed scaffold/templates/*
 var ScaffoldTemplates embed.FS
 
+//go:embed scaffold/terraform/*
+var ScaffoldTerraformTemplates embed.FS
+
 //go:embed apphost/templates/*
 var AppHostTemplates embed.FS
+
+//go:embed apphost/terraform/*
+var AppHostTerraformTemplates embed.FS
+
+//go:embed apphost/helm/templates/*
+var AppHostHelmTemplates embed.FS
@@ -42,3 +42,13 @@ func NewDeploymentsServiceFromMockContext(
 		mockContext.ArmClientOptions,
 	)
 }
+
+func NewDeploymentStacksServiceFromMockContext(
+	mockContext *mocks.MockContext) azapi.DeploymentStacks {
+	return azapi.NewDeploymentStacks(
+		mockaccount.SubscriptionCredentialProviderFunc(func(_ context.Context, _ string) (azcore.TokenCredential, error) {
+			return mockContext.Credentials, nil
+		}),
+		mockContext.ArmClientOptions,
+	)
+}
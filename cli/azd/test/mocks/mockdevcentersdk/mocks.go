@@ -331,3 +331,67 @@ func MockListEnvironmentTypes(
 
 	return mockRequest
 }
+
+func MockPatchEnvironment(
+	mockContext *mocks.MockContext,
+	projectName string,
+	userId string,
+	environmentName string,
+	environment *devcentersdk.Environment,
+) *http.Request {
+	mockRequest := &http.Request{}
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPatch &&
+			request.URL.Path == fmt.Sprintf(
+				"/projects/%s/users/%s/environments/%s",
+				projectName,
+				userId,
+				environmentName,
+			)
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		*mockRequest = *request
+
+		if environment == nil {
+			return mocks.CreateEmptyHttpResponse(request, http.StatusNotFound)
+		}
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, environment)
+	})
+
+	return mockRequest
+}
+
+func MockListEnvironmentOperations(
+	mockContext *mocks.MockContext,
+	projectName string,
+	userId string,
+	environmentName string,
+	operations []*devcentersdk.EnvironmentOperation,
+) *http.Request {
+	mockRequest := &http.Request{}
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet &&
+			request.URL.Path == fmt.Sprintf(
+				"/projects/%s/users/%s/environments/%s/operations",
+				projectName,
+				userId,
+				environmentName,
+			)
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		*mockRequest = *request
+
+		if operations == nil {
+			return mocks.CreateEmptyHttpResponse(request, http.StatusNotFound)
+		}
+
+		response := devcentersdk.EnvironmentOperationListResponse{
+			Value: operations,
+		}
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, response)
+	})
+
+	return mockRequest
+}
@@ -0,0 +1,14 @@
+// Package schemas embeds the JSON schemas of azure.yaml.
+package schemas
+
+import _ "embed"
+
+// AzureYamlV1 is the JSON schema of the stable azure.yaml properties.
+//
+//go:embed v1.0/azure.yaml.json
+var AzureYamlV1 []byte
+
+// AzureYamlAlpha is the JSON schema of azure.yaml, including the properties of alpha features.
+//
+//go:embed alpha/azure.yaml.json
+var AzureYamlAlpha []byte